@@ -0,0 +1,217 @@
+// Package client is a Go client for the Progressive table API (/api/v1):
+// tables, records, import and export, and templates.
+//
+//	c, err := client.New("http://localhost:8081")
+//	id, err := c.CreateRecord(ctx, "game_item", map[string]interface{}{"item_name": "sword"})
+//	for rec, err := range c.AllRecords(ctx, "game_item", 100) { ... }
+//
+// Failed requests return an *Error carrying the server's error code; compare
+// with errors.Is against ErrTableNotFound and the other sentinels.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIPrefix is the path of the API version the client speaks
+const APIPrefix = "/api/v1"
+
+// Client calls the API of one server. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retry      RetryPolicy
+	header     http.Header
+}
+
+// RetryPolicy decides how often a failed request is sent again. Requests
+// are retried on 429 and 503, and idempotent ones (GET, PUT, DELETE) also on
+// network errors, 502 and 504. A Retry-After header sets the wait.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 disables retries
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry; it doubles up to MaxBackoff
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetry is given
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, MinBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with hc instead of http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithRetry replaces the retry policy
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithHeader adds a header to every request, e.g. for authentication
+func WithHeader(key, value string) Option {
+	return func(c *Client) { c.header.Add(key, value) }
+}
+
+// New creates a client for the server at baseURL, e.g.
+// "http://localhost:8081"; the API prefix is added by the client
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("client: base URL %q needs a scheme and a host", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy,
+		header:     http.Header{"User-Agent": {"progressive-go-client"}},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	resp, err := c.send(ctx, method, path, query, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
+
+// send sends a request with retries and returns a successful response,
+// whose body the caller must close, or the error of the last attempt
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, in interface{}) (*http.Response, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, fmt.Errorf("client: encode request body: %w", err)
+		}
+	}
+	// path is already escaped
+	target := c.baseURL.String() + APIPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	attempts := max(c.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("client: %w", err)
+		}
+		for key, values := range c.header {
+			req.Header[key] = values
+		}
+		req.Header.Set("Accept", "application/json")
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+
+		var wait time.Duration
+		retry := attempt < attempts
+		if err != nil {
+			err = fmt.Errorf("client: %s %s: %w", method, path, err)
+			retry = retry && idempotent(method) && ctx.Err() == nil
+		} else {
+			retry = retry && retryable(method, resp.StatusCode)
+			wait = retryAfter(resp.Header.Get("Retry-After"))
+			err = decodeError(resp)
+			resp.Body.Close()
+		}
+		if !retry {
+			return nil, err
+		}
+
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		if c.retry.MaxBackoff > 0 {
+			wait = min(wait, c.retry.MaxBackoff)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// backoff waits MinBackoff·2^(attempt-1), half of it random
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retry.MinBackoff << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a request that got status may be sent again.
+// 429 and 503 mean the server did not process it.
+func retryable(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header in seconds or as a date
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// errMissingID is returned before sending a request without a table ID
+var errMissingID = errors.New("client: table ID is required")
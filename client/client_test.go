@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetry = WithRetry(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[{"id": "items", "name": "Items"}]`))
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL, fastRetry)
+	tables, err := c.ListTables(context.Background())
+	if err != nil || len(tables) != 1 || tables[0].ID != "items" {
+		t.Fatalf("Expected the third attempt to succeed, got %v, %v", tables, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestDoesNotRetryNonIdempotentOnBadGateway(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL, fastRetry)
+	_, err := c.CreateRecord(context.Background(), "items", map[string]interface{}{"name": "sword"})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected a 502 error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a POST to be sent once, got %d", calls.Load())
+	}
+}
+
+func TestRetriesTooManyRequestsWithRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"success": true, "id": 7}`))
+	}))
+	defer srv.Close()
+
+	// MaxBackoff caps the one second the server asks for
+	c, _ := New(srv.URL, fastRetry)
+	id, err := c.CreateRecord(context.Background(), "items", map[string]interface{}{"name": "sword"})
+	if err != nil || id != 7 {
+		t.Fatalf("Expected the retry to create record 7, got %d, %v", id, err)
+	}
+}
+
+func TestDecodesProblemDocuments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "urn:progressive:problem:validation_failed", "title": "Bad Request", "status": 400,
+			"code": "validation_failed", "detail": "No data to import", "request_id": "req-1",
+			"errors": [{"field": "data", "code": "required", "message": "No data to import"}]}`))
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL, fastRetry)
+	_, err := c.Import(context.Background(), "items", nil, ImportAppend)
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrTableNotFound) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	var apiErr *Error
+	errors.As(err, &apiErr)
+	if apiErr.RequestID != "req-1" || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "data" {
+		t.Errorf("Expected the problem members, got %+v", apiErr)
+	}
+}
+
+func TestAllRecordsFollowsPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"records": [{"_id": 3, "name": "c"}, {"_id": 2, "name": "b"}], "pagination": {"page": 1, "limit": 2, "total": 3, "has_more": true}}`))
+		default:
+			w.Write([]byte(`{"records": [{"_id": 1, "name": "a"}], "pagination": {"page": 2, "limit": 2, "total": 3, "has_more": false}}`))
+		}
+	}))
+	defer srv.Close()
+
+	c, _ := New(srv.URL)
+	var names []interface{}
+	for rec, err := range c.AllRecords(context.Background(), "items", 2) {
+		if err != nil {
+			t.Fatalf("Iteration failed: %v", err)
+		}
+		if _, ok := rec.Fields["_id"]; ok {
+			t.Error("Expected _id to be split out of the fields")
+		}
+		names = append(names, rec.Fields["name"])
+	}
+	if len(names) != 3 || names[2] != "a" {
+		t.Errorf("Expected 3 records over 2 pages, got %v", names)
+	}
+}
+
+func TestNewRejectsRelativeURL(t *testing.T) {
+	if _, err := New("localhost:8081"); err == nil {
+		t.Error("Expected an error for a URL without scheme")
+	}
+}
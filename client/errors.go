@@ -0,0 +1,98 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Error is a failed request, decoded from the server's RFC 7807 problem
+// document. Code is the stable machine-readable error code.
+type Error struct {
+	StatusCode int
+	Code       string
+	Title      string
+	Detail     string
+	// Instance is the request path
+	Instance  string
+	RequestID string
+	Fields    []FieldError
+}
+
+// FieldError points at one invalid input field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "progressive: %d", e.StatusCode)
+	if e.Code != "" {
+		b.WriteString(" " + e.Code)
+	}
+	if e.Detail != "" {
+		b.WriteString(": " + e.Detail)
+	}
+	for _, f := range e.Fields {
+		fmt.Fprintf(&b, "; %s: %s", f.Field, f.Message)
+	}
+	if e.RequestID != "" {
+		b.WriteString(" (request " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// Is matches the sentinels below by code, so that
+// errors.Is(err, client.ErrTableNotFound) works on any *Error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// Sentinels for the error codes of the table API
+var (
+	ErrNotFound         = &Error{Code: "not_found"}
+	ErrTableNotFound    = &Error{Code: "table_not_found"}
+	ErrRecordNotFound   = &Error{Code: "record_not_found"}
+	ErrTableExists      = &Error{Code: "table_exists"}
+	ErrInvalidJSON      = &Error{Code: "invalid_json"}
+	ErrValidation       = &Error{Code: "validation_failed"}
+	ErrInvalidSchema    = &Error{Code: "invalid_schema"}
+	ErrMethodNotAllowed = &Error{Code: "method_not_allowed"}
+	ErrNotImplemented   = &Error{Code: "not_implemented"}
+	ErrInternal         = &Error{Code: "internal_error"}
+)
+
+// decodeError reads an error response. A body that is not a problem
+// document, e.g. from a proxy, becomes the detail.
+func decodeError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	e := &Error{StatusCode: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}
+
+	var problem struct {
+		Title     string       `json:"title"`
+		Detail    string       `json:"detail"`
+		Instance  string       `json:"instance"`
+		Code      string       `json:"code"`
+		RequestID string       `json:"request_id"`
+		Errors    []FieldError `json:"errors"`
+	}
+	if json.Unmarshal(body, &problem) == nil && problem.Code != "" {
+		e.Code = problem.Code
+		e.Title = problem.Title
+		e.Detail = problem.Detail
+		e.Instance = problem.Instance
+		e.RequestID = problem.RequestID
+		e.Fields = problem.Errors
+	} else {
+		e.Detail = strings.TrimSpace(string(body))
+	}
+	if e.RequestID == "" {
+		e.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	return e
+}
@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Record is a row of a table. Fields holds the values defined by the
+// table's schema; the server's "_id" and "_created_at" are ID and CreatedAt.
+type Record struct {
+	ID        int64
+	CreatedAt time.Time
+	Fields    map[string]interface{}
+}
+
+// UnmarshalJSON splits the server's flat record into system and user fields
+func (r *Record) UnmarshalJSON(data []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var system struct {
+		ID        int64     `json:"_id"`
+		CreatedAt time.Time `json:"_created_at"`
+	}
+	if err := json.Unmarshal(data, &system); err != nil {
+		return err
+	}
+	delete(fields, "_id")
+	delete(fields, "_created_at")
+	*r = Record{ID: system.ID, CreatedAt: system.CreatedAt, Fields: fields}
+	return nil
+}
+
+// MarshalJSON writes the record flat, as the server does
+func (r Record) MarshalJSON() ([]byte, error) {
+	flat := make(map[string]interface{}, len(r.Fields)+2)
+	for k, v := range r.Fields {
+		flat[k] = v
+	}
+	flat["_id"] = r.ID
+	flat["_created_at"] = r.CreatedAt
+	return json.Marshal(flat)
+}
+
+// RecordPage is one page of a table's records, newest first
+type RecordPage struct {
+	Records []Record
+	Page    int
+	Limit   int
+	// Total is the number of records in the table
+	Total   int
+	HasMore bool
+}
+
+// ListOptions selects a page; zero values use the server defaults (page 1
+// of 20 records)
+type ListOptions struct {
+	Page  int
+	Limit int
+}
+
+// ImportMode says whether imported records replace the table's records or
+// are added to them
+type ImportMode string
+
+const (
+	ImportReplace ImportMode = "replace"
+	ImportAppend  ImportMode = "append"
+)
+
+// ExportFormat is the file format of an export
+type ExportFormat string
+
+const (
+	ExportJSON  ExportFormat = "json"
+	ExportCSV   ExportFormat = "csv"
+	ExportExcel ExportFormat = "excel"
+)
+
+func recordsPath(tableID string) string {
+	return "/tables/" + url.PathEscape(tableID) + "/records"
+}
+
+func recordPath(tableID string, id int64) string {
+	return recordsPath(tableID) + "/" + strconv.FormatInt(id, 10)
+}
+
+// ListRecords returns one page of a table's records
+func (c *Client) ListRecords(ctx context.Context, tableID string, opts ListOptions) (*RecordPage, error) {
+	if tableID == "" {
+		return nil, errMissingID
+	}
+	query := url.Values{}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	var resp struct {
+		Records    []Record `json:"records"`
+		Pagination struct {
+			Page    int  `json:"page"`
+			Limit   int  `json:"limit"`
+			Total   int  `json:"total"`
+			HasMore bool `json:"has_more"`
+		} `json:"pagination"`
+	}
+	if err := c.do(ctx, http.MethodGet, recordsPath(tableID), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &RecordPage{
+		Records: resp.Records,
+		Page:    resp.Pagination.Page,
+		Limit:   resp.Pagination.Limit,
+		Total:   resp.Pagination.Total,
+		HasMore: resp.Pagination.HasMore,
+	}, nil
+}
+
+// AllRecords iterates over every record of a table, fetching pageSize
+// records at a time (the server default when zero). Iteration stops at the
+// first error, which is yielded with a zero Record.
+func (c *Client) AllRecords(ctx context.Context, tableID string, pageSize int) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		for page := 1; ; page++ {
+			p, err := c.ListRecords(ctx, tableID, ListOptions{Page: page, Limit: pageSize})
+			if err != nil {
+				yield(Record{}, err)
+				return
+			}
+			for _, rec := range p.Records {
+				if !yield(rec, nil) {
+					return
+				}
+			}
+			if !p.HasMore || len(p.Records) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// GetRecord returns one record
+func (c *Client) GetRecord(ctx context.Context, tableID string, id int64) (*Record, error) {
+	if tableID == "" {
+		return nil, errMissingID
+	}
+	var rec Record
+	if err := c.do(ctx, http.MethodGet, recordPath(tableID, id), nil, nil, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// CreateRecord adds a record and returns its ID
+func (c *Client) CreateRecord(ctx context.Context, tableID string, fields map[string]interface{}) (int64, error) {
+	if tableID == "" {
+		return 0, errMissingID
+	}
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, recordsPath(tableID), nil, fields, &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+// UpdateRecord replaces the given fields of a record and keeps the others
+func (c *Client) UpdateRecord(ctx context.Context, tableID string, id int64, fields map[string]interface{}) error {
+	if tableID == "" {
+		return errMissingID
+	}
+	return c.do(ctx, http.MethodPatch, recordPath(tableID, id), nil, fields, nil)
+}
+
+// DeleteRecord deletes a record
+func (c *Client) DeleteRecord(ctx context.Context, tableID string, id int64) error {
+	if tableID == "" {
+		return errMissingID
+	}
+	return c.do(ctx, http.MethodDelete, recordPath(tableID, id), nil, nil, nil)
+}
+
+// CreateRecords adds several records in one transaction and returns how
+// many were added
+func (c *Client) CreateRecords(ctx context.Context, tableID string, records []map[string]interface{}) (int, error) {
+	return c.Import(ctx, tableID, records, ImportAppend)
+}
+
+// ReplaceRecords replaces every record of a table in one transaction
+func (c *Client) ReplaceRecords(ctx context.Context, tableID string, records []map[string]interface{}) error {
+	if tableID == "" {
+		return errMissingID
+	}
+	if records == nil {
+		records = []map[string]interface{}{}
+	}
+	return c.do(ctx, http.MethodPut, recordsPath(tableID), nil, map[string]interface{}{"records": records}, nil)
+}
+
+// Import loads records into a table and returns how many were imported
+func (c *Client) Import(ctx context.Context, tableID string, records []map[string]interface{}, mode ImportMode) (int, error) {
+	if tableID == "" {
+		return 0, errMissingID
+	}
+	var resp struct {
+		Imported int `json:"imported"`
+	}
+	body := map[string]interface{}{"data": records, "mode": mode}
+	if err := c.do(ctx, http.MethodPost, "/tables/"+url.PathEscape(tableID)+"/import", nil, body, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Imported, nil
+}
+
+// Export streams a table's records as a file in the given format; the
+// caller must close it
+func (c *Client) Export(ctx context.Context, tableID string, format ExportFormat) (io.ReadCloser, error) {
+	if tableID == "" {
+		return nil, errMissingID
+	}
+	resp, err := c.send(ctx, http.MethodGet, "/tables/"+url.PathEscape(tableID)+"/export", url.Values{"format": {string(format)}}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ExportRecords returns the fields of every record of a table, decoded
+// from a JSON export
+func (c *Client) ExportRecords(ctx context.Context, tableID string) ([]map[string]interface{}, error) {
+	body, err := c.Export(ctx, tableID, ExportJSON)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var records []map[string]interface{}
+	if err := json.NewDecoder(body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("client: decode export: %w", err)
+	}
+	return records, nil
+}
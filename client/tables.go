@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Table is a user-defined table; Schema is its JSON Schema
+type Table struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	RecordCount int             `json:"record_count"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// NewTable describes a table to create
+type NewTable struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema"`
+}
+
+// Template is a reusable table schema
+type Template struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Icon        string          `json:"icon"`
+	Schema      json.RawMessage `json:"schema"`
+	SampleData  json.RawMessage `json:"sample_data,omitempty"`
+	Workspace   string          `json:"workspace,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// ListTables returns every table
+func (c *Client) ListTables(ctx context.Context) ([]Table, error) {
+	var tables []Table
+	err := c.do(ctx, http.MethodGet, "/tables", nil, nil, &tables)
+	return tables, err
+}
+
+// GetTable returns a table's metadata and schema
+func (c *Client) GetTable(ctx context.Context, id string) (*Table, error) {
+	if id == "" {
+		return nil, errMissingID
+	}
+	var t Table
+	if err := c.do(ctx, http.MethodGet, "/tables/"+url.PathEscape(id), nil, nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTable creates a table with the ID t.ID. ErrTableExists is returned
+// when the ID is taken.
+func (c *Client) CreateTable(ctx context.Context, t NewTable) error {
+	if t.ID == "" {
+		return errMissingID
+	}
+	return c.do(ctx, http.MethodPut, "/tables/"+url.PathEscape(t.ID), nil, t, nil)
+}
+
+// DeleteTable deletes a table with its records
+func (c *Client) DeleteTable(ctx context.Context, id string) error {
+	if id == "" {
+		return errMissingID
+	}
+	return c.do(ctx, http.MethodDelete, "/tables/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ListTemplates returns the table templates
+func (c *Client) ListTemplates(ctx context.Context) ([]Template, error) {
+	var templates []Template
+	err := c.do(ctx, http.MethodGet, "/templates", nil, nil, &templates)
+	return templates, err
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"progressive/client"
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+)
+
+// backend is where the commands read and write tables: a running server
+// through the API, or the database directly
+type backend interface {
+	ListTables(ctx context.Context) ([]*table.Table, error)
+	// GetTable returns table.ErrNotFound for a missing table
+	GetTable(ctx context.Context, id string) (*table.Table, error)
+	CreateTable(ctx context.Context, t *table.Table) error
+	DeleteTable(ctx context.Context, id string) error
+
+	// Records returns every record of a table
+	Records(ctx context.Context, tableID string) ([]*record.Record, error)
+	// GetRecord returns record.ErrNotFound for a missing record
+	GetRecord(ctx context.Context, tableID string, id int64) (*record.Record, error)
+	// CreateRecord stores rec and sets its ID
+	CreateRecord(ctx context.Context, rec *record.Record) error
+	// UpdateRecord merges patch into a record; nil values clear a field
+	UpdateRecord(ctx context.Context, tableID string, id int64, patch map[string]interface{}) error
+	DeleteRecord(ctx context.Context, tableID string, id int64) error
+	// Import adds rows in one transaction, replacing the existing records
+	// when replace is set
+	Import(ctx context.Context, tableID string, rows []map[string]interface{}, replace bool) error
+
+	Close() error
+}
+
+// apiBackend talks to a running server
+type apiBackend struct {
+	c *client.Client
+}
+
+func newAPIBackend(baseURL string) (*apiBackend, error) {
+	c, err := client.New(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &apiBackend{c: c}, nil
+}
+
+// apiError maps the API's not-found errors to the domain errors the
+// database backend returns
+func apiError(err error) error {
+	switch {
+	case errors.Is(err, client.ErrTableNotFound):
+		return fmt.Errorf("%w: %v", table.ErrNotFound, err)
+	case errors.Is(err, client.ErrRecordNotFound):
+		return fmt.Errorf("%w: %v", record.ErrNotFound, err)
+	case errors.Is(err, client.ErrTableExists):
+		return fmt.Errorf("%w: %v", table.ErrAlreadyExists, err)
+	}
+	return err
+}
+
+func fromClientTable(t *client.Table) *table.Table {
+	return &table.Table{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Schema:      t.Schema,
+		RecordCount: t.RecordCount,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func (b *apiBackend) ListTables(ctx context.Context) ([]*table.Table, error) {
+	tables, err := b.c.ListTables(ctx)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	result := make([]*table.Table, len(tables))
+	for i := range tables {
+		result[i] = fromClientTable(&tables[i])
+	}
+	return result, nil
+}
+
+func (b *apiBackend) GetTable(ctx context.Context, id string) (*table.Table, error) {
+	t, err := b.c.GetTable(ctx, id)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return fromClientTable(t), nil
+}
+
+func (b *apiBackend) CreateTable(ctx context.Context, t *table.Table) error {
+	return apiError(b.c.CreateTable(ctx, client.NewTable{ID: t.ID, Name: t.Name, Description: t.Description, Schema: t.Schema}))
+}
+
+func (b *apiBackend) DeleteTable(ctx context.Context, id string) error {
+	return apiError(b.c.DeleteTable(ctx, id))
+}
+
+func (b *apiBackend) Records(ctx context.Context, tableID string) ([]*record.Record, error) {
+	var recs []*record.Record
+	for rec, err := range b.c.AllRecords(ctx, tableID, 100) {
+		if err != nil {
+			return nil, apiError(err)
+		}
+		recs = append(recs, &record.Record{ID: rec.ID, TableID: tableID, Data: rec.Fields, CreatedAt: rec.CreatedAt})
+	}
+	return recs, nil
+}
+
+func (b *apiBackend) GetRecord(ctx context.Context, tableID string, id int64) (*record.Record, error) {
+	rec, err := b.c.GetRecord(ctx, tableID, id)
+	if err != nil {
+		return nil, apiError(err)
+	}
+	return &record.Record{ID: rec.ID, TableID: tableID, Data: rec.Fields, CreatedAt: rec.CreatedAt}, nil
+}
+
+func (b *apiBackend) CreateRecord(ctx context.Context, rec *record.Record) error {
+	id, err := b.c.CreateRecord(ctx, rec.TableID, rec.Data)
+	if err != nil {
+		return apiError(err)
+	}
+	rec.ID = id
+	return nil
+}
+
+func (b *apiBackend) UpdateRecord(ctx context.Context, tableID string, id int64, patch map[string]interface{}) error {
+	return apiError(b.c.UpdateRecord(ctx, tableID, id, patch))
+}
+
+func (b *apiBackend) DeleteRecord(ctx context.Context, tableID string, id int64) error {
+	return apiError(b.c.DeleteRecord(ctx, tableID, id))
+}
+
+func (b *apiBackend) Import(ctx context.Context, tableID string, rows []map[string]interface{}, replace bool) error {
+	mode := client.ImportAppend
+	if replace {
+		mode = client.ImportReplace
+	}
+	_, err := b.c.Import(ctx, tableID, rows, mode)
+	return apiError(err)
+}
+
+func (b *apiBackend) Close() error { return nil }
+
+// storeBackend works on the database directly, for scripts that run
+// without a server
+type storeBackend struct {
+	store *storage.Store
+}
+
+func (b *storeBackend) ListTables(ctx context.Context) ([]*table.Table, error) {
+	return b.store.Tables.FindAll(ctx)
+}
+
+func (b *storeBackend) GetTable(ctx context.Context, id string) (*table.Table, error) {
+	return b.store.Tables.FindByID(ctx, id)
+}
+
+func (b *storeBackend) CreateTable(ctx context.Context, t *table.Table) error {
+	if err := table.ValidateSchema(t.Schema); err != nil {
+		return err
+	}
+	return b.store.Tables.Create(ctx, t)
+}
+
+func (b *storeBackend) DeleteTable(ctx context.Context, id string) error {
+	return b.store.Tables.Delete(ctx, id)
+}
+
+func (b *storeBackend) Records(ctx context.Context, tableID string) ([]*record.Record, error) {
+	if _, err := b.store.Tables.FindByID(ctx, tableID); err != nil {
+		return nil, err
+	}
+	return b.store.Records.FindByTable(ctx, tableID, record.Page{})
+}
+
+func (b *storeBackend) GetRecord(ctx context.Context, tableID string, id int64) (*record.Record, error) {
+	return b.store.Records.FindByID(ctx, tableID, id)
+}
+
+func (b *storeBackend) CreateRecord(ctx context.Context, rec *record.Record) error {
+	return b.store.Records.Create(ctx, rec)
+}
+
+func (b *storeBackend) UpdateRecord(ctx context.Context, tableID string, id int64, patch map[string]interface{}) error {
+	rec, err := b.store.Records.FindByID(ctx, tableID, id)
+	if err != nil {
+		return err
+	}
+	rec.Merge(patch)
+	return b.store.Records.Update(ctx, rec)
+}
+
+func (b *storeBackend) DeleteRecord(ctx context.Context, tableID string, id int64) error {
+	return b.store.Records.Delete(ctx, tableID, id)
+}
+
+func (b *storeBackend) Import(ctx context.Context, tableID string, rows []map[string]interface{}, replace bool) error {
+	recs := make([]*record.Record, len(rows))
+	for i, row := range rows {
+		recs[i] = record.NewRecord(tableID, row)
+	}
+	if replace {
+		return b.store.Records.Replace(ctx, tableID, recs)
+	}
+	return b.store.Records.Append(ctx, tableID, recs)
+}
+
+// Push applies a push plan in one transaction: a new table is created
+// together with its records, otherwise all record changes are applied
+// together
+func (b *storeBackend) Push(ctx context.Context, t *table.Table, plan *pushPlan) ([]*record.Record, error) {
+	created := make([]*record.Record, len(plan.creates))
+	for i, rec := range plan.creates {
+		created[i] = record.NewRecord(t.ID, rec.Data)
+	}
+	if plan.createTable {
+		if err := b.store.Records.CreateWithTable(ctx, t, created); err != nil {
+			return nil, err
+		}
+		return created, nil
+	}
+
+	changes := record.Changes{Create: created, Delete: plan.deletes}
+	for i, rec := range plan.updates {
+		stored, err := b.store.Records.FindByID(ctx, t.ID, rec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rec.Path, err)
+		}
+		stored.Merge(plan.patches[i])
+		changes.Update = append(changes.Update, stored)
+	}
+	if err := b.store.Records.Apply(ctx, t.ID, changes); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (b *storeBackend) Close() error {
+	return b.store.Close()
+}
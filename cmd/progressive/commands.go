@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/validation"
+)
+
+// cli runs commands against one backend
+type cli struct {
+	backend backend
+	out     io.Writer
+	// in is read by "records put" without a file (default: stdin)
+	in io.Reader
+}
+
+// commands lists every command, for checking one before the backend is opened
+var commands = []string{
+	"tables ls", "tables create", "tables rm",
+	"records get", "records put", "records rm",
+	"import", "export", "schema diff", "pull", "push",
+}
+
+// splitCommand separates the command, e.g. "tables ls", from its arguments
+func splitCommand(args []string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, errUsage
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case "tables", "records", "schema":
+		if len(args) == 0 {
+			return "", nil, fmt.Errorf("%w: %s needs a subcommand", errUsage, command)
+		}
+		command += " " + args[0]
+		args = args[1:]
+	}
+	if !slices.Contains(commands, command) {
+		return "", nil, fmt.Errorf("%w: unknown command %q", errUsage, command)
+	}
+	return command, args, nil
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	command, args, err := splitCommand(args)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "tables ls":
+		return c.listTables(ctx)
+	case "tables create":
+		return c.createTable(ctx, args)
+	case "tables rm":
+		return c.deleteTable(ctx, args)
+	case "records get":
+		return c.getRecord(ctx, args)
+	case "records put":
+		return c.putRecord(ctx, args)
+	case "records rm":
+		return c.deleteRecord(ctx, args)
+	case "import":
+		return c.importFile(ctx, args)
+	case "export":
+		return c.export(ctx, args)
+	case "schema diff":
+		return c.schemaDiff(ctx, args)
+	case "pull":
+		return c.pull(ctx, args)
+	case "push":
+		return c.push(ctx, args)
+	}
+	return nil
+}
+
+// parseArgs parses fs from args, allowing flags after positional arguments,
+// and checks the number of positional arguments
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) < min || (max >= 0 && len(positional) > max) {
+		return nil, fmt.Errorf("%w: %s expects %s", errUsage, fs.Name(), argCount(min, max))
+	}
+	return positional, nil
+}
+
+func argCount(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("at least %d arguments", min)
+	case min == max:
+		return fmt.Sprintf("%d arguments", min)
+	}
+	return fmt.Sprintf("%d to %d arguments", min, max)
+}
+
+func parseRecordID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid record id %q", s)
+	}
+	return id, nil
+}
+
+func (c *cli) listTables(ctx context.Context) error {
+	tables, err := c.backend.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tRECORDS\tDESCRIPTION")
+	for _, t := range tables {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", t.ID, t.Name, t.RecordCount, t.Description)
+	}
+	return w.Flush()
+}
+
+func (c *cli) createTable(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("tables create", flag.ExitOnError)
+	name := fs.String("name", "", "display name (default: the id)")
+	description := fs.String("description", "", "table description")
+	positional, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	schema, err := readSchemaFile(positional[1])
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		*name = positional[0]
+	}
+	if err := c.backend.CreateTable(ctx, table.NewTable(positional[0], *name, *description, schema)); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "created table %s\n", positional[0])
+	return nil
+}
+
+func (c *cli) deleteTable(ctx context.Context, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("tables rm", flag.ExitOnError), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := c.backend.DeleteTable(ctx, positional[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "deleted table %s\n", positional[0])
+	return nil
+}
+
+func (c *cli) getRecord(ctx context.Context, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("records get", flag.ExitOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseRecordID(positional[1])
+	if err != nil {
+		return err
+	}
+	rec, err := c.backend.GetRecord(ctx, positional[0], id)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rec.Flatten())
+}
+
+// putRecord creates a record, or updates the fields given in the object
+// when an id is passed
+func (c *cli) putRecord(ctx context.Context, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("records put", flag.ExitOnError), args, 1, 3)
+	if err != nil {
+		return err
+	}
+	tableID, rest := positional[0], positional[1:]
+	var id int64
+	if len(rest) > 0 {
+		if n, err := parseRecordID(rest[0]); err == nil {
+			id, rest = n, rest[1:]
+		}
+	}
+	file := "-"
+	switch len(rest) {
+	case 0:
+	case 1:
+		file = rest[0]
+	default:
+		return fmt.Errorf("invalid record id %q", positional[1])
+	}
+
+	var data map[string]interface{}
+	if file == "-" {
+		in := c.in
+		if in == nil {
+			in = os.Stdin
+		}
+		data, err = decodeObject(in, record.FormatJSON)
+	} else {
+		data, err = readObjectFile(file)
+	}
+	if err != nil {
+		return err
+	}
+	delete(data, "_id")
+	delete(data, "_created_at")
+
+	validator, err := c.validator(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		if errs := validator.Validate(data); len(errs) > 0 {
+			return validationError("record", errs)
+		}
+		rec := record.NewRecord(tableID, data)
+		if err := c.backend.CreateRecord(ctx, rec); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "created record %d\n", rec.ID)
+		return nil
+	}
+
+	if errs := validator.ValidatePartial(data); len(errs) > 0 {
+		return validationError("record", errs)
+	}
+	if err := c.backend.UpdateRecord(ctx, tableID, id, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "updated record %d\n", id)
+	return nil
+}
+
+func (c *cli) deleteRecord(ctx context.Context, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("records rm", flag.ExitOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseRecordID(positional[1])
+	if err != nil {
+		return err
+	}
+	if err := c.backend.DeleteRecord(ctx, positional[0], id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "deleted record %d\n", id)
+	return nil
+}
+
+// importFile loads a file with the same parsing as the editor's import:
+// CSV values are converted to the schema's types, and every row must
+// validate before anything is written
+func (c *cli) importFile(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	mode := fs.String("mode", "append", "append to the records or replace them")
+	positional, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if *mode != "append" && *mode != "replace" {
+		return fmt.Errorf("invalid -mode %q (expected append or replace)", *mode)
+	}
+	tableID, path := positional[0], positional[1]
+
+	format, err := record.FormatOf(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	rows, err := record.Read(file, format)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%s: no records to import", path)
+	}
+
+	validator, err := c.validator(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	var invalid []string
+	for i, row := range rows {
+		validator.Coerce(row)
+		for _, e := range validator.Validate(row) {
+			invalid = append(invalid, fmt.Sprintf("row %d: %s", i+1, e))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%s has invalid records:\n  %s", path, strings.Join(invalid, "\n  "))
+	}
+
+	if err := c.backend.Import(ctx, tableID, rows, *mode == "replace"); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "imported %d records into %s (%s)\n", len(rows), tableID, *mode)
+	return nil
+}
+
+func (c *cli) export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "json, csv, excel or yaml")
+	output := fs.String("o", "", "output file (default: stdout)")
+	positional, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	recs, err := c.backend.Records(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	rows := make([]map[string]interface{}, len(recs))
+	for i, rec := range recs {
+		rows[i] = rec.Data
+	}
+
+	out := c.out
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := record.Write(out, record.Format(*format), rows); err != nil {
+		return err
+	}
+	if *output != "" {
+		fmt.Fprintf(c.out, "exported %d records to %s\n", len(rows), *output)
+	}
+	return nil
+}
+
+// validator compiles the schema of a table
+func (c *cli) validator(ctx context.Context, tableID string) (*validation.Validator, error) {
+	t, err := c.backend.GetTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return validation.NewFromJSON(t.Schema)
+}
+
+// validationError lists the violations of one record
+func validationError(what string, errs []validation.FieldError) error {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+	return fmt.Errorf("invalid %s: %s", what, strings.Join(messages, "; "))
+}
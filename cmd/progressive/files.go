@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"progressive/internal/domain/record"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeObject reads one JSON or YAML object. YAML is normalized through
+// JSON so that numbers are float64 as in records read from the backend.
+func decodeObject(r io.Reader, format record.Format) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	switch format {
+	case record.FormatJSON:
+		if err := json.NewDecoder(r).Decode(&data); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case record.FormatYAML:
+		var raw map[string]interface{}
+		if err := yaml.NewDecoder(r).Decode(&raw); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return decodeObject(bytes.NewReader(encoded), record.FormatJSON)
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s", format)
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	return data, nil
+}
+
+// readObjectFile reads a JSON or YAML object, choosing by file extension
+func readObjectFile(path string) (map[string]interface{}, error) {
+	format, err := record.FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := decodeObject(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+// writeObjectFile writes data as indented JSON or YAML with sorted keys,
+// so that files diff cleanly in git
+func writeObjectFile(path string, format record.Format, data map[string]interface{}) error {
+	var encoded []byte
+	var err error
+	if format == record.FormatYAML {
+		var b bytes.Buffer
+		enc := yaml.NewEncoder(&b)
+		enc.SetIndent(2)
+		if err = enc.Encode(data); err == nil {
+			err = enc.Close()
+		}
+		encoded = b.Bytes()
+	} else {
+		encoded, err = json.MarshalIndent(data, "", "  ")
+		encoded = append(encoded, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, encoded, 0o644)
+}
+
+// readSchemaFile reads a JSON Schema, or the schema of a table file
+// written by pull
+func readSchemaFile(path string) (json.RawMessage, error) {
+	data, err := readObjectFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if schema, ok := data["schema"].(map[string]interface{}); ok {
+		if _, ok := data["properties"]; !ok {
+			data = schema
+		}
+	}
+	return json.Marshal(data)
+}
@@ -0,0 +1,108 @@
+// Command progressive manages tables and records from the shell, through the
+// API of a running server or directly on the database.
+//
+//	progressive -server http://localhost:8081 tables ls
+//	progressive -storage sqlite -sqlite-path data.db export -format csv game_item
+//	progressive pull -dir data game_item   # edit data/game_item/records/*.json
+//	progressive push -dir data game_item
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"progressive/internal/config"
+	"progressive/internal/storage"
+)
+
+// ServerEnv names the server URL when -server is not given
+const ServerEnv = config.EnvPrefix + "URL"
+
+const usage = `Usage: progressive [-server URL | database flags] <command> [arguments]
+
+Commands:
+  tables ls                              list tables
+  tables create [-name N] [-description D] <id> <schema file>
+  tables rm <id>                         delete a table with its records
+  records get <table> <id>               print a record as JSON
+  records put <table> [id] [file]        create, or update with an id, from a JSON/YAML object (default: stdin)
+  records rm <table> <id>                delete a record
+  import [-mode append|replace] <table> <file>
+                                         load a JSON, YAML or CSV file
+  export [-format json|csv|excel|yaml] [-o file] <table>
+  schema diff <table> <file>             compare a local schema or table file with the table
+  pull [-dir D] [-format json|yaml] <table>...
+                                         write tables to D/<table>/ as one file per record
+  push [-dir D] [-dry-run] <table>...    apply D/<table>/ to the tables
+
+Without -server the database is opened with the same flags, environment
+variables and config file as the web server. Run "progressive -h" for them.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			if err != errUsage {
+				fmt.Fprintf(os.Stderr, "%v\n\n", err)
+			}
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// errUsage is returned for a missing or unknown command
+var errUsage = errors.New("invalid usage")
+
+// run parses the global flags, opens the backend and runs the command
+func run(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("progressive", flag.ExitOnError)
+	server := fs.String("server", "", "URL of a running server, e.g. http://localhost:8081 (env: "+ServerEnv+"); without it the database is opened directly")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage+"\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	cfg, err := config.Load(fs, args, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if _, _, err := splitCommand(fs.Args()); err != nil {
+		return err
+	}
+
+	url := *server
+	if url == "" {
+		url = os.Getenv(ServerEnv)
+	}
+	var b backend
+	if url != "" {
+		b, err = newAPIBackend(url)
+	} else {
+		db := cfg.Database
+		if db.Storage == storage.Postgres && db.DSN == "" && !db.Embedded.Persistent {
+			return errors.New("no database to work on: pass -server, -dsn, -persistent or -storage sqlite")
+		}
+		if err = cfg.Validate(); err == nil {
+			var store *storage.Store
+			store, err = storage.Open(cfg.StorageOptions())
+			b = &storeBackend{store: store}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+
+	return (&cli{backend: b, out: out}).run(ctx, fs.Args())
+}
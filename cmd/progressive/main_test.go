@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+const itemSchema = `{
+	"type": "object",
+	"properties": {
+		"item_name": {"type": "string"},
+		"price": {"type": "integer", "minimum": 0},
+		"tradable": {"type": "boolean"}
+	},
+	"required": ["item_name"]
+}`
+
+func newTestCLI(t *testing.T) (*cli, *bytes.Buffer, *storage.Store) {
+	t.Helper()
+	store := storagetest.Open(t, storage.SQLite)
+	ctx := context.Background()
+	if err := store.Tables.Create(ctx, table.NewTable("game_item", "Items", "", json.RawMessage(itemSchema))); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for _, data := range []map[string]interface{}{
+		{"item_name": "sword", "price": 100},
+		{"item_name": "bow", "price": 80},
+	} {
+		if err := store.Records.Create(ctx, record.NewRecord("game_item", data)); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+	var out bytes.Buffer
+	return &cli{backend: &storeBackend{store: store}, out: &out}, &out, store
+}
+
+func records(t *testing.T, store *storage.Store) map[string]map[string]interface{} {
+	t.Helper()
+	recs, err := store.Records.FindByTable(context.Background(), "game_item", record.Page{})
+	if err != nil {
+		t.Fatalf("FindByTable failed: %v", err)
+	}
+	byName := make(map[string]map[string]interface{})
+	for _, rec := range recs {
+		byName[rec.Data["item_name"].(string)] = rec.Data
+	}
+	return byName
+}
+
+func TestPullPushRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			c, out, store := newTestCLI(t)
+			ctx := context.Background()
+			dir := t.TempDir()
+			recordsPath := filepath.Join(dir, "game_item", "records")
+
+			if err := c.run(ctx, []string{"pull", "game_item", "-dir", dir, "-format", format}); err != nil {
+				t.Fatalf("pull failed: %v", err)
+			}
+			files, _ := filepath.Glob(filepath.Join(recordsPath, "*."+format))
+			if len(files) != 2 {
+				t.Fatalf("Expected 2 record files, got %v", files)
+			}
+
+			// Pushing an unchanged pull changes nothing
+			out.Reset()
+			if err := c.run(ctx, []string{"push", "-dir", dir, "game_item"}); err != nil {
+				t.Fatalf("push failed: %v", err)
+			}
+			if !strings.Contains(out.String(), "0 to create, 0 to update, 0 to delete") {
+				t.Errorf("Expected no changes, got:\n%s", out.String())
+			}
+
+			// Edit one record, delete the other and add a new one
+			var swordPath, bowPath string
+			for _, file := range files {
+				data, _ := readObjectFile(file)
+				if data["item_name"] == "sword" {
+					swordPath = file
+				} else {
+					bowPath = file
+				}
+			}
+			writeObjectFile(swordPath, record.Format(format), map[string]interface{}{"item_name": "sword", "price": 120, "tradable": true})
+			os.Remove(bowPath)
+			newPath := filepath.Join(recordsPath, "axe."+format)
+			writeObjectFile(newPath, record.Format(format), map[string]interface{}{"item_name": "axe", "price": 90})
+
+			out.Reset()
+			if err := c.run(ctx, []string{"push", "-dir", dir, "-dry-run", "game_item"}); err != nil {
+				t.Fatalf("dry run failed: %v", err)
+			}
+			if len(records(t, store)) != 2 || !strings.Contains(out.String(), "1 to create, 1 to update, 1 to delete") {
+				t.Errorf("Expected a dry run to only print the plan, got:\n%s", out.String())
+			}
+
+			if err := c.run(ctx, []string{"push", "-dir", dir, "game_item"}); err != nil {
+				t.Fatalf("push failed: %v", err)
+			}
+			got := records(t, store)
+			if len(got) != 2 || got["sword"]["price"] != float64(120) || got["sword"]["tradable"] != true || got["axe"] == nil {
+				t.Errorf("Expected the edits to be pushed, got %v", got)
+			}
+			if _, err := os.Stat(newPath); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Expected the new record file to be renamed to its ID")
+			}
+
+			// The renamed file is known now: a second push changes nothing
+			out.Reset()
+			if err := c.run(ctx, []string{"push", "-dir", dir, "game_item"}); err != nil {
+				t.Fatalf("push failed: %v", err)
+			}
+			if !strings.Contains(out.String(), "0 to create, 0 to update, 0 to delete") {
+				t.Errorf("Expected the second push to change nothing, got:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestPushRejectsInvalidRecordsAndSchemaChanges(t *testing.T) {
+	c, _, store := newTestCLI(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := c.run(ctx, []string{"pull", "-dir", dir, "game_item"}); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+
+	badPath := filepath.Join(dir, "game_item", "records", "bad.json")
+	writeObjectFile(badPath, record.FormatJSON, map[string]interface{}{"item_name": "shield", "price": -5})
+	err := c.run(ctx, []string{"push", "-dir", dir, "game_item"})
+	if err == nil || !strings.Contains(err.Error(), "bad.json: price") {
+		t.Errorf("Expected the invalid record to be reported, got %v", err)
+	}
+	os.Remove(badPath)
+
+	tablePath := filepath.Join(dir, "game_item", "table.json")
+	tbl, _ := readObjectFile(tablePath)
+	tbl["schema"].(map[string]interface{})["properties"].(map[string]interface{})["rarity"] = map[string]interface{}{"type": "string"}
+	writeObjectFile(tablePath, record.FormatJSON, tbl)
+	err = c.run(ctx, []string{"push", "-dir", dir, "game_item"})
+	if err == nil || !strings.Contains(err.Error(), "+ rarity") {
+		t.Errorf("Expected the schema change to stop the push, got %v", err)
+	}
+	if len(records(t, store)) != 2 {
+		t.Error("Expected nothing to be written")
+	}
+}
+
+func TestPushCreatesMissingTable(t *testing.T) {
+	c, _, store := newTestCLI(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := c.run(ctx, []string{"pull", "-dir", dir, "game_item"}); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	os.Rename(filepath.Join(dir, "game_item"), filepath.Join(dir, "item_copy"))
+	tablePath := filepath.Join(dir, "item_copy", "table.json")
+	tbl, _ := readObjectFile(tablePath)
+	tbl["id"] = "item_copy"
+	writeObjectFile(tablePath, record.FormatJSON, tbl)
+
+	if err := c.run(ctx, []string{"push", "-dir", dir, "item_copy"}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	recs, err := store.Records.FindByTable(ctx, "item_copy", record.Page{})
+	if err != nil || len(recs) != 2 {
+		t.Errorf("Expected the table to be created with 2 records, got %d, %v", len(recs), err)
+	}
+}
+
+// failingDeletes hides storeBackend.Push, so pushes go step by step as
+// through the API, and fails every record delete
+type failingDeletes struct {
+	backend
+}
+
+func (b failingDeletes) DeleteRecord(ctx context.Context, tableID string, id int64) error {
+	return errors.New("connection reset")
+}
+
+func TestPushReportsAppliedStepsOnFailure(t *testing.T) {
+	c, out, store := newTestCLI(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	if err := c.run(ctx, []string{"pull", "-dir", dir, "game_item"}); err != nil {
+		t.Fatalf("pull failed: %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "game_item", "records", "*.json"))
+	for _, file := range files {
+		data, _ := readObjectFile(file)
+		if data["item_name"] == "sword" {
+			writeObjectFile(file, record.FormatJSON, map[string]interface{}{"item_name": "sword", "price": 120})
+		} else {
+			os.Remove(file)
+		}
+	}
+
+	stepwise := &cli{backend: failingDeletes{c.backend}, out: out}
+	out.Reset()
+	err := stepwise.run(ctx, []string{"push", "-dir", dir, "game_item"})
+	if err == nil || !strings.Contains(err.Error(), "1 changes applied") {
+		t.Fatalf("Expected the failed delete to be reported, got %v", err)
+	}
+	if !strings.Contains(out.String(), "applied before the failure:\n  ~ ") {
+		t.Errorf("Expected the applied update to be listed, got:\n%s", out.String())
+	}
+
+	// The applied update is not repeated by the next push
+	out.Reset()
+	if err := c.run(ctx, []string{"push", "-dir", dir, "game_item"}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "0 to create, 0 to update, 1 to delete") {
+		t.Errorf("Expected only the delete to be left, got:\n%s", out.String())
+	}
+	if got := records(t, store); len(got) != 1 || got["sword"]["price"] != float64(120) {
+		t.Errorf("Expected the push to finish, got %v", got)
+	}
+}
+
+func TestImportCoercesAndValidatesCSV(t *testing.T) {
+	c, out, store := newTestCLI(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "items.csv")
+
+	os.WriteFile(path, []byte("item_name,price,tradable\nstaff,60,true\nwand,x,false\n"), 0o644)
+	err := c.run(ctx, []string{"import", "game_item", path})
+	if err == nil || !strings.Contains(err.Error(), "row 2: price") {
+		t.Errorf("Expected row 2 to be rejected, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("item_name,price,tradable\nstaff,60,true\n"), 0o644)
+	if err := c.run(ctx, []string{"import", "-mode", "append", "game_item", path}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	got := records(t, store)
+	if got["staff"]["price"] != float64(60) || got["staff"]["tradable"] != true || len(got) != 3 {
+		t.Errorf("Expected a typed record to be appended, got %v", got)
+	}
+
+	out.Reset()
+	if err := c.run(ctx, []string{"export", "-format", "csv", "game_item"}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), `"item_name","price","tradable"`) || strings.Count(out.String(), "\n") != 4 {
+		t.Errorf("Unexpected export:\n%s", out.String())
+	}
+}
+
+func TestRecordsPutValidates(t *testing.T) {
+	c, out, store := newTestCLI(t)
+	ctx := context.Background()
+
+	c.in = strings.NewReader(`{"price": 10}`)
+	if err := c.run(ctx, []string{"records", "put", "game_item"}); err == nil {
+		t.Error("Expected a record without item_name to be rejected")
+	}
+
+	c.in = strings.NewReader(`{"item_name": "axe", "price": 90}`)
+	if err := c.run(ctx, []string{"records", "put", "game_item"}); err != nil {
+		t.Fatalf("put failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "created record") || records(t, store)["axe"] == nil {
+		t.Fatalf("Expected the record to be created, got %q", out.String())
+	}
+
+	c.in = strings.NewReader(`{"price": 95}`)
+	if err := c.run(ctx, []string{"records", "put", "game_item", "1"}); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if got := records(t, store); got["sword"]["price"] != float64(95) {
+		t.Errorf("Expected a partial update, got %v", got["sword"])
+	}
+}
+
+func TestDiffSchemas(t *testing.T) {
+	changes, err := diffSchemas(json.RawMessage(itemSchema), json.RawMessage(`{
+		"properties": {
+			"item_name": {"type": "string"},
+			"price": {"type": "number", "minimum": 0},
+			"rarity": {"type": "string"}
+		},
+		"required": ["item_name", "price"]
+	}`))
+	if err != nil {
+		t.Fatalf("diffSchemas failed: %v", err)
+	}
+	var b bytes.Buffer
+	printSchemaChanges(&b, changes)
+	want := "~ price\n    type: \"integer\" -> \"number\"\n    required: false -> true\n+ rarity\n- tradable\n"
+	if b.String() != want {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"progressive/internal/diff"
+)
+
+// schemaChange is a property or required flag that differs between two schemas
+type schemaChange struct {
+	Property string
+	Change   diff.Change
+	// Attributes lists the changed keywords of a modified property, e.g. type
+	Attributes []diff.FieldChange
+	// Required is set when only the required flag changed
+	Required *bool
+}
+
+// diffSchemas compares the properties and required lists of two JSON
+// Schemas, ordered by property name
+func diffSchemas(from, to json.RawMessage) ([]schemaChange, error) {
+	fromProps, fromRequired, err := schemaParts(from)
+	if err != nil {
+		return nil, err
+	}
+	toProps, toRequired, err := schemaParts(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []schemaChange
+	for _, field := range diff.Fields(fromProps, toProps) {
+		change := schemaChange{Property: field.Field, Change: field.Change}
+		if field.Change == diff.Modified {
+			oldProp, _ := field.Old.(map[string]interface{})
+			newProp, _ := field.New.(map[string]interface{})
+			change.Attributes = diff.Fields(oldProp, newProp)
+		}
+		changes = append(changes, change)
+	}
+
+	for name := range toProps {
+		if _, ok := fromProps[name]; !ok {
+			continue
+		}
+		required := slices.Contains(toRequired, name)
+		if required == slices.Contains(fromRequired, name) {
+			continue
+		}
+		i := slices.IndexFunc(changes, func(c schemaChange) bool { return c.Property == name })
+		if i < 0 {
+			changes = append(changes, schemaChange{Property: name, Change: diff.Modified})
+			i = len(changes) - 1
+		}
+		changes[i].Required = &required
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Property < changes[j].Property })
+	return changes, nil
+}
+
+func schemaParts(raw json.RawMessage) (map[string]interface{}, []string, error) {
+	var schema struct {
+		Properties map[string]interface{} `json:"properties"`
+		Required   []string               `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if schema.Properties == nil {
+		schema.Properties = make(map[string]interface{})
+	}
+	return schema.Properties, schema.Required, nil
+}
+
+// printSchemaChanges writes one line per change: + added, - removed, ~ modified
+func printSchemaChanges(w io.Writer, changes []schemaChange) {
+	for _, c := range changes {
+		switch c.Change {
+		case diff.Added:
+			fmt.Fprintf(w, "+ %s\n", c.Property)
+		case diff.Removed:
+			fmt.Fprintf(w, "- %s\n", c.Property)
+		default:
+			fmt.Fprintf(w, "~ %s\n", c.Property)
+		}
+		for _, a := range c.Attributes {
+			fmt.Fprintf(w, "    %s: %s -> %s\n", a.Field, schemaValue(a.Old), schemaValue(a.New))
+		}
+		if c.Required != nil {
+			fmt.Fprintf(w, "    required: %t -> %t\n", !*c.Required, *c.Required)
+		}
+	}
+}
+
+func schemaValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
+
+// schemaDiff prints how a local schema or table file differs from the table
+func (c *cli) schemaDiff(ctx context.Context, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("schema diff", flag.ExitOnError), args, 2, 2)
+	if err != nil {
+		return err
+	}
+	t, err := c.backend.GetTable(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	local, err := readSchemaFile(positional[1])
+	if err != nil {
+		return err
+	}
+	changes, err := diffSchemas(t.Schema, local)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(c.out, "%s: schema is up to date\n", t.ID)
+		return nil
+	}
+	fmt.Fprintf(c.out, "%s: %d properties differ (table -> %s)\n", t.ID, len(changes), positional[1])
+	printSchemaChanges(c.out, changes)
+	return nil
+}
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"progressive/internal/diff"
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/validation"
+)
+
+// A pulled table is a directory that can be kept in git:
+//
+//	<dir>/<table>/table.json         id, name, description and schema
+//	<dir>/<table>/records/12.json    the fields of record 12
+//	<dir>/<table>/records/new.json   any other name is a record to create
+//
+// YAML files (.yaml or .yml) work the same way.
+const (
+	tableFileName = "table"
+	recordsDir    = "records"
+)
+
+// localRecord is a record file; ID is 0 for a record not pushed yet
+type localRecord struct {
+	Path string
+	ID   int64
+	Data map[string]interface{}
+}
+
+// localTable is a table directory read back for push
+type localTable struct {
+	Table   *table.Table
+	Format  record.Format
+	Records []localRecord
+}
+
+func syncFormat(format string) (record.Format, error) {
+	switch f := record.Format(format); f {
+	case record.FormatJSON, record.FormatYAML:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid -format %q (expected json or yaml)", format)
+}
+
+// compact drops null fields, which the editor writes for cleared cells, so
+// that they do not show up as changes against files without the field
+func compact(data map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if value != nil {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func (c *cli) pull(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pull", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory the tables are written to")
+	formatName := fs.String("format", "json", "file format: json or yaml")
+	tableIDs, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	format, err := syncFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	for _, id := range tableIDs {
+		if err := c.pullTable(ctx, *dir, id, format); err != nil {
+			return fmt.Errorf("pull %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func (c *cli) pullTable(ctx context.Context, dir, tableID string, format record.Format) error {
+	t, err := c.backend.GetTable(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	recs, err := c.backend.Records(ctx, tableID)
+	if err != nil {
+		return err
+	}
+
+	tableDir := filepath.Join(dir, t.ID)
+	if err := os.MkdirAll(filepath.Join(tableDir, recordsDir), 0o755); err != nil {
+		return err
+	}
+	var schema interface{}
+	if err := json.Unmarshal(t.Schema, &schema); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	tablePath := filepath.Join(tableDir, tableFileName+"."+format.Extension())
+	if err := writeObjectFile(tablePath, format, map[string]interface{}{
+		"id":          t.ID,
+		"name":        t.Name,
+		"description": t.Description,
+		"schema":      schema,
+	}); err != nil {
+		return err
+	}
+	if err := removeOthers(tableDir, tableFileName, tablePath); err != nil {
+		return err
+	}
+
+	written := make(map[string]bool, len(recs))
+	for _, rec := range recs {
+		path := filepath.Join(tableDir, recordsDir, fmt.Sprintf("%d.%s", rec.ID, format.Extension()))
+		if err := writeObjectFile(path, format, compact(rec.Data)); err != nil {
+			return err
+		}
+		written[path] = true
+	}
+
+	// Files of records deleted on the server go; files of new records stay
+	// for the next push
+	files, err := recordFiles(filepath.Join(tableDir, recordsDir))
+	if err != nil {
+		return err
+	}
+	var pending int
+	for _, file := range files {
+		switch {
+		case written[file.Path]:
+		case file.ID == 0:
+			pending++
+		default:
+			if err := os.Remove(file.Path); err != nil {
+				return err
+			}
+		}
+	}
+
+	fmt.Fprintf(c.out, "pulled %s: %d records to %s\n", t.ID, len(recs), tableDir)
+	if pending > 0 {
+		fmt.Fprintf(c.out, "  %d new record files are not pushed yet\n", pending)
+	}
+	return nil
+}
+
+// removeOthers deletes the files named base with another extension than keep
+func removeOthers(dir, base, keep string) error {
+	for _, ext := range []string{".json", ".yaml", ".yml"} {
+		path := filepath.Join(dir, base+ext)
+		if path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordFiles lists the JSON and YAML files of a records directory
+func recordFiles(dir string) ([]localRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []localRecord
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		format, err := record.FormatOf(entry.Name())
+		if err != nil || (format != record.FormatJSON && format != record.FormatYAML) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		id, _ := parseRecordID(name)
+		files = append(files, localRecord{Path: filepath.Join(dir, entry.Name()), ID: id})
+	}
+	return files, nil
+}
+
+// readLocalTable reads a table directory written by pull
+func readLocalTable(dir, tableID string) (*localTable, error) {
+	tableDir := filepath.Join(dir, tableID)
+	local := &localTable{}
+	for _, ext := range []string{"json", "yaml", "yml"} {
+		path := filepath.Join(tableDir, tableFileName+"."+ext)
+		data, err := readObjectFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if local.Table != nil {
+			return nil, fmt.Errorf("%s has more than one table file", tableDir)
+		}
+		schema, err := json.Marshal(data["schema"])
+		if err != nil {
+			return nil, err
+		}
+		name, _ := data["name"].(string)
+		description, _ := data["description"].(string)
+		if id, _ := data["id"].(string); id != tableID {
+			return nil, fmt.Errorf("%s: id %q does not match the directory %q", path, id, tableID)
+		}
+		local.Table = table.NewTable(tableID, name, description, schema)
+		local.Format, _ = record.FormatOf(path)
+	}
+	if local.Table == nil {
+		return nil, fmt.Errorf("no %s.json or %s.yaml in %s", tableFileName, tableFileName, tableDir)
+	}
+
+	files, err := recordFiles(filepath.Join(tableDir, recordsDir))
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int64]string)
+	for _, file := range files {
+		if file.ID != 0 {
+			if other, ok := seen[file.ID]; ok {
+				return nil, fmt.Errorf("record %d is in both %s and %s", file.ID, other, file.Path)
+			}
+			seen[file.ID] = file.Path
+		}
+		if file.Data, err = readObjectFile(file.Path); err != nil {
+			return nil, err
+		}
+		local.Records = append(local.Records, file)
+	}
+	return local, nil
+}
+
+// recordPatch returns the fields to send to turn from into to; removed
+// fields are cleared with null
+func recordPatch(from, to map[string]interface{}) map[string]interface{} {
+	changes := diff.Fields(compact(from), compact(to))
+	if len(changes) == 0 {
+		return nil
+	}
+	patch := make(map[string]interface{}, len(changes))
+	for _, change := range changes {
+		patch[change.Field] = change.New
+	}
+	return patch
+}
+
+// pushPlan is what push changes on the backend
+type pushPlan struct {
+	createTable bool
+	creates     []localRecord
+	updates     []localRecord
+	patches     []map[string]interface{}
+	deletes     []int64
+}
+
+func (c *cli) push(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("push", flag.ExitOnError)
+	dir := fs.String("dir", ".", "directory the tables were pulled to")
+	dryRun := fs.Bool("dry-run", false, "print the changes without applying them")
+	tableIDs, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	for _, id := range tableIDs {
+		if err := c.pushTable(ctx, *dir, id, *dryRun); err != nil {
+			return fmt.Errorf("push %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// pushTable makes the table match its directory: changed record files are
+// updated, new ones created and records without a file deleted. Every
+// record is validated against the schema before anything is written.
+func (c *cli) pushTable(ctx context.Context, dir, tableID string, dryRun bool) error {
+	local, err := readLocalTable(dir, tableID)
+	if err != nil {
+		return err
+	}
+	if err := table.ValidateSchema(local.Table.Schema); err != nil {
+		return err
+	}
+	validator, err := validation.NewFromJSON(local.Table.Schema)
+	if err != nil {
+		return err
+	}
+	var invalid []string
+	for _, rec := range local.Records {
+		for _, e := range validator.Validate(rec.Data) {
+			invalid = append(invalid, fmt.Sprintf("%s: %s", rec.Path, e))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid records:\n  %s", strings.Join(invalid, "\n  "))
+	}
+
+	plan, err := c.planPush(ctx, local)
+	if err != nil {
+		return err
+	}
+	c.printPlan(tableID, plan)
+	if dryRun {
+		return nil
+	}
+
+	if pusher, ok := c.backend.(atomicPusher); ok {
+		created, err := pusher.Push(ctx, local.Table, plan)
+		if err != nil {
+			return err
+		}
+		for i, rec := range plan.creates {
+			if err := c.renameCreated(rec, created[i].ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return c.applyPlan(ctx, local.Table, plan)
+}
+
+// atomicPusher is a backend that applies a whole push plan in one
+// transaction and returns the created records in plan order
+type atomicPusher interface {
+	Push(ctx context.Context, t *table.Table, plan *pushPlan) ([]*record.Record, error)
+}
+
+// applyPlan applies a push plan one request at a time. When a step fails
+// the steps already applied are listed; they stay in place and the next
+// push plans only what is left.
+func (c *cli) applyPlan(ctx context.Context, t *table.Table, plan *pushPlan) error {
+	var applied []string
+	fail := func(step string, err error) error {
+		if len(applied) > 0 {
+			fmt.Fprintf(c.out, "applied before the failure:\n  %s\n", strings.Join(applied, "\n  "))
+		}
+		return fmt.Errorf("%s: %w (%d changes applied; push again to apply the rest)", step, err, len(applied))
+	}
+
+	if plan.createTable {
+		if err := c.backend.CreateTable(ctx, t); err != nil {
+			return fail("+ table "+t.ID, err)
+		}
+		applied = append(applied, "+ table "+t.ID)
+	}
+	for i, rec := range plan.updates {
+		if err := c.backend.UpdateRecord(ctx, t.ID, rec.ID, plan.patches[i]); err != nil {
+			return fail("~ "+rec.Path, err)
+		}
+		applied = append(applied, "~ "+rec.Path)
+	}
+	for _, id := range plan.deletes {
+		step := fmt.Sprintf("- record %d", id)
+		if err := c.backend.DeleteRecord(ctx, t.ID, id); err != nil {
+			return fail(step, err)
+		}
+		applied = append(applied, step)
+	}
+	for _, rec := range plan.creates {
+		created := record.NewRecord(t.ID, rec.Data)
+		if err := c.backend.CreateRecord(ctx, created); err != nil {
+			return fail("+ "+rec.Path, err)
+		}
+		applied = append(applied, fmt.Sprintf("+ %s (record %d)", rec.Path, created.ID))
+		if err := c.renameCreated(rec, created.ID); err != nil {
+			return fail("+ "+rec.Path, err)
+		}
+	}
+	return nil
+}
+
+// renameCreated names a new record's file after its ID so that the next
+// push updates the record instead of creating it again
+func (c *cli) renameCreated(rec localRecord, id int64) error {
+	path := filepath.Join(filepath.Dir(rec.Path), fmt.Sprintf("%d%s", id, filepath.Ext(rec.Path)))
+	if _, err := os.Stat(path); err == nil {
+		fmt.Fprintf(c.out, "  %s is record %d, but %s exists; rename it by hand\n", rec.Path, id, path)
+		return nil
+	}
+	return os.Rename(rec.Path, path)
+}
+
+// planPush compares the local table with the backend. A table that exists
+// with another schema is an error: tables cannot be altered by push.
+func (c *cli) planPush(ctx context.Context, local *localTable) (*pushPlan, error) {
+	plan := &pushPlan{}
+	remote, err := c.backend.GetTable(ctx, local.Table.ID)
+	switch {
+	case errors.Is(err, table.ErrNotFound):
+		plan.createTable = true
+		plan.creates = local.Records
+		return plan, nil
+	case err != nil:
+		return nil, err
+	}
+
+	changes, err := diffSchemas(remote.Schema, local.Table.Schema)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		var b strings.Builder
+		printSchemaChanges(&b, changes)
+		return nil, fmt.Errorf("the local schema differs from the table's; change the table first:\n%s", b.String())
+	}
+
+	recs, err := c.backend.Records(ctx, local.Table.ID)
+	if err != nil {
+		return nil, err
+	}
+	remoteByID := make(map[int64]*record.Record, len(recs))
+	for _, rec := range recs {
+		remoteByID[rec.ID] = rec
+	}
+	for _, rec := range local.Records {
+		remoteRec, ok := remoteByID[rec.ID]
+		if !ok {
+			// New, or deleted on the server since the pull
+			plan.creates = append(plan.creates, rec)
+			continue
+		}
+		delete(remoteByID, rec.ID)
+		if patch := recordPatch(remoteRec.Data, rec.Data); patch != nil {
+			plan.updates = append(plan.updates, rec)
+			plan.patches = append(plan.patches, patch)
+		}
+	}
+	for id := range remoteByID {
+		plan.deletes = append(plan.deletes, id)
+	}
+	slices.Sort(plan.deletes)
+	return plan, nil
+}
+
+func (c *cli) printPlan(tableID string, plan *pushPlan) {
+	if plan.createTable {
+		fmt.Fprintf(c.out, "+ table %s\n", tableID)
+	}
+	for _, rec := range plan.creates {
+		fmt.Fprintf(c.out, "+ %s\n", rec.Path)
+	}
+	for i, rec := range plan.updates {
+		fields := make([]string, 0, len(plan.patches[i]))
+		for field := range plan.patches[i] {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields)
+		fmt.Fprintf(c.out, "~ %s (%s)\n", rec.Path, strings.Join(fields, ", "))
+	}
+	for _, id := range plan.deletes {
+		fmt.Fprintf(c.out, "- record %d\n", id)
+	}
+	fmt.Fprintf(c.out, "push %s: %d to create, %d to update, %d to delete\n",
+		tableID, len(plan.creates), len(plan.updates), len(plan.deletes))
+}
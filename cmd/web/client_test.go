@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/client"
+	"progressive/internal/config"
+)
+
+// TestClientAgainstServer runs the Go client against the real routes
+func TestClientAgainstServer(t *testing.T) {
+	srv := httptest.NewServer(testRoutes(t, config.Default()))
+	defer srv.Close()
+	c, err := client.New(srv.URL)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	ctx := context.Background()
+
+	err = c.CreateTable(ctx, client.NewTable{
+		ID:     "items",
+		Name:   "Items",
+		Schema: json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}, "price": {"type": "integer"}}}`),
+	})
+	if err != nil {
+		t.Fatalf("CreateTable failed: %v", err)
+	}
+	if err := c.CreateTable(ctx, client.NewTable{ID: "items", Name: "Items", Schema: json.RawMessage(`{}`)}); !errors.Is(err, client.ErrTableExists) {
+		t.Errorf("Expected ErrTableExists, got %v", err)
+	}
+
+	id, err := c.CreateRecord(ctx, "items", map[string]interface{}{"name": "sword", "price": 100})
+	if err != nil {
+		t.Fatalf("CreateRecord failed: %v", err)
+	}
+	if err := c.UpdateRecord(ctx, "items", id, map[string]interface{}{"price": 120}); err != nil {
+		t.Fatalf("UpdateRecord failed: %v", err)
+	}
+	rec, err := c.GetRecord(ctx, "items", id)
+	if err != nil || rec.ID != id || rec.Fields["name"] != "sword" || rec.Fields["price"] != float64(120) {
+		t.Fatalf("Expected the updated record, got %+v, %v", rec, err)
+	}
+
+	n, err := c.CreateRecords(ctx, "items", []map[string]interface{}{{"name": "bow"}, {"name": "axe"}, {"name": "staff"}})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 records to be added, got %d, %v", n, err)
+	}
+	count := 0
+	for _, err := range c.AllRecords(ctx, "items", 3) {
+		if err != nil {
+			t.Fatalf("AllRecords failed: %v", err)
+		}
+		count++
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 records over two pages, got %d", count)
+	}
+
+	if err := c.DeleteRecord(ctx, "items", id); err != nil {
+		t.Fatalf("DeleteRecord failed: %v", err)
+	}
+	if _, err := c.GetRecord(ctx, "items", id); !errors.Is(err, client.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got %v", err)
+	}
+
+	records, err := c.ExportRecords(ctx, "items")
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected 3 exported records, got %v, %v", records, err)
+	}
+	csv, err := c.Export(ctx, "items", client.ExportCSV)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	body, _ := io.ReadAll(csv)
+	csv.Close()
+	if !strings.Contains(string(body), `"bow"`) {
+		t.Errorf("Expected CSV rows, got %s", body)
+	}
+
+	if err := c.ReplaceRecords(ctx, "items", []map[string]interface{}{{"name": "wand"}}); err != nil {
+		t.Fatalf("ReplaceRecords failed: %v", err)
+	}
+	page, err := c.ListRecords(ctx, "items", client.ListOptions{})
+	if err != nil || page.Total != 1 || page.Records[0].Fields["name"] != "wand" {
+		t.Errorf("Expected only the replacement, got %+v, %v", page, err)
+	}
+
+	tables, err := c.ListTables(ctx)
+	if err != nil || len(tables) != 1 || tables[0].RecordCount != 1 {
+		t.Errorf("Expected one table with one record, got %+v, %v", tables, err)
+	}
+	if err := c.DeleteTable(ctx, "items"); err != nil {
+		t.Fatalf("DeleteTable failed: %v", err)
+	}
+	if _, err := c.ListRecords(ctx, "items", client.ListOptions{}); !errors.Is(err, client.ErrTableNotFound) {
+		t.Errorf("Expected ErrTableNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+
+	"progressive/internal/config"
+)
+
+// runConfig prints the effective configuration with secrets redacted.
+//
+//	progressive config print -config progressive.yaml -addr :9000
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("usage: progressive config print [flags]")
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ExitOnError)
+	cfg, err := config.Load(fs, args[1:], os.LookupEnv)
+	if err != nil {
+		return err
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		return fmt.Errorf("failed to render config: %w", err)
+	}
+	os.Stdout.Write(out)
+	return cfg.Validate()
+}
+
+// loadConfig loads and validates the configuration for a command, adding
+// the configuration flags to fs. The configured logger becomes the default.
+func loadConfig(fs *flag.FlagSet, args []string) (*config.Config, error) {
+	cfg, err := config.Load(fs, args, os.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	slog.SetDefault(cfg.Log.Logger(os.Stderr))
+	return cfg, nil
+}
+
+// displayAddr turns a listen address like ":8081" into a browsable host:port
+func displayAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host != "" {
+		return addr
+	}
+	return net.JoinHostPort("localhost", port)
+}
@@ -1,90 +1,137 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 
+	"progressive/internal/backup"
+	auditrepo "progressive/internal/domain/audit/repository"
 	"progressive/internal/handlers"
-	"progressive/internal/infrastructure"
+	"progressive/internal/lifecycle"
+	"progressive/internal/metrics"
 	"progressive/internal/middleware"
+	"progressive/internal/storage"
 )
 
 func main() {
-	// 임베디드 PostgreSQL 인스턴스 생성 (자동 포트 발견 기능 사용)
-	embeddedDB, err := infrastructure.NewEmbeddedDB(
-		infrastructure.WithAutoPortDiscovery(10), // 최대 10개 포트 시도
-	)
-	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+	// 서브커맨드 처리 (인자가 없으면 웹 서버 실행)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "publish":
+			if err := runPublish(os.Args[2:]); err != nil {
+				fatal("publish failed", err)
+			}
+			return
+		case "config":
+			if err := runConfig(os.Args[2:]); err != nil {
+				fatal("config failed", err)
+			}
+			return
+		case "migrate":
+			if err := runMigrate(os.Args[2:]); err != nil {
+				fatal("migrate failed", err)
+			}
+			return
+		case "backup":
+			if err := runBackup(os.Args[2:]); err != nil {
+				fatal("backup failed", err)
+			}
+			return
+		case "restore":
+			if err := runRestore(os.Args[2:]); err != nil {
+				fatal("restore failed", err)
+			}
+			return
+		}
 	}
-	defer embeddedDB.Close()
 
-	// 사용된 설정 정보 출력
-	config := embeddedDB.GetConfig()
-	log.Printf("📊 PostgreSQL running on %s:%d", config.Host, config.Port)
+	runServer(os.Args[1:])
+}
 
-	// sqlx.DB 인스턴스
-	db := embeddedDB.DB
+func runServer(args []string) {
+	// 설정 로드 (기본값 -> 파일 -> 환경 변수 -> 플래그 순으로 덮어씀) 및 검증
+	cfg, err := loadConfig(flag.NewFlagSet("progressive", flag.ExitOnError), args)
+	if err != nil {
+		fatal("invalid configuration", err)
+	}
 
-	// 마이그레이션 실행
-	if err := infrastructure.RunMigrations(db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// 저장소 열기 및 마이그레이션 실행 (postgres: 임베디드 또는 외부 DSN, sqlite: 단일 파일)
+	store, err := storage.Open(cfg.StorageOptions())
+	if err != nil {
+		fatal("failed to initialize database", err)
 	}
 
-	// 핸들러에 DB 의존성 주입 (템플릿 초기화는 핸들러 생성 시 자동으로 실행됨)
-	h := handlers.NewHandlers(db)
-
-	// 라우트 설정을 위한 ServeMux 생성
-	mux := http.NewServeMux()
-
-	// 페이지 라우트 설정 (GET 요청으로 HTML 페이지 렌더링)
-	mux.HandleFunc("/", h.HomeHandler)
-	mux.HandleFunc("/dashboard", h.DashboardHandler)
-	mux.HandleFunc("/table/create", h.TableCreatePageHandler)
-	mux.HandleFunc("/table/", h.TableEditorPageHandler)
-	mux.HandleFunc("/fakeit", h.FakeitPageHandler)
-
-	// API 라우트 설정 (JSON 데이터 처리)
-	mux.HandleFunc("/api/templates", h.TemplatesAPIHandler)
-	mux.HandleFunc("/api/tables", h.TablesAPIHandler)
-	mux.HandleFunc("/api/table/", func(w http.ResponseWriter, r *http.Request) {
-		// Route to specific table API handlers based on URL pattern
-		path := r.URL.Path
-		if strings.Contains(path, "/export") {
-			h.Table.API.ExportHandler(w, r)
-		} else if strings.Contains(path, "/import") {
-			h.Table.API.ImportHandler(w, r)
-		} else if strings.Contains(path, "/record") {
-			h.Table.API.RecordHandler(w, r)
-		} else {
-			h.Table.API.DataHandler(w, r)
-		}
+	// 핸들러에 저장소 의존성 주입 (템플릿 초기화는 핸들러 생성 시 자동으로 실행됨)
+	h := handlers.NewHandlers(store)
+
+	// 애플리케이션 수명 주기: HTTP 서버, 백그라운드 작업, 저장소를 소유하고 순서대로 종료
+	app := lifecycle.New(&http.Server{
+		Addr:         cfg.Server.Addr,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	})
-	mux.HandleFunc("/api/table/create", h.TableCreateAPIHandler)
-	mux.HandleFunc("/api/fakeit/generate", h.FakeitGenerateAPIHandler)
+	app.TLSCert, app.TLSKey = cfg.Server.TLSCert, cfg.Server.TLSKey
+	app.ShutdownTimeout = cfg.Server.ShutdownTimeout
+	app.DrainDelay = cfg.Server.DrainDelay
+	app.OnStop("storage", store.Close)
+
+	// 주기적 백업 (backup.interval 이 0 이면 비활성화)
+	if cfg.Backup.Interval > 0 {
+		scheduler := &backup.Scheduler{DB: store.DB, Dir: cfg.Backup.Dir, Interval: cfg.Backup.Interval, Keep: cfg.Backup.Keep}
+		app.Go("backup scheduler", scheduler.Run)
+	}
+
+	// 상태 확인 라우트 (liveness: 프로세스 동작 중, readiness: DB 응답 및 마이그레이션 적용 완료)
+	app.AddReadinessCheck("database", store.Ping)
+	app.AddReadinessCheck("migrations", store.CheckMigrations)
 
-	// 정적 파일 서빙
-	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("./static"))))
+	// Prometheus 메트릭 (요청 수/지연 시간, DB 커넥션 풀, 가져오기/내보내기 행 수, 테이블/레코드 수)
+	if store.DB != nil {
+		metrics.RegisterDBStats(metrics.Default, store.DB)
+	}
+	metrics.RegisterContentCounts(metrics.Default, store.Counts)
 
-	// 미들웨어 체인 적용 (에러 핸들링 -> 로깅 순서)
-	errorHandledMux := middleware.ErrorHandlingMiddleware(mux)
-	loggedMux := middleware.LoggingMiddleware(errorHandledMux)
+	// 라우트 설정 (페이지, 상태 확인, /api/v1 API 와 폐기 예정인 기존 /api 경로는 routes.go 참고)
+	rt := routes(h, cfg, store, app)
+
+	// 미들웨어 체인 적용 (에러 핸들링 -> 감사 로그 -> 로깅 -> 요청 ID/트레이스 컨텍스트 순서)
+	var handler http.Handler = middleware.ErrorHandlingMiddleware(rt)
+	if !cfg.Features.Audit {
+		slog.Warn("audit log disabled by configuration")
+	} else if store.SupportsAllFeatures() {
+		// X-Forwarded-For 는 설정한 프록시에서 온 요청일 때만 클라이언트 주소로 사용 (Validate 에서 이미 검사함)
+		trustedProxies, _ := cfg.Server.TrustedProxyPrefixes()
+		handler = middleware.AuditMiddleware(auditrepo.NewPostgresRepository(store.DB), trustedProxies)(handler)
+	} else {
+		slog.Warn("audit log disabled: not supported by the storage backend", slog.String("backend", store.Backend))
+	}
+	loggedMux := middleware.LoggingMiddleware(handler, metrics.RequestObserver(metrics.MuxRoute(rt.Mux())))
 
-	// Graceful shutdown 설정
-	go func() {
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-		<-sigChan
+	app.Server.Handler = middleware.RequestContextMiddleware(loggedMux)
 
-		log.Println("🛑 Shutting down server...")
-		embeddedDB.Close()
-		os.Exit(0)
-	}()
+	// SIGINT/SIGTERM 수신 시 새 연결을 받지 않고 진행 중인 요청과 작업을 마친 뒤 저장소를 닫음
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	scheme := "http"
+	if cfg.TLS() {
+		scheme = "https"
+	}
+	slog.Info("server started", slog.String("url", scheme+"://"+displayAddr(cfg.Server.Addr)))
+	if err := app.Run(ctx); err != nil {
+		fatal("server stopped with error", err)
+	}
+	slog.Info("server stopped")
+}
 
-	log.Println("🚀 Progressive 앱이 http://localhost:8081 에서 실행 중입니다...")
-	log.Fatal(http.ListenAndServe(":8081", loggedMux))
+// fatal logs err and exits with status 1
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"progressive/internal/infrastructure"
+	"progressive/internal/storage"
+)
+
+// runMigrate applies, rolls back or lists schema migrations.
+//
+//	progressive migrate up [-to 5]
+//	progressive migrate down [-n 1]
+//	progressive migrate redo
+//	progressive migrate status
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: progressive migrate up|down|status|redo [flags]")
+	}
+	command := args[0]
+
+	fs := flag.NewFlagSet("migrate "+command, flag.ExitOnError)
+	to := fs.Int("to", 0, "up: stop after this version (default: latest)")
+	steps := fs.Int("n", 1, "down: number of migrations to roll back")
+	cfg, err := loadConfig(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	// 마이그레이션은 명령이 직접 실행하므로 저장소를 열 때는 건너뛴다
+	opts := cfg.StorageOptions()
+	opts.SkipMigrations = true
+	store, err := storage.Open(opts)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	m, err := infrastructure.NewMigrator(store.DB)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := m.Up(ctx, *to)
+		if err == nil && len(applied) == 0 {
+			fmt.Println("✅ Already up to date")
+		}
+		return err
+	case "down":
+		_, err := m.Down(ctx, *steps)
+		return err
+	case "redo":
+		_, err := m.Redo(ctx)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if status.Modified {
+				state = "modified"
+			}
+			fmt.Fprintf(tw, "%03d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up, down, status or redo)", command)
+	}
+}
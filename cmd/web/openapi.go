@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"progressive/internal/apierror"
+	"progressive/internal/diff"
+	"progressive/internal/domain/audit"
+	"progressive/internal/domain/branch"
+	"progressive/internal/domain/comment"
+	"progressive/internal/domain/schematemplate"
+	"progressive/internal/domain/snapshot"
+	"progressive/internal/domain/table"
+	"progressive/internal/handlers"
+	tablehandlers "progressive/internal/handlers/table"
+	"progressive/internal/openapi"
+	"progressive/internal/publish"
+	"progressive/internal/router"
+)
+
+// apiVersion is the version of the API described by the OpenAPI document
+const apiVersion = "1.0.0"
+
+// Schemas shared by the endpoints below
+var (
+	successSchema = openapi.Success(nil)
+	recordSchema  = openapi.Schema{"type": "object", "additionalProperties": true, "description": "Record fields as defined by the table schema; see the table's OpenAPI document"}
+	recordsSchema = openapi.ArrayOf(recordSchema)
+	stringSchema  = openapi.Schema{"type": "string"}
+	integerSchema = openapi.Schema{"type": "integer"}
+)
+
+// apiEndpoints documents the operations of the versioned API by pattern
+// relative to apiPrefix. Paths and methods of the document come from the
+// router; every /api/v1 route must have an entry here.
+var apiEndpoints = map[string]openapi.Endpoint{
+	"GET /openapi.json":             {Tag: "api", Summary: "This document", Response: openapi.Schema{"type": "object"}},
+	"GET /tables/{id}/openapi.json": {Tag: "api", Summary: "OpenAPI document of a table, with its schema as the record model", Response: openapi.Schema{"type": "object"}},
+
+	"GET /templates": {Tag: "templates", Summary: "List the built-in templates and the custom templates of a workspace", Query: []openapi.Parameter{
+		openapi.QueryParameter("workspace", "string", "Workspace of the custom templates"),
+		openapi.QueryParameter("category", "string", "business, game or custom"),
+	}, Response: []*schematemplate.SchemaTemplate{}},
+	"POST /templates":            {Tag: "templates", Summary: "Create a template; without a category it is a custom template", Request: handlers.TemplateRequest{}, Status: http.StatusCreated, Response: schematemplate.SchemaTemplate{}},
+	"GET /templates/export":      {Tag: "templates", Summary: "Download templates as a JSON file", Query: []openapi.Parameter{openapi.QueryParameter("id", "string", "Template to export, repeatable; defaults to the custom templates of the workspace"), openapi.QueryParameter("workspace", "string", "")}, Response: schematemplate.ExportFile{}},
+	"POST /templates/import":     {Tag: "templates", Summary: "Import a template file as custom templates of a workspace", Query: []openapi.Parameter{openapi.QueryParameter("workspace", "string", ""), openapi.QueryParameter("overwrite", "boolean", "Replace custom templates of the workspace with the same ID")}, Request: schematemplate.ExportFile{}, Response: openapi.Object(openapi.Schema{"imported": openapi.ArrayOf(stringSchema), "updated": integerSchema})},
+	"GET /templates/{id}":        {Tag: "templates", Summary: "Get a template", Response: schematemplate.SchemaTemplate{}},
+	"PUT /templates/{id}":        {Tag: "templates", Summary: "Update a template", Request: handlers.TemplateRequest{}, Response: schematemplate.SchemaTemplate{}},
+	"DELETE /templates/{id}":     {Tag: "templates", Summary: "Delete a template", Response: openapi.Object(openapi.Schema{"status": stringSchema})},
+	"POST /tables/{id}/template": {Tag: "templates", Summary: "Save the table's schema, and optionally sample records, as a custom template", Request: handlers.SaveTemplateRequest{}, Status: http.StatusCreated, Response: schematemplate.SchemaTemplate{}},
+
+	"GET /tables":  {Tag: "tables", Summary: "List tables", Response: []*table.Table{}},
+	"POST /tables": {Tag: "tables", Summary: "Create a table with a generated ID", Request: tablehandlers.TableCreateRequest{}, Status: http.StatusCreated, Response: openapi.Success(openapi.Schema{"tableId": stringSchema, "name": stringSchema, "schema": openapi.Schema{}, "dataOption": stringSchema, "redirect": stringSchema})},
+	"GET /tables/{id}": {Tag: "tables", Summary: "Get a table with all of its records", Response: struct {
+		*table.Table
+		Records []map[string]interface{} `json:"records"`
+	}{}},
+	"PUT /tables/{id}": {Tag: "tables", Summary: "Create a table with the given ID", Status: http.StatusCreated, Response: openapi.Object(openapi.Schema{"id": stringSchema}, "id"), Request: struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Schema      json.RawMessage `json:"schema"`
+	}{}},
+	"DELETE /tables/{id}": {Tag: "tables", Summary: "Delete a table and its records", Response: openapi.Object(openapi.Schema{"status": stringSchema})},
+
+	"GET /tables/{id}/records": {Tag: "records", Summary: "List records", Query: []openapi.Parameter{
+		openapi.QueryParameter("page", "integer", "Page number, from 1"),
+		openapi.QueryParameter("limit", "integer", "Records per page (default 20)"),
+	}, Response: struct {
+		Table      *table.Table             `json:"table"`
+		Records    []map[string]interface{} `json:"records"`
+		Pagination struct {
+			Page    int  `json:"page"`
+			Limit   int  `json:"limit"`
+			Total   int  `json:"total"`
+			HasMore bool `json:"has_more"`
+		} `json:"pagination"`
+	}{}},
+	"PUT /tables/{id}/records":             {Tag: "records", Summary: "Replace all records", Request: openapi.Object(openapi.Schema{"records": recordsSchema}, "records"), Response: openapi.Object(openapi.Schema{"status": stringSchema})},
+	"POST /tables/{id}/records":            {Tag: "records", Summary: "Create a record", Request: recordSchema, Response: openapi.Success(openapi.Schema{"id": integerSchema})},
+	"GET /tables/{id}/records/{record}":    {Tag: "records", Summary: "Get a record", Response: recordSchema},
+	"PATCH /tables/{id}/records/{record}":  {Tag: "records", Summary: "Update fields of a record", Request: recordSchema, Response: successSchema},
+	"DELETE /tables/{id}/records/{record}": {Tag: "records", Summary: "Delete a record", Response: successSchema},
+	"POST /tables/{id}/import":             {Tag: "records", Summary: "Import records", Request: openapi.Object(openapi.Schema{"mode": openapi.Schema{"type": "string", "enum": []string{"append", "replace"}}, "data": recordsSchema}, "data"), Response: openapi.Success(openapi.Schema{"imported": integerSchema, "mode": stringSchema})},
+	"GET /tables/{id}/export":              {Tag: "records", Summary: "Export records", Query: []openapi.Parameter{openapi.QueryParameter("format", "string", "json, csv or excel")}, ContentType: "application/octet-stream"},
+	"GET /tables/{id}/codegen":             {Tag: "records", Summary: "Generate record types from the schema", Query: []openapi.Parameter{openapi.QueryParameter("lang", "string", "go, ts, csharp or sql"), openapi.QueryParameter("package", "string", "Go package name"), openapi.QueryParameter("namespace", "string", "C# namespace")}, ContentType: "text/plain"},
+	"GET /tables/{id}/revisions":           {Tag: "records", Summary: "Record revision history, newest first", Query: []openapi.Parameter{openapi.QueryParameter("record_id", "integer", ""), openapi.QueryParameter("limit", "integer", "")}, Response: []tablehandlers.Revision{}},
+	"POST /fakeit/generate":                {Tag: "records", Summary: "Generate fake records for a schema", Request: handlers.FakeitGenerateRequest{}, Response: openapi.Success(openapi.Schema{"data": recordsSchema, "count": integerSchema})},
+
+	"GET /tables/{id}/comments": {Tag: "comments", Summary: "List comment threads", Query: []openapi.Parameter{
+		openapi.QueryParameter("status", "string", "open, resolved or all"),
+		openapi.QueryParameter("record_id", "integer", ""),
+		openapi.QueryParameter("field", "string", ""),
+		openapi.QueryParameter("mention", "string", "Threads mentioning a user"),
+	}, Response: []*comment.Thread{}},
+	"POST /tables/{id}/comments":                                  {Tag: "comments", Summary: "Open a thread on a table, record or cell", Request: tablehandlers.CreateThreadRequest{}, Status: http.StatusCreated, Response: comment.Thread{}},
+	"GET /tables/{id}/comments/open":                              {Tag: "comments", Summary: "Open thread counts per cell", Response: []comment.CellCount{}},
+	"GET /tables/{id}/comments/export":                            {Tag: "comments", Summary: "Export unresolved threads", Query: []openapi.Parameter{openapi.QueryParameter("format", "string", "markdown, csv or json")}, ContentType: "text/markdown"},
+	"GET /tables/{id}/comments/{thread}":                          {Tag: "comments", Summary: "Get a thread with its comments", Response: comment.Thread{}},
+	"POST /tables/{id}/comments/{thread}/replies":                 {Tag: "comments", Summary: "Reply to a thread", Request: tablehandlers.CommentRequest{}, Status: http.StatusCreated, Response: comment.Comment{}},
+	"POST /tables/{id}/comments/{thread}/resolve":                 {Tag: "comments", Summary: "Resolve a thread", Request: tablehandlers.ResolveRequest{}, Response: comment.Thread{}},
+	"POST /tables/{id}/comments/{thread}/unresolve":               {Tag: "comments", Summary: "Reopen a thread", Request: tablehandlers.ResolveRequest{}, Response: comment.Thread{}},
+	"PATCH /tables/{id}/comments/{thread}/comments/{comment}":     {Tag: "comments", Summary: "Edit a comment (author only)", Request: tablehandlers.CommentRequest{}, Response: comment.Comment{}},
+	"GET /tables/{id}/comments/{thread}/comments/{comment}/edits": {Tag: "comments", Summary: "Edit history of a comment", Response: []*comment.Edit{}},
+
+	"POST /publish": {Tag: "publish", Summary: "Build a game data bundle", Request: handlers.PublishRequest{}, ContentType: "application/zip"},
+	"POST /publish/validate": {Tag: "publish", Summary: "Validate tables for publishing", Request: handlers.PublishRequest{}, Response: struct {
+		Success bool            `json:"success"`
+		Report  *publish.Report `json:"report"`
+	}{}},
+
+	"GET /snapshots":  {Tag: "snapshots", Summary: "List snapshots", Response: []*snapshot.Snapshot{}},
+	"POST /snapshots": {Tag: "snapshots", Summary: "Capture a snapshot", Request: handlers.CreateSnapshotRequest{}, Status: http.StatusCreated, Response: snapshot.Snapshot{}},
+	"GET /snapshots/diff": {Tag: "snapshots", Summary: "Compare two snapshots, or a snapshot and the current tables", Query: []openapi.Parameter{openapi.QueryParameter("from", "string", "Snapshot ID or name"), openapi.QueryParameter("to", "string", "Snapshot ID or name, or current (default)")}, Response: struct {
+		From    string       `json:"from"`
+		To      string       `json:"to"`
+		Changed bool         `json:"changed"`
+		Diff    *diff.Result `json:"diff"`
+	}{}},
+	"GET /snapshots/{id}":             {Tag: "snapshots", Summary: "Get a snapshot by ID or name", Response: snapshot.Snapshot{}},
+	"GET /snapshots/{id}/promotions":  {Tag: "snapshots", Summary: "List the promotions of a snapshot", Response: []*snapshot.Promotion{}},
+	"POST /snapshots/{id}/promotions": {Tag: "snapshots", Summary: "Request promotion to the next environment", Request: handlers.PromotionRequest{}, Status: http.StatusCreated, Response: snapshot.Promotion{}},
+	"GET /promotions/{id}":            {Tag: "snapshots", Summary: "Get a promotion", Response: snapshot.Promotion{}},
+	"POST /promotions/{id}/approve":   {Tag: "snapshots", Summary: "Approve a pending promotion", Request: handlers.PromotionReviewRequest{}, Response: snapshot.Promotion{}},
+	"POST /promotions/{id}/reject":    {Tag: "snapshots", Summary: "Reject a pending promotion", Request: handlers.PromotionReviewRequest{}, Response: snapshot.Promotion{}},
+
+	"GET /branches":  {Tag: "branches", Summary: "List branches", Query: []openapi.Parameter{openapi.QueryParameter("table_id", "string", "")}, Response: []*branch.Branch{}},
+	"POST /branches": {Tag: "branches", Summary: "Branch a table", Request: handlers.CreateBranchRequest{}, Status: http.StatusCreated, Response: branch.Branch{}},
+	"GET /branches/{id}": {Tag: "branches", Summary: "Get a branch with its record view", Response: struct {
+		Branch  *branch.Branch      `json:"branch"`
+		Records []branch.RecordView `json:"records"`
+	}{}},
+	"DELETE /branches/{id}":               {Tag: "branches", Summary: "Close a branch", Response: successSchema},
+	"GET /branches/{id}/changes":          {Tag: "branches", Summary: "Changes made on a branch", Response: []branch.Change{}},
+	"POST /branches/{id}/records":         {Tag: "branches", Summary: "Add a record on a branch", Request: recordSchema, Status: http.StatusCreated, Response: openapi.Success(openapi.Schema{"ref": stringSchema})},
+	"PATCH /branches/{id}/records/{ref}":  {Tag: "branches", Summary: "Update a record on a branch", Request: recordSchema, Response: successSchema},
+	"DELETE /branches/{id}/records/{ref}": {Tag: "branches", Summary: "Delete a record on a branch", Response: successSchema},
+	"GET /merge-requests":                 {Tag: "branches", Summary: "List merge requests", Query: []openapi.Parameter{openapi.QueryParameter("status", "string", "")}, Response: []*branch.MergeRequest{}},
+	"POST /merge-requests":                {Tag: "branches", Summary: "Open a merge request", Request: handlers.CreateMergeRequestRequest{}, Status: http.StatusCreated, Response: branch.MergeRequest{}},
+	"GET /merge-requests/{id}": {Tag: "branches", Summary: "Get a merge request with its merge preview", Response: struct {
+		MergeRequest *branch.MergeRequest `json:"merge_request"`
+		Branch       *branch.Branch       `json:"branch"`
+		Plan         *branch.MergePlan    `json:"plan"`
+		Mergeable    bool                 `json:"mergeable"`
+	}{}},
+	"POST /merge-requests/{id}/merge": {Tag: "branches", Summary: "Merge, resolving conflicts", Request: handlers.MergeRequestMergeRequest{}, Response: openapi.Success(openapi.Schema{"updated": integerSchema, "added": integerSchema, "deleted": integerSchema})},
+	"POST /merge-requests/{id}/close": {Tag: "branches", Summary: "Close without merging", Response: successSchema},
+
+	"GET /audit": {Tag: "audit", Summary: "List audit entries, newest first", Query: []openapi.Parameter{
+		openapi.QueryParameter("actor", "string", ""),
+		openapi.QueryParameter("action", "string", "An action, or a group such as table"),
+		openapi.QueryParameter("target", "string", ""),
+		openapi.QueryParameter("request_id", "string", ""),
+		openapi.QueryParameter("from", "string", "RFC 3339 timestamp or date"),
+		openapi.QueryParameter("to", "string", "RFC 3339 timestamp or date"),
+		openapi.QueryParameter("before", "integer", "Entry ID to page from"),
+		openapi.QueryParameter("limit", "integer", ""),
+	}, Response: struct {
+		Entries    []*audit.Entry `json:"entries"`
+		NextBefore int64          `json:"next_before,omitempty"`
+	}{}},
+	"GET /audit/verify": {Tag: "audit", Summary: "Verify the audit hash chain", Response: audit.VerifyResult{}},
+}
+
+// apiDocument builds the OpenAPI document of the /api/v1 routes of rt
+func apiDocument(rt *router.Router) *openapi.Document {
+	doc := openapi.New("Progressive API", apiVersion, apiPrefix)
+	doc.Info.Description = "Tables, records and the release workflow of Progressive. " +
+		"Each table also has its own document at /tables/{id}/openapi.json with typed records."
+	for _, route := range rt.Routes() {
+		if route.Method == "" || route.Successor != "" || !strings.HasPrefix(route.Path, apiPrefix+"/") {
+			continue
+		}
+		path := strings.TrimPrefix(route.Path, apiPrefix)
+		doc.Add(route.Method, path, apiEndpoints[route.Method+" "+path])
+	}
+	return doc
+}
+
+// openAPIHandler serves the document of rt, built on the first request
+// once every route is registered
+func openAPIHandler(rt *router.Router) apierror.HandlerFunc {
+	var once sync.Once
+	var doc []byte
+	var err error
+	return func(w http.ResponseWriter, r *http.Request) error {
+		once.Do(func() {
+			doc, err = json.Marshal(apiDocument(rt))
+		})
+		if err != nil {
+			return apierror.Internal(fmt.Errorf("encode OpenAPI document: %w", err))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+		return nil
+	}
+}
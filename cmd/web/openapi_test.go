@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"progressive/internal/config"
+)
+
+func TestEveryAPIRouteIsDocumented(t *testing.T) {
+	rt := testRoutes(t, config.Default())
+
+	for _, route := range rt.Routes() {
+		if route.Method == "" || route.Successor != "" || !strings.HasPrefix(route.Path, apiPrefix+"/") {
+			continue
+		}
+		key := route.Method + " " + strings.TrimPrefix(route.Path, apiPrefix)
+		if _, ok := apiEndpoints[key]; !ok {
+			t.Errorf("Route %s %s has no entry in apiEndpoints", route.Method, route.Path)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	rt := testRoutes(t, config.Default())
+
+	rec := send(rt, "GET", "/api/openapi.json", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected document, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/tables/{id}/records"]["post"]; !ok {
+		t.Errorf("Expected record creation in paths, got %v", doc.Paths)
+	}
+	if _, ok := doc.Paths["/table/{id}/record/"]; ok {
+		t.Error("Deprecated paths should not be documented")
+	}
+
+	send(rt, "PUT", "/api/v1/tables/items", `{"name": "Items", "schema": {"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}}`)
+	rec = send(rt, "GET", "/api/v1/tables/items/openapi.json", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"Record"`) {
+		t.Errorf("Expected table document, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = send(rt, "GET", "/api/v1/tables/missing/openapi.json", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing table, got %d", rec.Code)
+	}
+}
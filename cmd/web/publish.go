@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"progressive/internal/publish"
+	"progressive/internal/storage"
+)
+
+// runPublish builds a game data bundle into a local directory.
+//
+//	progressive publish -tables quest,shop_item -version 1.4.0 -out ./dist
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	tables := fs.String("tables", "", "comma-separated table IDs to publish (default: all tables)")
+	version := fs.String("version", "", "bundle version label (default: build timestamp)")
+	formats := fs.String("formats", "json,msgpack,binary", "comma-separated data formats")
+	out := fs.String("out", "dist", "output directory; the bundle is written to <out>/<version>")
+	goPackage := fs.String("go-package", "gamedata", "package name for the generated Go types")
+	cfg, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+
+	parsedFormats, err := publish.ParseFormats(splitList(*formats))
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(cfg.StorageOptions())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	// Publishing reads through Postgres-only SQL, as the publish API does
+	if !store.SupportsAllFeatures() {
+		return fmt.Errorf("publish requires the %s storage backend, got %s", storage.Postgres, store.Backend)
+	}
+
+	bundle, err := publish.Build(context.Background(), publish.NewPostgresSource(store.DB), publish.Options{
+		TableIDs:  splitList(*tables),
+		Version:   *version,
+		Formats:   parsedFormats,
+		GoPackage: *goPackage,
+	})
+
+	var validationErr *publish.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, issue := range validationErr.Report.Issues {
+			fmt.Fprintf(os.Stderr, "  %s record=%d field=%s [%s] %s\n",
+				issue.Table, issue.RecordID, issue.Field, issue.Code, issue.Message)
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	dir, err := bundle.WriteDir(*out)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("bundle written", slog.String("version", bundle.Manifest.Version), slog.String("dir", dir),
+		slog.Int("tables", len(bundle.Manifest.Tables)), slog.Int("files", len(bundle.Manifest.Files)),
+		slog.String("content_hash", bundle.Manifest.ContentHash))
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"progressive/internal/apierror"
+	"progressive/internal/config"
+	"progressive/internal/gql"
+	"progressive/internal/handlers"
+	"progressive/internal/lifecycle"
+	"progressive/internal/metrics"
+	"progressive/internal/router"
+	"progressive/internal/storage"
+)
+
+// apiPrefix is the prefix of the current API version
+const apiPrefix = "/api/v1"
+
+// apiRoute is an endpoint of the versioned API
+type apiRoute struct {
+	// pattern is "METHOD /path" under apiPrefix
+	pattern string
+	handler apierror.HandlerFunc
+	// legacy are the paths the endpoint had before versioning, served as
+	// deprecated aliases; an entry without a method uses pattern's method
+	legacy []string
+}
+
+// routes builds the router: pages, operational endpoints, the versioned API
+// and its deprecated pre-versioning paths
+func routes(h *handlers.Handlers, cfg *config.Config, store *storage.Store, app *lifecycle.App) *router.Router {
+	postgres := requirePostgres(store)
+	rt := router.New()
+
+	// Operational endpoints
+	rt.HandleHTTP("GET /healthz", http.HandlerFunc(app.LivenessHandler))
+	rt.HandleHTTP("GET /readyz", http.HandlerFunc(app.ReadinessHandler))
+	rt.HandleHTTP("GET /metrics", metrics.Default.Handler())
+	rt.HandleHTTP("GET /static/", http.StripPrefix("/static/", http.FileServer(http.Dir("./static"))))
+
+	// Pages
+	rt.HandleHTTP("GET /{$}", http.HandlerFunc(h.HomeHandler))
+	rt.HandleHTTP("GET /dashboard", http.HandlerFunc(h.DashboardHandler))
+	rt.Handle("GET /table/create", h.TableCreatePageHandler)
+	rt.Handle("GET /table/{id}", h.TableEditorPageHandler)
+	rt.Handle("GET /fakeit", h.FakeitPageHandler)
+	rt.Handle("GET /merge-requests/{id}", h.MergeRequestPageHandler, requireFeature(cfg.Features.Branches), postgres)
+	rt.Handle("GET /audit", h.AuditPageHandler, requireFeature(cfg.Features.Audit), postgres)
+	rt.Handle("GET /api-docs", h.APIDocsPageHandler)
+
+	// The OpenAPI document is also served outside the version prefix, as the
+	// entry point for clients
+	spec := openAPIHandler(rt)
+	rt.Handle("GET /api/openapi.json", spec)
+
+	// GraphQL over the tables, outside the versioned REST API
+	graphql := gql.New(store.Tables, store.Records)
+	rt.Handle("GET /graphql", graphql.Handler, requireFeature(cfg.Features.GraphQL))
+	rt.Handle("POST /graphql", graphql.Handler, requireFeature(cfg.Features.GraphQL))
+
+	api := rt.Group(apiPrefix)
+	mountAPI(rt, api, []apiRoute{
+		{"GET /openapi.json", spec, nil},
+		{"GET /tables/{id}/openapi.json", h.Table.API.OpenAPIHandler, []string{"/api/table/{id}/openapi.json"}},
+		{"GET /templates", h.TemplatesAPIHandler, []string{"/api/templates"}},
+		{"POST /templates", h.CreateTemplateAPIHandler, nil},
+		{"GET /templates/export", h.ExportTemplatesAPIHandler, nil},
+		{"POST /templates/import", h.ImportTemplatesAPIHandler, nil},
+		{"GET /templates/{id}", h.GetTemplateAPIHandler, nil},
+		{"PUT /templates/{id}", h.UpdateTemplateAPIHandler, nil},
+		{"DELETE /templates/{id}", h.DeleteTemplateAPIHandler, nil},
+		{"POST /tables/{id}/template", h.SaveTableAsTemplateAPIHandler, nil},
+		{"GET /tables", h.ListTablesAPIHandler, []string{"/api/tables"}},
+		{"POST /tables", h.Table.Create.APIHandler, []string{"/api/table/create"}},
+		{"GET /tables/{id}", h.GetTableAPIHandler, nil},
+		{"PUT /tables/{id}", h.CreateTableAPIHandler, []string{"POST /api/tables"}},
+		{"DELETE /tables/{id}", h.DeleteTableAPIHandler, nil},
+		{"GET /tables/{id}/records", h.Table.API.DataHandler, []string{"/api/table/{id}"}},
+		{"PUT /tables/{id}/records", h.ReplaceRecordsAPIHandler, nil},
+		{"POST /tables/{id}/records", h.Table.API.CreateRecordHandler, []string{"/api/table/{id}/record", "/api/table/{id}/record/{$}"}},
+		{"GET /tables/{id}/records/{record}", h.Table.API.GetRecordHandler, nil},
+		{"PATCH /tables/{id}/records/{record}", h.Table.API.UpdateRecordHandler, []string{"/api/table/{id}/record/{record}"}},
+		{"DELETE /tables/{id}/records/{record}", h.Table.API.DeleteRecordHandler, []string{"/api/table/{id}/record/{record}"}},
+		{"POST /tables/{id}/import", h.Table.API.ImportHandler, []string{"/api/table/{id}/import"}},
+		{"GET /tables/{id}/export", h.Table.API.ExportHandler, []string{"/api/table/{id}/export"}},
+		{"GET /tables/{id}/codegen", h.Table.API.CodegenHandler, []string{"/api/table/{id}/codegen"}},
+		{"POST /fakeit/generate", h.FakeitGenerateAPIHandler, []string{"/api/fakeit/generate"}},
+	})
+	mountAPI(rt.With(postgres), api.With(postgres), []apiRoute{
+		{"GET /tables/{id}/revisions", h.Table.API.RevisionsHandler, []string{"/api/table/{id}/revisions"}},
+	})
+
+	comments := requireFeature(cfg.Features.Comments)
+	mountAPI(rt.With(comments, postgres), api.Group("/tables/{id}/comments", comments, postgres), []apiRoute{
+		{"GET /", h.Table.API.ListThreadsHandler, []string{"/api/table/{id}/comments"}},
+		{"POST /", h.Table.API.CreateThreadHandler, []string{"/api/table/{id}/comments"}},
+		{"GET /open", h.Table.API.OpenThreadCountsHandler, []string{"/api/table/{id}/comments/open"}},
+		{"GET /export", h.Table.API.ExportThreadsHandler, []string{"/api/table/{id}/comments/export"}},
+		{"GET /{thread}", h.Table.API.ThreadHandler, []string{"/api/table/{id}/comments/{thread}"}},
+		{"POST /{thread}/replies", h.Table.API.ReplyHandler, []string{"/api/table/{id}/comments/{thread}/replies"}},
+		{"POST /{thread}/resolve", h.Table.API.ResolveThreadHandler, []string{"/api/table/{id}/comments/{thread}/resolve"}},
+		{"POST /{thread}/unresolve", h.Table.API.UnresolveThreadHandler, []string{"/api/table/{id}/comments/{thread}/unresolve"}},
+		{"PATCH /{thread}/comments/{comment}", h.Table.API.EditCommentHandler, []string{"/api/table/{id}/comments/{thread}/comments/{comment}"}},
+		{"GET /{thread}/comments/{comment}/edits", h.Table.API.CommentEditsHandler, []string{"/api/table/{id}/comments/{thread}/comments/{comment}/edits"}},
+	})
+
+	publish := requireFeature(cfg.Features.Publish)
+	mountAPI(rt.With(publish, postgres), api.With(publish, postgres), []apiRoute{
+		{"POST /publish", h.PublishAPIHandler, []string{"/api/publish"}},
+		{"POST /publish/validate", h.PublishValidateAPIHandler, []string{"/api/publish/validate"}},
+	})
+
+	snapshots := requireFeature(cfg.Features.Snapshots)
+	mountAPI(rt.With(snapshots, postgres), api.With(snapshots, postgres), []apiRoute{
+		{"GET /snapshots", h.ListSnapshotsAPIHandler, []string{"/api/snapshots"}},
+		{"POST /snapshots", h.CreateSnapshotAPIHandler, []string{"/api/snapshots"}},
+		{"GET /snapshots/diff", h.SnapshotDiffAPIHandler, []string{"/api/snapshots/diff"}},
+		{"GET /snapshots/{id}", h.SnapshotAPIHandler, []string{"/api/snapshots/{id}"}},
+		{"GET /snapshots/{id}/promotions", h.PromotionsAPIHandler, []string{"/api/snapshots/{id}/promotions"}},
+		{"POST /snapshots/{id}/promotions", h.RequestPromotionAPIHandler, []string{"/api/snapshots/{id}/promotions"}},
+		{"GET /promotions/{id}", h.PromotionAPIHandler, []string{"/api/promotions/{id}"}},
+		{"POST /promotions/{id}/approve", h.ApprovePromotionAPIHandler, []string{"/api/promotions/{id}/approve"}},
+		{"POST /promotions/{id}/reject", h.RejectPromotionAPIHandler, []string{"/api/promotions/{id}/reject"}},
+	})
+
+	branches := requireFeature(cfg.Features.Branches)
+	mountAPI(rt.With(branches, postgres), api.With(branches, postgres), []apiRoute{
+		{"GET /branches", h.ListBranchesAPIHandler, []string{"/api/branches"}},
+		{"POST /branches", h.CreateBranchAPIHandler, []string{"/api/branches"}},
+		{"GET /branches/{id}", h.BranchAPIHandler, []string{"/api/branches/{id}"}},
+		{"DELETE /branches/{id}", h.CloseBranchAPIHandler, []string{"/api/branches/{id}"}},
+		{"GET /branches/{id}/changes", h.BranchChangesAPIHandler, []string{"/api/branches/{id}/changes"}},
+		{"POST /branches/{id}/records", h.CreateBranchRecordAPIHandler, []string{"/api/branches/{id}/records"}},
+		{"PATCH /branches/{id}/records/{ref}", h.UpdateBranchRecordAPIHandler, []string{"/api/branches/{id}/records/{ref}"}},
+		{"DELETE /branches/{id}/records/{ref}", h.DeleteBranchRecordAPIHandler, []string{"/api/branches/{id}/records/{ref}"}},
+		{"GET /merge-requests", h.ListMergeRequestsAPIHandler, []string{"/api/merge-requests"}},
+		{"POST /merge-requests", h.CreateMergeRequestAPIHandler, []string{"/api/merge-requests"}},
+		{"GET /merge-requests/{id}", h.MergeRequestAPIHandler, []string{"/api/merge-requests/{id}"}},
+		{"POST /merge-requests/{id}/merge", h.MergeAPIHandler, []string{"/api/merge-requests/{id}/merge"}},
+		{"POST /merge-requests/{id}/close", h.CloseMergeRequestAPIHandler, []string{"/api/merge-requests/{id}/close"}},
+	})
+
+	audit := requireFeature(cfg.Features.Audit)
+	mountAPI(rt.With(audit, postgres), api.With(audit, postgres), []apiRoute{
+		{"GET /audit", h.AuditAPIHandler, []string{"/api/audit"}},
+		{"GET /audit/verify", h.AuditVerifyAPIHandler, []string{"/api/audit/verify"}},
+	})
+
+	return rt
+}
+
+// mountAPI registers routes on api and their legacy paths on root, which
+// must carry the same middleware
+func mountAPI(root, api *router.Router, routes []apiRoute) {
+	for _, route := range routes {
+		api.Handle(route.pattern, route.handler)
+
+		method, path, _ := strings.Cut(route.pattern, " ")
+		successor := api.Path(path)
+		for _, old := range route.legacy {
+			if !strings.Contains(old, " ") {
+				old = method + " " + old
+			}
+			root.Deprecated(old, successor, route.handler)
+		}
+	}
+}
+
+// requirePostgres answers 501 on backends without the Postgres-only SQL that
+// snapshots, branches, comments, audit and publish use
+func requirePostgres(store *storage.Store) router.Middleware {
+	return func(next apierror.HandlerFunc) apierror.HandlerFunc {
+		if store.SupportsAllFeatures() {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) error {
+			return apierror.New(http.StatusNotImplemented, apierror.CodeNotImplemented,
+				"This feature requires the postgres storage backend")
+		}
+	}
+}
+
+// requireFeature answers 404 for a feature turned off in the configuration,
+// as if the route did not exist
+func requireFeature(enabled bool) router.Middleware {
+	return func(next apierror.HandlerFunc) apierror.HandlerFunc {
+		if enabled {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) error {
+			return apierror.NotFound(apierror.CodeNotFound, "Not found")
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/internal/config"
+	"progressive/internal/handlers"
+	"progressive/internal/lifecycle"
+	"progressive/internal/router"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+func testRoutes(t *testing.T, cfg *config.Config) *router.Router {
+	store := storagetest.Open(t, storage.SQLite)
+	return routes(handlers.NewHandlers(store), cfg, store, lifecycle.New(&http.Server{}))
+}
+
+func send(rt *router.Router, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestVersionedAndLegacyRoutes(t *testing.T) {
+	rt := testRoutes(t, config.Default())
+
+	rec := send(rt, "PUT", "/api/v1/tables/items", `{"name": "Items", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected table to be created, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = send(rt, "POST", "/api/v1/tables/items/records", `{"name": "sword"}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Deprecation") != "" {
+		t.Fatalf("Expected v1 record creation without deprecation, got %d %v: %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+
+	rec = send(rt, "POST", "/api/table/items/record/", `{"name": "bow"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected legacy record creation to work, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected Deprecation header on legacy path, got: %v", rec.Header())
+	}
+	if link := rec.Header().Get("Link"); link != `</api/v1/tables/items/records>; rel="successor-version"` {
+		t.Errorf("Unexpected successor link: %q", link)
+	}
+
+	rec = send(rt, "GET", "/api/v1/tables/items/records?page=1&limit=20", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"total":2`) {
+		t.Errorf("Expected both records, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = send(rt, "DELETE", "/api/v1/tables", "")
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD, POST" {
+		t.Errorf("Expected 405 with Allow, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestFeatureAndBackendGates(t *testing.T) {
+	cfg := config.Default()
+	cfg.Features.Branches = false
+	rt := testRoutes(t, cfg)
+
+	if rec := send(rt, "GET", "/api/v1/snapshots", ""); rec.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 for a postgres-only feature on sqlite, got %d", rec.Code)
+	}
+	if rec := send(rt, "GET", "/api/v1/branches", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a disabled feature, got %d", rec.Code)
+	}
+	if rec := send(rt, "GET", "/api/branches", ""); rec.Code != http.StatusNotFound || rec.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected the legacy path of a disabled feature to be gated too, got %d", rec.Code)
+	}
+}
+
+func TestGraphQLRoute(t *testing.T) {
+	rt := testRoutes(t, config.Default())
+	send(rt, "PUT", "/api/v1/tables/items", `{"name": "Items", "schema": {"type": "object", "properties": {"name": {"type": "string"}}}}`)
+	send(rt, "POST", "/api/v1/tables/items/records", `{"name": "sword"}`)
+
+	rec := send(rt, "POST", "/graphql", `{"query": "{ itemsList { total items { name } } }"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"sword"`) {
+		t.Errorf("Expected the record through GraphQL, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	cfg := config.Default()
+	cfg.Features.GraphQL = false
+	if rec := send(testRoutes(t, cfg), "POST", "/graphql", `{"query": "{ tables { id } }"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 with GraphQL disabled, got %d", rec.Code)
+	}
+}
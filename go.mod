@@ -3,11 +3,19 @@ module progressive
 go 1.24.1
 
 require (
-	github.com/a-h/templ v0.3.924 // indirect
+	github.com/a-h/templ v0.3.924
+	github.com/brianvoe/gofakeit/v7 v7.3.0
+	github.com/fergusstrange/embedded-postgres v1.32.0
+	github.com/graphql-go/graphql v0.8.1
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
 	github.com/brianvoe/gofakeit/v6 v6.28.0 // indirect
-	github.com/brianvoe/gofakeit/v7 v7.3.0 // indirect
-	github.com/fergusstrange/embedded-postgres v1.32.0 // indirect
-	github.com/jmoiron/sqlx v1.4.0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	github.com/xi2/xz v0.0.0-20171230120015-48954b6210f8 // indirect
 )
@@ -0,0 +1,252 @@
+// Package apierror is the error model of every API: handlers return an
+// *Error, and it is rendered as an RFC 7807 problem+json document with a
+// stable machine-readable code and the request ID. Causes are logged, never
+// sent to the client.
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"progressive/internal/logging"
+)
+
+// ContentType is the media type of problem documents
+const ContentType = "application/problem+json"
+
+// typePrefix turns a code into the problem type URI
+const typePrefix = "urn:progressive:problem:"
+
+// Codes shared across APIs. Domains add their own, e.g. "table_not_found".
+const (
+	CodeBadRequest       = "bad_request"
+	CodeInvalidJSON      = "invalid_json"
+	CodeValidation       = "validation_failed"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeForbidden        = "forbidden"
+	CodeUnprocessable    = "unprocessable"
+	CodeNotImplemented   = "not_implemented"
+	CodeInternal         = "internal_error"
+)
+
+// Error is an error a handler returns to end the request
+type Error struct {
+	Status int
+	// Code is stable and machine-readable; clients switch on it
+	Code string
+	// Message is shown to the client and must not contain internal details
+	Message string
+	Fields  []FieldError
+	// Extensions are extra members of the problem document, e.g. "conflicts"
+	Extensions map[string]interface{}
+	// Err is the cause; it is logged and never rendered
+	Err error
+}
+
+// FieldError points at one invalid input field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error with a client-facing message
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// Newf creates an error with a formatted client-facing message
+func Newf(status int, code, format string, args ...interface{}) *Error {
+	return New(status, code, fmt.Sprintf(format, args...))
+}
+
+// Wrap attaches the cause to e for logging
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// WithFields attaches field errors
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	copied := *e
+	copied.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &copied
+}
+
+// With attaches an extension member to the problem document
+func (e *Error) With(key string, value interface{}) *Error {
+	copied := *e
+	copied.Extensions = make(map[string]interface{}, len(e.Extensions)+1)
+	for k, v := range e.Extensions {
+		copied.Extensions[k] = v
+	}
+	copied.Extensions[key] = value
+	return &copied
+}
+
+// BadRequest is a 400 with a specific code
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// InvalidJSON is a 400 for a request body that does not decode
+func InvalidJSON(err error) *Error {
+	return New(http.StatusBadRequest, CodeInvalidJSON, "Invalid JSON request").Wrap(err)
+}
+
+// Validation is a 400 listing the invalid fields
+func Validation(message string, fields ...FieldError) *Error {
+	return New(http.StatusBadRequest, CodeValidation, message).WithFields(fields...)
+}
+
+// NotFound is a 404 with a specific code
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+// MethodNotAllowed is a 405
+func MethodNotAllowed() *Error {
+	return New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed")
+}
+
+// Internal is a 500 that hides err from the client
+func Internal(err error) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, "Internal server error").Wrap(err)
+}
+
+// From returns err as an *Error; anything else is an internal error
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return Internal(err)
+}
+
+// Problem is the RFC 7807 document sent to clients
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	// Extensions are written as top-level members next to the standard ones
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON writes the extension members inline
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+	members := make(map[string]json.RawMessage, len(p.Extensions))
+	for k, v := range p.Extensions {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		members[k] = raw
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+// Problem builds the client document for r
+func (e *Error) Problem(r *http.Request) Problem {
+	p := Problem{
+		Type:       typePrefix + e.Code,
+		Title:      http.StatusText(e.Status),
+		Status:     e.Status,
+		Detail:     e.Message,
+		Code:       e.Code,
+		Errors:     e.Fields,
+		RequestID:  logging.RequestID(r.Context()),
+		Extensions: e.Extensions,
+	}
+	if r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	return p
+}
+
+// Write renders err as a problem document
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	e := From(err)
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(e.Problem(r))
+}
+
+// HandlerFunc is a handler that returns its error instead of writing it
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP reports the returned error
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		Report(w, r, err)
+	}
+}
+
+// Handler adapts an error-returning handler to http.HandlerFunc
+func Handler(f HandlerFunc) http.HandlerFunc {
+	return f.ServeHTTP
+}
+
+type slotKey struct{}
+
+// Slot receives the error of a request for a middleware to render
+type Slot struct {
+	Err error
+}
+
+// WithSlot returns a context in which Report hands errors to the slot
+// instead of writing them
+func WithSlot(ctx context.Context) (context.Context, *Slot) {
+	slot := &Slot{}
+	return context.WithValue(ctx, slotKey{}, slot), slot
+}
+
+// Report hands err to the middleware that owns the request's slot, or
+// writes it directly when there is none
+func Report(w http.ResponseWriter, r *http.Request, err error) {
+	if slot, ok := r.Context().Value(slotKey{}).(*Slot); ok && slot.Err == nil {
+		slot.Err = err
+		return
+	}
+	Write(w, r, err)
+}
+
+// Summary describes err for logs: the message and the cause
+func Summary(err error) string {
+	e := From(err)
+	if e.Err == nil {
+		return e.Code + ": " + e.Message
+	}
+	return e.Code + ": " + strings.TrimSpace(e.Error())
+}
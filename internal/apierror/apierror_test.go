@@ -0,0 +1,63 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"progressive/internal/logging"
+)
+
+func TestWriteProblem(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/merge-requests/3/merge", nil)
+	req = req.WithContext(logging.WithRequestID(req.Context(), "req-1"))
+	rec := httptest.NewRecorder()
+
+	err := New(http.StatusConflict, "merge_conflict", "2 conflicts").With("conflicts", []string{"a", "b"})
+	Write(rec, req, fmt.Errorf("merge: %w", err))
+
+	if rec.Code != http.StatusConflict || rec.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("Expected a 409 problem document, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"type":       "urn:progressive:problem:merge_conflict",
+		"title":      "Conflict",
+		"status":     float64(409),
+		"detail":     "2 conflicts",
+		"instance":   "/api/merge-requests/3/merge",
+		"code":       "merge_conflict",
+		"request_id": "req-1",
+	}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, body[k])
+		}
+	}
+	if conflicts, _ := body["conflicts"].([]interface{}); len(conflicts) != 2 {
+		t.Errorf("Expected the conflicts extension, got %v", body["conflicts"])
+	}
+}
+
+func TestInternalHidesCause(t *testing.T) {
+	cause := errors.New(`pq: duplicate key value violates unique constraint "tables_pkey"`)
+	e := From(fmt.Errorf("create table: %w", cause))
+	if e.Status != http.StatusInternalServerError || e.Code != CodeInternal {
+		t.Fatalf("Expected plain errors to become internal errors, got %+v", e)
+	}
+	if p := e.Problem(httptest.NewRequest("GET", "/", nil)); p.Detail != "Internal server error" {
+		t.Errorf("Expected a generic detail, got %q", p.Detail)
+	}
+	if !errors.Is(e, cause) {
+		t.Errorf("Expected the cause to be kept for logging")
+	}
+	if got := Summary(e); got != "internal_error: Internal server error: create table: "+cause.Error() {
+		t.Errorf("Unexpected summary %q", got)
+	}
+}
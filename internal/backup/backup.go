@@ -0,0 +1,438 @@
+// Package backup writes and restores logical dumps of every application
+// table: tables, records, templates, snapshots, branches, comments, the
+// audit log and their metadata.
+//
+// A dump is gzip-compressed JSON. It is taken in a single read-only
+// transaction so it is consistent even while the server is writing, and it
+// is restored in a single transaction with triggers suspended so counts,
+// revisions and the audit chain come back exactly as they were dumped.
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"progressive/internal/infrastructure"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FormatName identifies backup files
+const FormatName = "progressive-backup"
+
+// FormatVersion is bumped when the file layout changes
+const FormatVersion = 1
+
+var (
+	// ErrInvalidBackup is returned for files that are not readable backups
+	ErrInvalidBackup = errors.New("invalid backup")
+	// ErrIncompatible is returned when a backup cannot be restored into the database
+	ErrIncompatible = errors.New("incompatible backup")
+)
+
+// skippedTables are never dumped; the migrator owns them
+var skippedTables = map[string]bool{
+	"schema_migrations": true,
+	"migrations":        true,
+}
+
+// Manifest describes a backup
+type Manifest struct {
+	Format        string         `json:"format"`
+	FormatVersion int            `json:"format_version"`
+	Backend       string         `json:"backend"`
+	SchemaVersion int            `json:"schema_version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Rows          map[string]int `json:"rows"`
+}
+
+// file is the on-disk layout
+type file struct {
+	Manifest Manifest    `json:"manifest"`
+	Tables   []tableDump `json:"tables"`
+}
+
+// tableDump holds one database table
+type tableDump struct {
+	Name    string          `json:"name"`
+	Columns []string        `json:"columns"`
+	Types   []string        `json:"types"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// Write dumps the database to w
+func Write(ctx context.Context, db *sqlx.DB, w io.Writer) (*Manifest, error) {
+	d, err := dialectFor(db)
+	if err != nil {
+		return nil, err
+	}
+	schemaVersion, err := appliedVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTxx(ctx, d.snapshotTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	names, err := d.tableNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := file{Manifest: Manifest{
+		Format:        FormatName,
+		FormatVersion: FormatVersion,
+		Backend:       d.backend,
+		SchemaVersion: schemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Rows:          make(map[string]int, len(names)),
+	}}
+	for _, name := range names {
+		dump, err := dumpTable(ctx, tx, name)
+		if err != nil {
+			return nil, err
+		}
+		out.Tables = append(out.Tables, *dump)
+		out.Manifest.Rows[name] = len(dump.Rows)
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(out); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	return &out.Manifest, nil
+}
+
+// ReadManifest reads only what is needed to describe a backup
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	f, err := read(r)
+	if err != nil {
+		return nil, err
+	}
+	return &f.Manifest, nil
+}
+
+// Restore replaces the contents of every table with the backup. The
+// database must already be migrated to the backup's schema version or a
+// later one, and use the same backend the backup was taken from.
+func Restore(ctx context.Context, db *sqlx.DB, r io.Reader) (*Manifest, error) {
+	d, err := dialectFor(db)
+	if err != nil {
+		return nil, err
+	}
+	f, err := read(r)
+	if err != nil {
+		return nil, err
+	}
+	if f.Manifest.Backend != d.backend {
+		return nil, fmt.Errorf("%w: taken from %s, restoring into %s", ErrIncompatible, f.Manifest.Backend, d.backend)
+	}
+	schemaVersion, err := appliedVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if f.Manifest.SchemaVersion > schemaVersion {
+		return nil, fmt.Errorf("%w: backup schema version %d is newer than the database (%d); run migrate up first",
+			ErrIncompatible, f.Manifest.SchemaVersion, schemaVersion)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	resume, err := d.suspendTriggers(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := d.tableNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+quote(name)); err != nil {
+			return nil, fmt.Errorf("failed to clear %s: %w", name, err)
+		}
+	}
+	for _, dump := range f.Tables {
+		if err := restoreTable(ctx, tx, dump); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := resume(); err != nil {
+		return nil, err
+	}
+	if err := d.resetSequences(ctx, tx); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return &f.Manifest, nil
+}
+
+func read(r io.Reader) (*file, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	defer gz.Close()
+
+	decoder := json.NewDecoder(gz)
+	decoder.UseNumber()
+	var f file
+	if err := decoder.Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if f.Manifest.Format != FormatName {
+		return nil, fmt.Errorf("%w: not a %s file", ErrInvalidBackup, FormatName)
+	}
+	if f.Manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: format version %d is newer than supported (%d)", ErrInvalidBackup, f.Manifest.FormatVersion, FormatVersion)
+	}
+	return &f, nil
+}
+
+// appliedVersion returns the highest applied migration
+func appliedVersion(ctx context.Context, db *sqlx.DB) (int, error) {
+	m, err := infrastructure.NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, status := range statuses {
+		if status.Applied && status.Version > version {
+			version = status.Version
+		}
+	}
+	return version, nil
+}
+
+func dumpTable(ctx context.Context, tx *sqlx.Tx, name string) (*tableDump, error) {
+	rows, err := tx.QueryxContext(ctx, "SELECT * FROM "+quote(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	dump := &tableDump{Name: name, Columns: columns, Rows: [][]interface{}{}}
+	for _, columnType := range columnTypes {
+		dump.Types = append(dump.Types, strings.ToUpper(columnType.DatabaseTypeName()))
+	}
+
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		for i, value := range values {
+			// JSON, arrays and text come back as bytes; keep them readable
+			if b, ok := value.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		dump.Rows = append(dump.Rows, values)
+	}
+	return dump, rows.Err()
+}
+
+func restoreTable(ctx context.Context, tx *sqlx.Tx, dump tableDump) error {
+	if len(dump.Rows) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(dump.Columns))
+	for i, column := range dump.Columns {
+		quoted[i] = quote(column)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(dump.Columns)), ", ")
+	insert := tx.Rebind(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quote(dump.Name), strings.Join(quoted, ", "), placeholders))
+
+	for _, row := range dump.Rows {
+		if len(row) != len(dump.Columns) {
+			return fmt.Errorf("%w: %s row has %d values for %d columns", ErrInvalidBackup, dump.Name, len(row), len(dump.Columns))
+		}
+		args := make([]interface{}, len(row))
+		for i, value := range row {
+			args[i] = restoreValue(value, columnType(dump.Types, i))
+		}
+		if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", dump.Name, err)
+		}
+	}
+	return nil
+}
+
+func columnType(types []string, i int) string {
+	if i < len(types) {
+		return types[i]
+	}
+	return ""
+}
+
+// restoreValue converts a decoded JSON value back to a driver argument
+func restoreValue(value interface{}, dbType string) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case string:
+		if strings.Contains(dbType, "TIME") || strings.Contains(dbType, "DATE") {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		}
+		return v
+	}
+	return value
+}
+
+// quote quotes an identifier for both PostgreSQL and SQLite
+func quote(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// dialect holds the backend-specific parts of dumping and restoring
+type dialect struct {
+	backend         string
+	snapshotTx      *sql.TxOptions
+	tableNames      func(ctx context.Context, tx *sqlx.Tx) ([]string, error)
+	suspendTriggers func(ctx context.Context, tx *sqlx.Tx) (func() error, error)
+	resetSequences  func(ctx context.Context, tx *sqlx.Tx) error
+}
+
+func dialectFor(db *sqlx.DB) (*dialect, error) {
+	if db == nil {
+		return nil, fmt.Errorf("backups need a database backend")
+	}
+	if db.DriverName() == infrastructure.SQLiteDriver {
+		return &sqliteDialect, nil
+	}
+	return &postgresDialect, nil
+}
+
+var postgresDialect = dialect{
+	backend:    "postgres",
+	snapshotTx: &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true},
+	tableNames: func(ctx context.Context, tx *sqlx.Tx) ([]string, error) {
+		return selectTableNames(ctx, tx, `SELECT table_name FROM information_schema.tables
+			WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'`)
+	},
+	// replica mode skips user triggers and foreign key checks, so rows load
+	// in any order and trigger-maintained columns keep their dumped values
+	suspendTriggers: func(ctx context.Context, tx *sqlx.Tx) (func() error, error) {
+		if _, err := tx.ExecContext(ctx, `SET LOCAL session_replication_role = replica`); err != nil {
+			return nil, fmt.Errorf("failed to suspend triggers (restore needs a superuser): %w", err)
+		}
+		return func() error {
+			_, err := tx.ExecContext(ctx, `SET LOCAL session_replication_role = DEFAULT`)
+			return err
+		}, nil
+	},
+	resetSequences: func(ctx context.Context, tx *sqlx.Tx) error {
+		var sequences []struct {
+			Table    string `db:"table_name"`
+			Sequence string `db:"sequence"`
+		}
+		query := `SELECT table_name, pg_get_serial_sequence(quote_ident(table_name), 'id') AS sequence
+			FROM information_schema.columns
+			WHERE table_schema = current_schema() AND column_name = 'id'
+			  AND pg_get_serial_sequence(quote_ident(table_name), 'id') IS NOT NULL`
+		if err := tx.SelectContext(ctx, &sequences, query); err != nil {
+			return fmt.Errorf("failed to list sequences: %w", err)
+		}
+		for _, seq := range sequences {
+			setval := fmt.Sprintf(`SELECT setval($1, COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM %s`, quote(seq.Table))
+			if _, err := tx.ExecContext(ctx, setval, seq.Sequence); err != nil {
+				return fmt.Errorf("failed to reset sequence for %s: %w", seq.Table, err)
+			}
+		}
+		return nil
+	},
+}
+
+var sqliteDialect = dialect{
+	backend: "sqlite",
+	// a transaction in WAL mode already reads from one snapshot
+	snapshotTx: nil,
+	tableNames: func(ctx context.Context, tx *sqlx.Tx) ([]string, error) {
+		return selectTableNames(ctx, tx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`)
+	},
+	// SQLite cannot disable triggers, so they are dropped and recreated
+	// from their stored definitions inside the restore transaction
+	suspendTriggers: func(ctx context.Context, tx *sqlx.Tx) (func() error, error) {
+		if _, err := tx.ExecContext(ctx, `PRAGMA defer_foreign_keys = ON`); err != nil {
+			return nil, fmt.Errorf("failed to defer foreign keys: %w", err)
+		}
+		var triggers []struct {
+			Name string `db:"name"`
+			SQL  string `db:"sql"`
+		}
+		if err := tx.SelectContext(ctx, &triggers, `SELECT name, sql FROM sqlite_master WHERE type = 'trigger'`); err != nil {
+			return nil, fmt.Errorf("failed to read triggers: %w", err)
+		}
+		for _, trigger := range triggers {
+			if _, err := tx.ExecContext(ctx, "DROP TRIGGER "+quote(trigger.Name)); err != nil {
+				return nil, fmt.Errorf("failed to suspend trigger %s: %w", trigger.Name, err)
+			}
+		}
+		return func() error {
+			for _, trigger := range triggers {
+				if _, err := tx.ExecContext(ctx, trigger.SQL); err != nil {
+					return fmt.Errorf("failed to restore trigger %s: %w", trigger.Name, err)
+				}
+			}
+			return nil
+		}, nil
+	},
+	// AUTOINCREMENT counters follow explicit ids on insert
+	resetSequences: func(ctx context.Context, tx *sqlx.Tx) error {
+		return nil
+	},
+}
+
+func selectTableNames(ctx context.Context, tx *sqlx.Tx, query string) ([]string, error) {
+	var names []string
+	if err := tx.SelectContext(ctx, &names, query); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	kept := names[:0]
+	for _, name := range names {
+		if !skippedTables[name] {
+			kept = append(kept, name)
+		}
+	}
+	sort.Strings(kept)
+	return kept, nil
+}
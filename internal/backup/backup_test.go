@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+func seed(t *testing.T, store *storage.Store) {
+	t.Helper()
+	ctx := context.Background()
+	if err := store.Tables.Create(ctx, table.NewTable("items", "Items", "", []byte(`{"type": "object", "properties": {"name": {"type": "string"}}}`))); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for _, name := range []string{"sword", "shield"} {
+		if err := store.Records.Create(ctx, record.NewRecord("items", map[string]interface{}{"name": name})); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+}
+
+func count(t *testing.T, store *storage.Store, query string) int {
+	t.Helper()
+	var n int
+	if err := store.DB.Get(&n, query); err != nil {
+		t.Fatalf("Failed to run %q: %v", query, err)
+	}
+	return n
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.Open(t, storage.SQLite)
+	seed(t, store)
+	revisions := count(t, store, `SELECT COUNT(*) FROM record_revisions`)
+
+	var buf bytes.Buffer
+	manifest, err := Write(ctx, store.DB, &buf)
+	if err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	if manifest.Rows["records"] != 2 || manifest.Rows["tables"] != 1 || manifest.SchemaVersion == 0 {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	if _, ok := manifest.Rows["schema_migrations"]; ok {
+		t.Error("Expected schema_migrations to be left out of the backup")
+	}
+
+	// Diverge from the backup, including the append-only audit log
+	store.DB.MustExec(`DELETE FROM records WHERE id = 1`)
+	store.DB.MustExec(`INSERT INTO records (table_id, data) VALUES ('items', '{"name": "bow"}')`)
+	store.DB.MustExec(`INSERT INTO audit_log (occurred_at, actor, method, path, status, action, prev_hash, hash) VALUES (CURRENT_TIMESTAMP, 'x', 'POST', '/', 200, 'test', '', 'h')`)
+
+	if _, err := Restore(ctx, store.DB, bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	recs, err := store.Records.FindByTable(ctx, "items", record.Page{})
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("Expected the 2 backed up records, got %d: %v", len(recs), err)
+	}
+	if tbl, _ := store.Tables.FindByID(ctx, "items"); tbl.RecordCount != 2 {
+		t.Errorf("Expected record_count 2 without trigger double counting, got %d", tbl.RecordCount)
+	}
+	if got := count(t, store, `SELECT COUNT(*) FROM record_revisions`); got != revisions {
+		t.Errorf("Expected %d revisions restored as dumped, got %d", revisions, got)
+	}
+	if got := count(t, store, `SELECT COUNT(*) FROM audit_log`); got != 0 {
+		t.Errorf("Expected the audit log to match the backup, got %d rows", got)
+	}
+
+	// Triggers are back after the restore
+	if err := store.Records.Create(ctx, record.NewRecord("items", map[string]interface{}{"name": "axe"})); err != nil {
+		t.Fatalf("Failed to create record after restore: %v", err)
+	}
+	if tbl, _ := store.Tables.FindByID(ctx, "items"); tbl.RecordCount != 3 {
+		t.Errorf("Expected record_count trigger to run again, got %d", tbl.RecordCount)
+	}
+	store.DB.MustExec(`INSERT INTO audit_log (occurred_at, actor, method, path, status, action, prev_hash, hash) VALUES (CURRENT_TIMESTAMP, 'x', 'POST', '/', 200, 'test', '', 'h2')`)
+	if _, err := store.DB.Exec(`DELETE FROM audit_log`); err == nil {
+		t.Error("Expected the audit log to be append-only again")
+	}
+}
+
+func TestRestoreRejectsIncompatibleBackups(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.Open(t, storage.SQLite)
+
+	if _, err := Restore(ctx, store.DB, bytes.NewReader([]byte("not gzip"))); !errors.Is(err, ErrInvalidBackup) {
+		t.Errorf("Expected invalid backup error, got: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := Write(ctx, store.DB, &buf); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	store.DB.MustExec(`DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)`)
+	if _, err := Restore(ctx, store.DB, bytes.NewReader(buf.Bytes())); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("Expected a newer schema version to be rejected, got: %v", err)
+	}
+}
+
+func TestWriteFileAndPrune(t *testing.T) {
+	store := storagetest.Open(t, storage.SQLite)
+	dir := t.TempDir()
+
+	path, _, err := WriteFile(context.Background(), store.DB, dir)
+	if err != nil {
+		t.Fatalf("Failed to write backup file: %v", err)
+	}
+	if _, err := RestoreFile(context.Background(), store.DB, path); err != nil {
+		t.Errorf("Failed to restore backup file: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		old := FileName(time.Date(2020, 1, i, 0, 0, 0, 0, time.UTC))
+		os.WriteFile(dir+"/"+old, nil, 0o644)
+	}
+	removed, err := Prune(dir, 2)
+	if err != nil || len(removed) != 2 {
+		t.Fatalf("Expected 2 old backups pruned, got %v: %v", removed, err)
+	}
+	paths, _ := List(dir)
+	if len(paths) != 2 || paths[0] != path {
+		t.Errorf("Expected the newest backups to remain, got: %v", paths)
+	}
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// filePrefix and fileSuffix surround the timestamp in backup file names
+const (
+	filePrefix = "progressive-"
+	fileSuffix = ".json.gz"
+)
+
+// FileName returns the backup file name for a point in time
+func FileName(at time.Time) string {
+	return filePrefix + at.UTC().Format("20060102T150405Z") + fileSuffix
+}
+
+// WriteFile writes a timestamped backup into dir. The file is written under
+// a temporary name and renamed, so a crash never leaves a truncated backup.
+func WriteFile(ctx context.Context, db *sqlx.DB, dir string) (string, *Manifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	path := filepath.Join(dir, FileName(time.Now()))
+
+	tmp, err := os.CreateTemp(dir, ".backup-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	manifest, err := Write(ctx, db, tmp)
+	if err != nil {
+		tmp.Close()
+		return "", nil, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", nil, fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", nil, fmt.Errorf("failed to write backup file: %w", err)
+	}
+	return path, manifest, nil
+}
+
+// RestoreFile restores the backup at path
+func RestoreFile(ctx context.Context, db *sqlx.DB, path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+	return Restore(ctx, db, f)
+}
+
+// List returns the backup files in dir, newest first
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, filePrefix) && strings.HasSuffix(name, fileSuffix) {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	// Timestamps in the names sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
+
+// Prune deletes all but the newest keep backups in dir and returns the
+// deleted paths. keep <= 0 keeps everything.
+func Prune(dir string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	paths, err := List(dir)
+	if err != nil || len(paths) <= keep {
+		return nil, err
+	}
+
+	var removed []string
+	for _, path := range paths[keep:] {
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// Scheduler takes a backup every Interval and keeps the newest Keep files
+type Scheduler struct {
+	DB       *sqlx.DB
+	Dir      string
+	Interval time.Duration
+	Keep     int
+}
+
+// Run backs up on every tick until ctx is cancelled. Failures are logged
+// and retried on the next tick.
+func (s *Scheduler) Run(ctx context.Context) {
+	slog.InfoContext(ctx, "scheduled backups enabled",
+		slog.Duration("interval", s.Interval), slog.String("dir", s.Dir), slog.Int("keep", s.Keep))
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.backup(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) backup(ctx context.Context) {
+	path, manifest, err := WriteFile(ctx, s.DB, s.Dir)
+	if err != nil {
+		slog.ErrorContext(ctx, "scheduled backup failed", slog.Any("error", err))
+		return
+	}
+	slog.InfoContext(ctx, "backup written", slog.String("path", path), slog.Int("tables", len(manifest.Rows)))
+
+	removed, err := Prune(s.Dir, s.Keep)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to prune old backups", slog.Any("error", err))
+	}
+	for _, path := range removed {
+		slog.InfoContext(ctx, "removed old backup", slog.String("path", path))
+	}
+}
@@ -0,0 +1,214 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// Table is the input for code generation: a named table and its schema
+type Table struct {
+	ID     string
+	Name   string
+	Schema *repository.SchemaDefinition
+}
+
+// Language identifies a code generation target
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguageTypeScript Language = "ts"
+	LanguageCSharp     Language = "csharp"
+	LanguageSQL        Language = "sql"
+)
+
+// ErrUnsupportedLanguage is returned for an unknown generation target
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// Options control naming in the generated source
+type Options struct {
+	// Package is the Go package name (default "gamedata")
+	Package string
+	// Namespace wraps the generated C# classes when set
+	Namespace string
+}
+
+// ParseLanguage accepts the language names used by the codegen API
+func ParseLanguage(s string) (Language, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "go", "golang":
+		return LanguageGo, nil
+	case "ts", "typescript":
+		return LanguageTypeScript, nil
+	case "cs", "csharp", "c#":
+		return LanguageCSharp, nil
+	case "sql":
+		return LanguageSQL, nil
+	}
+	return "", fmt.Errorf("%w: %q (expected go, ts, csharp or sql)", ErrUnsupportedLanguage, s)
+}
+
+// Extension returns the file extension for generated source
+func (l Language) Extension() string {
+	switch l {
+	case LanguageTypeScript:
+		return "ts"
+	case LanguageCSharp:
+		return "cs"
+	default:
+		return string(l)
+	}
+}
+
+// Generate produces source for the tables in the given language
+func Generate(lang Language, tables []Table, opts Options) ([]byte, error) {
+	switch lang {
+	case LanguageGo:
+		return Go(tables, opts)
+	case LanguageTypeScript:
+		return TypeScript(tables), nil
+	case LanguageCSharp:
+		return CSharp(tables, opts), nil
+	case LanguageSQL:
+		return SQL(tables), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, lang)
+}
+
+// TypeName returns the exported type name generated for the table
+func (t Table) TypeName() string {
+	name := PascalCase(t.Name)
+	if name == "" {
+		name = PascalCase(t.ID)
+	}
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Table" + name
+	}
+	return name
+}
+
+// TypeNames assigns each table a unique type name, numbering duplicates
+func TypeNames(tables []Table) []string {
+	names := make([]string, len(tables))
+	seen := make(map[string]int)
+	for i, table := range tables {
+		name := table.TypeName()
+		seen[name]++
+		if seen[name] > 1 {
+			name += strconv.Itoa(seen[name])
+		}
+		names[i] = name
+	}
+	return names
+}
+
+// PascalCase converts snake_case, kebab-case or spaced names to PascalCase,
+// dropping characters that are not valid in identifiers
+func PascalCase(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			upper = true
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if r > unicode.MaxASCII {
+				// Non-ASCII letters are valid in Go but not in every target
+				// language we emit, so they are dropped from identifiers.
+				upper = true
+				continue
+			}
+			if upper {
+				b.WriteRune(unicode.ToUpper(r))
+				upper = false
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// CamelCase converts a name to camelCase
+func CamelCase(s string) string {
+	p := PascalCase(s)
+	if p == "" {
+		return p
+	}
+	runes := []rune(p)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+// fieldIdent returns an exported identifier for a property, falling back to a
+// positional name when the property name has no usable characters
+func fieldIdent(name string, index int) string {
+	ident := PascalCase(name)
+	if ident == "" {
+		return "Field" + strconv.Itoa(index)
+	}
+	if unicode.IsDigit([]rune(ident)[0]) {
+		ident = "F" + ident
+	}
+	return ident
+}
+
+// commentText flattens a title/description into a single comment line
+func commentText(prop repository.PropertyDef) string {
+	text := prop.Title
+	if prop.Description != "" {
+		if text != "" {
+			text += " - "
+		}
+		text += prop.Description
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// enumLiterals formats the property's enum values as source literals, quoting
+// strings with quote and dropping duplicates. It reports false when a value
+// does not fit the property's type, so no enum constraint is generated.
+func enumLiterals(prop repository.PropertyDef, quote func(string) string) ([]string, bool) {
+	if len(prop.Enum) == 0 {
+		return nil, false
+	}
+	var literals []string
+	seen := make(map[string]bool)
+	for _, value := range prop.Enum {
+		var literal string
+		switch v := value.(type) {
+		case string:
+			if prop.Type != "string" && prop.Type != "" {
+				return nil, false
+			}
+			literal = quote(v)
+		case float64:
+			switch {
+			case prop.Type == "integer" && v == math.Trunc(v):
+				literal = strconv.FormatFloat(v, 'f', 0, 64)
+			case prop.Type == "number":
+				literal = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				return nil, false
+			}
+		case bool:
+			if prop.Type != "boolean" {
+				return nil, false
+			}
+			literal = strconv.FormatBool(v)
+		default:
+			return nil, false
+		}
+		if !seen[literal] {
+			seen[literal] = true
+			literals = append(literals, literal)
+		}
+	}
+	return literals, true
+}
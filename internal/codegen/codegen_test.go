@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+func questTables(t *testing.T) []Table {
+	t.Helper()
+	schema, err := repository.ParseSchemaDefinition(json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"quest_name": { "type": "string", "title": "퀘스트명", "minLength": 1, "maxLength": 40 },
+			"difficulty": { "type": "string", "enum": ["쉬움", "보통", "어려움"] },
+			"reward_gold": { "type": "integer", "minimum": 0, "maximum": 100000 },
+			"tier": { "type": "integer", "enum": [1, 2, 3] },
+			"drop_rate": { "type": "number", "minimum": 0.5 },
+			"code": { "type": "string", "pattern": "^Q[0-9]+$" },
+			"start_date": { "type": "string", "format": "date" },
+			"repeatable": { "type": "boolean" }
+		},
+		"required": ["quest_name", "difficulty"]
+	}`))
+	if err != nil {
+		t.Fatalf("Expected schema to parse, got: %v", err)
+	}
+	return []Table{{ID: "table_quest", Name: "quest", Schema: schema}}
+}
+
+func TestGoOutputTypeChecks(t *testing.T) {
+	src, err := Go(questTables(t), Options{Package: "gamedata"})
+	if err != nil {
+		t.Fatalf("Expected Go generation to succeed, got: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gamedata.go", src, 0)
+	if err != nil {
+		t.Fatalf("Expected generated Go to parse, got: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("gamedata", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("Expected generated Go to type-check, got: %v\n%s", err, src)
+	}
+	if !strings.Contains(string(src), "func (r *Quest) Validate() error") {
+		t.Errorf("Expected a Validate method, got:\n%s", src)
+	}
+}
+
+func TestSQLOutput(t *testing.T) {
+	out := string(SQL(questTables(t)))
+	for _, expected := range []string{
+		"CREATE TABLE quest (",
+		"id BIGINT PRIMARY KEY",
+		"quest_name VARCHAR(40) NOT NULL CHECK (char_length(quest_name) >= 1)",
+		"difficulty TEXT NOT NULL CHECK (difficulty IN ('쉬움', '보통', '어려움'))",
+		"reward_gold INTEGER CHECK (reward_gold BETWEEN 0 AND 100000)",
+		"drop_rate DOUBLE PRECISION CHECK (drop_rate >= 0.5)",
+		"tier BIGINT CHECK (tier IN (1, 2, 3))",
+		"start_date DATE",
+		"COMMENT ON COLUMN quest.quest_name IS '퀘스트명';",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected SQL to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestCSharpOutput(t *testing.T) {
+	out := string(CSharp(questTables(t), Options{Namespace: "Game.Data"}))
+	for _, expected := range []string{
+		"namespace Game.Data",
+		"public class Quest",
+		"[JsonProperty(\"reward_gold\")]",
+		"public long? RewardGold;",
+		"public string QuestName;",
+		"public static readonly string[] DifficultyValues = { \"쉬움\", \"보통\", \"어려움\" };",
+		"public static readonly long[] TierValues = { 1, 2, 3 };",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected C# to contain %q, got:\n%s", expected, out)
+		}
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if lang, err := ParseLanguage("TypeScript"); err != nil || lang != LanguageTypeScript {
+		t.Errorf("Expected ts, got: %v, %v", lang, err)
+	}
+	if _, err := ParseLanguage("rust"); err == nil {
+		t.Error("Expected unsupported language error")
+	}
+}
@@ -0,0 +1,150 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// CSharp generates one serializable class per table for the Unity client.
+// Fields carry Newtonsoft.Json attributes so the keys match the stored records.
+func CSharp(tables []Table, opts Options) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by progressive. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "using System;\n")
+	fmt.Fprintf(&buf, "using System.Collections.Generic;\n")
+	fmt.Fprintf(&buf, "using Newtonsoft.Json;\n")
+
+	indent := ""
+	if opts.Namespace != "" {
+		fmt.Fprintf(&buf, "\nnamespace %s\n{\n", opts.Namespace)
+		indent = "    "
+	}
+
+	names := TypeNames(tables)
+	for i, table := range tables {
+		writeCSharpClass(&buf, indent, names[i], table)
+	}
+
+	if opts.Namespace != "" {
+		fmt.Fprintf(&buf, "}\n")
+	}
+	return buf.Bytes()
+}
+
+func writeCSharpClass(buf *bytes.Buffer, indent, typeName string, table Table) {
+	line := func(format string, args ...interface{}) {
+		buf.WriteString(indent)
+		fmt.Fprintf(buf, format, args...)
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString("\n")
+	line("/// <summary>Generated from table %s (%s).</summary>", xmlEscape(strconv.Quote(table.Name)), xmlEscape(table.ID))
+	line("[Serializable]")
+	line("public class %s", typeName)
+	line("{")
+	line("    [JsonProperty(\"_id\")]")
+	line("    public long RecordId;")
+
+	// C# does not allow members named after their enclosing type
+	used := map[string]bool{typeName: true, "RecordId": true}
+	unique := func(ident string) string {
+		for used[ident] {
+			ident += "_"
+		}
+		used[ident] = true
+		return ident
+	}
+
+	var enums []string
+	for i, name := range table.Schema.PropertyNames() {
+		prop := table.Schema.Properties[name]
+		ident := unique(fieldIdent(name, i))
+
+		fieldType := csTypeFor(prop)
+		if !table.Schema.IsRequired(name) && csValueType(fieldType) {
+			fieldType += "?"
+		}
+
+		buf.WriteString("\n")
+		if comment := commentText(prop); comment != "" {
+			line("    /// <summary>%s</summary>", xmlEscape(comment))
+		}
+		line("    [JsonProperty(%s)]", csQuote(name))
+		line("    public %s %s;", fieldType, ident)
+
+		if literals, ok := enumLiterals(prop, csQuote); ok {
+			enums = append(enums, fmt.Sprintf("    public static readonly %s[] %s = { %s };",
+				csTypeFor(prop), unique(ident+"Values"), strings.Join(literals, ", ")))
+		}
+	}
+
+	if len(enums) > 0 {
+		buf.WriteString("\n")
+		for _, enum := range enums {
+			line("%s", enum)
+		}
+	}
+	line("}")
+}
+
+func csTypeFor(prop repository.PropertyDef) string {
+	switch prop.Type {
+	case "integer":
+		return "long"
+	case "number":
+		return "double"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "List<object>"
+	case "object":
+		return "Dictionary<string, object>"
+	default:
+		return "string"
+	}
+}
+
+func csValueType(csType string) bool {
+	switch csType {
+	case "long", "double", "bool":
+		return true
+	}
+	return false
+}
+
+// csQuote returns a C# regular string literal
+func csQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,263 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// Go generates one struct per table with json tags matching the stored
+// records, plus a Validate method enforcing the schema's constraints
+func Go(tables []Table, opts Options) ([]byte, error) {
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = "gamedata"
+	}
+
+	g := &goGenerator{imports: map[string]bool{"errors": true}}
+	names := TypeNames(tables)
+	for i, table := range tables {
+		g.writeStruct(names[i], table)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by progressive. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&buf, "import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", imp)
+	}
+	fmt.Fprintf(&buf, ")\n")
+
+	if g.vars.Len() > 0 {
+		fmt.Fprintf(&buf, "\nvar (\n%s)\n", g.vars.String())
+	}
+	buf.Write(g.body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go source: %w", err)
+	}
+	return src, nil
+}
+
+type goGenerator struct {
+	imports map[string]bool
+	vars    bytes.Buffer
+	body    bytes.Buffer
+}
+
+type goField struct {
+	name     string
+	ident    string
+	goType   string
+	prop     repository.PropertyDef
+	required bool
+	pointer  bool
+}
+
+func (g *goGenerator) writeStruct(typeName string, table Table) {
+	buf := &g.body
+	fmt.Fprintf(buf, "\n// %s is generated from table %q (%s).\n", typeName, table.Name, table.ID)
+	fmt.Fprintf(buf, "type %s struct {\n", typeName)
+	fmt.Fprintf(buf, "\tRecordID int64 `json:\"_id,omitempty\"`\n")
+
+	var fields []goField
+	used := map[string]bool{"RecordID": true, "Validate": true}
+	for i, name := range table.Schema.PropertyNames() {
+		prop := table.Schema.Properties[name]
+		field := goField{
+			name:     name,
+			ident:    fieldIdent(name, i),
+			goType:   goTypeFor(prop),
+			prop:     prop,
+			required: table.Schema.IsRequired(name),
+		}
+		if used[field.ident] {
+			field.ident += strconv.Itoa(i)
+		}
+		used[field.ident] = true
+
+		tag := name
+		if !field.required {
+			tag += ",omitempty"
+			field.pointer = isGoScalar(field.goType)
+		}
+		fieldType := field.goType
+		if field.pointer {
+			fieldType = "*" + fieldType
+		}
+
+		if comment := commentText(prop); comment != "" {
+			fmt.Fprintf(buf, "\t// %s\n", comment)
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%s`\n", field.ident, fieldType, strconv.Quote(tag))
+		fields = append(fields, field)
+	}
+	fmt.Fprintf(buf, "}\n")
+
+	g.writeValidate(typeName, fields)
+}
+
+func (g *goGenerator) writeValidate(typeName string, fields []goField) {
+	buf := &g.body
+	fmt.Fprintf(buf, "\n// Validate checks the constraints declared in the %s schema.\n", typeName)
+	fmt.Fprintf(buf, "func (r *%s) Validate() error {\n", typeName)
+	fmt.Fprintf(buf, "\tvar errs []error\n")
+
+	for _, f := range fields {
+		expr := "r." + f.ident
+		if f.pointer {
+			expr = "*r." + f.ident
+		}
+
+		var checks []string
+		if f.required && f.goType == "string" {
+			checks = append(checks, fmt.Sprintf("if %s == \"\" {\n\terrs = append(errs, errors.New(%q))\n}", expr, f.name+" is required"))
+		}
+		checks = append(checks, g.constraintChecks(typeName, f, expr)...)
+		if len(checks) == 0 {
+			continue
+		}
+
+		if f.pointer {
+			fmt.Fprintf(buf, "\tif r.%s != nil {\n", f.ident)
+		}
+		for _, check := range checks {
+			buf.WriteString(check)
+			buf.WriteString("\n")
+		}
+		if f.pointer {
+			fmt.Fprintf(buf, "\t}\n")
+		}
+	}
+
+	fmt.Fprintf(buf, "\treturn errors.Join(errs...)\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+// constraintChecks returns Go statements validating one field value
+func (g *goGenerator) constraintChecks(typeName string, f goField, expr string) []string {
+	var checks []string
+	fail := func(cond, message string, args ...string) {
+		g.imports["fmt"] = true
+		params := append([]string{strconv.Quote(f.name + ": " + message)}, args...)
+		checks = append(checks, fmt.Sprintf("if %s {\n\terrs = append(errs, fmt.Errorf(%s))\n}", cond, strings.Join(params, ", ")))
+	}
+
+	if literals, ok := enumLiterals(f.prop, strconv.Quote); ok && isGoScalar(f.goType) {
+		g.imports["fmt"] = true
+		verb := "%v"
+		if f.goType == "string" {
+			verb = "%q"
+		}
+		checks = append(checks, fmt.Sprintf("switch %s {\ncase %s:\ndefault:\n\terrs = append(errs, fmt.Errorf(%q, %s))\n}",
+			expr, strings.Join(literals, ", "), f.name+": invalid value "+verb, expr))
+	}
+
+	switch f.goType {
+	case "string":
+		if f.prop.MinLength > 0 {
+			g.imports["unicode/utf8"] = true
+			fail(fmt.Sprintf("utf8.RuneCountInString(%s) < %d", expr, f.prop.MinLength),
+				fmt.Sprintf("must be at least %d characters", f.prop.MinLength))
+		}
+		if f.prop.MaxLength > 0 {
+			g.imports["unicode/utf8"] = true
+			fail(fmt.Sprintf("utf8.RuneCountInString(%s) > %d", expr, f.prop.MaxLength),
+				fmt.Sprintf("must be at most %d characters", f.prop.MaxLength))
+		}
+		if f.prop.Pattern != "" {
+			if _, err := regexp.Compile(f.prop.Pattern); err == nil {
+				g.imports["regexp"] = true
+				varName := lowerFirst(typeName) + f.ident + "Pattern"
+				fmt.Fprintf(&g.vars, "\t%s = regexp.MustCompile(%s)\n", varName, strconv.Quote(f.prop.Pattern))
+				fail(fmt.Sprintf("%s != \"\" && !%s.MatchString(%s)", expr, varName, expr),
+					"does not match pattern "+strings.ReplaceAll(f.prop.Pattern, "%", "%%"))
+			}
+		}
+		switch f.prop.Format {
+		case "date":
+			g.imports["time"] = true
+			fail(fmt.Sprintf("_, err := time.Parse(\"2006-01-02\", %s); %s != \"\" && err != nil", expr, expr), "invalid date %q", expr)
+		case "date-time":
+			g.imports["time"] = true
+			fail(fmt.Sprintf("_, err := time.Parse(time.RFC3339, %s); %s != \"\" && err != nil", expr, expr), "invalid date-time %q", expr)
+		}
+	case "int64", "float64":
+		lhs := expr
+		if f.goType == "int64" && (hasFraction(f.prop.Minimum) || hasFraction(f.prop.Maximum)) {
+			lhs = "float64(" + expr + ")"
+		}
+		if f.prop.Minimum != nil {
+			bound := goNumber(*f.prop.Minimum, f.goType)
+			fail(fmt.Sprintf("%s < %s", lhs, bound), "must be >= "+bound)
+		}
+		if f.prop.Maximum != nil {
+			bound := goNumber(*f.prop.Maximum, f.goType)
+			fail(fmt.Sprintf("%s > %s", lhs, bound), "must be <= "+bound)
+		}
+	}
+
+	return checks
+}
+
+// goNumber formats a schema bound as a Go literal of the field's type
+func goNumber(v float64, goType string) string {
+	if goType == "int64" && v == float64(int64(v)) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if goType == "float64" && !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+func hasFraction(v *float64) bool {
+	return v != nil && *v != float64(int64(*v))
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func goTypeFor(prop repository.PropertyDef) string {
+	switch prop.Type {
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]any"
+	case "object":
+		return "map[string]any"
+	default:
+		return "string"
+	}
+}
+
+func isGoScalar(goType string) bool {
+	switch goType {
+	case "int64", "float64", "bool", "string":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+var sqlIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// sqlReserved lists reserved words likely to appear as field names
+var sqlReserved = map[string]bool{
+	"all": true, "and": true, "as": true, "check": true, "column": true, "default": true,
+	"desc": true, "end": true, "from": true, "group": true, "limit": true, "order": true,
+	"primary": true, "references": true, "select": true, "table": true, "to": true,
+	"user": true, "where": true, "with": true,
+}
+
+// SQL generates a PostgreSQL CREATE TABLE statement per table. Columns are
+// typed from the schema's type/format and constraints become CHECK clauses;
+// x-ref references to the _id of another generated table become foreign keys.
+func SQL(tables []Table) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "-- Code generated by progressive. DO NOT EDIT.\n")
+
+	tableNames := sqlTableNames(tables)
+	byRef := make(map[string]string)
+	for i, table := range tables {
+		target := fmt.Sprintf("%s (%s)", tableNames[i], sqlPrimaryKey(table.Schema))
+		byRef[table.ID] = target
+		if table.Name != "" {
+			byRef[table.Name] = target
+		}
+	}
+
+	var foreignKeys []string
+	for i, table := range tables {
+		foreignKeys = append(foreignKeys, writeSQLTable(&buf, tableNames[i], table, byRef)...)
+	}
+
+	if len(foreignKeys) > 0 {
+		buf.WriteString("\n")
+		for _, fk := range foreignKeys {
+			buf.WriteString(fk)
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+func writeSQLTable(buf *bytes.Buffer, tableName string, table Table, byRef map[string]string) []string {
+	columns := []string{fmt.Sprintf("%s BIGINT PRIMARY KEY", sqlPrimaryKey(table.Schema))}
+	var comments, foreignKeys []string
+
+	for _, name := range table.Schema.PropertyNames() {
+		prop := table.Schema.Properties[name]
+		column := sqlQuoteIdent(name)
+
+		def := column + " " + sqlTypeFor(prop)
+		if table.Schema.IsRequired(name) {
+			def += " NOT NULL"
+		}
+		for _, check := range sqlChecks(column, prop) {
+			def += " CHECK (" + check + ")"
+		}
+		columns = append(columns, def)
+
+		if comment := commentText(prop); comment != "" {
+			comments = append(comments, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;", tableName, column, sqlQuote(comment)))
+		}
+
+		if prop.Ref == "" {
+			continue
+		}
+		refTable, refField, _ := strings.Cut(prop.Ref, ".")
+		target, ok := byRef[refTable]
+		if ok && refField == "" && prop.Type == "integer" {
+			foreignKeys = append(foreignKeys, fmt.Sprintf("ALTER TABLE %s ADD FOREIGN KEY (%s) REFERENCES %s;",
+				tableName, column, target))
+			continue
+		}
+		// References to non-key fields cannot be expressed as foreign keys
+		// without a unique constraint on the target, so they are documented only.
+		comments = append(comments, fmt.Sprintf("-- %s.%s references %s", tableName, column, prop.Ref))
+	}
+
+	fmt.Fprintf(buf, "\n-- Generated from table %q (%s).\n", table.Name, table.ID)
+	fmt.Fprintf(buf, "CREATE TABLE %s (\n    %s\n);\n", tableName, strings.Join(columns, ",\n    "))
+	for _, comment := range comments {
+		buf.WriteString(comment)
+		buf.WriteString("\n")
+	}
+	return foreignKeys
+}
+
+// sqlPrimaryKey names the record id column, avoiding a clash with an "id" property
+func sqlPrimaryKey(schema *repository.SchemaDefinition) string {
+	if _, ok := schema.Properties["id"]; ok {
+		return "record_id"
+	}
+	return "id"
+}
+
+func sqlTypeFor(prop repository.PropertyDef) string {
+	switch prop.Type {
+	case "integer":
+		if fitsInt32(prop.Minimum) && fitsInt32(prop.Maximum) && prop.Minimum != nil && prop.Maximum != nil {
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case "number":
+		return "DOUBLE PRECISION"
+	case "boolean":
+		return "BOOLEAN"
+	case "array", "object":
+		return "JSONB"
+	}
+
+	switch prop.Format {
+	case "date":
+		return "DATE"
+	case "date-time":
+		return "TIMESTAMPTZ"
+	case "time":
+		return "TIME"
+	}
+	if prop.MaxLength > 0 && len(prop.Enum) == 0 {
+		return fmt.Sprintf("VARCHAR(%d)", prop.MaxLength)
+	}
+	return "TEXT"
+}
+
+// sqlChecks returns the CHECK expressions for a column
+func sqlChecks(column string, prop repository.PropertyDef) []string {
+	var checks []string
+
+	if literals, ok := enumLiterals(prop, sqlQuote); ok {
+		checks = append(checks, fmt.Sprintf("%s IN (%s)", column, strings.Join(literals, ", ")))
+	}
+
+	switch prop.Type {
+	case "integer", "number":
+		switch {
+		case prop.Minimum != nil && prop.Maximum != nil:
+			checks = append(checks, fmt.Sprintf("%s BETWEEN %s AND %s", column, sqlNumber(*prop.Minimum), sqlNumber(*prop.Maximum)))
+		case prop.Minimum != nil:
+			checks = append(checks, fmt.Sprintf("%s >= %s", column, sqlNumber(*prop.Minimum)))
+		case prop.Maximum != nil:
+			checks = append(checks, fmt.Sprintf("%s <= %s", column, sqlNumber(*prop.Maximum)))
+		}
+	case "string", "":
+		if prop.MinLength > 0 && prop.Format == "" {
+			checks = append(checks, fmt.Sprintf("char_length(%s) >= %d", column, prop.MinLength))
+		}
+	}
+
+	return checks
+}
+
+// sqlTableNames assigns each table a unique snake_case identifier
+func sqlTableNames(tables []Table) []string {
+	names := make([]string, len(tables))
+	seen := make(map[string]int)
+	for i, table := range tables {
+		name := table.Name
+		if !sqlIdentifier.MatchString(name) || sqlReserved[name] {
+			name = strings.ToLower(table.ID)
+		}
+		if !sqlIdentifier.MatchString(name) {
+			name = "table_" + strconv.Itoa(i)
+		}
+		seen[name]++
+		if seen[name] > 1 {
+			name += "_" + strconv.Itoa(seen[name])
+		}
+		names[i] = name
+	}
+	return names
+}
+
+func sqlQuoteIdent(name string) string {
+	if sqlIdentifier.MatchString(name) && !sqlReserved[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sqlNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func fitsInt32(v *float64) bool {
+	return v == nil || (*v >= math.MinInt32 && *v <= math.MaxInt32)
+}
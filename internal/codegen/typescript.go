@@ -0,0 +1,71 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript generates one exported interface per table
+func TypeScript(tables []Table) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by progressive. DO NOT EDIT.\n")
+
+	names := TypeNames(tables)
+	for i, table := range tables {
+		writeTSInterface(&buf, names[i], table)
+	}
+
+	return buf.Bytes()
+}
+
+func writeTSInterface(buf *bytes.Buffer, typeName string, table Table) {
+	fmt.Fprintf(buf, "\n/** Generated from table %q (%s). */\n", table.Name, table.ID)
+	fmt.Fprintf(buf, "export interface %s {\n", typeName)
+	fmt.Fprintf(buf, "  _id?: number;\n")
+
+	for _, name := range table.Schema.PropertyNames() {
+		prop := table.Schema.Properties[name]
+
+		if comment := commentText(prop); comment != "" {
+			fmt.Fprintf(buf, "  /** %s */\n", strings.ReplaceAll(comment, "*/", "*\\/"))
+		}
+
+		key := name
+		if !tsIdentifier.MatchString(name) {
+			key = strconv.Quote(name)
+		}
+		optional := ""
+		if !table.Schema.IsRequired(name) {
+			optional = "?"
+		}
+		fmt.Fprintf(buf, "  %s%s: %s;\n", key, optional, tsTypeFor(prop))
+	}
+
+	fmt.Fprintf(buf, "}\n")
+}
+
+func tsTypeFor(prop repository.PropertyDef) string {
+	if literals, ok := enumLiterals(prop, strconv.Quote); ok {
+		return strings.Join(literals, " | ")
+	}
+
+	switch prop.Type {
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		return "unknown[]"
+	case "object":
+		return "Record<string, unknown>"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,332 @@
+// Package config loads the web server configuration from a YAML file,
+// PROGRESSIVE_* environment variables and command-line flags.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/netip"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"progressive/internal/infrastructure"
+	"progressive/internal/logging"
+	"progressive/internal/storage"
+)
+
+// ErrInvalid is wrapped by every validation error
+var ErrInvalid = errors.New("invalid configuration")
+
+// redacted replaces secrets in printed configuration
+const redacted = "REDACTED"
+
+// Config is the complete server configuration
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Log      LogConfig      `yaml:"log"`
+	Backup   BackupConfig   `yaml:"backup"`
+	Features FeaturesConfig `yaml:"features"`
+}
+
+// ServerConfig configures the HTTP listener
+type ServerConfig struct {
+	Addr         string        `yaml:"addr"`
+	TLSCert      string        `yaml:"tls_cert"`
+	TLSKey       string        `yaml:"tls_key"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout bounds draining requests and background jobs on SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// DrainDelay keeps serving with /readyz failing before the listener closes
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// TrustedProxies lists the IPs or CIDRs whose X-Forwarded-For is believed
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// DatabaseConfig selects the storage backend. With the postgres backend an
+// empty DSN starts embedded PostgreSQL; otherwise the DSN is used as is.
+type DatabaseConfig struct {
+	Storage    string         `yaml:"storage"`
+	DSN        string         `yaml:"dsn"`
+	SQLitePath string         `yaml:"sqlite_path"`
+	Pool       PoolConfig     `yaml:"pool"`
+	Embedded   EmbeddedConfig `yaml:"embedded"`
+}
+
+// PoolConfig limits PostgreSQL connections
+type PoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// EmbeddedConfig configures embedded PostgreSQL
+type EmbeddedConfig struct {
+	Port     uint32 `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+	// Persistent keeps the cluster in DataDir across restarts
+	Persistent bool   `yaml:"persistent"`
+	DataDir    string `yaml:"data_dir"`
+	// RuntimeDir holds the extracted binaries and is wiped on every start;
+	// empty uses the embedded-postgres default next to its download cache
+	RuntimeDir string `yaml:"runtime_dir"`
+}
+
+// BackupConfig configures the backup command and scheduled backups
+type BackupConfig struct {
+	Dir string `yaml:"dir"`
+	// Interval between scheduled backups; 0 disables them
+	Interval time.Duration `yaml:"interval"`
+	// Keep is how many backups scheduled runs retain; 0 keeps all
+	Keep int `yaml:"keep"`
+}
+
+// LogConfig configures the process logger
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// FeaturesConfig switches optional feature routes on or off
+type FeaturesConfig struct {
+	Audit     bool `yaml:"audit"`
+	Comments  bool `yaml:"comments"`
+	Branches  bool `yaml:"branches"`
+	Snapshots bool `yaml:"snapshots"`
+	Publish   bool `yaml:"publish"`
+	GraphQL   bool `yaml:"graphql"`
+}
+
+// Default returns the configuration used when nothing is set
+func Default() *Config {
+	pool := infrastructure.DefaultPool()
+	return &Config{
+		Server: ServerConfig{
+			Addr:            ":8081",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Storage:    storage.Postgres,
+			SQLitePath: "progressive.db",
+			Pool: PoolConfig{
+				MaxOpenConns:    pool.MaxOpenConns,
+				MaxIdleConns:    pool.MaxIdleConns,
+				ConnMaxLifetime: pool.ConnMaxLifetime,
+			},
+			Embedded: EmbeddedConfig{
+				Port:       5432,
+				Username:   "postgres",
+				Password:   "postgres",
+				Database:   "progressive",
+				Persistent: true,
+				DataDir:    "data/postgres",
+			},
+		},
+		Log:    LogConfig{Level: "info", Format: logging.FormatConsole},
+		Backup: BackupConfig{Dir: "data/backups", Keep: 7},
+		Features: FeaturesConfig{
+			Audit:     true,
+			Comments:  true,
+			Branches:  true,
+			Snapshots: true,
+			Publish:   true,
+			GraphQL:   true,
+		},
+	}
+}
+
+// Validate reports every problem at once
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		invalid("server.addr %q must be host:port", c.Server.Addr)
+	}
+	if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+		invalid("server.tls_cert and server.tls_key must be set together")
+	}
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.DrainDelay < 0 {
+		invalid("server timeouts cannot be negative")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		invalid("server.shutdown_timeout must be positive")
+	} else if c.Server.DrainDelay >= c.Server.ShutdownTimeout {
+		invalid("server.drain_delay (%v) must be shorter than shutdown_timeout (%v)", c.Server.DrainDelay, c.Server.ShutdownTimeout)
+	}
+	if _, err := c.Server.TrustedProxyPrefixes(); err != nil {
+		invalid("server.trusted_proxies: %v", err)
+	}
+
+	db := c.Database
+	switch db.Storage {
+	case storage.Postgres:
+		if db.DSN == "" {
+			if db.Embedded.Port == 0 {
+				invalid("database.embedded.port must be set")
+			}
+			if db.Embedded.Username == "" || db.Embedded.Database == "" {
+				invalid("database.embedded.username and database.embedded.database must be set")
+			}
+			if db.Embedded.Persistent && db.Embedded.DataDir == "" {
+				invalid("database.embedded.data_dir must be set when persistent is true")
+			}
+			if db.Embedded.Persistent && db.Embedded.RuntimeDir != "" && within(db.Embedded.DataDir, db.Embedded.RuntimeDir) {
+				invalid("database.embedded.data_dir must not be inside runtime_dir, which is wiped on start")
+			}
+		}
+	case storage.SQLite:
+		if db.SQLitePath == "" {
+			invalid("database.sqlite_path must be set for the sqlite backend")
+		}
+		if db.DSN != "" {
+			invalid("database.dsn cannot be used with the sqlite backend")
+		}
+	default:
+		invalid("database.storage %q must be %s or %s", db.Storage, storage.Postgres, storage.SQLite)
+	}
+	if db.Pool.MaxOpenConns < 0 || db.Pool.MaxIdleConns < 0 || db.Pool.ConnMaxLifetime < 0 {
+		invalid("database.pool values cannot be negative")
+	}
+	if db.Pool.MaxOpenConns > 0 && db.Pool.MaxIdleConns > db.Pool.MaxOpenConns {
+		invalid("database.pool.max_idle_conns (%d) cannot exceed max_open_conns (%d)", db.Pool.MaxIdleConns, db.Pool.MaxOpenConns)
+	}
+
+	if c.Backup.Interval < 0 || c.Backup.Keep < 0 {
+		invalid("backup.interval and backup.keep cannot be negative")
+	}
+	if c.Backup.Interval > 0 && c.Backup.Dir == "" {
+		invalid("backup.dir must be set for scheduled backups")
+	}
+
+	if _, err := c.Log.level(); err != nil {
+		invalid("log.level %q must be debug, info, warn or error", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case logging.FormatConsole, logging.FormatText, logging.FormatJSON:
+	default:
+		invalid("log.format %q must be console, text or json", c.Log.Format)
+	}
+
+	return errors.Join(errs...)
+}
+
+// within reports whether path is dir or below it
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
+
+// Redacted returns a copy that is safe to print
+func (c *Config) Redacted() *Config {
+	copied := *c
+	if copied.Database.DSN != "" {
+		copied.Database.DSN = redactDSN(copied.Database.DSN)
+	}
+	if copied.Database.Embedded.Password != "" {
+		copied.Database.Embedded.Password = redacted
+	}
+	return &copied
+}
+
+// dsnPassword matches password=... in key/value connection strings
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// redactDSN hides the password in URL and key/value connection strings
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", redacted)
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redacted)
+}
+
+// TLS reports whether the server should listen with TLS
+func (c *Config) TLS() bool {
+	return c.Server.TLSCert != ""
+}
+
+// TrustedProxyPrefixes parses TrustedProxies; a bare IP is a single-address prefix
+func (s ServerConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(s.TrustedProxies))
+	for _, proxy := range s.TrustedProxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP or CIDR", proxy)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// StorageOptions converts the database section for storage.Open
+func (c *Config) StorageOptions() storage.Options {
+	db := c.Database
+	opts := storage.Options{
+		Backend:    db.Storage,
+		SQLitePath: db.SQLitePath,
+		DSN:        db.DSN,
+		Pool: infrastructure.PoolConfig{
+			MaxOpenConns:    db.Pool.MaxOpenConns,
+			MaxIdleConns:    db.Pool.MaxIdleConns,
+			ConnMaxLifetime: db.Pool.ConnMaxLifetime,
+		},
+		Postgres: []infrastructure.Option{
+			infrastructure.WithConfig(infrastructure.Config{
+				Host:     "localhost",
+				Port:     db.Embedded.Port,
+				Username: db.Embedded.Username,
+				Password: db.Embedded.Password,
+				Database: db.Embedded.Database,
+			}),
+			infrastructure.WithAutoPortDiscovery(10), // 최대 10개 포트 시도
+		},
+	}
+	if db.Embedded.Persistent {
+		opts.Postgres = append(opts.Postgres, infrastructure.WithDataDir(db.Embedded.DataDir))
+	}
+	if db.Embedded.RuntimeDir != "" {
+		opts.Postgres = append(opts.Postgres, infrastructure.WithRuntimeDir(db.Embedded.RuntimeDir))
+	}
+	return opts
+}
+
+// Logger builds a slog logger writing to w at the configured level and
+// format; records logged with a request context carry its request ID
+func (l LogConfig) Logger(w io.Writer) *slog.Logger {
+	level, _ := l.level()
+	return logging.New(w, l.Format, level)
+}
+
+func (l LogConfig) level() (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.ToUpper(l.Level)))
+	return level, err
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"progressive/internal/storage"
+)
+
+func load(t *testing.T, args []string, env map[string]string) (*Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	return Load(fs, args, func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	})
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progressive.yaml")
+	file := "server:\n  addr: \":7000\"\ndatabase:\n  pool:\n    conn_max_lifetime: 90s\nlog:\n  level: warn\n  format: json\n"
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := load(t, []string{"-log-level", "debug", "-feature-audit=false"}, map[string]string{
+		ConfigEnv:                            path,
+		"PROGRESSIVE_SERVER_ADDR":            ":7001",
+		"PROGRESSIVE_LOG_LEVEL":              "error",
+		"PROGRESSIVE_SERVER_TRUSTED_PROXIES": "10.0.0.0/8, 192.0.2.1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Server.Addr != ":7001" {
+		t.Errorf("Expected env to override the file, got addr %q", cfg.Server.Addr)
+	}
+	if prefixes, err := cfg.Server.TrustedProxyPrefixes(); err != nil || len(prefixes) != 2 || prefixes[1].String() != "192.0.2.1/32" {
+		t.Errorf("Expected the proxy list from env, got %v (%v)", prefixes, err)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Expected flag to override env, got level %q", cfg.Log.Level)
+	}
+	if cfg.Log.Format != "json" || cfg.Database.Pool.ConnMaxLifetime != 90*time.Second {
+		t.Errorf("Expected file values to override defaults, got %+v", cfg)
+	}
+	if cfg.Features.Audit || !cfg.Features.Comments {
+		t.Errorf("Expected only audit to be disabled, got %+v", cfg.Features)
+	}
+	if cfg.Database.Embedded.Username != "postgres" {
+		t.Errorf("Expected defaults to survive, got username %q", cfg.Database.Embedded.Username)
+	}
+}
+
+func TestLoadRejectsUnknownKeysAndBadEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progressive.yaml")
+	os.WriteFile(path, []byte("server:\n  adr: \":7000\"\n"), 0o644)
+	if _, err := load(t, []string{"-config", path}, nil); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected unknown key to be rejected, got: %v", err)
+	}
+
+	if _, err := load(t, nil, map[string]string{"PROGRESSIVE_DATABASE_POOL_MAX_OPEN_CONNS": "many"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected a non-numeric pool size to be rejected, got: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Expected defaults to be valid, got: %v", err)
+	}
+
+	cfg := Default()
+	cfg.Server.Addr = "8081"
+	cfg.Server.TLSCert = "cert.pem"
+	cfg.Server.DrainDelay = time.Minute
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy.internal"}
+	cfg.Database.Storage = storage.SQLite
+	cfg.Database.DSN = "postgres://localhost/p"
+	cfg.Database.Pool.MaxIdleConns = 50
+	cfg.Log.Format = "xml"
+
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Expected validation to fail, got: %v", err)
+	}
+	for _, want := range []string{"server.addr", "tls_key", "drain_delay", "trusted_proxies", "database.dsn", "max_idle_conns", "log.format"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cases := map[string]string{
+		"postgres://admin:hunter2@db:5432/p?sslmode=disable": "postgres://admin:REDACTED@db:5432/p?sslmode=disable",
+		"postgres://db/p?password=hunter2":                   "postgres://db/p?password=REDACTED",
+		"host=db user=admin password=hunter2 dbname=p":       "host=db user=admin password=REDACTED dbname=p",
+		"host=db password='hunter 2' dbname=p":               "host=db password=REDACTED dbname=p",
+	}
+	for dsn, want := range cases {
+		cfg := Default()
+		cfg.Database.DSN = dsn
+		if got := cfg.Redacted().Database.DSN; got != want {
+			t.Errorf("redact(%q) = %q, want %q", dsn, got, want)
+		}
+		if cfg.Database.DSN != dsn {
+			t.Errorf("Expected Redacted to leave the original untouched, got %q", cfg.Database.DSN)
+		}
+	}
+
+	out, err := Default().Redacted().YAML()
+	if err != nil || strings.Contains(string(out), "password: postgres") {
+		t.Errorf("Expected embedded password to be redacted, got %s: %v", out, err)
+	}
+}
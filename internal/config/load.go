@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts every environment variable read by Load
+const EnvPrefix = "PROGRESSIVE_"
+
+// ConfigEnv names the configuration file when -config is not given
+const ConfigEnv = EnvPrefix + "CONFIG"
+
+// setting binds one configuration value to its flag and environment variable
+type setting struct {
+	key   string // dotted YAML path, e.g. server.addr
+	flag  string
+	usage string
+	value flag.Value
+}
+
+// env returns the environment variable for the setting, e.g. PROGRESSIVE_SERVER_ADDR
+func (s setting) env() string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_").Replace(s.key))
+}
+
+// settings lists every flag and environment variable bound into c
+func settings(c *Config) []setting {
+	return []setting{
+		{"server.addr", "addr", "HTTP listen address", (*stringValue)(&c.Server.Addr)},
+		{"server.tls_cert", "tls-cert", "TLS certificate file (enables HTTPS with -tls-key)", (*stringValue)(&c.Server.TLSCert)},
+		{"server.tls_key", "tls-key", "TLS private key file", (*stringValue)(&c.Server.TLSKey)},
+		{"server.read_timeout", "read-timeout", "maximum time to read a request (0: none)", (*durationValue)(&c.Server.ReadTimeout)},
+		{"server.write_timeout", "write-timeout", "maximum time to write a response (0: none)", (*durationValue)(&c.Server.WriteTimeout)},
+		{"server.idle_timeout", "idle-timeout", "maximum time a keep-alive connection stays idle (0: read timeout)", (*durationValue)(&c.Server.IdleTimeout)},
+		{"server.shutdown_timeout", "shutdown-timeout", "maximum time to drain requests and jobs on shutdown", (*durationValue)(&c.Server.ShutdownTimeout)},
+		{"server.drain_delay", "drain-delay", "time /readyz fails before the listener closes on shutdown", (*durationValue)(&c.Server.DrainDelay)},
+		{"server.trusted_proxies", "trusted-proxies", "comma-separated proxy IPs or CIDRs whose X-Forwarded-For is trusted", (*listValue)(&c.Server.TrustedProxies)},
+		{"database.storage", "storage", "storage backend: postgres or sqlite", (*stringValue)(&c.Database.Storage)},
+		{"database.dsn", "dsn", "PostgreSQL DSN of an external database (default: start embedded PostgreSQL)", (*stringValue)(&c.Database.DSN)},
+		{"database.sqlite_path", "sqlite-path", "SQLite database file used by -storage=sqlite", (*stringValue)(&c.Database.SQLitePath)},
+		{"database.pool.max_open_conns", "db-max-open-conns", "maximum open PostgreSQL connections (0: unlimited)", (*intValue)(&c.Database.Pool.MaxOpenConns)},
+		{"database.pool.max_idle_conns", "db-max-idle-conns", "maximum idle PostgreSQL connections", (*intValue)(&c.Database.Pool.MaxIdleConns)},
+		{"database.pool.conn_max_lifetime", "db-conn-max-lifetime", "maximum lifetime of a PostgreSQL connection", (*durationValue)(&c.Database.Pool.ConnMaxLifetime)},
+		{"database.embedded.port", "embedded-port", "first port tried for embedded PostgreSQL", (*uint32Value)(&c.Database.Embedded.Port)},
+		{"database.embedded.username", "embedded-username", "embedded PostgreSQL user", (*stringValue)(&c.Database.Embedded.Username)},
+		{"database.embedded.password", "embedded-password", "embedded PostgreSQL password", (*stringValue)(&c.Database.Embedded.Password)},
+		{"database.embedded.database", "embedded-database", "embedded PostgreSQL database name", (*stringValue)(&c.Database.Embedded.Database)},
+		{"database.embedded.persistent", "persistent", "keep embedded PostgreSQL data in -data-dir across restarts", (*boolValue)(&c.Database.Embedded.Persistent)},
+		{"database.embedded.data_dir", "data-dir", "embedded PostgreSQL data directory", (*stringValue)(&c.Database.Embedded.DataDir)},
+		{"database.embedded.runtime_dir", "runtime-dir", "directory the embedded PostgreSQL binaries are extracted to (wiped on start)", (*stringValue)(&c.Database.Embedded.RuntimeDir)},
+		{"log.level", "log-level", "log level: debug, info, warn or error", (*stringValue)(&c.Log.Level)},
+		{"log.format", "log-format", "log format: console (development), text or json (production)", (*stringValue)(&c.Log.Format)},
+		{"backup.dir", "backup-dir", "directory for backup files", (*stringValue)(&c.Backup.Dir)},
+		{"backup.interval", "backup-interval", "interval between scheduled backups (0: disabled)", (*durationValue)(&c.Backup.Interval)},
+		{"backup.keep", "backup-keep", "number of scheduled backups to keep (0: all)", (*intValue)(&c.Backup.Keep)},
+		{"features.audit", "feature-audit", "record mutating requests in the audit log", (*boolValue)(&c.Features.Audit)},
+		{"features.comments", "feature-comments", "enable comment threads", (*boolValue)(&c.Features.Comments)},
+		{"features.branches", "feature-branches", "enable branches and merge requests", (*boolValue)(&c.Features.Branches)},
+		{"features.snapshots", "feature-snapshots", "enable snapshots and promotions", (*boolValue)(&c.Features.Snapshots)},
+		{"features.publish", "feature-publish", "enable the publish API", (*boolValue)(&c.Features.Publish)},
+		{"features.graphql", "feature-graphql", "enable the GraphQL endpoint", (*boolValue)(&c.Features.GraphQL)},
+	}
+}
+
+// Load builds the configuration from defaults, the YAML file named by
+// -config or PROGRESSIVE_CONFIG, PROGRESSIVE_* environment variables and
+// flags, each overriding the one before. The flags are registered on fs so
+// subcommands can add their own before calling Load.
+func Load(fs *flag.FlagSet, args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	parsed := Default()
+	configPath := fs.String("config", "", "YAML configuration file (env: "+ConfigEnv+")")
+	for _, s := range settings(parsed) {
+		fs.Var(s.value, s.flag, fmt.Sprintf("%s (env: %s)", s.usage, s.env()))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+	path := *configPath
+	if path == "" {
+		path, _ = lookupEnv(ConfigEnv)
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	current := settings(cfg)
+	byFlag := make(map[string]setting, len(current))
+	for _, s := range current {
+		byFlag[s.flag] = s
+		if value, ok := lookupEnv(s.env()); ok {
+			if err := s.value.Set(value); err != nil {
+				return nil, fmt.Errorf("%w: %s=%q: %v", ErrInvalid, s.env(), value, err)
+			}
+		}
+	}
+
+	// 명시적으로 지정한 플래그만 덮어쓴다 (기본값이 파일/환경 변수를 가리지 않도록)
+	fs.Visit(func(f *flag.Flag) {
+		if s, ok := byFlag[f.Name]; ok {
+			s.value.Set(f.Value.String())
+		}
+	})
+	return cfg, nil
+}
+
+// loadFile overlays the YAML file at path; unknown keys are rejected
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+	return nil
+}
+
+// YAML renders the configuration in the config file format
+func (c *Config) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), encoder.Close()
+}
+
+type stringValue string
+
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+func (v *stringValue) String() string     { return string(*v) }
+
+type listValue []string
+
+func (v *listValue) Set(s string) error {
+	*v = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*v = append(*v, item)
+		}
+	}
+	return nil
+}
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+
+type intValue int
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	*v = intValue(n)
+	return err
+}
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+type uint32Value uint32
+
+func (v *uint32Value) Set(s string) error {
+	n, err := strconv.ParseUint(s, 10, 32)
+	*v = uint32Value(n)
+	return err
+}
+func (v *uint32Value) String() string { return strconv.FormatUint(uint64(*v), 10) }
+
+type boolValue bool
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	*v = boolValue(b)
+	return err
+}
+func (v *boolValue) String() string   { return strconv.FormatBool(bool(*v)) }
+func (v *boolValue) IsBoolFlag() bool { return true }
+
+type durationValue time.Duration
+
+func (v *durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	*v = durationValue(d)
+	return err
+}
+func (v *durationValue) String() string { return time.Duration(*v).String() }
@@ -0,0 +1,267 @@
+// Package diff compares table contents at the record and field level.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change describes how an item differs between two states
+type Change string
+
+const (
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Modified  Change = "modified"
+	Unchanged Change = "unchanged"
+)
+
+// Record is one row of table data keyed by its record ID
+type Record struct {
+	ID   int64                  `json:"_id"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// Table is a table's schema and records at one point in time
+type Table struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Schema  json.RawMessage `json:"schema"`
+	Records []Record        `json:"records"`
+}
+
+// FieldChange is a single field that differs between two versions of a record
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Change Change      `json:"change"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// RecordDiff lists the field changes of one record
+type RecordDiff struct {
+	RecordID int64         `json:"record_id"`
+	Change   Change        `json:"change"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// Summary counts record changes
+type Summary struct {
+	Added    int `json:"added"`
+	Removed  int `json:"removed"`
+	Modified int `json:"modified"`
+}
+
+// TableDiff lists the record changes of one table
+type TableDiff struct {
+	TableID       string       `json:"table_id"`
+	Name          string       `json:"name"`
+	Change        Change       `json:"change"`
+	SchemaChanged bool         `json:"schema_changed"`
+	Summary       Summary      `json:"summary"`
+	Records       []RecordDiff `json:"records,omitempty"`
+}
+
+// Result is the difference between two sets of tables
+type Result struct {
+	Tables  []TableDiff `json:"tables"`
+	Summary Summary     `json:"summary"`
+}
+
+// HasChanges reports whether anything differs
+func (r *Result) HasChanges() bool {
+	for _, t := range r.Tables {
+		if t.Change != Unchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Tables compares two sets of tables matched by table ID. Tables only present
+// in from are reported as removed, tables only present in to as added.
+func Tables(from, to []Table) *Result {
+	fromByID := make(map[string]Table, len(from))
+	for _, t := range from {
+		fromByID[t.ID] = t
+	}
+	toByID := make(map[string]Table, len(to))
+	for _, t := range to {
+		toByID[t.ID] = t
+	}
+
+	ids := make([]string, 0, len(fromByID)+len(toByID))
+	for id := range fromByID {
+		ids = append(ids, id)
+	}
+	for id := range toByID {
+		if _, ok := fromByID[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	result := &Result{Tables: make([]TableDiff, 0, len(ids))}
+	for _, id := range ids {
+		oldTable, inFrom := fromByID[id]
+		newTable, inTo := toByID[id]
+
+		var td TableDiff
+		switch {
+		case !inTo:
+			td = TableDiff{TableID: id, Name: oldTable.Name, Change: Removed, Records: Records(oldTable.Records, nil)}
+		case !inFrom:
+			td = TableDiff{TableID: id, Name: newTable.Name, Change: Added, Records: Records(nil, newTable.Records)}
+		default:
+			td = TableDiff{
+				TableID:       id,
+				Name:          newTable.Name,
+				SchemaChanged: !jsonEqual(oldTable.Schema, newTable.Schema),
+				Records:       Records(oldTable.Records, newTable.Records),
+			}
+			td.Change = Unchanged
+			if td.SchemaChanged || len(td.Records) > 0 || oldTable.Name != newTable.Name {
+				td.Change = Modified
+			}
+		}
+
+		td.Summary = summarize(td.Records)
+		result.Summary.Added += td.Summary.Added
+		result.Summary.Removed += td.Summary.Removed
+		result.Summary.Modified += td.Summary.Modified
+		result.Tables = append(result.Tables, td)
+	}
+
+	return result
+}
+
+// Records compares two record lists by record ID and returns only the records
+// that differ, ordered by ID
+func Records(from, to []Record) []RecordDiff {
+	fromByID := make(map[int64]Record, len(from))
+	for _, r := range from {
+		fromByID[r.ID] = r
+	}
+	toByID := make(map[int64]Record, len(to))
+	for _, r := range to {
+		toByID[r.ID] = r
+	}
+
+	var diffs []RecordDiff
+	for id, oldRecord := range fromByID {
+		newRecord, ok := toByID[id]
+		if !ok {
+			diffs = append(diffs, RecordDiff{RecordID: id, Change: Removed, Fields: Fields(oldRecord.Data, nil)})
+			continue
+		}
+		if fields := Fields(oldRecord.Data, newRecord.Data); len(fields) > 0 {
+			diffs = append(diffs, RecordDiff{RecordID: id, Change: Modified, Fields: fields})
+		}
+	}
+	for id, newRecord := range toByID {
+		if _, ok := fromByID[id]; !ok {
+			diffs = append(diffs, RecordDiff{RecordID: id, Change: Added, Fields: Fields(nil, newRecord.Data)})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].RecordID < diffs[j].RecordID })
+	return diffs
+}
+
+// Fields compares two records field by field and returns the differing fields
+// ordered by name
+func Fields(from, to map[string]interface{}) []FieldChange {
+	var changes []FieldChange
+	for field, oldValue := range from {
+		newValue, ok := to[field]
+		switch {
+		case !ok:
+			changes = append(changes, FieldChange{Field: field, Change: Removed, Old: oldValue})
+		case !ValueEqual(oldValue, newValue):
+			changes = append(changes, FieldChange{Field: field, Change: Modified, Old: oldValue, New: newValue})
+		}
+	}
+	for field, newValue := range to {
+		if _, ok := from[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, Change: Added, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// ValueEqual compares two decoded JSON values. Numbers are compared by value
+// so that int64 and float64 representations of the same number are equal.
+func ValueEqual(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func summarize(records []RecordDiff) Summary {
+	var s Summary
+	for _, r := range records {
+		switch r.Change {
+		case Added:
+			s.Added++
+		case Removed:
+			s.Removed++
+		case Modified:
+			s.Modified++
+		}
+	}
+	return s
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// normalize round-trips nested values through JSON so that containers built
+// in Go compare equal to the same containers decoded from the database
+func normalize(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, string, bool:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
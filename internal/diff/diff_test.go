@@ -0,0 +1,58 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTablesReportsRecordAndFieldChanges(t *testing.T) {
+	from := []Table{
+		{ID: "table_item", Name: "game_item", Schema: json.RawMessage(`{"type": "object"}`), Records: []Record{
+			{ID: 1, Data: map[string]interface{}{"code": "potion", "price": float64(10)}},
+			{ID: 2, Data: map[string]interface{}{"code": "sword", "price": float64(100)}},
+		}},
+		{ID: "table_old", Name: "old", Records: []Record{{ID: 9, Data: map[string]interface{}{"x": true}}}},
+	}
+	to := []Table{
+		{ID: "table_item", Name: "game_item", Schema: json.RawMessage(`{ "type":"object" }`), Records: []Record{
+			{ID: 1, Data: map[string]interface{}{"code": "potion", "price": int64(10)}},
+			{ID: 2, Data: map[string]interface{}{"code": "sword", "price": float64(120), "tier": "rare"}},
+			{ID: 3, Data: map[string]interface{}{"code": "shield"}},
+		}},
+	}
+
+	result := Tables(from, to)
+	if len(result.Tables) != 2 {
+		t.Fatalf("Expected 2 table diffs, got: %d", len(result.Tables))
+	}
+
+	item := result.Tables[0]
+	if item.TableID != "table_item" || item.Change != Modified || item.SchemaChanged {
+		t.Errorf("Expected modified table_item without schema change, got: %+v", item)
+	}
+	if item.Summary != (Summary{Added: 1, Modified: 1}) {
+		t.Errorf("Expected 1 added and 1 modified record, got: %+v", item.Summary)
+	}
+
+	sword := item.Records[0]
+	if sword.RecordID != 2 || len(sword.Fields) != 2 {
+		t.Fatalf("Expected record 2 with 2 field changes, got: %+v", sword)
+	}
+	if sword.Fields[0].Field != "price" || sword.Fields[0].Old != float64(100) || sword.Fields[0].New != float64(120) {
+		t.Errorf("Unexpected price change: %+v", sword.Fields[0])
+	}
+	if sword.Fields[1].Field != "tier" || sword.Fields[1].Change != Added {
+		t.Errorf("Unexpected tier change: %+v", sword.Fields[1])
+	}
+
+	if result.Tables[1].Change != Removed || result.Summary.Removed != 1 {
+		t.Errorf("Expected table_old to be removed, got: %+v", result.Tables[1])
+	}
+}
+
+func TestTablesWithoutChanges(t *testing.T) {
+	tables := []Table{{ID: "t", Records: []Record{{ID: 1, Data: map[string]interface{}{"tags": []interface{}{"a"}}}}}}
+	if result := Tables(tables, tables); result.HasChanges() {
+		t.Errorf("Expected no changes, got: %+v", result)
+	}
+}
@@ -0,0 +1,64 @@
+package diff
+
+import "sort"
+
+// MergeResult is the outcome of a three-way merge of one record
+type MergeResult struct {
+	Merged    map[string]interface{}
+	Conflicts []string
+}
+
+// Merge3 merges the changes made on two sides of a record since their common
+// base. A field changed on only one side takes that side's value; a field
+// changed on both sides to different values is a conflict and keeps ours.
+// Missing fields are treated as a distinct value, so removing a field is a change.
+func Merge3(base, ours, theirs map[string]interface{}) MergeResult {
+	fields := make(map[string]bool)
+	for _, m := range []map[string]interface{}{base, ours, theirs} {
+		for field := range m {
+			fields[field] = true
+		}
+	}
+
+	result := MergeResult{Merged: make(map[string]interface{}, len(fields))}
+	for field := range fields {
+		b, inBase := base[field]
+		o, inOurs := ours[field]
+		t, inTheirs := theirs[field]
+
+		useTheirs := false
+		switch {
+		case sameValue(t, inTheirs, b, inBase):
+			// only ours changed (or neither)
+		case sameValue(o, inOurs, b, inBase):
+			useTheirs = true
+		case sameValue(o, inOurs, t, inTheirs):
+			// both sides made the same change
+		default:
+			result.Conflicts = append(result.Conflicts, field)
+		}
+
+		if useTheirs {
+			if inTheirs {
+				result.Merged[field] = t
+			}
+		} else if inOurs {
+			result.Merged[field] = o
+		}
+	}
+
+	sort.Strings(result.Conflicts)
+	return result
+}
+
+// RecordEqual compares two records field by field using ValueEqual
+func RecordEqual(a, b map[string]interface{}) bool {
+	return len(Fields(a, b)) == 0
+}
+
+func sameValue(a interface{}, aOK bool, b interface{}, bOK bool) bool {
+	if aOK != bOK {
+		return false
+	}
+	return !aOK || ValueEqual(a, b)
+}
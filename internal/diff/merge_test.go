@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge3CombinesNonOverlappingChanges(t *testing.T) {
+	base := map[string]interface{}{"code": "sword", "price": float64(100), "tier": "common", "weight": float64(3)}
+	ours := map[string]interface{}{"code": "sword", "price": float64(120), "tier": "common", "weight": float64(3)}
+	theirs := map[string]interface{}{"code": "sword", "price": float64(100), "tier": "rare", "new": true}
+
+	result := Merge3(base, ours, theirs)
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("Expected no conflicts, got: %v", result.Conflicts)
+	}
+	expected := map[string]interface{}{"code": "sword", "price": float64(120), "tier": "rare", "new": true}
+	if !reflect.DeepEqual(result.Merged, expected) {
+		t.Errorf("Expected %v, got: %v", expected, result.Merged)
+	}
+}
+
+func TestMerge3ReportsFieldsChangedOnBothSides(t *testing.T) {
+	base := map[string]interface{}{"price": float64(100), "tier": "common"}
+	ours := map[string]interface{}{"price": float64(120), "tier": "rare"}
+	theirs := map[string]interface{}{"price": float64(150), "tier": "rare"}
+
+	result := Merge3(base, ours, theirs)
+	if !reflect.DeepEqual(result.Conflicts, []string{"price"}) {
+		t.Fatalf("Expected conflict on price only, got: %v", result.Conflicts)
+	}
+	if result.Merged["price"] != float64(120) || result.Merged["tier"] != "rare" {
+		t.Errorf("Expected ours kept on conflict and identical change merged, got: %v", result.Merged)
+	}
+}
+
+func TestMerge3TreatsNumericTypesAsEqual(t *testing.T) {
+	base := map[string]interface{}{"price": float64(100)}
+	ours := map[string]interface{}{"price": int64(100)}
+	theirs := map[string]interface{}{"price": float64(200)}
+
+	result := Merge3(base, ours, theirs)
+	if len(result.Conflicts) != 0 || result.Merged["price"] != float64(200) {
+		t.Errorf("Expected theirs to win over an unchanged numeric value, got: %+v", result)
+	}
+}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"regexp"
+	"strings"
+)
+
+// rule maps a request to an action and a target. Target may reference
+// submatches of pattern ($1, $2). Method "*" matches any mutating method.
+type rule struct {
+	method  string
+	pattern *regexp.Regexp
+	action  string
+	target  string
+}
+
+func newRule(method, pattern, action, target string) rule {
+	return rule{method: method, pattern: regexp.MustCompile("^" + pattern + "/?$"), action: action, target: target}
+}
+
+// api matches the API prefix with or without the version, for endpoints
+// whose paths did not change in v1
+const api = `/api(?:/v1)?`
+
+// tableAPI matches a table in the legacy (/api/table/{id}) and v1
+// (/api/v1/tables/{id}) shapes and captures its ID as $1
+const tableAPI = `/api(?:/table|/v1/tables)/([^/]+)`
+
+var rules = []rule{
+	// Authentication and permissions
+	newRule("POST", `/(?:api/)?(?:auth/)?login`, "auth.login", ""),
+	newRule("POST", `/(?:api/)?(?:auth/)?logout`, "auth.logout", ""),
+	newRule("*", `/api/(?:permissions|roles)(?:/([^/]+))?`, "permission.change", "permission:$1"),
+	newRule("*", `/api/workspaces/([^/]+)/members(?:/([^/]+))?`, "permission.change", "workspace:$1"),
+
+	// Tables and records. Only the record paths changed shape in v1
+	// (table/{id}/record became tables/{id}/records); everything else under
+	// a table matches both versions through tableAPI.
+	newRule("POST", api+`/tables`, "table.create", "table"),
+	newRule("POST", `/api/table/create`, "table.create", "table"),
+	newRule("PUT", `/api/v1/tables/([^/]+)`, "table.create", "table:$1"),
+	newRule("DELETE", `/api/v1/tables/([^/]+)`, "table.delete", "table:$1"),
+	newRule("POST", tableAPI+`/import`, "table.import", "table:$1"),
+	newRule("GET", tableAPI+`/export`, "table.export", "table:$1"),
+	newRule("POST", `/api/table/([^/]+)/record`, "record.create", "table:$1"),
+	newRule("PATCH", `/api/table/([^/]+)/record/([^/]+)`, "record.update", "record:$1/$2"),
+	newRule("DELETE", `/api/table/([^/]+)/record/([^/]+)`, "record.delete", "record:$1/$2"),
+	newRule("PUT", `/api/v1/tables/([^/]+)/records`, "record.replace", "table:$1"),
+	newRule("POST", `/api/v1/tables/([^/]+)/records`, "record.create", "table:$1"),
+	newRule("PATCH", `/api/v1/tables/([^/]+)/records/([^/]+)`, "record.update", "record:$1/$2"),
+	newRule("DELETE", `/api/v1/tables/([^/]+)/records/([^/]+)`, "record.delete", "record:$1/$2"),
+
+	// Templates
+	newRule("POST", api+`/templates`, "template.create", "template"),
+	newRule("POST", api+`/templates/import`, "template.import", "template"),
+	newRule("GET", api+`/templates/export`, "template.export", "template"),
+	newRule("PUT", api+`/templates/([^/]+)`, "template.update", "template:$1"),
+	newRule("DELETE", api+`/templates/([^/]+)`, "template.delete", "template:$1"),
+	newRule("POST", api+`/tables/([^/]+)/template`, "table.save_as_template", "table:$1"),
+
+	// Comments
+	newRule("GET", tableAPI+`/comments/export`, "comment.export", "table:$1"),
+	newRule("POST", tableAPI+`/comments`, "comment.create", "table:$1"),
+	newRule("POST", tableAPI+`/comments/([^/]+)/replies`, "comment.reply", "thread:$1/$2"),
+	newRule("POST", tableAPI+`/comments/([^/]+)/resolve`, "comment.resolve", "thread:$1/$2"),
+	newRule("POST", tableAPI+`/comments/([^/]+)/unresolve`, "comment.unresolve", "thread:$1/$2"),
+	newRule("PATCH", tableAPI+`/comments/([^/]+)/comments/([^/]+)`, "comment.edit", "thread:$1/$2"),
+
+	// GraphQL queries are audited too, since the body may hold mutations
+	newRule("POST", `/graphql`, "graphql.request", ""),
+
+	// Releases
+	newRule("POST", api+`/publish`, "publish.run", ""),
+	newRule("POST", api+`/publish/validate`, "publish.validate", ""),
+	newRule("POST", api+`/snapshots`, "snapshot.create", "snapshot"),
+	newRule("POST", api+`/snapshots/([^/]+)/promotions`, "promotion.request", "snapshot:$1"),
+	newRule("POST", api+`/promotions/([^/]+)/approve`, "promotion.approve", "promotion:$1"),
+	newRule("POST", api+`/promotions/([^/]+)/reject`, "promotion.reject", "promotion:$1"),
+
+	// Branches
+	newRule("POST", api+`/branches`, "branch.create", "branch"),
+	newRule("DELETE", api+`/branches/([^/]+)`, "branch.close", "branch:$1"),
+	newRule("POST", api+`/branches/([^/]+)/records`, "branch.record_create", "branch:$1"),
+	newRule("PATCH", api+`/branches/([^/]+)/records/([^/]+)`, "branch.record_update", "branch:$1/$2"),
+	newRule("DELETE", api+`/branches/([^/]+)/records/([^/]+)`, "branch.record_delete", "branch:$1/$2"),
+	newRule("POST", api+`/merge-requests`, "merge_request.create", "merge_request"),
+	newRule("POST", api+`/merge-requests/([^/]+)/merge`, "merge_request.merge", "merge_request:$1"),
+	newRule("POST", api+`/merge-requests/([^/]+)/close`, "merge_request.close", "merge_request:$1"),
+}
+
+// Classify names the action and target of a request and reports whether it
+// must be audited: every request that is not GET, HEAD or OPTIONS, plus
+// read requests that take data out of the system (exports).
+func Classify(method, path string) (action, target string, audited bool) {
+	readOnly := method == "GET" || method == "HEAD" || method == "OPTIONS"
+	for _, rule := range rules {
+		if rule.method == "*" && readOnly || rule.method != "*" && rule.method != method {
+			continue
+		}
+		match := rule.pattern.FindStringSubmatchIndex(path)
+		if match == nil {
+			continue
+		}
+		target = string(rule.pattern.ExpandString(nil, rule.target, path, match))
+		return rule.action, strings.TrimSuffix(target, ":"), true
+	}
+
+	if readOnly {
+		return "", "", false
+	}
+	return "request." + strings.ToLower(method), path, true
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first entry in the chain
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Entry is one append-only audit record. Hash covers every other field plus
+// PrevHash, so editing or removing any entry breaks the chain after it.
+type Entry struct {
+	ID         int64                  `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      string                 `json:"actor"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	RequestID  string                 `json:"request_id"`
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	Status     int                    `json:"status"`
+	Action     string                 `json:"action"`
+	Target     string                 `json:"target"`
+	Payload    map[string]interface{} `json:"payload"`
+	PrevHash   string                 `json:"prev_hash"`
+	Hash       string                 `json:"hash"`
+}
+
+// hashInput fixes the field order that is hashed
+type hashInput struct {
+	PrevHash   string                 `json:"prev_hash"`
+	OccurredAt string                 `json:"occurred_at"`
+	Actor      string                 `json:"actor"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	RequestID  string                 `json:"request_id"`
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	Status     int                    `json:"status"`
+	Action     string                 `json:"action"`
+	Target     string                 `json:"target"`
+	Payload    map[string]interface{} `json:"payload"`
+}
+
+// ComputeHash returns the SHA-256 of the entry's contents and PrevHash
+func (e *Entry) ComputeHash() string {
+	data, _ := json.Marshal(hashInput{
+		PrevHash:   e.PrevHash,
+		OccurredAt: e.OccurredAt.UTC().Format(time.RFC3339Nano),
+		Actor:      e.Actor,
+		IP:         e.IP,
+		UserAgent:  e.UserAgent,
+		RequestID:  e.RequestID,
+		Method:     e.Method,
+		Path:       e.Path,
+		Status:     e.Status,
+		Action:     e.Action,
+		Target:     e.Target,
+		Payload:    e.Payload,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal links the entry to its predecessor and sets its hash. OccurredAt and
+// Payload are first normalized to what the database stores and returns
+// (microsecond timestamps, JSON values), so a stored entry re-hashes the same.
+func (e *Entry) Seal(prevHash string) {
+	e.OccurredAt = e.OccurredAt.UTC().Truncate(time.Microsecond)
+	payload := map[string]interface{}{}
+	if data, err := json.Marshal(e.Payload); err == nil {
+		json.Unmarshal(data, &payload)
+	}
+	e.Payload = payload
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// VerifyResult reports the state of the hash chain
+type VerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	LastHash string `json:"last_hash"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verifier checks entries in ID order, possibly across several batches
+type Verifier struct {
+	result VerifyResult
+}
+
+// NewVerifier starts a verification from the genesis hash
+func NewVerifier() *Verifier {
+	return &Verifier{result: VerifyResult{Valid: true, LastHash: GenesisHash}}
+}
+
+// Add checks the next entry; it returns false once the chain is broken
+func (v *Verifier) Add(e *Entry) bool {
+	if !v.result.Valid {
+		return false
+	}
+	switch {
+	case e.PrevHash != v.result.LastHash:
+		v.result.Valid, v.result.BrokenAt, v.result.Reason = false, e.ID, "previous hash does not match the preceding entry"
+	case e.ComputeHash() != e.Hash:
+		v.result.Valid, v.result.BrokenAt, v.result.Reason = false, e.ID, "entry contents do not match its hash"
+	default:
+		v.result.Checked++
+		v.result.LastHash = e.Hash
+	}
+	return v.result.Valid
+}
+
+// Result returns the verification outcome so far
+func (v *Verifier) Result() VerifyResult {
+	return v.result
+}
+
+// Filter narrows audit queries. BeforeID pages backwards from newest entries.
+type Filter struct {
+	Actor     string
+	Action    string
+	Target    string
+	RequestID string
+	From      time.Time
+	To        time.Time
+	BeforeID  int64
+	Limit     int
+}
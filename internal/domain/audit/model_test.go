@@ -0,0 +1,164 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sealedChain(n int) []*Entry {
+	prev := GenesisHash
+	entries := make([]*Entry, n)
+	for i := range entries {
+		e := &Entry{
+			ID:         int64(i + 1),
+			OccurredAt: time.Date(2025, 1, 1, 0, 0, i, 123456789, time.UTC),
+			Actor:      "alice",
+			Method:     "POST",
+			Path:       "/api/tables",
+			Status:     201,
+			Action:     "table.create",
+			Payload:    map[string]interface{}{"keys": []string{"name"}, "bytes": int64(42)},
+		}
+		e.Seal(prev)
+		prev = e.Hash
+		entries[i] = e
+	}
+	return entries
+}
+
+func verify(entries []*Entry) VerifyResult {
+	v := NewVerifier()
+	for _, e := range entries {
+		if !v.Add(e) {
+			break
+		}
+	}
+	return v.Result()
+}
+
+func TestSealNormalizesForStorage(t *testing.T) {
+	e := sealedChain(1)[0]
+	if e.OccurredAt.Nanosecond()%1000 != 0 {
+		t.Errorf("Expected microsecond precision, got: %v", e.OccurredAt)
+	}
+
+	// Round trip through JSON as the database does
+	data, _ := json.Marshal(e.Payload)
+	var stored map[string]interface{}
+	json.Unmarshal(data, &stored)
+	reloaded := *e
+	reloaded.Payload = stored
+	if reloaded.ComputeHash() != e.Hash {
+		t.Error("Expected hash to survive a JSON round trip of the payload")
+	}
+}
+
+func TestVerifierDetectsTampering(t *testing.T) {
+	if result := verify(sealedChain(5)); !result.Valid || result.Checked != 5 {
+		t.Fatalf("Expected a valid chain of 5, got: %+v", result)
+	}
+
+	edited := sealedChain(5)
+	edited[2].Actor = "mallory"
+	if result := verify(edited); result.Valid || result.BrokenAt != 3 || result.Checked != 2 {
+		t.Errorf("Expected edit detected at entry 3, got: %+v", result)
+	}
+
+	removed := sealedChain(5)
+	removed = append(removed[:1], removed[2:]...)
+	if result := verify(removed); result.Valid || result.BrokenAt != 3 {
+		t.Errorf("Expected removal detected at entry 3, got: %+v", result)
+	}
+
+	rehashed := sealedChain(5)
+	rehashed[2].Actor = "mallory"
+	rehashed[2].Hash = rehashed[2].ComputeHash()
+	if result := verify(rehashed); result.Valid || result.BrokenAt != 4 {
+		t.Errorf("Expected a rehashed edit to break the next link, got: %+v", result)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method, path   string
+		action, target string
+		audited        bool
+	}{
+		{"POST", "/api/tables", "table.create", "table", true},
+		{"PUT", "/api/table/table_item", "request.put", "/api/table/table_item", true},
+		{"DELETE", "/api/v1/tables/table_item/", "table.delete", "table:table_item", true},
+		{"POST", "/api/table/table_item/import", "table.import", "table:table_item", true},
+		{"GET", "/api/table/table_item/export", "table.export", "table:table_item", true},
+		{"PATCH", "/api/table/table_item/record/12", "record.update", "record:table_item/12", true},
+		{"POST", "/api/promotions/3/approve", "promotion.approve", "promotion:3", true},
+		{"PUT", "/api/v1/tables/table_item", "table.create", "table:table_item", true},
+		{"PUT", "/api/v1/tables/table_item/records", "record.replace", "table:table_item", true},
+		{"POST", "/api/v1/tables/table_item/import", "table.import", "table:table_item", true},
+		{"POST", "/api/table/table_item/record", "record.create", "table:table_item", true},
+		{"POST", "/api/v1/tables/table_item/records", "record.create", "table:table_item", true},
+		{"POST", "/api/table/table_item/comments/7/resolve", "comment.resolve", "thread:table_item/7", true},
+		{"PATCH", "/api/v1/tables/table_item/comments/7/comments/9", "comment.edit", "thread:table_item/7", true},
+		{"DELETE", "/api/v1/tables/table_item/records/12", "record.delete", "record:table_item/12", true},
+		{"GET", "/api/v1/tables/table_item/comments/export", "comment.export", "table:table_item", true},
+		{"POST", "/api/v1/templates", "template.create", "template", true},
+		{"PUT", "/api/v1/templates/loot_table", "template.update", "template:loot_table", true},
+		{"DELETE", "/api/v1/templates/loot_table", "template.delete", "template:loot_table", true},
+		{"POST", "/api/v1/templates/import", "template.import", "template", true},
+		{"GET", "/api/v1/templates/export", "template.export", "template", true},
+		{"POST", "/api/v1/tables/table_item/template", "table.save_as_template", "table:table_item", true},
+		{"GET", "/api/v1/templates/loot_table", "", "", false},
+		{"POST", "/api/v1/promotions/3/approve", "promotion.approve", "promotion:3", true},
+		{"POST", "/api/login", "auth.login", "", true},
+		{"PUT", "/api/permissions", "permission.change", "permission", true},
+		{"POST", "/api/fakeit/generate", "request.post", "/api/fakeit/generate", true},
+		{"POST", "/graphql", "graphql.request", "", true},
+		{"GET", "/api/table/table_item", "", "", false},
+		{"GET", "/api/permissions", "", "", false},
+		{"GET", "/api/v1/tables/table_item/records", "", "", false},
+		{"GET", "/graphql", "", "", false},
+	}
+	for _, tt := range tests {
+		action, target, audited := Classify(tt.method, tt.path)
+		if action != tt.action || target != tt.target || audited != tt.audited {
+			t.Errorf("Classify(%s %s) = %q, %q, %v; expected %q, %q, %v",
+				tt.method, tt.path, action, target, audited, tt.action, tt.target, tt.audited)
+		}
+	}
+}
+
+func TestSummarizePayloadOmitsValues(t *testing.T) {
+	body := []byte(`{"name": "secret-name", "records": [{"a": 1}, {"a": 2}], "schema": {"type": "object"}}`)
+	summary := SummarizePayload("application/json", url.Values{"mode": {"append"}}, body, int64(len(body)), false)
+
+	if !reflect.DeepEqual(summary["keys"], []string{"name", "records", "schema"}) {
+		t.Errorf("Unexpected keys: %v", summary["keys"])
+	}
+	if counts := summary["counts"].(map[string]interface{}); counts["records"] != 2 || counts["schema"] != 1 {
+		t.Errorf("Unexpected counts: %v", counts)
+	}
+	if !reflect.DeepEqual(summary["query"], []string{"mode"}) {
+		t.Errorf("Unexpected query: %v", summary["query"])
+	}
+	data, _ := json.Marshal(summary)
+	if strings.Contains(string(data), "secret-name") {
+		t.Errorf("Expected values to be omitted, got: %s", data)
+	}
+}
+
+func TestSummarizePayloadMultipart(t *testing.T) {
+	body := "--b\r\nContent-Disposition: form-data; name=\"mode\"\r\n\r\nreplace\r\n" +
+		"--b\r\nContent-Disposition: form-data; name=\"file\"; filename=\"items.csv\"\r\n\r\na,b\n1,2\n\r\n--b--\r\n"
+	summary := SummarizePayload("multipart/form-data; boundary=b", nil, []byte(body), int64(len(body)), false)
+
+	files := summary["files"].([]map[string]interface{})
+	if len(files) != 1 || files[0]["name"] != "items.csv" || files[0]["bytes"] != int64(8) {
+		t.Errorf("Unexpected files: %v", files)
+	}
+	if !reflect.DeepEqual(summary["fields"], []string{"mode"}) {
+		t.Errorf("Unexpected fields: %v", summary["fields"])
+	}
+}
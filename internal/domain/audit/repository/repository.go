@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"progressive/internal/domain/audit"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// auditChainLock is the advisory lock key that serializes appends to the chain
+const auditChainLock = 0x61756469 // "audi"
+
+// verifyBatchSize is how many entries Verify reads per query
+const verifyBatchSize = 1000
+
+// ReadRepository defines read operations for the audit log
+type ReadRepository interface {
+	Find(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error)
+	Verify(ctx context.Context) (*audit.VerifyResult, error)
+}
+
+// WriteRepository defines write operations for the audit log
+type WriteRepository interface {
+	Append(ctx context.Context, e *audit.Entry) error
+}
+
+// AuditRepository combines both ReadRepository and WriteRepository interfaces
+type AuditRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// entryDB represents the database model
+type entryDB struct {
+	ID         int64           `db:"id"`
+	OccurredAt time.Time       `db:"occurred_at"`
+	Actor      string          `db:"actor"`
+	IP         string          `db:"ip"`
+	UserAgent  string          `db:"user_agent"`
+	RequestID  string          `db:"request_id"`
+	Method     string          `db:"method"`
+	Path       string          `db:"path"`
+	Status     int             `db:"status"`
+	Action     string          `db:"action"`
+	Target     string          `db:"target"`
+	Payload    json.RawMessage `db:"payload"`
+	PrevHash   string          `db:"prev_hash"`
+	Hash       string          `db:"hash"`
+}
+
+const entryColumns = `id, occurred_at, actor, ip, user_agent, request_id, method, path, status, action, target, payload, prev_hash, hash`
+
+// PostgresAuditRepository implements AuditRepository using PostgreSQL
+type PostgresAuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL audit repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+// Append seals the entry against the latest hash and stores it. Appends are
+// serialized with a transaction-scoped advisory lock so the chain never forks.
+func (r *PostgresAuditRepository) Append(ctx context.Context, e *audit.Entry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return fmt.Errorf("failed to lock audit chain: %w", err)
+	}
+
+	prevHash := audit.GenesisHash
+	err = tx.GetContext(ctx, &prevHash, `SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read audit chain head: %w", err)
+	}
+
+	e.Seal(prevHash)
+	row, err := r.toDBModel(e)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO audit_log (occurred_at, actor, ip, user_agent, request_id, method, path, status, action, target, payload, prev_hash, hash)
+		VALUES (:occurred_at, :actor, :ip, :user_agent, :request_id, :method, :path, :status, :action, :target, :payload, :prev_hash, :hash)
+		RETURNING id
+	`
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare audit insert: %w", err)
+	}
+	defer stmt.Close()
+	if err := stmt.GetContext(ctx, &e.ID, row); err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Find lists entries matching the filter, newest first
+func (r *PostgresAuditRepository) Find(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	var conditions []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		// "table" matches every table.* action
+		if strings.Contains(filter.Action, ".") {
+			add("action = $%d", filter.Action)
+		} else {
+			add("starts_with(action, $%d || '.')", filter.Action)
+		}
+	}
+	if filter.Target != "" {
+		// "record:table_x" also matches "record:table_x/12"
+		add("(target = $%d OR starts_with(target, $%[1]d || '/'))", filter.Target)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		add("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("occurred_at < $%d", filter.To)
+	}
+	if filter.BeforeID > 0 {
+		add("id < $%d", filter.BeforeID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	query := `SELECT ` + entryColumns + ` FROM audit_log`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(` ORDER BY id DESC LIMIT %d`, limit)
+
+	var rows []entryDB
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find audit entries: %w", err)
+	}
+	return r.toDomainList(rows)
+}
+
+// Verify recomputes the whole hash chain in ID order
+func (r *PostgresAuditRepository) Verify(ctx context.Context) (*audit.VerifyResult, error) {
+	verifier := audit.NewVerifier()
+	var lastID int64
+	for {
+		var rows []entryDB
+		query := `SELECT ` + entryColumns + ` FROM audit_log WHERE id > $1 ORDER BY id LIMIT $2`
+		if err := r.db.SelectContext(ctx, &rows, query, lastID, verifyBatchSize); err != nil {
+			return nil, fmt.Errorf("failed to read audit entries: %w", err)
+		}
+		entries, err := r.toDomainList(rows)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !verifier.Add(e) {
+				result := verifier.Result()
+				return &result, nil
+			}
+			lastID = e.ID
+		}
+		if len(rows) < verifyBatchSize {
+			break
+		}
+	}
+	result := verifier.Result()
+	return &result, nil
+}
+
+// toDomain converts database model to domain model
+func (r *PostgresAuditRepository) toDomain(row *entryDB) (*audit.Entry, error) {
+	payload := map[string]interface{}{}
+	if len(row.Payload) > 0 {
+		if err := json.Unmarshal(row.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("failed to decode audit payload %d: %w", row.ID, err)
+		}
+	}
+	return &audit.Entry{
+		ID:         row.ID,
+		OccurredAt: row.OccurredAt.UTC(),
+		Actor:      row.Actor,
+		IP:         row.IP,
+		UserAgent:  row.UserAgent,
+		RequestID:  row.RequestID,
+		Method:     row.Method,
+		Path:       row.Path,
+		Status:     row.Status,
+		Action:     row.Action,
+		Target:     row.Target,
+		Payload:    payload,
+		PrevHash:   row.PrevHash,
+		Hash:       row.Hash,
+	}, nil
+}
+
+func (r *PostgresAuditRepository) toDomainList(rows []entryDB) ([]*audit.Entry, error) {
+	entries := make([]*audit.Entry, len(rows))
+	for i := range rows {
+		e, err := r.toDomain(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+// toDBModel converts domain model to database model
+func (r *PostgresAuditRepository) toDBModel(e *audit.Entry) (*entryDB, error) {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit payload: %w", err)
+	}
+	return &entryDB{
+		ID:         e.ID,
+		OccurredAt: e.OccurredAt,
+		Actor:      e.Actor,
+		IP:         e.IP,
+		UserAgent:  e.UserAgent,
+		RequestID:  e.RequestID,
+		Method:     e.Method,
+		Path:       e.Path,
+		Status:     e.Status,
+		Action:     e.Action,
+		Target:     e.Target,
+		Payload:    payload,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// maxSummaryKeys bounds how many object keys a summary lists
+const maxSummaryKeys = 50
+
+// SummarizePayload describes a request body without storing its values:
+// size, content type, top-level JSON keys and array lengths, multipart file
+// names and sizes, and query parameter names. truncated reports that only the
+// first part of the body was captured.
+func SummarizePayload(contentType string, query url.Values, body []byte, size int64, truncated bool) map[string]interface{} {
+	summary := map[string]interface{}{}
+	if len(query) > 0 {
+		summary["query"] = sortedKeys(query)
+	}
+	if size <= 0 && len(body) == 0 {
+		return summary
+	}
+
+	summary["bytes"] = size
+	if truncated {
+		summary["truncated"] = true
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if mediaType != "" {
+		summary["content_type"] = mediaType
+	}
+
+	switch {
+	case mediaType == "multipart/form-data" && params["boundary"] != "":
+		if files, fields := summarizeMultipart(body, params["boundary"]); len(files)+len(fields) > 0 {
+			summary["files"] = files
+			summary["fields"] = fields
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			summary["fields"] = sortedKeys(form)
+		}
+	case !truncated && (mediaType == "" || strings.HasSuffix(mediaType, "json")):
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err == nil {
+			describeJSON(summary, v)
+		}
+	}
+	return summary
+}
+
+func describeJSON(summary map[string]interface{}, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		counts := map[string]interface{}{}
+		for k, val := range v {
+			keys = append(keys, k)
+			switch val := val.(type) {
+			case []interface{}:
+				counts[k] = len(val)
+			case map[string]interface{}:
+				counts[k] = len(val)
+			}
+		}
+		sort.Strings(keys)
+		if len(keys) > maxSummaryKeys {
+			keys = keys[:maxSummaryKeys]
+			summary["keys_truncated"] = true
+		}
+		summary["keys"] = keys
+		if len(counts) > 0 {
+			summary["counts"] = counts
+		}
+	case []interface{}:
+		summary["items"] = len(v)
+	}
+}
+
+func summarizeMultipart(body []byte, boundary string) (files []map[string]interface{}, fields []string) {
+	files = []map[string]interface{}{}
+	fields = []string{}
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		if part.FileName() != "" {
+			n, _ := io.Copy(io.Discard, part)
+			files = append(files, map[string]interface{}{"field": part.FormName(), "name": part.FileName(), "bytes": n})
+		} else {
+			fields = append(fields, part.FormName())
+		}
+		part.Close()
+	}
+	return files, fields
+}
+
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
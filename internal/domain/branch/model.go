@@ -0,0 +1,362 @@
+package branch
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"progressive/internal/diff"
+)
+
+// Branch and merge request statuses
+const (
+	StatusOpen   = "open"
+	StatusMerged = "merged"
+	StatusClosed = "closed"
+)
+
+// Change operations recorded in a branch overlay
+const (
+	OpInsert = "insert"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Sides a conflict can be resolved to
+const (
+	UseMain   = "main"
+	UseBranch = "branch"
+	UseValue  = "value"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrNameTaken     = errors.New("branch name already exists for this table")
+	ErrNotOpen       = errors.New("branch or merge request is not open")
+	ErrAlreadyOpen   = errors.New("an open merge request already exists for this branch")
+	ErrInvalidInput  = errors.New("invalid input")
+	ErrTableNotFound = errors.New("table not found")
+)
+
+// ConflictError is returned when a merge has unresolved conflicts
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("merge has %d unresolved conflict(s)", len(e.Conflicts))
+}
+
+// Branch is an isolated workspace over one table. Records the branch has not
+// touched read through to the base table; edits are stored as an overlay.
+type Branch struct {
+	ID          string    `json:"id"`
+	TableID     string    `json:"table_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewBranch creates an open branch of the given table
+func NewBranch(tableID, name, description, createdBy string) (*Branch, error) {
+	name = strings.TrimSpace(name)
+	if tableID == "" || name == "" {
+		return nil, fmt.Errorf("%w: table_id and name are required", ErrInvalidInput)
+	}
+	now := time.Now()
+	return &Branch{
+		ID:          fmt.Sprintf("branch_%d", now.UnixNano()),
+		TableID:     tableID,
+		Name:        name,
+		Description: description,
+		Status:      StatusOpen,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Change is one overlay entry. BaseData holds the base record as it was when
+// the branch first touched it and is the merge base for three-way merges.
+type Change struct {
+	ID        int64                  `json:"id"`
+	BranchID  string                 `json:"branch_id"`
+	RecordID  int64                  `json:"record_id,omitempty"`
+	Operation string                 `json:"operation"`
+	BaseData  map[string]interface{} `json:"base_data,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// Ref returns the reference used to address the changed record
+func (c *Change) Ref() Ref {
+	if c.Operation == OpInsert {
+		return Ref{ChangeID: c.ID}
+	}
+	return Ref{RecordID: c.RecordID}
+}
+
+// Ref addresses a record on a branch: a base record by ID, or a record added
+// on the branch by its change ID (written "new-<id>")
+type Ref struct {
+	RecordID int64
+	ChangeID int64
+}
+
+// ParseRef parses "123" or "new-5"
+func ParseRef(s string) (Ref, error) {
+	if rest, ok := strings.CutPrefix(s, "new-"); ok {
+		id, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil || id <= 0 {
+			return Ref{}, fmt.Errorf("%w: invalid record reference %q", ErrInvalidInput, s)
+		}
+		return Ref{ChangeID: id}, nil
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return Ref{}, fmt.Errorf("%w: invalid record reference %q", ErrInvalidInput, s)
+	}
+	return Ref{RecordID: id}, nil
+}
+
+func (r Ref) String() string {
+	if r.ChangeID != 0 {
+		return "new-" + strconv.FormatInt(r.ChangeID, 10)
+	}
+	return strconv.FormatInt(r.RecordID, 10)
+}
+
+// Record is a base table record
+type Record struct {
+	ID   int64
+	Data map[string]interface{}
+}
+
+// RecordView is a record as seen on a branch
+type RecordView struct {
+	Ref    string                 `json:"_ref"`
+	ID     int64                  `json:"_id,omitempty"`
+	Status string                 `json:"_status"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+// Overlay applies the branch changes to the current base records.
+// Deleted records are omitted; added records follow the base records.
+func Overlay(base []Record, changes []Change) []RecordView {
+	byRecord := make(map[int64]Change)
+	var inserts []Change
+	for _, c := range changes {
+		if c.Operation == OpInsert {
+			inserts = append(inserts, c)
+		} else {
+			byRecord[c.RecordID] = c
+		}
+	}
+
+	views := make([]RecordView, 0, len(base)+len(inserts))
+	for _, r := range base {
+		view := RecordView{Ref: Ref{RecordID: r.ID}.String(), ID: r.ID, Status: string(diff.Unchanged), Data: r.Data}
+		if c, ok := byRecord[r.ID]; ok {
+			delete(byRecord, r.ID)
+			if c.Operation == OpDelete {
+				continue
+			}
+			view.Status = string(diff.Modified)
+			view.Data = c.Data
+		}
+		views = append(views, view)
+	}
+
+	// Records edited on the branch but since deleted on main stay visible
+	// until the conflict is resolved by a merge
+	var orphaned []Change
+	for _, c := range byRecord {
+		if c.Operation == OpUpdate {
+			orphaned = append(orphaned, c)
+		}
+	}
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].RecordID < orphaned[j].RecordID })
+	for _, c := range orphaned {
+		views = append(views, RecordView{Ref: c.Ref().String(), ID: c.RecordID, Status: string(diff.Modified), Data: c.Data})
+	}
+
+	sort.Slice(inserts, func(i, j int) bool { return inserts[i].ID < inserts[j].ID })
+	for _, c := range inserts {
+		views = append(views, RecordView{Ref: c.Ref().String(), Status: string(diff.Added), Data: c.Data})
+	}
+	return views
+}
+
+// MergeRequest asks to merge a branch back into its base table
+type MergeRequest struct {
+	ID          int64      `json:"id"`
+	BranchID    string     `json:"branch_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	MergedBy    string     `json:"merged_by,omitempty"`
+	MergedAt    *time.Time `json:"merged_at,omitempty"`
+}
+
+// NewMergeRequest opens a merge request for the branch
+func NewMergeRequest(b *Branch, title, description, createdBy string) (*MergeRequest, error) {
+	if b.Status != StatusOpen {
+		return nil, ErrNotOpen
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "Merge " + b.Name
+	}
+	return &MergeRequest{
+		BranchID:    b.ID,
+		Title:       title,
+		Description: description,
+		Status:      StatusOpen,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Conflict is a field (or, with an empty Field, a whole record) changed on
+// both the branch and the base table since the branch touched it
+type Conflict struct {
+	Ref    string      `json:"ref"`
+	Field  string      `json:"field,omitempty"`
+	Reason string      `json:"reason"`
+	Base   interface{} `json:"base"`
+	Main   interface{} `json:"main"`
+	Branch interface{} `json:"branch"`
+}
+
+// Resolution picks a side (or an explicit value) for one conflict
+type Resolution struct {
+	Ref   string      `json:"ref"`
+	Field string      `json:"field,omitempty"`
+	Use   string      `json:"use"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ChangeView describes one branch change for review
+type ChangeView struct {
+	Ref       string             `json:"ref"`
+	RecordID  int64              `json:"record_id,omitempty"`
+	Operation string             `json:"operation"`
+	Fields    []diff.FieldChange `json:"fields"`
+	Conflicts []Conflict         `json:"conflicts,omitempty"`
+}
+
+// RecordUpdate is a base record to overwrite on merge
+type RecordUpdate struct {
+	ID   int64
+	Data map[string]interface{}
+}
+
+// MergePlan is the result of merging a branch into the current base state
+type MergePlan struct {
+	Changes   []ChangeView             `json:"changes"`
+	Conflicts []Conflict               `json:"conflicts"`
+	Inserts   []map[string]interface{} `json:"-"`
+	Updates   []RecordUpdate           `json:"-"`
+	Deletes   []int64                  `json:"-"`
+}
+
+// Mergeable reports whether every conflict has been resolved
+func (p *MergePlan) Mergeable() bool {
+	return len(p.Conflicts) == 0
+}
+
+// PlanMerge three-way merges each branch change against the current base
+// records, applying the given conflict resolutions
+func PlanMerge(changes []Change, current map[int64]map[string]interface{}, resolutions []Resolution) *MergePlan {
+	resolved := make(map[string]Resolution, len(resolutions))
+	for _, r := range resolutions {
+		resolved[r.Ref+"\x00"+r.Field] = r
+	}
+
+	plan := &MergePlan{Changes: []ChangeView{}, Conflicts: []Conflict{}}
+	for _, c := range changes {
+		ref := c.Ref().String()
+		view := ChangeView{Ref: ref, RecordID: c.RecordID, Operation: c.Operation, Fields: diff.Fields(c.BaseData, c.Data)}
+		main, exists := current[c.RecordID]
+
+		recordConflict := func(reason string) (Resolution, bool) {
+			if r, ok := resolved[ref+"\x00"]; ok && (r.Use == UseMain || r.Use == UseBranch) {
+				return r, true
+			}
+			conflict := Conflict{Ref: ref, Reason: reason, Base: c.BaseData, Branch: c.Data}
+			if exists {
+				conflict.Main = main
+			}
+			view.Conflicts = append(view.Conflicts, conflict)
+			return Resolution{}, false
+		}
+
+		switch c.Operation {
+		case OpInsert:
+			plan.Inserts = append(plan.Inserts, c.Data)
+
+		case OpUpdate:
+			if !exists {
+				// Deleted on main, modified on the branch
+				if r, ok := recordConflict("deleted on main, modified on branch"); ok && r.Use == UseBranch {
+					plan.Inserts = append(plan.Inserts, c.Data)
+				}
+				break
+			}
+
+			result := diff.Merge3(c.BaseData, main, c.Data)
+			for _, field := range result.Conflicts {
+				r, ok := resolved[ref+"\x00"+field]
+				switch {
+				case ok && r.Use == UseMain:
+					setField(result.Merged, field, main)
+				case ok && r.Use == UseBranch:
+					setField(result.Merged, field, c.Data)
+				case ok && r.Use == UseValue:
+					result.Merged[field] = r.Value
+				default:
+					view.Conflicts = append(view.Conflicts, Conflict{
+						Ref: ref, Field: field, Reason: "changed on both main and branch",
+						Base: c.BaseData[field], Main: main[field], Branch: c.Data[field],
+					})
+				}
+			}
+			if !diff.RecordEqual(main, result.Merged) {
+				plan.Updates = append(plan.Updates, RecordUpdate{ID: c.RecordID, Data: result.Merged})
+			}
+
+		case OpDelete:
+			if !exists {
+				break
+			}
+			if diff.RecordEqual(c.BaseData, main) {
+				plan.Deletes = append(plan.Deletes, c.RecordID)
+				break
+			}
+			if r, ok := recordConflict("modified on main, deleted on branch"); ok && r.Use == UseBranch {
+				plan.Deletes = append(plan.Deletes, c.RecordID)
+			}
+		}
+
+		plan.Conflicts = append(plan.Conflicts, view.Conflicts...)
+		plan.Changes = append(plan.Changes, view)
+	}
+
+	return plan
+}
+
+func setField(dst map[string]interface{}, field string, src map[string]interface{}) {
+	if v, ok := src[field]; ok {
+		dst[field] = v
+	} else {
+		delete(dst, field)
+	}
+}
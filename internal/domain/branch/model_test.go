@@ -0,0 +1,128 @@
+package branch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("42")
+	if err != nil || ref != (Ref{RecordID: 42}) || ref.String() != "42" {
+		t.Errorf("Expected record ref 42, got: %+v (%v)", ref, err)
+	}
+	ref, err = ParseRef("new-7")
+	if err != nil || ref != (Ref{ChangeID: 7}) || ref.String() != "new-7" {
+		t.Errorf("Expected change ref new-7, got: %+v (%v)", ref, err)
+	}
+	for _, s := range []string{"", "0", "new-", "new-x", "abc"} {
+		if _, err := ParseRef(s); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for %q, got: %v", s, err)
+		}
+	}
+}
+
+func TestOverlayAppliesBranchChanges(t *testing.T) {
+	base := []Record{
+		{ID: 1, Data: map[string]interface{}{"code": "potion"}},
+		{ID: 2, Data: map[string]interface{}{"code": "sword"}},
+		{ID: 3, Data: map[string]interface{}{"code": "shield"}},
+	}
+	changes := []Change{
+		{ID: 10, RecordID: 2, Operation: OpUpdate, Data: map[string]interface{}{"code": "great sword"}},
+		{ID: 11, RecordID: 3, Operation: OpDelete},
+		{ID: 12, Operation: OpInsert, Data: map[string]interface{}{"code": "bow"}},
+		{ID: 13, RecordID: 9, Operation: OpUpdate, Data: map[string]interface{}{"code": "axe"}},
+	}
+
+	views := Overlay(base, changes)
+	refs := make([]string, len(views))
+	for i, v := range views {
+		refs[i] = v.Ref + ":" + v.Status
+	}
+	expected := []string{"1:unchanged", "2:modified", "9:modified", "new-12:added"}
+	if len(refs) != len(expected) {
+		t.Fatalf("Expected %v, got: %v", expected, refs)
+	}
+	for i := range expected {
+		if refs[i] != expected[i] {
+			t.Errorf("Expected %v, got: %v", expected, refs)
+			break
+		}
+	}
+	if views[1].Data["code"] != "great sword" {
+		t.Errorf("Expected branch data for record 2, got: %v", views[1].Data)
+	}
+}
+
+func TestPlanMergeAppliesNonConflictingChanges(t *testing.T) {
+	changes := []Change{
+		{ID: 1, RecordID: 1, Operation: OpUpdate,
+			BaseData: map[string]interface{}{"price": float64(10), "tier": "common"},
+			Data:     map[string]interface{}{"price": float64(12), "tier": "common"}},
+		{ID: 2, RecordID: 2, Operation: OpDelete, BaseData: map[string]interface{}{"code": "old"}},
+		{ID: 3, Operation: OpInsert, Data: map[string]interface{}{"code": "new"}},
+	}
+	current := map[int64]map[string]interface{}{
+		1: {"price": float64(10), "tier": "rare"},
+		2: {"code": "old"},
+	}
+
+	plan := PlanMerge(changes, current, nil)
+	if !plan.Mergeable() {
+		t.Fatalf("Expected mergeable plan, got conflicts: %+v", plan.Conflicts)
+	}
+	if len(plan.Updates) != 1 || plan.Updates[0].Data["price"] != float64(12) || plan.Updates[0].Data["tier"] != "rare" {
+		t.Errorf("Expected merged update of record 1, got: %+v", plan.Updates)
+	}
+	if len(plan.Deletes) != 1 || plan.Deletes[0] != 2 {
+		t.Errorf("Expected record 2 deleted, got: %v", plan.Deletes)
+	}
+	if len(plan.Inserts) != 1 || plan.Inserts[0]["code"] != "new" {
+		t.Errorf("Expected one insert, got: %v", plan.Inserts)
+	}
+}
+
+func TestPlanMergeReportsAndResolvesConflicts(t *testing.T) {
+	changes := []Change{
+		{ID: 1, RecordID: 1, Operation: OpUpdate,
+			BaseData: map[string]interface{}{"price": float64(10)},
+			Data:     map[string]interface{}{"price": float64(12)}},
+		{ID: 2, RecordID: 2, Operation: OpDelete, BaseData: map[string]interface{}{"code": "old"}},
+		{ID: 3, RecordID: 3, Operation: OpUpdate,
+			BaseData: map[string]interface{}{"code": "gone"},
+			Data:     map[string]interface{}{"code": "kept"}},
+	}
+	current := map[int64]map[string]interface{}{
+		1: {"price": float64(15)},
+		2: {"code": "renamed"},
+	}
+
+	plan := PlanMerge(changes, current, nil)
+	if len(plan.Conflicts) != 3 {
+		t.Fatalf("Expected 3 conflicts, got: %+v", plan.Conflicts)
+	}
+	if plan.Conflicts[0].Field != "price" || plan.Conflicts[0].Main != float64(15) || plan.Conflicts[0].Branch != float64(12) {
+		t.Errorf("Expected field conflict on price, got: %+v", plan.Conflicts[0])
+	}
+	if plan.Conflicts[1].Field != "" || plan.Conflicts[2].Field != "" {
+		t.Errorf("Expected record-level conflicts for delete/modify, got: %+v", plan.Conflicts[1:])
+	}
+
+	plan = PlanMerge(changes, current, []Resolution{
+		{Ref: "1", Field: "price", Use: UseValue, Value: float64(13)},
+		{Ref: "2", Use: UseMain},
+		{Ref: "3", Use: UseBranch},
+	})
+	if !plan.Mergeable() {
+		t.Fatalf("Expected all conflicts resolved, got: %+v", plan.Conflicts)
+	}
+	if len(plan.Updates) != 1 || plan.Updates[0].Data["price"] != float64(13) {
+		t.Errorf("Expected resolved price 13, got: %+v", plan.Updates)
+	}
+	if len(plan.Deletes) != 0 {
+		t.Errorf("Expected main kept for record 2, got deletes: %v", plan.Deletes)
+	}
+	if len(plan.Inserts) != 1 || plan.Inserts[0]["code"] != "kept" {
+		t.Errorf("Expected record 3 restored from branch, got: %v", plan.Inserts)
+	}
+}
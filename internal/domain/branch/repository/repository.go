@@ -0,0 +1,715 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"progressive/internal/domain/branch"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ReadRepository defines read operations for branches and merge requests
+type ReadRepository interface {
+	FindBranches(ctx context.Context, tableID string) ([]*branch.Branch, error)
+	FindBranchByID(ctx context.Context, id string) (*branch.Branch, error)
+	FindChanges(ctx context.Context, branchID string) ([]branch.Change, error)
+	FindRecords(ctx context.Context, b *branch.Branch) ([]branch.RecordView, error)
+	FindMergeRequests(ctx context.Context, status string) ([]*branch.MergeRequest, error)
+	FindMergeRequestByID(ctx context.Context, id int64) (*branch.MergeRequest, error)
+	PlanMerge(ctx context.Context, mr *branch.MergeRequest, resolutions []branch.Resolution) (*branch.MergePlan, error)
+}
+
+// WriteRepository defines write operations for branches and merge requests
+type WriteRepository interface {
+	CreateBranch(ctx context.Context, b *branch.Branch) error
+	CloseBranch(ctx context.Context, id string) error
+	CreateRecord(ctx context.Context, branchID string, data map[string]interface{}) (*branch.Change, error)
+	UpdateRecord(ctx context.Context, branchID string, ref branch.Ref, patch map[string]interface{}) (*branch.Change, error)
+	DeleteRecord(ctx context.Context, branchID string, ref branch.Ref) error
+	CreateMergeRequest(ctx context.Context, mr *branch.MergeRequest) error
+	CloseMergeRequest(ctx context.Context, id int64) error
+	Merge(ctx context.Context, id int64, mergedBy string, resolutions []branch.Resolution) (*branch.MergePlan, error)
+}
+
+// BranchRepository combines both ReadRepository and WriteRepository interfaces
+type BranchRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// branchDB represents the database model
+type branchDB struct {
+	ID          string    `db:"id"`
+	TableID     string    `db:"table_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Status      string    `db:"status"`
+	CreatedBy   string    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type changeDB struct {
+	ID        int64           `db:"id"`
+	BranchID  string          `db:"branch_id"`
+	RecordID  sql.NullInt64   `db:"record_id"`
+	Operation string          `db:"operation"`
+	BaseData  json.RawMessage `db:"base_data"`
+	Data      json.RawMessage `db:"data"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
+
+type mergeRequestDB struct {
+	ID          int64          `db:"id"`
+	BranchID    string         `db:"branch_id"`
+	Title       string         `db:"title"`
+	Description string         `db:"description"`
+	Status      string         `db:"status"`
+	CreatedBy   string         `db:"created_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+	MergedBy    sql.NullString `db:"merged_by"`
+	MergedAt    sql.NullTime   `db:"merged_at"`
+}
+
+type recordDB struct {
+	ID   int64           `db:"id"`
+	Data json.RawMessage `db:"data"`
+}
+
+const (
+	branchColumns       = `id, table_id, name, description, status, created_by, created_at, updated_at`
+	changeColumns       = `id, branch_id, record_id, operation, base_data, data, updated_at`
+	mergeRequestColumns = `id, branch_id, title, description, status, created_by, created_at, merged_by, merged_at`
+)
+
+// PostgresBranchRepository implements BranchRepository using PostgreSQL
+type PostgresBranchRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL branch repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresBranchRepository {
+	return &PostgresBranchRepository{db: db}
+}
+
+// FindBranches lists the branches of a table, or of all tables when tableID is empty
+func (r *PostgresBranchRepository) FindBranches(ctx context.Context, tableID string) ([]*branch.Branch, error) {
+	var rows []branchDB
+	query := `SELECT ` + branchColumns + ` FROM branches WHERE ($1 = '' OR table_id = $1) ORDER BY updated_at DESC`
+	if err := r.db.SelectContext(ctx, &rows, query, tableID); err != nil {
+		return nil, fmt.Errorf("failed to find branches: %w", err)
+	}
+
+	branches := make([]*branch.Branch, len(rows))
+	for i := range rows {
+		branches[i] = r.toDomain(&rows[i])
+	}
+	return branches, nil
+}
+
+// FindBranchByID retrieves a branch by ID
+func (r *PostgresBranchRepository) FindBranchByID(ctx context.Context, id string) (*branch.Branch, error) {
+	return r.getBranch(ctx, r.db, id, false)
+}
+
+// FindChanges retrieves the overlay entries of a branch
+func (r *PostgresBranchRepository) FindChanges(ctx context.Context, branchID string) ([]branch.Change, error) {
+	return r.selectChanges(ctx, r.db, branchID)
+}
+
+// FindRecords returns the branch view: current base records with the overlay applied
+func (r *PostgresBranchRepository) FindRecords(ctx context.Context, b *branch.Branch) ([]branch.RecordView, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var rows []recordDB
+	if err := tx.SelectContext(ctx, &rows, `SELECT id, data FROM records WHERE table_id = $1 ORDER BY id`, b.TableID); err != nil {
+		return nil, fmt.Errorf("failed to find base records: %w", err)
+	}
+	base := make([]branch.Record, len(rows))
+	for i, row := range rows {
+		data, err := decodeData(row.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode record %d: %w", row.ID, err)
+		}
+		base[i] = branch.Record{ID: row.ID, Data: data}
+	}
+
+	changes, err := r.selectChanges(ctx, tx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	return branch.Overlay(base, changes), nil
+}
+
+// FindMergeRequests lists merge requests, optionally filtered by status
+func (r *PostgresBranchRepository) FindMergeRequests(ctx context.Context, status string) ([]*branch.MergeRequest, error) {
+	var rows []mergeRequestDB
+	query := `SELECT ` + mergeRequestColumns + ` FROM merge_requests WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &rows, query, status); err != nil {
+		return nil, fmt.Errorf("failed to find merge requests: %w", err)
+	}
+
+	requests := make([]*branch.MergeRequest, len(rows))
+	for i := range rows {
+		requests[i] = r.toDomainMergeRequest(&rows[i])
+	}
+	return requests, nil
+}
+
+// FindMergeRequestByID retrieves a merge request by ID
+func (r *PostgresBranchRepository) FindMergeRequestByID(ctx context.Context, id int64) (*branch.MergeRequest, error) {
+	return r.getMergeRequest(ctx, r.db, id, false)
+}
+
+// PlanMerge previews merging the request's branch into the current base table
+func (r *PostgresBranchRepository) PlanMerge(ctx context.Context, mr *branch.MergeRequest, resolutions []branch.Resolution) (*branch.MergePlan, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	b, err := r.getBranch(ctx, tx, mr.BranchID, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.planMerge(ctx, tx, b, resolutions, false)
+}
+
+// CreateBranch creates a branch of an existing table
+func (r *PostgresBranchRepository) CreateBranch(ctx context.Context, b *branch.Branch) error {
+	query := `
+		INSERT INTO branches (` + branchColumns + `)
+		VALUES (:id, :table_id, :name, :description, :status, :created_by, :created_at, :updated_at)
+	`
+	if _, err := r.db.NamedExecContext(ctx, query, r.toDBModel(b)); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return fmt.Errorf("%w: %s", branch.ErrNameTaken, b.Name)
+			case "23503":
+				return fmt.Errorf("%w: %s", branch.ErrTableNotFound, b.TableID)
+			}
+		}
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+	return nil
+}
+
+// CloseBranch abandons an open branch and any open merge request for it
+func (r *PostgresBranchRepository) CloseBranch(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := r.getOpenBranch(ctx, tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE branches SET status = $1, updated_at = NOW() WHERE id = $2`, branch.StatusClosed, id); err != nil {
+		return fmt.Errorf("failed to close branch: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE merge_requests SET status = $1 WHERE branch_id = $2 AND status = $3`,
+		branch.StatusClosed, id, branch.StatusOpen); err != nil {
+		return fmt.Errorf("failed to close merge requests: %w", err)
+	}
+	return tx.Commit()
+}
+
+// CreateRecord adds a record that exists only on the branch
+func (r *PostgresBranchRepository) CreateRecord(ctx context.Context, branchID string, data map[string]interface{}) (*branch.Change, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := r.getOpenBranch(ctx, tx, branchID); err != nil {
+		return nil, err
+	}
+
+	change := &branch.Change{BranchID: branchID, Operation: branch.OpInsert, Data: data}
+	if err := r.saveChange(ctx, tx, change); err != nil {
+		return nil, err
+	}
+	return change, tx.Commit()
+}
+
+// UpdateRecord applies a partial update to a record on the branch. The first
+// edit of a base record copies it into the overlay and keeps it as merge base.
+func (r *PostgresBranchRepository) UpdateRecord(ctx context.Context, branchID string, ref branch.Ref, patch map[string]interface{}) (*branch.Change, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	b, err := r.getOpenBranch(ctx, tx, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := r.getChange(ctx, tx, branchID, ref)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		base, err := r.getBaseRecord(ctx, tx, b.TableID, ref.RecordID)
+		if err != nil {
+			return nil, err
+		}
+		change = &branch.Change{BranchID: branchID, RecordID: ref.RecordID, Operation: branch.OpUpdate, BaseData: base, Data: base}
+	}
+	if change.Operation == branch.OpDelete {
+		return nil, fmt.Errorf("%w: record %s is deleted on this branch", branch.ErrNotFound, ref)
+	}
+
+	change.Data = applyPatch(change.Data, patch)
+	if err := r.saveChange(ctx, tx, change); err != nil {
+		return nil, err
+	}
+	return change, tx.Commit()
+}
+
+// DeleteRecord deletes a record on the branch
+func (r *PostgresBranchRepository) DeleteRecord(ctx context.Context, branchID string, ref branch.Ref) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	b, err := r.getOpenBranch(ctx, tx, branchID)
+	if err != nil {
+		return err
+	}
+
+	change, err := r.getChange(ctx, tx, branchID, ref)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case change != nil && change.Operation == branch.OpInsert:
+		// A record that only ever existed on the branch simply disappears
+		if _, err := tx.ExecContext(ctx, `DELETE FROM branch_records WHERE id = $1`, change.ID); err != nil {
+			return fmt.Errorf("failed to delete branch record: %w", err)
+		}
+	case change != nil && change.Operation == branch.OpDelete:
+		return fmt.Errorf("%w: record %s is already deleted on this branch", branch.ErrNotFound, ref)
+	default:
+		if change == nil {
+			base, err := r.getBaseRecord(ctx, tx, b.TableID, ref.RecordID)
+			if err != nil {
+				return err
+			}
+			change = &branch.Change{BranchID: branchID, RecordID: ref.RecordID, BaseData: base}
+		}
+		change.Operation = branch.OpDelete
+		change.Data = nil
+		if err := r.saveChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CreateMergeRequest opens a merge request; a branch has at most one open request
+func (r *PostgresBranchRepository) CreateMergeRequest(ctx context.Context, mr *branch.MergeRequest) error {
+	query := `
+		INSERT INTO merge_requests (branch_id, title, description, status, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(ctx, query, mr.BranchID, mr.Title, mr.Description, mr.Status, mr.CreatedBy, mr.CreatedAt).Scan(&mr.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return branch.ErrAlreadyOpen
+		}
+		return fmt.Errorf("failed to create merge request: %w", err)
+	}
+	return nil
+}
+
+// CloseMergeRequest closes an open merge request without merging
+func (r *PostgresBranchRepository) CloseMergeRequest(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE merge_requests SET status = $1 WHERE id = $2 AND status = $3`,
+		branch.StatusClosed, id, branch.StatusOpen)
+	if err != nil {
+		return fmt.Errorf("failed to close merge request: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if _, err := r.FindMergeRequestByID(ctx, id); err != nil {
+			return err
+		}
+		return branch.ErrNotOpen
+	}
+	return nil
+}
+
+// Merge applies the branch to its base table in one transaction. The merge
+// request, branch, base table row and touched records are locked so that
+// concurrent edits and merges cannot interleave. Every record write is
+// captured by the record revision log with the merge request as its source.
+func (r *PostgresBranchRepository) Merge(ctx context.Context, id int64, mergedBy string, resolutions []branch.Resolution) (*branch.MergePlan, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	mr, err := r.getMergeRequest(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if mr.Status != branch.StatusOpen {
+		return nil, branch.ErrNotOpen
+	}
+	b, err := r.getOpenBranch(ctx, tx, mr.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM tables WHERE id = $1 FOR UPDATE`, b.TableID); err != nil {
+		return nil, fmt.Errorf("failed to lock table: %w", err)
+	}
+
+	plan, err := r.planMerge(ctx, tx, b, resolutions, true)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.Mergeable() {
+		return plan, &branch.ConflictError{Conflicts: plan.Conflicts}
+	}
+
+	source := fmt.Sprintf("merge_request:%d", mr.ID)
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('progressive.revision_source', $1, true), set_config('progressive.revision_actor', $2, true)`,
+		source, mergedBy); err != nil {
+		return nil, fmt.Errorf("failed to set revision context: %w", err)
+	}
+
+	now := time.Now()
+	for _, u := range plan.Updates {
+		data, err := json.Marshal(u.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode record %d: %w", u.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE records SET data = $1, updated_at = $2 WHERE id = $3 AND table_id = $4`,
+			json.RawMessage(data), now, u.ID, b.TableID); err != nil {
+			return nil, fmt.Errorf("failed to update record %d: %w", u.ID, err)
+		}
+	}
+	for _, recordData := range plan.Inserts {
+		data, err := json.Marshal(recordData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode record: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO records (table_id, data, created_at) VALUES ($1, $2, $3)`,
+			b.TableID, json.RawMessage(data), now); err != nil {
+			return nil, fmt.Errorf("failed to insert record: %w", err)
+		}
+	}
+	if len(plan.Deletes) > 0 {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE table_id = $1 AND id = ANY($2)`,
+			b.TableID, pq.Array(plan.Deletes)); err != nil {
+			return nil, fmt.Errorf("failed to delete records: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE tables SET updated_at = $1 WHERE id = $2`, now, b.TableID); err != nil {
+		return nil, fmt.Errorf("failed to update table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE merge_requests SET status = $1, merged_by = $2, merged_at = $3 WHERE id = $4`,
+		branch.StatusMerged, mergedBy, now, mr.ID); err != nil {
+		return nil, fmt.Errorf("failed to update merge request: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE branches SET status = $1, updated_at = $2 WHERE id = $3`,
+		branch.StatusMerged, now, b.ID); err != nil {
+		return nil, fmt.Errorf("failed to update branch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+	return plan, nil
+}
+
+// planMerge loads the branch changes and the base records they touch
+func (r *PostgresBranchRepository) planMerge(ctx context.Context, q sqlx.QueryerContext, b *branch.Branch, resolutions []branch.Resolution, lock bool) (*branch.MergePlan, error) {
+	changes, err := r.selectChanges(ctx, q, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	for _, c := range changes {
+		if c.RecordID != 0 {
+			ids = append(ids, c.RecordID)
+		}
+	}
+
+	query := `SELECT id, data FROM records WHERE table_id = $1 AND id = ANY($2)`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var rows []recordDB
+	if err := sqlx.SelectContext(ctx, q, &rows, query, b.TableID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to load base records: %w", err)
+	}
+
+	current := make(map[int64]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		data, err := decodeData(row.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode record %d: %w", row.ID, err)
+		}
+		current[row.ID] = data
+	}
+
+	return branch.PlanMerge(changes, current, resolutions), nil
+}
+
+func (r *PostgresBranchRepository) getBranch(ctx context.Context, q sqlx.QueryerContext, id string, lock bool) (*branch.Branch, error) {
+	query := `SELECT ` + branchColumns + ` FROM branches WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var row branchDB
+	if err := sqlx.GetContext(ctx, q, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: branch %s", branch.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find branch %s: %w", id, err)
+	}
+	return r.toDomain(&row), nil
+}
+
+// getOpenBranch locks the branch row and checks that it still accepts edits
+func (r *PostgresBranchRepository) getOpenBranch(ctx context.Context, tx *sqlx.Tx, id string) (*branch.Branch, error) {
+	b, err := r.getBranch(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != branch.StatusOpen {
+		return nil, fmt.Errorf("%w: branch %s is %s", branch.ErrNotOpen, b.Name, b.Status)
+	}
+	return b, nil
+}
+
+func (r *PostgresBranchRepository) getMergeRequest(ctx context.Context, q sqlx.QueryerContext, id int64, lock bool) (*branch.MergeRequest, error) {
+	query := `SELECT ` + mergeRequestColumns + ` FROM merge_requests WHERE id = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var row mergeRequestDB
+	if err := sqlx.GetContext(ctx, q, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: merge request %d", branch.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find merge request %d: %w", id, err)
+	}
+	return r.toDomainMergeRequest(&row), nil
+}
+
+func (r *PostgresBranchRepository) selectChanges(ctx context.Context, q sqlx.QueryerContext, branchID string) ([]branch.Change, error) {
+	var rows []changeDB
+	query := `SELECT ` + changeColumns + ` FROM branch_records WHERE branch_id = $1 ORDER BY id`
+	if err := sqlx.SelectContext(ctx, q, &rows, query, branchID); err != nil {
+		return nil, fmt.Errorf("failed to find branch changes: %w", err)
+	}
+
+	changes := make([]branch.Change, len(rows))
+	for i := range rows {
+		change, err := r.toDomainChange(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = *change
+	}
+	return changes, nil
+}
+
+// getChange returns the overlay entry for ref, or nil if the branch has not touched it
+func (r *PostgresBranchRepository) getChange(ctx context.Context, tx *sqlx.Tx, branchID string, ref branch.Ref) (*branch.Change, error) {
+	query := `SELECT ` + changeColumns + ` FROM branch_records WHERE branch_id = $1 AND record_id = $2`
+	arg := ref.RecordID
+	if ref.ChangeID != 0 {
+		query = `SELECT ` + changeColumns + ` FROM branch_records WHERE branch_id = $1 AND id = $2 AND operation = 'insert'`
+		arg = ref.ChangeID
+	}
+
+	var row changeDB
+	if err := tx.GetContext(ctx, &row, query+` FOR UPDATE`, branchID, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if ref.ChangeID != 0 {
+				return nil, fmt.Errorf("%w: record %s", branch.ErrNotFound, ref)
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find branch record: %w", err)
+	}
+	return r.toDomainChange(&row)
+}
+
+func (r *PostgresBranchRepository) getBaseRecord(ctx context.Context, tx *sqlx.Tx, tableID string, recordID int64) (map[string]interface{}, error) {
+	var raw json.RawMessage
+	err := tx.GetContext(ctx, &raw, `SELECT data FROM records WHERE id = $1 AND table_id = $2`, recordID, tableID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: record %d", branch.ErrNotFound, recordID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find record %d: %w", recordID, err)
+	}
+	return decodeData(raw)
+}
+
+// saveChange inserts or updates an overlay entry and touches the branch
+func (r *PostgresBranchRepository) saveChange(ctx context.Context, tx *sqlx.Tx, c *branch.Change) error {
+	baseData, err := encodeData(c.BaseData)
+	if err != nil {
+		return err
+	}
+	data, err := encodeData(c.Data)
+	if err != nil {
+		return err
+	}
+	var recordID sql.NullInt64
+	if c.Operation != branch.OpInsert {
+		recordID = sql.NullInt64{Int64: c.RecordID, Valid: true}
+	}
+
+	c.UpdatedAt = time.Now()
+	if c.ID == 0 {
+		query := `
+			INSERT INTO branch_records (branch_id, record_id, operation, base_data, data, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`
+		err = tx.QueryRowContext(ctx, query, c.BranchID, recordID, c.Operation, baseData, data, c.UpdatedAt).Scan(&c.ID)
+	} else {
+		query := `UPDATE branch_records SET operation = $1, data = $2, updated_at = $3 WHERE id = $4`
+		_, err = tx.ExecContext(ctx, query, c.Operation, data, c.UpdatedAt, c.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save branch record: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE branches SET updated_at = $1 WHERE id = $2`, c.UpdatedAt, c.BranchID); err != nil {
+		return fmt.Errorf("failed to touch branch: %w", err)
+	}
+	return nil
+}
+
+// Helper methods for conversion between domain and database models
+
+func (r *PostgresBranchRepository) toDomain(db *branchDB) *branch.Branch {
+	return &branch.Branch{
+		ID:          db.ID,
+		TableID:     db.TableID,
+		Name:        db.Name,
+		Description: db.Description,
+		Status:      db.Status,
+		CreatedBy:   db.CreatedBy,
+		CreatedAt:   db.CreatedAt,
+		UpdatedAt:   db.UpdatedAt,
+	}
+}
+
+func (r *PostgresBranchRepository) toDBModel(domain *branch.Branch) *branchDB {
+	return &branchDB{
+		ID:          domain.ID,
+		TableID:     domain.TableID,
+		Name:        domain.Name,
+		Description: domain.Description,
+		Status:      domain.Status,
+		CreatedBy:   domain.CreatedBy,
+		CreatedAt:   domain.CreatedAt,
+		UpdatedAt:   domain.UpdatedAt,
+	}
+}
+
+func (r *PostgresBranchRepository) toDomainChange(db *changeDB) (*branch.Change, error) {
+	baseData, err := decodeData(db.BaseData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base data of branch record %d: %w", db.ID, err)
+	}
+	data, err := decodeData(db.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode branch record %d: %w", db.ID, err)
+	}
+	return &branch.Change{
+		ID:        db.ID,
+		BranchID:  db.BranchID,
+		RecordID:  db.RecordID.Int64,
+		Operation: db.Operation,
+		BaseData:  baseData,
+		Data:      data,
+		UpdatedAt: db.UpdatedAt,
+	}, nil
+}
+
+func (r *PostgresBranchRepository) toDomainMergeRequest(db *mergeRequestDB) *branch.MergeRequest {
+	mr := &branch.MergeRequest{
+		ID:          db.ID,
+		BranchID:    db.BranchID,
+		Title:       db.Title,
+		Description: db.Description,
+		Status:      db.Status,
+		CreatedBy:   db.CreatedBy,
+		CreatedAt:   db.CreatedAt,
+		MergedBy:    db.MergedBy.String,
+	}
+	if db.MergedAt.Valid {
+		mergedAt := db.MergedAt.Time
+		mr.MergedAt = &mergedAt
+	}
+	return mr
+}
+
+// applyPatch returns a copy of data with the patch fields set, matching the
+// shallow merge used by the record PATCH API
+func applyPatch(data, patch map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(data)+len(patch))
+	for k, v := range data {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		merged[k] = v
+	}
+	return merged
+}
+
+func decodeData(raw json.RawMessage) (map[string]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func encodeData(data map[string]interface{}) (interface{}, error) {
+	if data == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode record data: %w", err)
+	}
+	return json.RawMessage(raw), nil
+}
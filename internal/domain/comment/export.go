@@ -0,0 +1,92 @@
+package comment
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Location describes a thread anchor for people: "table", "record 12" or "record 12 · price"
+func (a Anchor) Location() string {
+	switch {
+	case a.RecordID == 0:
+		return "table"
+	case a.Field == "":
+		return fmt.Sprintf("record %d", a.RecordID)
+	default:
+		return fmt.Sprintf("record %d · %s", a.RecordID, a.Field)
+	}
+}
+
+// WriteCSV writes one row per thread, with its opening and latest comment
+func WriteCSV(w io.Writer, threads []*Thread) error {
+	cw := csv.NewWriter(w)
+	header := []string{"thread_id", "record_id", "field", "opened_by", "opened_at", "comments", "mentions", "first_comment", "latest_by", "latest_comment", "last_activity"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, t := range threads {
+		recordID := ""
+		if t.Anchor.RecordID != 0 {
+			recordID = strconv.FormatInt(t.Anchor.RecordID, 10)
+		}
+		var first, latest *Comment
+		if n := len(t.Comments); n > 0 {
+			first, latest = t.Comments[0], t.Comments[n-1]
+		} else {
+			first, latest = &Comment{}, &Comment{}
+		}
+		row := []string{
+			strconv.FormatInt(t.ID, 10),
+			recordID,
+			t.Anchor.Field,
+			t.CreatedBy,
+			t.CreatedAt.Format("2006-01-02 15:04"),
+			strconv.Itoa(len(t.Comments)),
+			strings.Join(t.Mentions(), " "),
+			first.Body,
+			latest.Author,
+			latest.Body,
+			t.UpdatedAt.Format("2006-01-02 15:04"),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown writes the threads as a review-meeting agenda
+func WriteMarkdown(w io.Writer, title string, threads []*Thread) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if len(threads) == 0 {
+		b.WriteString("No unresolved threads.\n")
+	}
+
+	for _, t := range threads {
+		fmt.Fprintf(&b, "## #%d — %s\n\n", t.ID, t.Anchor.Location())
+		fmt.Fprintf(&b, "Opened by %s on %s", t.CreatedBy, t.CreatedAt.Format("2006-01-02"))
+		if mentions := t.Mentions(); len(mentions) > 0 {
+			fmt.Fprintf(&b, " · mentions @%s", strings.Join(mentions, ", @"))
+		}
+		b.WriteString("\n\n")
+		for _, c := range t.Comments {
+			edited := ""
+			if c.Edited {
+				edited = " (edited)"
+			}
+			fmt.Fprintf(&b, "- **%s** %s%s: %s\n", c.Author, c.CreatedAt.Format("01-02 15:04"), edited,
+				strings.ReplaceAll(c.Body, "\n", "\n  "))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,179 @@
+package comment
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Thread statuses used for filtering
+const (
+	StatusOpen     = "open"
+	StatusResolved = "resolved"
+	StatusAll      = "all"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidInput  = errors.New("invalid input")
+	ErrTableNotFound = errors.New("table not found")
+	ErrNotAuthor     = errors.New("only the author can edit a comment")
+)
+
+// Anchor identifies what a thread is attached to: the whole table, a record,
+// or a single (record, field) cell
+type Anchor struct {
+	RecordID int64  `json:"record_id,omitempty"`
+	Field    string `json:"field,omitempty"`
+}
+
+// Validate rejects a field anchor without a record
+func (a Anchor) Validate() error {
+	if a.RecordID < 0 {
+		return fmt.Errorf("%w: invalid record_id", ErrInvalidInput)
+	}
+	if a.Field != "" && a.RecordID == 0 {
+		return fmt.Errorf("%w: a field anchor requires record_id", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Thread is a discussion attached to a table, record or cell
+type Thread struct {
+	ID         int64      `json:"id"`
+	TableID    string     `json:"table_id"`
+	Anchor     Anchor     `json:"anchor"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedBy string     `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Comments   []*Comment `json:"comments"`
+}
+
+// NewThread creates an open thread whose first comment is body
+func NewThread(tableID string, anchor Anchor, author, body string) (*Thread, error) {
+	if tableID == "" {
+		return nil, fmt.Errorf("%w: table_id is required", ErrInvalidInput)
+	}
+	if err := anchor.Validate(); err != nil {
+		return nil, err
+	}
+	first, err := NewComment(author, body)
+	if err != nil {
+		return nil, err
+	}
+	return &Thread{
+		TableID:   tableID,
+		Anchor:    anchor,
+		CreatedBy: first.Author,
+		CreatedAt: first.CreatedAt,
+		UpdatedAt: first.CreatedAt,
+		Comments:  []*Comment{first},
+	}, nil
+}
+
+// Mentions returns every distinct user mentioned in the thread
+func (t *Thread) Mentions() []string {
+	var all []string
+	for _, c := range t.Comments {
+		all = append(all, c.Mentions...)
+	}
+	return dedupe(all)
+}
+
+// Comment is one message in a thread
+type Comment struct {
+	ID        int64     `json:"id"`
+	ThreadID  int64     `json:"thread_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Mentions  []string  `json:"mentions"`
+	Edited    bool      `json:"edited"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewComment creates a comment and extracts its @mentions
+func NewComment(author, body string) (*Comment, error) {
+	author = strings.TrimSpace(author)
+	body = strings.TrimSpace(body)
+	if author == "" || body == "" {
+		return nil, fmt.Errorf("%w: author and body are required", ErrInvalidInput)
+	}
+	now := time.Now()
+	return &Comment{
+		Author:    author,
+		Body:      body,
+		Mentions:  ParseMentions(body),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Edit replaces the body, returning the previous one for the edit history
+func (c *Comment) Edit(editor, body string) (*Edit, error) {
+	if editor != c.Author {
+		return nil, ErrNotAuthor
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidInput)
+	}
+	now := time.Now()
+	edit := &Edit{CommentID: c.ID, Body: c.Body, EditedAt: now}
+	c.Body = body
+	c.Mentions = ParseMentions(body)
+	c.Edited = true
+	c.UpdatedAt = now
+	return edit, nil
+}
+
+// Edit is a previous version of a comment body
+type Edit struct {
+	ID        int64     `json:"id"`
+	CommentID int64     `json:"comment_id"`
+	Body      string    `json:"body"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+// Filter narrows thread queries
+type Filter struct {
+	Status   string
+	RecordID int64
+	Field    string
+	Mention  string
+}
+
+// CellCount is the number of open threads on one anchor, used for grid indicators
+type CellCount struct {
+	RecordID int64  `json:"record_id" db:"record_id"`
+	Field    string `json:"field" db:"field"`
+	Open     int    `json:"open" db:"open"`
+}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w][\w.-]*)`)
+
+// ParseMentions returns the distinct @user names in body, in order of appearance
+func ParseMentions(body string) []string {
+	var names []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		names = append(names, strings.TrimRight(m[1], ".-"))
+	}
+	return dedupe(names)
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := []string{}
+	for _, n := range names {
+		if n != "" && !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
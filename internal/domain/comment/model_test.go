@@ -0,0 +1,97 @@
+package comment
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMentions(t *testing.T) {
+	got := ParseMentions("@alice can you check this with @bob.lee? cc @alice, mail: x@example.com")
+	expected := []string{"alice", "bob.lee"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got: %v", expected, got)
+	}
+	if got := ParseMentions("no mentions here"); len(got) != 0 {
+		t.Errorf("Expected no mentions, got: %v", got)
+	}
+}
+
+func TestAnchorValidate(t *testing.T) {
+	valid := []Anchor{{}, {RecordID: 3}, {RecordID: 3, Field: "price"}}
+	for _, a := range valid {
+		if err := a.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got: %v", a, err)
+		}
+	}
+	if err := (Anchor{Field: "price"}).Validate(); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for a field without record, got: %v", err)
+	}
+}
+
+func TestNewThreadRequiresAuthorAndBody(t *testing.T) {
+	if _, err := NewThread("table_item", Anchor{RecordID: 1}, "alice", "  "); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for empty body, got: %v", err)
+	}
+	thread, err := NewThread("table_item", Anchor{RecordID: 1, Field: "price"}, "alice", "Too cheap @bob")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if thread.CreatedBy != "alice" || len(thread.Comments) != 1 || !reflect.DeepEqual(thread.Mentions(), []string{"bob"}) {
+		t.Errorf("Unexpected thread: %+v", thread)
+	}
+}
+
+func TestCommentEditKeepsPreviousBody(t *testing.T) {
+	c, _ := NewComment("alice", "price 10 @bob")
+	if _, err := c.Edit("bob", "hijacked"); !errors.Is(err, ErrNotAuthor) {
+		t.Errorf("Expected ErrNotAuthor, got: %v", err)
+	}
+
+	edit, err := c.Edit("alice", "price 12 @carol")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if edit.Body != "price 10 @bob" {
+		t.Errorf("Expected previous body in edit, got: %q", edit.Body)
+	}
+	if c.Body != "price 12 @carol" || !c.Edited || !reflect.DeepEqual(c.Mentions, []string{"carol"}) {
+		t.Errorf("Unexpected edited comment: %+v", c)
+	}
+}
+
+func TestExportFormats(t *testing.T) {
+	at := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	threads := []*Thread{{
+		ID: 7, Anchor: Anchor{RecordID: 12, Field: "price"}, CreatedBy: "alice", CreatedAt: at, UpdatedAt: at,
+		Comments: []*Comment{
+			{Author: "alice", Body: "Too cheap, @bob?", Mentions: []string{"bob"}, CreatedAt: at},
+			{Author: "bob", Body: "Agreed, \"120\"", CreatedAt: at, Edited: true},
+		},
+	}}
+
+	var csvOut bytes.Buffer
+	if err := WriteCSV(&csvOut, threads); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header and one row, got: %q", csvOut.String())
+	}
+	if lines[1] != `7,12,price,alice,2025-03-01 10:30,2,bob,"Too cheap, @bob?",bob,"Agreed, ""120""",2025-03-01 10:30` {
+		t.Errorf("Unexpected CSV row: %s", lines[1])
+	}
+
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, "game_item", threads); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	for _, want := range []string{"# game_item", "## #7 — record 12 · price", "mentions @bob", "**bob** 03-01 10:30 (edited)"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, md.String())
+		}
+	}
+}
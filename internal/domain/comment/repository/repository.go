@@ -0,0 +1,393 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"progressive/internal/domain/comment"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ReadRepository defines read operations for comment threads
+type ReadRepository interface {
+	FindThreads(ctx context.Context, tableID string, filter comment.Filter) ([]*comment.Thread, error)
+	FindThreadByID(ctx context.Context, tableID string, id int64) (*comment.Thread, error)
+	FindEdits(ctx context.Context, tableID string, threadID, commentID int64) ([]*comment.Edit, error)
+	CountOpen(ctx context.Context, tableID string) ([]comment.CellCount, error)
+}
+
+// WriteRepository defines write operations for comment threads
+type WriteRepository interface {
+	CreateThread(ctx context.Context, t *comment.Thread) error
+	AddComment(ctx context.Context, tableID string, threadID int64, c *comment.Comment) error
+	EditComment(ctx context.Context, tableID string, threadID, commentID int64, editor, body string) (*comment.Comment, error)
+	SetResolved(ctx context.Context, tableID string, threadID int64, resolved bool, by string) (*comment.Thread, error)
+}
+
+// CommentRepository combines both ReadRepository and WriteRepository interfaces
+type CommentRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// threadDB represents the database model
+type threadDB struct {
+	ID         int64          `db:"id"`
+	TableID    string         `db:"table_id"`
+	RecordID   sql.NullInt64  `db:"record_id"`
+	Field      string         `db:"field"`
+	Resolved   bool           `db:"resolved"`
+	ResolvedBy sql.NullString `db:"resolved_by"`
+	ResolvedAt sql.NullTime   `db:"resolved_at"`
+	CreatedBy  string         `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+}
+
+type commentDB struct {
+	ID        int64          `db:"id"`
+	ThreadID  int64          `db:"thread_id"`
+	Author    string         `db:"author"`
+	Body      string         `db:"body"`
+	Mentions  pq.StringArray `db:"mentions"`
+	Edited    bool           `db:"edited"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+const (
+	threadColumns  = `t.id, t.table_id, t.record_id, t.field, t.resolved, t.resolved_by, t.resolved_at, t.created_by, t.created_at, t.updated_at`
+	commentColumns = `c.id, c.thread_id, c.author, c.body, c.mentions, c.edited, c.created_at, c.updated_at`
+)
+
+// PostgresCommentRepository implements CommentRepository using PostgreSQL
+type PostgresCommentRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL comment repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresCommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+// FindThreads lists the threads of a table with their comments, oldest first
+func (r *PostgresCommentRepository) FindThreads(ctx context.Context, tableID string, filter comment.Filter) ([]*comment.Thread, error) {
+	conditions := []string{"t.table_id = $1"}
+	args := []interface{}{tableID}
+
+	switch filter.Status {
+	case "", comment.StatusAll:
+	case comment.StatusOpen:
+		conditions = append(conditions, "NOT t.resolved")
+	case comment.StatusResolved:
+		conditions = append(conditions, "t.resolved")
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", comment.ErrInvalidInput, filter.Status)
+	}
+	if filter.RecordID != 0 {
+		args = append(args, filter.RecordID)
+		conditions = append(conditions, fmt.Sprintf("t.record_id = $%d", len(args)))
+	}
+	if filter.Field != "" {
+		args = append(args, filter.Field)
+		conditions = append(conditions, fmt.Sprintf("t.field = $%d", len(args)))
+	}
+	if filter.Mention != "" {
+		args = append(args, filter.Mention)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM comments m WHERE m.thread_id = t.id AND $%d = ANY(m.mentions))", len(args)))
+	}
+
+	var rows []threadDB
+	query := `SELECT ` + threadColumns + ` FROM comment_threads t WHERE ` + strings.Join(conditions, " AND ") +
+		` ORDER BY t.record_id NULLS FIRST, t.field, t.id`
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find comment threads: %w", err)
+	}
+
+	threads := make([]*comment.Thread, len(rows))
+	byID := make(map[int64]*comment.Thread, len(rows))
+	ids := make([]int64, len(rows))
+	for i := range rows {
+		threads[i] = r.toDomain(&rows[i])
+		byID[rows[i].ID] = threads[i]
+		ids[i] = rows[i].ID
+	}
+	if len(ids) == 0 {
+		return threads, nil
+	}
+
+	var comments []commentDB
+	query = `SELECT ` + commentColumns + ` FROM comments c WHERE c.thread_id = ANY($1) ORDER BY c.thread_id, c.id`
+	if err := r.db.SelectContext(ctx, &comments, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	for i := range comments {
+		t := byID[comments[i].ThreadID]
+		t.Comments = append(t.Comments, toDomainComment(&comments[i]))
+	}
+	return threads, nil
+}
+
+// FindThreadByID retrieves a thread of the table with its comments
+func (r *PostgresCommentRepository) FindThreadByID(ctx context.Context, tableID string, id int64) (*comment.Thread, error) {
+	return r.getThread(ctx, r.db, tableID, id, false)
+}
+
+// FindEdits returns the previous bodies of a comment, oldest first
+func (r *PostgresCommentRepository) FindEdits(ctx context.Context, tableID string, threadID, commentID int64) ([]*comment.Edit, error) {
+	if _, err := r.getComment(ctx, r.db, tableID, threadID, commentID, false); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ID        int64     `db:"id"`
+		CommentID int64     `db:"comment_id"`
+		Body      string    `db:"body"`
+		EditedAt  time.Time `db:"edited_at"`
+	}
+	query := `SELECT id, comment_id, body, edited_at FROM comment_edits WHERE comment_id = $1 ORDER BY id`
+	if err := r.db.SelectContext(ctx, &rows, query, commentID); err != nil {
+		return nil, fmt.Errorf("failed to find comment edits: %w", err)
+	}
+
+	edits := make([]*comment.Edit, len(rows))
+	for i, row := range rows {
+		edits[i] = &comment.Edit{ID: row.ID, CommentID: row.CommentID, Body: row.Body, EditedAt: row.EditedAt}
+	}
+	return edits, nil
+}
+
+// CountOpen returns the number of open threads per record and field
+func (r *PostgresCommentRepository) CountOpen(ctx context.Context, tableID string) ([]comment.CellCount, error) {
+	counts := []comment.CellCount{}
+	query := `
+		SELECT COALESCE(record_id, 0) AS record_id, field, COUNT(*) AS open
+		FROM comment_threads
+		WHERE table_id = $1 AND NOT resolved
+		GROUP BY record_id, field
+		ORDER BY record_id NULLS FIRST, field
+	`
+	if err := r.db.SelectContext(ctx, &counts, query, tableID); err != nil {
+		return nil, fmt.Errorf("failed to count open threads: %w", err)
+	}
+	return counts, nil
+}
+
+// CreateThread stores a thread and its first comment
+func (r *PostgresCommentRepository) CreateThread(ctx context.Context, t *comment.Thread) error {
+	if len(t.Comments) == 0 {
+		return fmt.Errorf("%w: a thread needs a first comment", comment.ErrInvalidInput)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var recordID sql.NullInt64
+	if t.Anchor.RecordID != 0 {
+		recordID = sql.NullInt64{Int64: t.Anchor.RecordID, Valid: true}
+	}
+	query := `
+		INSERT INTO comment_threads (table_id, record_id, field, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id
+	`
+	if err := tx.GetContext(ctx, &t.ID, query, t.TableID, recordID, t.Anchor.Field, t.CreatedBy, t.CreatedAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%w: %s", comment.ErrTableNotFound, t.TableID)
+		}
+		return fmt.Errorf("failed to create comment thread: %w", err)
+	}
+
+	if err := insertComment(ctx, tx, t.ID, t.Comments[0]); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddComment appends a reply to a thread
+func (r *PostgresCommentRepository) AddComment(ctx context.Context, tableID string, threadID int64, c *comment.Comment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.lockThread(ctx, tx, tableID, threadID); err != nil {
+		return err
+	}
+	if err := insertComment(ctx, tx, threadID, c); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE comment_threads SET updated_at = $2 WHERE id = $1`, threadID, c.CreatedAt); err != nil {
+		return fmt.Errorf("failed to touch comment thread: %w", err)
+	}
+	return tx.Commit()
+}
+
+// EditComment replaces a comment body and records the previous one
+func (r *PostgresCommentRepository) EditComment(ctx context.Context, tableID string, threadID, commentID int64, editor, body string) (*comment.Comment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	c, err := r.getComment(ctx, tx, tableID, threadID, commentID, true)
+	if err != nil {
+		return nil, err
+	}
+	edit, err := c.Edit(editor, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO comment_edits (comment_id, body, edited_at) VALUES ($1, $2, $3)`,
+		edit.CommentID, edit.Body, edit.EditedAt); err != nil {
+		return nil, fmt.Errorf("failed to record comment edit: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE comments SET body = $2, mentions = $3, edited = TRUE, updated_at = $4 WHERE id = $1`,
+		c.ID, c.Body, pq.Array(c.Mentions), c.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return c, nil
+}
+
+// SetResolved resolves or reopens a thread
+func (r *PostgresCommentRepository) SetResolved(ctx context.Context, tableID string, threadID int64, resolved bool, by string) (*comment.Thread, error) {
+	query := `
+		UPDATE comment_threads
+		SET resolved = $3,
+			resolved_by = CASE WHEN $3 THEN $4 END,
+			resolved_at = CASE WHEN $3 THEN NOW() END,
+			updated_at = NOW()
+		WHERE table_id = $1 AND id = $2
+	`
+	result, err := r.db.ExecContext(ctx, query, tableID, threadID, resolved, by)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment thread: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, comment.ErrNotFound
+	}
+	return r.FindThreadByID(ctx, tableID, threadID)
+}
+
+func (r *PostgresCommentRepository) getThread(ctx context.Context, q sqlx.QueryerContext, tableID string, id int64, forUpdate bool) (*comment.Thread, error) {
+	query := `SELECT ` + threadColumns + ` FROM comment_threads t WHERE t.table_id = $1 AND t.id = $2`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	var row threadDB
+	if err := sqlx.GetContext(ctx, q, &row, query, tableID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, comment.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find comment thread: %w", err)
+	}
+	t := r.toDomain(&row)
+
+	var comments []commentDB
+	query = `SELECT ` + commentColumns + ` FROM comments c WHERE c.thread_id = $1 ORDER BY c.id`
+	if err := sqlx.SelectContext(ctx, q, &comments, query, id); err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	for i := range comments {
+		t.Comments = append(t.Comments, toDomainComment(&comments[i]))
+	}
+	return t, nil
+}
+
+func (r *PostgresCommentRepository) lockThread(ctx context.Context, tx *sqlx.Tx, tableID string, id int64) error {
+	var threadID int64
+	err := tx.GetContext(ctx, &threadID, `SELECT id FROM comment_threads WHERE table_id = $1 AND id = $2 FOR UPDATE`, tableID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return comment.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock comment thread: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCommentRepository) getComment(ctx context.Context, q sqlx.QueryerContext, tableID string, threadID, commentID int64, forUpdate bool) (*comment.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments c JOIN comment_threads t ON t.id = c.thread_id
+		WHERE t.table_id = $1 AND c.thread_id = $2 AND c.id = $3
+	`
+	if forUpdate {
+		query += ` FOR UPDATE OF c`
+	}
+	var row commentDB
+	if err := sqlx.GetContext(ctx, q, &row, query, tableID, threadID, commentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, comment.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+	return toDomainComment(&row), nil
+}
+
+func insertComment(ctx context.Context, tx *sqlx.Tx, threadID int64, c *comment.Comment) error {
+	query := `
+		INSERT INTO comments (thread_id, author, body, mentions, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	if err := tx.GetContext(ctx, &c.ID, query, threadID, c.Author, c.Body, pq.Array(c.Mentions), c.CreatedAt, c.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	c.ThreadID = threadID
+	return nil
+}
+
+// toDomain converts database model to domain model
+func (r *PostgresCommentRepository) toDomain(row *threadDB) *comment.Thread {
+	t := &comment.Thread{
+		ID:         row.ID,
+		TableID:    row.TableID,
+		Anchor:     comment.Anchor{RecordID: row.RecordID.Int64, Field: row.Field},
+		Resolved:   row.Resolved,
+		ResolvedBy: row.ResolvedBy.String,
+		CreatedBy:  row.CreatedBy,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+		Comments:   []*comment.Comment{},
+	}
+	if row.ResolvedAt.Valid {
+		t.ResolvedAt = &row.ResolvedAt.Time
+	}
+	return t
+}
+
+func toDomainComment(row *commentDB) *comment.Comment {
+	mentions := []string(row.Mentions)
+	if mentions == nil {
+		mentions = []string{}
+	}
+	return &comment.Comment{
+		ID:        row.ID,
+		ThreadID:  row.ThreadID,
+		Author:    row.Author,
+		Body:      row.Body,
+		Mentions:  mentions,
+		Edited:    row.Edited,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,220 @@
+package record
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a file format records are exported to or imported from
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatCSV   Format = "csv"
+	FormatExcel Format = "excel"
+	FormatYAML  Format = "yaml"
+)
+
+// ExportFormats are the formats the export API offers
+var ExportFormats = []Format{FormatJSON, FormatCSV, FormatExcel}
+
+// ContentType returns the MIME type of an export
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatExcel:
+		return "application/vnd.ms-excel"
+	case FormatYAML:
+		return "application/yaml"
+	default:
+		return "application/json"
+	}
+}
+
+// Extension returns the file extension of an export, without the dot
+func (f Format) Extension() string {
+	switch f {
+	case FormatExcel:
+		return "xlsx"
+	case FormatYAML:
+		return "yaml"
+	default:
+		return string(f)
+	}
+}
+
+// FormatOf guesses the format of a file from its name
+func FormatOf(filename string) (Format, error) {
+	dot := strings.LastIndex(filename, ".")
+	switch strings.ToLower(filename[dot+1:]) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "xlsx", "xls":
+		return FormatExcel, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported file type: %s", filename)
+}
+
+// Write writes rows in the given format
+func Write(w io.Writer, format Format, rows []map[string]interface{}) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, rows)
+	case FormatCSV, FormatExcel:
+		// Excel opens the CSV export; there is no native workbook writer yet
+		return WriteCSV(w, rows)
+	case FormatYAML:
+		return WriteYAML(w, rows)
+	}
+	return fmt.Errorf("unsupported format: %s", format)
+}
+
+// WriteJSON writes rows as a JSON array
+func WriteJSON(w io.Writer, rows []map[string]interface{}) error {
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+	return json.NewEncoder(w).Encode(rows)
+}
+
+// WriteYAML writes rows as a YAML sequence
+func WriteYAML(w io.Writer, rows []map[string]interface{}) error {
+	if rows == nil {
+		rows = []map[string]interface{}{}
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(rows); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteCSV writes rows with a header of every field name, sorted. Every
+// value is quoted; arrays and objects are written as JSON.
+func WriteCSV(w io.Writer, rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	headers := Columns(rows)
+
+	var b strings.Builder
+	for _, row := range append([]map[string]interface{}{nil}, rows...) {
+		for i, header := range headers {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			value := header
+			if row != nil {
+				value = cell(row[header])
+			}
+			b.WriteString(`"` + strings.ReplaceAll(value, `"`, `""`) + `"`)
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Columns returns the field names used by any of the rows, sorted
+func Columns(rows []map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, key)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func cell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}, map[string]interface{}:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Read parses rows from a file in the given format. CSV values stay
+// strings; convert them with validation.Validator.Coerce.
+func Read(r io.Reader, format Format) ([]map[string]interface{}, error) {
+	switch format {
+	case FormatJSON:
+		return ReadJSON(r)
+	case FormatYAML:
+		return ReadYAML(r)
+	case FormatCSV:
+		return ReadCSV(r)
+	}
+	return nil, fmt.Errorf("unsupported import format: %s", format)
+}
+
+// ReadJSON parses a JSON array of objects, keeping numbers as float64 like
+// the API does
+func ReadJSON(r io.Reader) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// ReadYAML parses a YAML sequence of mappings. Values are normalized
+// through JSON so that they compare equal to records read from the API.
+func ReadYAML(r io.Reader) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	if err := yaml.NewDecoder(r).Decode(&rows); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	encoded, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return ReadJSON(bytes.NewReader(encoded))
+}
+
+// ReadCSV parses a CSV file with a header row; empty cells are left out
+func ReadCSV(r io.Reader) ([]map[string]interface{}, error) {
+	cr := csv.NewReader(r)
+	lines, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	headers := lines[0]
+	rows := make([]map[string]interface{}, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		row := make(map[string]interface{}, len(headers))
+		for i, header := range headers {
+			if i < len(line) && line[i] != "" {
+				row[strings.TrimSpace(header)] = line[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,56 @@
+package record
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSVUsesEveryColumn(t *testing.T) {
+	var b bytes.Buffer
+	err := WriteCSV(&b, []map[string]interface{}{
+		{"name": `the "sword"`, "price": float64(100)},
+		{"name": "bow", "tags": []interface{}{"ranged"}},
+	})
+	if err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	want := `"name","price","tags"` + "\n" +
+		`"the ""sword""","100",""` + "\n" +
+		`"bow","","[""ranged""]"` + "\n"
+	if b.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nexpected:\n%s", b.String(), want)
+	}
+}
+
+func TestReadRoundTrips(t *testing.T) {
+	rows := []map[string]interface{}{{"name": "sword", "price": float64(100)}}
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatCSV} {
+		var b bytes.Buffer
+		if err := Write(&b, format, rows); err != nil {
+			t.Fatalf("Write %s failed: %v", format, err)
+		}
+		read, err := Read(&b, format)
+		if err != nil {
+			t.Fatalf("Read %s failed: %v", format, err)
+		}
+		if len(read) != 1 || read[0]["name"] != "sword" {
+			t.Errorf("%s: unexpected rows %v", format, read)
+		}
+		// CSV keeps strings until the values are coerced to the schema
+		if format != FormatCSV && read[0]["price"] != float64(100) {
+			t.Errorf("%s: expected a float64 price, got %#v", format, read[0]["price"])
+		}
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	for name, want := range map[string]Format{"items.json": FormatJSON, "a.b.YML": FormatYAML, "x.csv": FormatCSV} {
+		if got, err := FormatOf(name); err != nil || got != want {
+			t.Errorf("FormatOf(%q) = %q, %v; expected %q", name, got, err, want)
+		}
+	}
+	if _, err := FormatOf("items.txt"); err == nil || !strings.Contains(err.Error(), "items.txt") {
+		t.Errorf("Expected an unsupported file error, got %v", err)
+	}
+}
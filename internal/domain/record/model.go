@@ -0,0 +1,86 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"progressive/internal/domain/table"
+)
+
+var (
+	ErrNotFound     = errors.New("record not found")
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrTableNotFound is table.ErrNotFound, so a missing table matches either
+	ErrTableNotFound = table.ErrNotFound
+)
+
+// Record is a single row of a table, stored as a JSON object
+type Record struct {
+	ID        int64                  `json:"id"`
+	TableID   string                 `json:"table_id"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// Page selects a window of records ordered newest first
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Changes are record writes to one table that are applied together
+type Changes struct {
+	Create []*Record
+	Update []*Record
+	Delete []int64
+}
+
+// NewRecord creates a new unsaved record
+func NewRecord(tableID string, data map[string]interface{}) *Record {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	now := time.Now()
+	return &Record{
+		TableID:   tableID,
+		Data:      data,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the fields required to store a record
+func (r *Record) Validate() error {
+	if r.TableID == "" {
+		return fmt.Errorf("%w: table id is required", ErrInvalidInput)
+	}
+	if r.Data == nil {
+		return fmt.Errorf("%w: record data is required", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Merge applies a partial update on top of the existing data
+func (r *Record) Merge(updates map[string]interface{}) {
+	if r.Data == nil {
+		r.Data = make(map[string]interface{}, len(updates))
+	}
+	for key, value := range updates {
+		r.Data[key] = value
+	}
+	r.UpdatedAt = time.Now()
+}
+
+// Flatten returns the record data with the "_id" and "_created_at" fields
+// the editor and API clients expect alongside the user fields
+func (r *Record) Flatten() map[string]interface{} {
+	flat := make(map[string]interface{}, len(r.Data)+2)
+	for key, value := range r.Data {
+		flat[key] = value
+	}
+	flat["_id"] = r.ID
+	flat["_created_at"] = r.CreatedAt
+	return flat
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
+)
+
+// MemoryRecordRepository implements RecordRepository in memory for tests.
+// It checks table existence and maintains record counts through the
+// in-memory table repository, mirroring the foreign key and count trigger.
+type MemoryRecordRepository struct {
+	mu      sync.RWMutex
+	tables  *tablerepo.MemoryTableRepository
+	records map[string][]*record.Record
+	nextID  int64
+}
+
+// NewMemoryRepository creates a new, empty in-memory record repository
+func NewMemoryRepository(tables *tablerepo.MemoryTableRepository) *MemoryRecordRepository {
+	return &MemoryRecordRepository{
+		tables:  tables,
+		records: make(map[string][]*record.Record),
+	}
+}
+
+// FindByTable retrieves a table's records, newest first.
+// A zero page limit returns every record.
+func (r *MemoryRecordRepository) FindByTable(ctx context.Context, tableID string, page record.Page) ([]*record.Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.records[tableID]
+	recs := make([]*record.Record, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		recs = append(recs, cloneRecord(stored[i]))
+	}
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].CreatedAt.After(recs[j].CreatedAt)
+	})
+
+	if page.Limit > 0 {
+		if page.Offset >= len(recs) {
+			return []*record.Record{}, nil
+		}
+		end := page.Offset + page.Limit
+		if end > len(recs) {
+			end = len(recs)
+		}
+		recs = recs[page.Offset:end]
+	}
+	return recs, nil
+}
+
+// FindByID retrieves a record of the given table
+func (r *MemoryRecordRepository) FindByID(ctx context.Context, tableID string, id int64) (*record.Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if i := r.indexOf(tableID, id); i >= 0 {
+		return cloneRecord(r.records[tableID][i]), nil
+	}
+	return nil, fmt.Errorf("%w: %d", record.ErrNotFound, id)
+}
+
+// Create inserts a new record and sets its ID
+func (r *MemoryRecordRepository) Create(ctx context.Context, rec *record.Record) error {
+	return r.Append(ctx, rec.TableID, []*record.Record{rec})
+}
+
+// Update replaces the data of an existing record
+func (r *MemoryRecordRepository) Update(ctx context.Context, rec *record.Record) error {
+	if err := rec.Validate(); err != nil {
+		return err
+	}
+	if _, err := r.tables.FindByID(ctx, rec.TableID); err != nil {
+		return fmt.Errorf("%w: %s", record.ErrTableNotFound, rec.TableID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(rec.TableID, rec.ID)
+	if i < 0 {
+		return fmt.Errorf("%w: %d", record.ErrNotFound, rec.ID)
+	}
+	data, err := normalize(rec.Data)
+	if err != nil {
+		return err
+	}
+	stored := r.records[rec.TableID][i]
+	stored.Data = data
+	stored.UpdatedAt = rec.UpdatedAt
+	r.tables.Touch(rec.TableID)
+	return nil
+}
+
+// Delete removes a record of the given table
+func (r *MemoryRecordRepository) Delete(ctx context.Context, tableID string, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(tableID, id)
+	if i < 0 {
+		return fmt.Errorf("%w: %d", record.ErrNotFound, id)
+	}
+	stored := r.records[tableID]
+	r.records[tableID] = append(stored[:i:i], stored[i+1:]...)
+	r.tables.SetRecordCount(tableID, len(r.records[tableID]))
+	return nil
+}
+
+// Append inserts records after the existing ones
+func (r *MemoryRecordRepository) Append(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.write(ctx, tableID, recs, false)
+}
+
+// Replace deletes every record of the table and inserts the given ones
+func (r *MemoryRecordRepository) Replace(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.write(ctx, tableID, recs, true)
+}
+
+// Apply updates, deletes and creates records. The changes are made on a
+// copy of the table's records, so a failure leaves them as they were.
+func (r *MemoryRecordRepository) Apply(ctx context.Context, tableID string, changes record.Changes) error {
+	if _, err := r.tables.FindByID(ctx, tableID); err != nil {
+		return fmt.Errorf("%w: %s", record.ErrTableNotFound, tableID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]*record.Record, len(r.records[tableID]))
+	for i, rec := range r.records[tableID] {
+		stored[i] = cloneRecord(rec)
+	}
+	for _, rec := range changes.Update {
+		rec.TableID = tableID
+		if err := rec.Validate(); err != nil {
+			return err
+		}
+		i := indexIn(stored, rec.ID)
+		if i < 0 {
+			return fmt.Errorf("%w: %d", record.ErrNotFound, rec.ID)
+		}
+		data, err := normalize(rec.Data)
+		if err != nil {
+			return err
+		}
+		stored[i].Data = data
+		stored[i].UpdatedAt = rec.UpdatedAt
+	}
+	for _, id := range changes.Delete {
+		i := indexIn(stored, id)
+		if i < 0 {
+			return fmt.Errorf("%w: %d", record.ErrNotFound, id)
+		}
+		stored = append(stored[:i:i], stored[i+1:]...)
+	}
+	nextID := r.nextID
+	for _, rec := range changes.Create {
+		rec.TableID = tableID
+		if err := rec.Validate(); err != nil {
+			return err
+		}
+		data, err := normalize(rec.Data)
+		if err != nil {
+			return err
+		}
+		nextID++
+		rec.ID = nextID
+		c := *rec
+		c.Data = data
+		stored = append(stored, &c)
+	}
+
+	r.nextID = nextID
+	r.records[tableID] = stored
+	r.tables.SetRecordCount(tableID, len(stored))
+	return nil
+}
+
+// CreateWithTable creates a table and its first records, removing the
+// table again when the records are rejected
+func (r *MemoryRecordRepository) CreateWithTable(ctx context.Context, t *table.Table, recs []*record.Record) error {
+	if err := r.tables.Create(ctx, t); err != nil {
+		return err
+	}
+	if err := r.write(ctx, t.ID, recs, false); err != nil {
+		r.tables.Delete(ctx, t.ID)
+		return err
+	}
+	return nil
+}
+
+func (r *MemoryRecordRepository) write(ctx context.Context, tableID string, recs []*record.Record, replace bool) error {
+	if _, err := r.tables.FindByID(ctx, tableID); err != nil {
+		return fmt.Errorf("%w: %s", record.ErrTableNotFound, tableID)
+	}
+	normalized := make([]map[string]interface{}, len(recs))
+	for i, rec := range recs {
+		rec.TableID = tableID
+		if err := rec.Validate(); err != nil {
+			return err
+		}
+		data, err := normalize(rec.Data)
+		if err != nil {
+			return err
+		}
+		normalized[i] = data
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.records[tableID]
+	if replace {
+		stored = nil
+	}
+	for i, rec := range recs {
+		r.nextID++
+		rec.ID = r.nextID
+		c := *rec
+		c.Data = normalized[i]
+		stored = append(stored, &c)
+	}
+	r.records[tableID] = stored
+	r.tables.SetRecordCount(tableID, len(stored))
+	return nil
+}
+
+func (r *MemoryRecordRepository) indexOf(tableID string, id int64) int {
+	return indexIn(r.records[tableID], id)
+}
+
+func indexIn(recs []*record.Record, id int64) int {
+	for i, rec := range recs {
+		if rec.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize round-trips data through JSON so stored values have the same
+// types a database would return, e.g. float64 for numbers
+func normalize(data map[string]interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+	return decoded, nil
+}
+
+func cloneRecord(rec *record.Record) *record.Record {
+	c := *rec
+	c.Data = cloneData(rec.Data)
+	return &c
+}
+
+func cloneData(data map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		c[key] = value
+	}
+	return c
+}
@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ReadRepository defines read operations for records
+type ReadRepository interface {
+	FindByTable(ctx context.Context, tableID string, page record.Page) ([]*record.Record, error)
+	FindByID(ctx context.Context, tableID string, id int64) (*record.Record, error)
+}
+
+// WriteRepository defines write operations for records.
+// Every write keeps the owning table's record_count current.
+type WriteRepository interface {
+	Create(ctx context.Context, rec *record.Record) error
+	Update(ctx context.Context, rec *record.Record) error
+	Delete(ctx context.Context, tableID string, id int64) error
+	Append(ctx context.Context, tableID string, recs []*record.Record) error
+	Replace(ctx context.Context, tableID string, recs []*record.Record) error
+	// Apply updates, deletes and creates records of one table; nothing is
+	// stored when any of them fails
+	Apply(ctx context.Context, tableID string, changes record.Changes) error
+	// CreateWithTable creates a table together with its first records;
+	// nothing is stored when any of them fails
+	CreateWithTable(ctx context.Context, t *table.Table, recs []*record.Record) error
+}
+
+// RecordRepository combines both ReadRepository and WriteRepository interfaces
+type RecordRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// recordDB represents the database model
+type recordDB struct {
+	ID        int64           `db:"id"`
+	TableID   string          `db:"table_id"`
+	Data      json.RawMessage `db:"data"`
+	CreatedAt time.Time       `db:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
+
+// PostgresRecordRepository implements RecordRepository using PostgreSQL
+type PostgresRecordRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL record repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresRecordRepository {
+	return &PostgresRecordRepository{db: db}
+}
+
+// FindByTable retrieves a table's records, newest first.
+// A zero page limit returns every record.
+func (r *PostgresRecordRepository) FindByTable(ctx context.Context, tableID string, page record.Page) ([]*record.Record, error) {
+	query := `
+		SELECT id, table_id, data, created_at, updated_at
+		FROM records
+		WHERE table_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	args := []interface{}{tableID}
+	if page.Limit > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+		args = append(args, page.Limit, page.Offset)
+	}
+
+	var rows []recordDB
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find records: %w", err)
+	}
+
+	recs := make([]*record.Record, 0, len(rows))
+	for i := range rows {
+		rec, err := toDomain(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// FindByID retrieves a record of the given table
+func (r *PostgresRecordRepository) FindByID(ctx context.Context, tableID string, id int64) (*record.Record, error) {
+	var row recordDB
+	query := `SELECT id, table_id, data, created_at, updated_at FROM records WHERE id = $1 AND table_id = $2`
+	if err := r.db.GetContext(ctx, &row, query, id, tableID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", record.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find record by id %d: %w", id, err)
+	}
+	return toDomain(&row)
+}
+
+// Create inserts a new record and sets its ID
+func (r *PostgresRecordRepository) Create(ctx context.Context, rec *record.Record) error {
+	return r.withTx(ctx, rec.TableID, func(tx *sqlx.Tx) error {
+		return r.insert(ctx, tx, rec)
+	})
+}
+
+// Update replaces the data of an existing record
+func (r *PostgresRecordRepository) Update(ctx context.Context, rec *record.Record) error {
+	return r.withTx(ctx, rec.TableID, func(tx *sqlx.Tx) error {
+		return r.update(ctx, tx, rec)
+	})
+}
+
+// Delete removes a record of the given table
+func (r *PostgresRecordRepository) Delete(ctx context.Context, tableID string, id int64) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		return r.delete(ctx, tx, tableID, id)
+	})
+}
+
+// Append inserts records after the existing ones in a single transaction
+func (r *PostgresRecordRepository) Append(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		return r.insertAll(ctx, tx, tableID, recs)
+	})
+}
+
+// Replace deletes every record of the table and inserts the given ones in a single transaction
+func (r *PostgresRecordRepository) Replace(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE table_id = $1`, tableID); err != nil {
+			return fmt.Errorf("failed to clear existing records: %w", err)
+		}
+		return r.insertAll(ctx, tx, tableID, recs)
+	})
+}
+
+// Apply updates, deletes and creates records in a single transaction
+func (r *PostgresRecordRepository) Apply(ctx context.Context, tableID string, changes record.Changes) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		for _, rec := range changes.Update {
+			rec.TableID = tableID
+			if err := r.update(ctx, tx, rec); err != nil {
+				return err
+			}
+		}
+		for _, id := range changes.Delete {
+			if err := r.delete(ctx, tx, tableID, id); err != nil {
+				return err
+			}
+		}
+		return r.insertAll(ctx, tx, tableID, changes.Create)
+	})
+}
+
+// CreateWithTable creates a table and its first records in a single transaction
+func (r *PostgresRecordRepository) CreateWithTable(ctx context.Context, t *table.Table, recs []*record.Record) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tablerepo.InsertPostgres(ctx, tx, t); err != nil {
+		return err
+	}
+	if err := r.insertAll(ctx, tx, t.ID, recs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withTx touches the table's updated_at and runs fn in one transaction.
+// The touch doubles as the existence check, so a missing table is
+// table.ErrNotFound even when fn writes nothing.
+func (r *PostgresRecordRepository) withTx(ctx context.Context, tableID string, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE tables SET updated_at = $1 WHERE id = $2`, time.Now(), tableID)
+	if err != nil {
+		return fmt.Errorf("failed to touch table: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if affected == 0 {
+		return fmt.Errorf("%w: %s", table.ErrNotFound, tableID)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresRecordRepository) update(ctx context.Context, tx *sqlx.Tx, rec *record.Record) error {
+	if err := rec.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+
+	query := `UPDATE records SET data = $1, updated_at = $2 WHERE id = $3 AND table_id = $4`
+	result, err := tx.ExecContext(ctx, query, json.RawMessage(data), rec.UpdatedAt, rec.ID, rec.TableID)
+	if err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	return checkAffected(result, rec.ID)
+}
+
+func (r *PostgresRecordRepository) delete(ctx context.Context, tx *sqlx.Tx, tableID string, id int64) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM records WHERE id = $1 AND table_id = $2`, id, tableID)
+	if err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return checkAffected(result, id)
+}
+
+func (r *PostgresRecordRepository) insertAll(ctx context.Context, tx *sqlx.Tx, tableID string, recs []*record.Record) error {
+	for _, rec := range recs {
+		rec.TableID = tableID
+		if err := r.insert(ctx, tx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRecordRepository) insert(ctx context.Context, tx *sqlx.Tx, rec *record.Record) error {
+	if err := rec.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+
+	query := `INSERT INTO records (table_id, data, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id`
+	err = tx.QueryRowContext(ctx, query, rec.TableID, json.RawMessage(data), rec.CreatedAt, rec.UpdatedAt).Scan(&rec.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("%w: %s", record.ErrTableNotFound, rec.TableID)
+		}
+		return fmt.Errorf("failed to create record: %w", err)
+	}
+	return nil
+}
+
+func checkAffected(result sql.Result, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %d", record.ErrNotFound, id)
+	}
+	return nil
+}
+
+// toDomain converts database model to domain model
+func toDomain(row *recordDB) (*record.Record, error) {
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(row.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode record %d: %w", row.ID, err)
+	}
+	return &record.Record{
+		ID:        row.ID,
+		TableID:   row.TableID,
+		Data:      data,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLiteRecordRepository implements RecordRepository using SQLite.
+// Record data is stored as TEXT and normalized with the JSON1 json() function.
+type SQLiteRecordRepository struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteRepository creates a new SQLite record repository
+func NewSQLiteRepository(db *sqlx.DB) *SQLiteRecordRepository {
+	return &SQLiteRecordRepository{db: db}
+}
+
+// FindByTable retrieves a table's records, newest first.
+// A zero page limit returns every record.
+func (r *SQLiteRecordRepository) FindByTable(ctx context.Context, tableID string, page record.Page) ([]*record.Record, error) {
+	query := `
+		SELECT id, table_id, CAST(data AS BLOB) AS data, created_at, updated_at
+		FROM records
+		WHERE table_id = ?
+		ORDER BY created_at DESC, id DESC
+	`
+	args := []interface{}{tableID}
+	if page.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, page.Limit, page.Offset)
+	}
+
+	var rows []recordDB
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find records: %w", err)
+	}
+
+	recs := make([]*record.Record, 0, len(rows))
+	for i := range rows {
+		rec, err := toDomain(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, nil
+}
+
+// FindByID retrieves a record of the given table
+func (r *SQLiteRecordRepository) FindByID(ctx context.Context, tableID string, id int64) (*record.Record, error) {
+	var row recordDB
+	query := `SELECT id, table_id, CAST(data AS BLOB) AS data, created_at, updated_at FROM records WHERE id = ? AND table_id = ?`
+	if err := r.db.GetContext(ctx, &row, query, id, tableID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %d", record.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find record by id %d: %w", id, err)
+	}
+	return toDomain(&row)
+}
+
+// Create inserts a new record and sets its ID
+func (r *SQLiteRecordRepository) Create(ctx context.Context, rec *record.Record) error {
+	return r.withTx(ctx, rec.TableID, func(tx *sqlx.Tx) error {
+		return r.insert(ctx, tx, rec)
+	})
+}
+
+// Update replaces the data of an existing record
+func (r *SQLiteRecordRepository) Update(ctx context.Context, rec *record.Record) error {
+	return r.withTx(ctx, rec.TableID, func(tx *sqlx.Tx) error {
+		return r.update(ctx, tx, rec)
+	})
+}
+
+// Delete removes a record of the given table
+func (r *SQLiteRecordRepository) Delete(ctx context.Context, tableID string, id int64) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		return r.delete(ctx, tx, tableID, id)
+	})
+}
+
+// Append inserts records after the existing ones in a single transaction
+func (r *SQLiteRecordRepository) Append(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		return r.insertAll(ctx, tx, tableID, recs)
+	})
+}
+
+// Replace deletes every record of the table and inserts the given ones in a single transaction
+func (r *SQLiteRecordRepository) Replace(ctx context.Context, tableID string, recs []*record.Record) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE table_id = ?`, tableID); err != nil {
+			return fmt.Errorf("failed to clear existing records: %w", err)
+		}
+		return r.insertAll(ctx, tx, tableID, recs)
+	})
+}
+
+// Apply updates, deletes and creates records in a single transaction
+func (r *SQLiteRecordRepository) Apply(ctx context.Context, tableID string, changes record.Changes) error {
+	return r.withTx(ctx, tableID, func(tx *sqlx.Tx) error {
+		for _, rec := range changes.Update {
+			rec.TableID = tableID
+			if err := r.update(ctx, tx, rec); err != nil {
+				return err
+			}
+		}
+		for _, id := range changes.Delete {
+			if err := r.delete(ctx, tx, tableID, id); err != nil {
+				return err
+			}
+		}
+		return r.insertAll(ctx, tx, tableID, changes.Create)
+	})
+}
+
+// CreateWithTable creates a table and its first records in a single transaction
+func (r *SQLiteRecordRepository) CreateWithTable(ctx context.Context, t *table.Table, recs []*record.Record) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := tablerepo.InsertSQLite(ctx, tx, t); err != nil {
+		return err
+	}
+	if err := r.insertAll(ctx, tx, t.ID, recs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withTx touches the table's updated_at and runs fn in one transaction.
+// The touch doubles as the existence check, so a missing table is
+// table.ErrNotFound even when fn writes nothing.
+func (r *SQLiteRecordRepository) withTx(ctx context.Context, tableID string, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE tables SET updated_at = ? WHERE id = ?`, time.Now().UTC(), tableID)
+	if err != nil {
+		return fmt.Errorf("failed to touch table: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if affected == 0 {
+		return fmt.Errorf("%w: %s", table.ErrNotFound, tableID)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLiteRecordRepository) update(ctx context.Context, tx *sqlx.Tx, rec *record.Record) error {
+	if err := rec.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+
+	query := `UPDATE records SET data = json(?), updated_at = ? WHERE id = ? AND table_id = ?`
+	result, err := tx.ExecContext(ctx, query, string(data), rec.UpdatedAt.UTC(), rec.ID, rec.TableID)
+	if err != nil {
+		return fmt.Errorf("failed to update record: %w", err)
+	}
+	return checkAffected(result, rec.ID)
+}
+
+func (r *SQLiteRecordRepository) delete(ctx context.Context, tx *sqlx.Tx, tableID string, id int64) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM records WHERE id = ? AND table_id = ?`, id, tableID)
+	if err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	return checkAffected(result, id)
+}
+
+func (r *SQLiteRecordRepository) insertAll(ctx context.Context, tx *sqlx.Tx, tableID string, recs []*record.Record) error {
+	for _, rec := range recs {
+		rec.TableID = tableID
+		if err := r.insert(ctx, tx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SQLiteRecordRepository) insert(ctx context.Context, tx *sqlx.Tx, rec *record.Record) error {
+	if err := rec.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rec.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", record.ErrInvalidInput, err)
+	}
+
+	query := `INSERT INTO records (table_id, data, created_at, updated_at) VALUES (?, json(?), ?, ?)`
+	result, err := tx.ExecContext(ctx, query, rec.TableID, string(data), rec.CreatedAt.UTC(), rec.UpdatedAt.UTC())
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return fmt.Errorf("%w: %s", record.ErrTableNotFound, rec.TableID)
+		}
+		return fmt.Errorf("failed to create record: %w", err)
+	}
+
+	if rec.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get record id: %w", err)
+	}
+	return nil
+}
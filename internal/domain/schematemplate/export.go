@@ -0,0 +1,74 @@
+package schematemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportFormat identifies a template export file
+const ExportFormat = "progressive-templates"
+
+// ExportFile is the JSON file templates are shared as between teams
+type ExportFile struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Templates  []*SchemaTemplate `json:"templates"`
+}
+
+// WriteExport writes templates as an export file. Workspaces are left out:
+// the importing side chooses its own.
+func WriteExport(w io.Writer, templates []*SchemaTemplate) error {
+	file := ExportFile{Format: ExportFormat, Version: 1, ExportedAt: time.Now().UTC(), Templates: make([]*SchemaTemplate, len(templates))}
+	for i, t := range templates {
+		copied := *t
+		copied.Workspace = ""
+		file.Templates[i] = &copied
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(file)
+}
+
+// ReadExport reads an export file. A bare array of templates or a single
+// template object is accepted too.
+func ReadExport(r io.Reader) ([]*SchemaTemplate, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimSpace(body)
+
+	var templates []*SchemaTemplate
+	switch {
+	case bytes.HasPrefix(body, []byte("[")):
+		err = json.Unmarshal(body, &templates)
+	default:
+		var file ExportFile
+		if err = json.Unmarshal(body, &file); err != nil {
+			break
+		}
+		if file.Format == ExportFormat {
+			if file.Version != 1 {
+				return nil, fmt.Errorf("%w: unsupported template file version %d", ErrInvalidInput, file.Version)
+			}
+			templates = file.Templates
+			break
+		}
+		var single SchemaTemplate
+		if err = json.Unmarshal(body, &single); err != nil {
+			break
+		}
+		if single.ID == "" {
+			return nil, fmt.Errorf("%w: not a template file", ErrInvalidInput)
+		}
+		templates = []*SchemaTemplate{&single}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid template file: %v", ErrInvalidInput, err)
+	}
+	return templates, nil
+}
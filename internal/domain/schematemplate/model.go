@@ -1,8 +1,22 @@
 package schematemplate
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
+
+	"progressive/internal/domain/table"
+)
+
+var (
+	ErrNotFound      = errors.New("template not found")
+	ErrAlreadyExists = errors.New("template already exists")
+	ErrInvalidInput  = errors.New("invalid input")
 )
 
 // SchemaTemplate represents a reusable table template
@@ -14,7 +28,17 @@ type SchemaTemplate struct {
 	Icon        string          `json:"icon"`
 	Schema      json.RawMessage `json:"schema"`
 	SampleData  json.RawMessage `json:"sample_data,omitempty"`
-	CreatedAt   time.Time       `json:"created_at"`
+	// Workspace owns a custom template; built-in templates have none and
+	// are offered in every workspace
+	Workspace string `json:"workspace,omitempty"`
+	// Version is the built-in template version the template was last written
+	// from; user templates have none
+	Version int `json:"version,omitempty"`
+	// Checksum is the ContentChecksum of that built-in version. It stops
+	// matching once the template is edited.
+	Checksum  string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // Category constants
@@ -24,8 +48,15 @@ const (
 	CategoryCustom   = "custom"
 )
 
+// Categories lists the valid categories
+var Categories = []string{CategoryBusiness, CategoryGame, CategoryCustom}
+
+// idPattern keeps template IDs usable in URLs and file names
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
 // NewSchemaTemplate creates a new schema template
 func NewSchemaTemplate(id, name, description, category, icon string, schema json.RawMessage) *SchemaTemplate {
+	now := time.Now()
 	return &SchemaTemplate{
 		ID:          id,
 		Name:        name,
@@ -33,12 +64,91 @@ func NewSchemaTemplate(id, name, description, category, icon string, schema json
 		Category:    category,
 		Icon:        icon,
 		Schema:      schema,
-		CreatedAt:   time.Now(),
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 }
 
-// Validate validates the schema template
+// Validate checks the fields required to store a template. The schema must
+// be usable to create a table, and sample data must be an array of objects.
 func (st *SchemaTemplate) Validate() error {
-	// Add validation logic here
+	if !idPattern.MatchString(st.ID) {
+		return fmt.Errorf("%w: template id %q must be 1-64 lowercase letters, digits, '_' or '-'", ErrInvalidInput, st.ID)
+	}
+	if st.Name == "" {
+		return fmt.Errorf("%w: template name is required", ErrInvalidInput)
+	}
+	if !st.IsKnownCategory() {
+		return fmt.Errorf("%w: category %q must be one of %v", ErrInvalidInput, st.Category, Categories)
+	}
+	if st.Workspace != "" && st.Category != CategoryCustom {
+		return fmt.Errorf("%w: only custom templates belong to a workspace", ErrInvalidInput)
+	}
+	if err := table.ValidateSchema(st.Schema); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, strings.TrimPrefix(err.Error(), table.ErrInvalidInput.Error()+": "))
+	}
+	if len(st.SampleData) > 0 && string(st.SampleData) != "null" {
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(st.SampleData, &rows); err != nil {
+			return fmt.Errorf("%w: sample_data must be an array of objects", ErrInvalidInput)
+		}
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// IsKnownCategory reports whether the category is one of Categories
+func (st *SchemaTemplate) IsKnownCategory() bool {
+	for _, c := range Categories {
+		if st.Category == c {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleIn reports whether the template is offered in a workspace: built-in
+// templates everywhere, custom ones only in their own workspace
+func (st *SchemaTemplate) VisibleIn(workspace string) bool {
+	return st.Category != CategoryCustom || st.Workspace == workspace
+}
+
+// ContentChecksum hashes the user-visible content of the template. JSON is
+// re-encoded first so that key order and spacing do not count as edits.
+func (st *SchemaTemplate) ContentChecksum() string {
+	h := sha256.New()
+	for _, field := range []string{st.Name, st.Description, st.Category, st.Icon} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	for _, raw := range []json.RawMessage{st.Schema, st.SampleData} {
+		h.Write(canonicalJSON(raw))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Modified reports whether a built-in template was edited after it was
+// written from its built-in version
+func (st *SchemaTemplate) Modified() bool {
+	if st.Checksum == "" {
+		// Seeded before checksums were kept: only edits move UpdatedAt
+		return st.UpdatedAt.After(st.CreatedAt)
+	}
+	return st.ContentChecksum() != st.Checksum
+}
+
+// canonicalJSON re-encodes raw with sorted keys; null and empty are the same
+func canonicalJSON(raw json.RawMessage) []byte {
+	var v interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &v) != nil {
+		return raw
+	}
+	if v == nil {
+		return nil
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return raw
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package schematemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{"type": "object", "properties": {"name": {"type": "string"}}}`
+
+func TestValidate(t *testing.T) {
+	valid := func() *SchemaTemplate {
+		return NewSchemaTemplate("game_items", "Game Items", "", CategoryCustom, "", json.RawMessage(testSchema))
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Expected a valid template, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*SchemaTemplate)
+		want   string
+	}{
+		{"uppercase id", func(st *SchemaTemplate) { st.ID = "Items" }, "template id"},
+		{"missing name", func(st *SchemaTemplate) { st.Name = "" }, "name is required"},
+		{"unknown category", func(st *SchemaTemplate) { st.Category = "misc" }, "category"},
+		{"workspace on a built-in", func(st *SchemaTemplate) { st.Category, st.Workspace = CategoryGame, "team" }, "workspace"},
+		{"empty schema", func(st *SchemaTemplate) { st.Schema = json.RawMessage(`{"type": "object", "properties": {}}`) }, "at least one property"},
+		{"sample data object", func(st *SchemaTemplate) { st.SampleData = json.RawMessage(`{"name": "x"}`) }, "sample_data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := valid()
+			tt.change(st)
+			err := st.Validate()
+			if !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected ErrInvalidInput mentioning %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestVisibleIn(t *testing.T) {
+	builtin := &SchemaTemplate{Category: CategoryGame}
+	custom := &SchemaTemplate{Category: CategoryCustom, Workspace: "team-a"}
+	if !builtin.VisibleIn("") || !builtin.VisibleIn("team-b") {
+		t.Error("Expected built-in templates in every workspace")
+	}
+	if !custom.VisibleIn("team-a") || custom.VisibleIn("team-b") || custom.VisibleIn("") {
+		t.Error("Expected custom templates only in their workspace")
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	st := NewSchemaTemplate("items", "Items", "", CategoryCustom, "📦", json.RawMessage(testSchema))
+	st.Workspace = "team-a"
+	st.SampleData = json.RawMessage(`[{"name": "sword"}]`)
+
+	var buf bytes.Buffer
+	if err := WriteExport(&buf, []*SchemaTemplate{st}); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if strings.Contains(buf.String(), "team-a") {
+		t.Errorf("Expected the workspace to be left out: %s", buf.String())
+	}
+	if st.Workspace != "team-a" {
+		t.Error("Expected the exported template to be unchanged")
+	}
+
+	read, err := ReadExport(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if len(read) != 1 || read[0].ID != "items" || read[0].Icon != "📦" || read[0].Workspace != "" {
+		t.Errorf("Unexpected templates: %+v", read)
+	}
+
+	// A single template or an array is accepted as well
+	for _, body := range []string{`{"id": "one", "name": "One"}`, `[{"id": "one"}, {"id": "two"}]`} {
+		if _, err := ReadExport(strings.NewReader(body)); err != nil {
+			t.Errorf("Expected %s to be read, got: %v", body, err)
+		}
+	}
+	for _, body := range []string{`{"format": "progressive-templates", "version": 2}`, `{"name": "no id"}`, `nope`} {
+		if _, err := ReadExport(strings.NewReader(body)); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput for %s, got: %v", body, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"progressive/internal/domain/schematemplate"
+)
+
+func TestDefaultsAreValid(t *testing.T) {
+	defaults, err := Defaults()
+	if err != nil {
+		t.Fatalf("Failed to load defaults: %v", err)
+	}
+	if len(defaults) != 7 {
+		t.Errorf("Expected 7 built-in templates, got %d", len(defaults))
+	}
+	for _, d := range defaults {
+		if d.Category == schematemplate.CategoryCustom || d.Checksum != d.ContentChecksum() {
+			t.Errorf("Unexpected built-in template %s: %+v", d.ID, d)
+		}
+	}
+}
+
+// previousVersion stores a built-in template as an older version would have
+func previousVersion(t *testing.T, repo SchemaTemplateRepository, id string) *schematemplate.SchemaTemplate {
+	t.Helper()
+	defaults, _ := Defaults()
+	for _, d := range defaults {
+		if d.ID == id {
+			old := *d
+			old.Version = d.Version - 1
+			old.Description = "older description"
+			old.Checksum = old.ContentChecksum()
+			if err := repo.Create(context.Background(), &old); err != nil {
+				t.Fatalf("Failed to store %s: %v", id, err)
+			}
+			return &old
+		}
+	}
+	t.Fatalf("No built-in template %s", id)
+	return nil
+}
+
+func TestSyncDefaultsUpgradesUneditedTemplates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+	previousVersion(t, repo, "quest")
+
+	edited := previousVersion(t, repo, "customer")
+	edited.Name = "우리 고객"
+	if err := repo.Update(ctx, edited); err != nil {
+		t.Fatalf("Failed to edit: %v", err)
+	}
+
+	schema := json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+	custom := schematemplate.NewSchemaTemplate("event", "Our events", "", schematemplate.CategoryCustom, "", schema)
+	if err := repo.Create(ctx, custom); err != nil {
+		t.Fatalf("Failed to create custom template: %v", err)
+	}
+
+	report, err := SyncDefaults(ctx, repo)
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+	if !slices.Equal(report.Upgraded, []string{"quest"}) || !slices.Equal(report.Kept, []string{"customer"}) ||
+		!slices.Equal(report.Shadowed, []string{"event"}) || len(report.Created) != 4 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	quest, _ := repo.FindByID(ctx, "quest")
+	if quest.Description == "older description" || quest.Modified() {
+		t.Errorf("Expected quest to be upgraded, got %+v", quest)
+	}
+	if customer, _ := repo.FindByID(ctx, "customer"); customer.Name != "우리 고객" {
+		t.Errorf("Expected the edit to be kept, got %+v", customer)
+	}
+	if event, _ := repo.FindByID(ctx, "event"); event.Name != "Our events" {
+		t.Errorf("Expected the custom template to be kept, got %+v", event)
+	}
+}
+
+func TestSyncDefaultsUpgradesTemplatesSeededWithoutVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+	schema := json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+	created := time.Now().Add(-time.Hour)
+
+	// Seeded by an older release: no version or checksum
+	legacy := schematemplate.NewSchemaTemplate("project", "project_management", "", schematemplate.CategoryBusiness, "📋", schema)
+	legacy.CreatedAt, legacy.UpdatedAt = created, created
+	editedLegacy := schematemplate.NewSchemaTemplate("inventory", "My inventory", "", schematemplate.CategoryBusiness, "📦", schema)
+	editedLegacy.CreatedAt, editedLegacy.UpdatedAt = created, created.Add(time.Minute)
+	for _, tmpl := range []*schematemplate.SchemaTemplate{legacy, editedLegacy} {
+		if err := repo.Create(ctx, tmpl); err != nil {
+			t.Fatalf("Failed to store %s: %v", tmpl.ID, err)
+		}
+	}
+
+	report, err := SyncDefaults(ctx, repo)
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+	if !slices.Equal(report.Upgraded, []string{"project"}) || !slices.Equal(report.Kept, []string{"inventory"}) {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if project, _ := repo.FindByID(ctx, "project"); project.Name != "프로젝트 관리" || project.Version != 1 || !project.CreatedAt.Equal(created) {
+		t.Errorf("Expected project to be upgraded in place, got %+v", project)
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"progressive/internal/domain/schematemplate"
+)
+
+// MemorySchemaTemplateRepository implements SchemaTemplateRepository in memory for tests
+type MemorySchemaTemplateRepository struct {
+	mu        sync.RWMutex
+	templates map[string]*schematemplate.SchemaTemplate
+}
+
+// NewMemoryRepository creates a new, empty in-memory template repository
+func NewMemoryRepository() *MemorySchemaTemplateRepository {
+	return &MemorySchemaTemplateRepository{templates: make(map[string]*schematemplate.SchemaTemplate)}
+}
+
+func cloneTemplate(t *schematemplate.SchemaTemplate) *schematemplate.SchemaTemplate {
+	copied := *t
+	return &copied
+}
+
+func (r *MemorySchemaTemplateRepository) filter(keep func(*schematemplate.SchemaTemplate) bool) []*schematemplate.SchemaTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var templates []*schematemplate.SchemaTemplate
+	for _, t := range r.templates {
+		if keep(t) {
+			templates = append(templates, cloneTemplate(t))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Category != templates[j].Category {
+			return templates[i].Category < templates[j].Category
+		}
+		return templates[i].Name < templates[j].Name
+	})
+	return templates
+}
+
+// FindAll retrieves all schema templates
+func (r *MemorySchemaTemplateRepository) FindAll(ctx context.Context) ([]*schematemplate.SchemaTemplate, error) {
+	return r.filter(func(*schematemplate.SchemaTemplate) bool { return true }), nil
+}
+
+// FindByID retrieves a schema template by ID
+func (r *MemorySchemaTemplateRepository) FindByID(ctx context.Context, id string) (*schematemplate.SchemaTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.templates[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
+	}
+	return cloneTemplate(t), nil
+}
+
+// FindByCategory retrieves schema templates by category
+func (r *MemorySchemaTemplateRepository) FindByCategory(ctx context.Context, category string) ([]*schematemplate.SchemaTemplate, error) {
+	return r.filter(func(t *schematemplate.SchemaTemplate) bool { return t.Category == category }), nil
+}
+
+// Exists checks if a template exists by ID
+func (r *MemorySchemaTemplateRepository) Exists(ctx context.Context, id string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.templates[id]
+	return ok, nil
+}
+
+// Create creates a new schema template
+func (r *MemorySchemaTemplateRepository) Create(ctx context.Context, t *schematemplate.SchemaTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.templates[t.ID]; ok {
+		return fmt.Errorf("%w: %s", schematemplate.ErrAlreadyExists, t.ID)
+	}
+	r.templates[t.ID] = cloneTemplate(t)
+	return nil
+}
+
+// Update updates an existing schema template
+func (r *MemorySchemaTemplateRepository) Update(ctx context.Context, t *schematemplate.SchemaTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.templates[t.ID]
+	if !ok {
+		return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, t.ID)
+	}
+	updated := cloneTemplate(t)
+	updated.CreatedAt = stored.CreatedAt
+	r.templates[t.ID] = updated
+	return nil
+}
+
+// Delete deletes a schema template by ID
+func (r *MemorySchemaTemplateRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.templates[id]; !ok {
+		return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
+	}
+	delete(r.templates, id)
+	return nil
+}
+
+// Import creates and updates templates, checking all of them before
+// writing any so that a failure leaves the repository unchanged
+func (r *MemorySchemaTemplateRepository) Import(ctx context.Context, creates, updates []*schematemplate.SchemaTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, t := range creates {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		if _, ok := r.templates[t.ID]; ok || seen[t.ID] {
+			return fmt.Errorf("%w: %s", schematemplate.ErrAlreadyExists, t.ID)
+		}
+		seen[t.ID] = true
+	}
+	for _, t := range updates {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		if _, ok := r.templates[t.ID]; !ok {
+			return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, t.ID)
+		}
+	}
+
+	for _, t := range creates {
+		r.templates[t.ID] = cloneTemplate(t)
+	}
+	for _, t := range updates {
+		updated := cloneTemplate(t)
+		updated.CreatedAt = r.templates[t.ID].CreatedAt
+		r.templates[t.ID] = updated
+	}
+	return nil
+}
@@ -2,13 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"progressive/internal/domain/schematemplate"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // ReadRepository defines read operations for schema templates
@@ -24,6 +27,9 @@ type WriteRepository interface {
 	Create(ctx context.Context, template *schematemplate.SchemaTemplate) error
 	Update(ctx context.Context, template *schematemplate.SchemaTemplate) error
 	Delete(ctx context.Context, id string) error
+	// Import creates and updates templates in one transaction: when any of
+	// them fails, none is written
+	Import(ctx context.Context, creates, updates []*schematemplate.SchemaTemplate) error
 }
 
 // SchemaTemplateRepository combines both ReadRepository and WriteRepository interfaces
@@ -40,10 +46,19 @@ type schemaTemplateDB struct {
 	Category    string          `db:"category"`
 	Icon        string          `db:"icon"`
 	Schema      json.RawMessage `db:"schema"`
-	SampleData  json.RawMessage `db:"sample_data"`
-	CreatedAt   time.Time       `db:"created_at"`
+	// SampleData is NULL for templates without sample rows
+	SampleData []byte    `db:"sample_data"`
+	Workspace  string    `db:"workspace"`
+	Version    int       `db:"version"`
+	Checksum   string    `db:"checksum"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
 }
 
+// templateColumns are the columns read into schemaTemplateDB
+const templateColumns = `id, name, COALESCE(description, '') AS description, category, COALESCE(icon, '') AS icon,
+	schema, sample_data, workspace, version, checksum, created_at, updated_at`
+
 // PostgresSchemaTemplateRepository implements Repository using PostgreSQL
 type PostgresSchemaTemplateRepository struct {
 	db *sqlx.DB
@@ -54,95 +69,43 @@ func NewPostgresRepository(db *sqlx.DB) *PostgresSchemaTemplateRepository {
 	return &PostgresSchemaTemplateRepository{db: db}
 }
 
-// NewPostgresRepositoryWithDefaults creates a new PostgreSQL repository and initializes default templates
-func NewPostgresRepositoryWithDefaults(ctx context.Context, db *sqlx.DB) (*PostgresSchemaTemplateRepository, error) {
-	repo := &PostgresSchemaTemplateRepository{db: db}
-	
-	// Initialize default templates
-	if err := repo.initializeDefaultTemplates(ctx); err != nil {
-		return nil, fmt.Errorf("failed to initialize default templates: %w", err)
-	}
-	
-	return repo, nil
-}
-
-// initializeDefaultTemplates initializes default templates using type-safe definitions
-func (r *PostgresSchemaTemplateRepository) initializeDefaultTemplates(ctx context.Context) error {
-	templateDefs := GetDefaultTemplateDefinitions()
-	
-	for _, def := range templateDefs {
-		// Check if template already exists
-		exists, err := r.Exists(ctx, def.ID)
-		if err != nil {
-			return fmt.Errorf("failed to check template existence for %s: %w", def.ID, err)
-		}
-		
-		if exists {
-			continue // Skip if already exists
-		}
-		
-		// Convert to domain model
-		template, err := def.toDomainModel()
-		if err != nil {
-			return fmt.Errorf("failed to convert template definition %s: %w", def.ID, err)
-		}
-		
-		// Create template
-		if err := r.Create(ctx, template); err != nil {
-			return fmt.Errorf("failed to create template %s: %w", def.ID, err)
-		}
-	}
-	
-	return nil
-}
-
 // FindAll retrieves all schema templates
 func (r *PostgresSchemaTemplateRepository) FindAll(ctx context.Context) ([]*schematemplate.SchemaTemplate, error) {
-	query := `
-		SELECT id, name, description, category, icon, schema, sample_data, created_at 
-		FROM templates 
-		ORDER BY category, name
-	`
+	query := `SELECT ` + templateColumns + ` FROM templates ORDER BY category, name`
 
 	var templates []schemaTemplateDB
 	if err := r.db.SelectContext(ctx, &templates, query); err != nil {
 		return nil, fmt.Errorf("failed to find all templates: %w", err)
 	}
 
-	return r.toDomainList(templates), nil
+	return toDomainList(templates), nil
 }
 
 // FindByID retrieves a schema template by ID
 func (r *PostgresSchemaTemplateRepository) FindByID(ctx context.Context, id string) (*schematemplate.SchemaTemplate, error) {
-	query := `
-		SELECT id, name, description, category, icon, schema, sample_data, created_at 
-		FROM templates 
-		WHERE id = $1
-	`
+	query := `SELECT ` + templateColumns + ` FROM templates WHERE id = $1`
 
 	var template schemaTemplateDB
 	if err := r.db.GetContext(ctx, &template, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
+		}
 		return nil, fmt.Errorf("failed to find template by id %s: %w", id, err)
 	}
 
-	return r.toDomain(&template), nil
+	return toDomain(&template), nil
 }
 
 // FindByCategory retrieves schema templates by category
 func (r *PostgresSchemaTemplateRepository) FindByCategory(ctx context.Context, category string) ([]*schematemplate.SchemaTemplate, error) {
-	query := `
-		SELECT id, name, description, category, icon, schema, sample_data, created_at 
-		FROM templates 
-		WHERE category = $1
-		ORDER BY name
-	`
+	query := `SELECT ` + templateColumns + ` FROM templates WHERE category = $1 ORDER BY name`
 
 	var templates []schemaTemplateDB
 	if err := r.db.SelectContext(ctx, &templates, query, category); err != nil {
 		return nil, fmt.Errorf("failed to find templates by category %s: %w", category, err)
 	}
 
-	return r.toDomainList(templates), nil
+	return toDomainList(templates), nil
 }
 
 // Exists checks if a template exists by ID
@@ -159,13 +122,25 @@ func (r *PostgresSchemaTemplateRepository) Exists(ctx context.Context, id string
 
 // Create creates a new schema template
 func (r *PostgresSchemaTemplateRepository) Create(ctx context.Context, template *schematemplate.SchemaTemplate) error {
+	return r.create(ctx, r.db, template)
+}
+
+func (r *PostgresSchemaTemplateRepository) create(ctx context.Context, ext sqlx.ExtContext, template *schematemplate.SchemaTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO templates (id, name, description, category, icon, schema, sample_data, created_at)
-		VALUES (:id, :name, :description, :category, :icon, :schema, :sample_data, :created_at)
+		INSERT INTO templates (id, name, description, category, icon, schema, sample_data, workspace, version, checksum, created_at, updated_at)
+		VALUES (:id, :name, :description, :category, :icon, :schema, :sample_data, :workspace, :version, :checksum, :created_at, :updated_at)
 	`
 
-	dbModel := r.toDBModel(template)
-	if _, err := r.db.NamedExecContext(ctx, query, dbModel); err != nil {
+	dbModel := toDBModel(template)
+	if _, err := sqlx.NamedExecContext(ctx, ext, query, dbModel); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return fmt.Errorf("%w: %s", schematemplate.ErrAlreadyExists, template.ID)
+		}
 		return fmt.Errorf("failed to create template: %w", err)
 	}
 
@@ -174,19 +149,31 @@ func (r *PostgresSchemaTemplateRepository) Create(ctx context.Context, template
 
 // Update updates an existing schema template
 func (r *PostgresSchemaTemplateRepository) Update(ctx context.Context, template *schematemplate.SchemaTemplate) error {
+	return r.update(ctx, r.db, template)
+}
+
+func (r *PostgresSchemaTemplateRepository) update(ctx context.Context, ext sqlx.ExtContext, template *schematemplate.SchemaTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
 	query := `
-		UPDATE templates 
+		UPDATE templates
 		SET name = :name,
 		    description = :description,
 		    category = :category,
 		    icon = :icon,
 		    schema = :schema,
-		    sample_data = :sample_data
+		    sample_data = :sample_data,
+		    workspace = :workspace,
+		    version = :version,
+		    checksum = :checksum,
+		    updated_at = :updated_at
 		WHERE id = :id
 	`
 
-	dbModel := r.toDBModel(template)
-	result, err := r.db.NamedExecContext(ctx, query, dbModel)
+	dbModel := toDBModel(template)
+	result, err := sqlx.NamedExecContext(ctx, ext, query, dbModel)
 	if err != nil {
 		return fmt.Errorf("failed to update template: %w", err)
 	}
@@ -197,7 +184,7 @@ func (r *PostgresSchemaTemplateRepository) Update(ctx context.Context, template
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("template with id %s not found", template.ID)
+		return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, template.ID)
 	}
 
 	return nil
@@ -218,15 +205,39 @@ func (r *PostgresSchemaTemplateRepository) Delete(ctx context.Context, id string
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("template with id %s not found", id)
+		return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
 	}
 
 	return nil
 }
 
+// Import creates and updates templates in one transaction
+func (r *PostgresSchemaTemplateRepository) Import(ctx context.Context, creates, updates []*schematemplate.SchemaTemplate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, t := range creates {
+		if err := r.create(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	for _, t := range updates {
+		if err := r.update(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import: %w", err)
+	}
+	return nil
+}
+
 // Helper methods for conversion between domain and database models
 
-func (r *PostgresSchemaTemplateRepository) toDomain(db *schemaTemplateDB) *schematemplate.SchemaTemplate {
+func toDomain(db *schemaTemplateDB) *schematemplate.SchemaTemplate {
 	return &schematemplate.SchemaTemplate{
 		ID:          db.ID,
 		Name:        db.Name,
@@ -234,20 +245,32 @@ func (r *PostgresSchemaTemplateRepository) toDomain(db *schemaTemplateDB) *schem
 		Category:    db.Category,
 		Icon:        db.Icon,
 		Schema:      db.Schema,
-		SampleData:  db.SampleData,
+		SampleData:  sampleData(db.SampleData),
+		Workspace:   db.Workspace,
+		Version:     db.Version,
+		Checksum:    db.Checksum,
 		CreatedAt:   db.CreatedAt,
+		UpdatedAt:   db.UpdatedAt,
+	}
+}
+
+// sampleData returns nil for a NULL column so the field is omitted in JSON
+func sampleData(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
 	}
+	return json.RawMessage(b)
 }
 
-func (r *PostgresSchemaTemplateRepository) toDomainList(dbList []schemaTemplateDB) []*schematemplate.SchemaTemplate {
+func toDomainList(dbList []schemaTemplateDB) []*schematemplate.SchemaTemplate {
 	templates := make([]*schematemplate.SchemaTemplate, len(dbList))
 	for i, db := range dbList {
-		templates[i] = r.toDomain(&db)
+		templates[i] = toDomain(&db)
 	}
 	return templates
 }
 
-func (r *PostgresSchemaTemplateRepository) toDBModel(domain *schematemplate.SchemaTemplate) *schemaTemplateDB {
+func toDBModel(domain *schematemplate.SchemaTemplate) *schemaTemplateDB {
 	return &schemaTemplateDB{
 		ID:          domain.ID,
 		Name:        domain.Name,
@@ -255,7 +278,11 @@ func (r *PostgresSchemaTemplateRepository) toDBModel(domain *schematemplate.Sche
 		Category:    domain.Category,
 		Icon:        domain.Icon,
 		Schema:      domain.Schema,
-		SampleData:  domain.SampleData,
+		SampleData:  []byte(domain.SampleData),
+		Workspace:   domain.Workspace,
+		Version:     domain.Version,
+		Checksum:    domain.Checksum,
 		CreatedAt:   domain.CreatedAt,
+		UpdatedAt:   domain.UpdatedAt,
 	}
 }
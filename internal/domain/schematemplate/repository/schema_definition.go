@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SchemaDefinition represents the JSON schema structure
+type SchemaDefinition struct {
+	Type       string                 `json:"type"`
+	Properties map[string]PropertyDef `json:"properties"`
+	Required   []string               `json:"required,omitempty"`
+}
+
+// PropertyDef represents individual property definitions
+type PropertyDef struct {
+	Type        string        `json:"type"`
+	Title       string        `json:"title,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+	MinLength   int           `json:"minLength,omitempty"`
+	MaxLength   int           `json:"maxLength,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Description string        `json:"description,omitempty"`
+	// Ref points at another table's field as "table_id.field" (or just
+	// "table_id" to reference the record id). Publishing checks that every
+	// value resolves to an existing record.
+	Ref string `json:"x-ref,omitempty"`
+}
+
+// ParseSchemaDefinition decodes a stored JSON Schema into a SchemaDefinition
+func ParseSchemaDefinition(raw json.RawMessage) (*SchemaDefinition, error) {
+	var def SchemaDefinition
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if def.Type != "object" {
+		return nil, fmt.Errorf("schema type must be 'object', got '%s'", def.Type)
+	}
+	return &def, nil
+}
+
+// PropertyNames returns the schema's property names in a stable order
+func (s *SchemaDefinition) PropertyNames() []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsRequired reports whether a property is listed in "required"
+func (s *SchemaDefinition) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnumIncludes reports whether value is one of the property's enum values.
+// Values are compared as JSON, so 2 matches an enum entry of 2.0.
+func (p PropertyDef) EnumIncludes(value interface{}) bool {
+	normalized, ok := jsonValue(value)
+	if !ok {
+		return false
+	}
+	for _, entry := range p.Enum {
+		if e, ok := jsonValue(entry); ok && reflect.DeepEqual(e, normalized) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonValue round-trips value through JSON so that numbers of any Go type
+// become float64
+func jsonValue(value interface{}) (interface{}, bool) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, false
+	}
+	return normalized, true
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"progressive/internal/domain/schematemplate"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteTemplateColumns reads the JSON columns as BLOBs so they scan into
+// byte slices
+const sqliteTemplateColumns = `id, name, COALESCE(description, '') AS description, category, COALESCE(icon, '') AS icon,
+	CAST(schema AS BLOB) AS schema, CAST(sample_data AS BLOB) AS sample_data, workspace, version, checksum,
+	created_at, updated_at`
+
+// SQLiteSchemaTemplateRepository implements SchemaTemplateRepository using SQLite
+type SQLiteSchemaTemplateRepository struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteRepository creates a new SQLite template repository
+func NewSQLiteRepository(db *sqlx.DB) *SQLiteSchemaTemplateRepository {
+	return &SQLiteSchemaTemplateRepository{db: db}
+}
+
+func (r *SQLiteSchemaTemplateRepository) find(ctx context.Context, where string, args ...interface{}) ([]*schematemplate.SchemaTemplate, error) {
+	var rows []schemaTemplateDB
+	query := `SELECT ` + sqliteTemplateColumns + ` FROM templates ` + where
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	templates := make([]*schematemplate.SchemaTemplate, len(rows))
+	for i := range rows {
+		templates[i] = toDomain(&rows[i])
+	}
+	return templates, nil
+}
+
+// FindAll retrieves all schema templates
+func (r *SQLiteSchemaTemplateRepository) FindAll(ctx context.Context) ([]*schematemplate.SchemaTemplate, error) {
+	templates, err := r.find(ctx, `ORDER BY category, name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find all templates: %w", err)
+	}
+	return templates, nil
+}
+
+// FindByID retrieves a schema template by ID
+func (r *SQLiteSchemaTemplateRepository) FindByID(ctx context.Context, id string) (*schematemplate.SchemaTemplate, error) {
+	templates, err := r.find(ctx, `WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template by id %s: %w", id, err)
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
+	}
+	return templates[0], nil
+}
+
+// FindByCategory retrieves schema templates by category
+func (r *SQLiteSchemaTemplateRepository) FindByCategory(ctx context.Context, category string) ([]*schematemplate.SchemaTemplate, error) {
+	templates, err := r.find(ctx, `WHERE category = ? ORDER BY name`, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find templates by category %s: %w", category, err)
+	}
+	return templates, nil
+}
+
+// Exists checks if a template exists by ID
+func (r *SQLiteSchemaTemplateRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM templates WHERE id = ?`, id); err != nil {
+		return false, fmt.Errorf("failed to check template existence: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create creates a new schema template
+func (r *SQLiteSchemaTemplateRepository) Create(ctx context.Context, t *schematemplate.SchemaTemplate) error {
+	return r.create(ctx, r.db, t)
+}
+
+func (r *SQLiteSchemaTemplateRepository) create(ctx context.Context, ext sqlx.ExtContext, t *schematemplate.SchemaTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO templates (id, name, description, category, icon, schema, sample_data, workspace, version, checksum, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, json(?), json(?), ?, ?, ?, ?, ?)
+	`
+	_, err := ext.ExecContext(ctx, query, t.ID, t.Name, t.Description, t.Category, t.Icon,
+		string(t.Schema), nullJSON(t.SampleData), t.Workspace, t.Version, t.Checksum, t.CreatedAt.UTC(), t.UpdatedAt.UTC())
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique) {
+			return fmt.Errorf("%w: %s", schematemplate.ErrAlreadyExists, t.ID)
+		}
+		return fmt.Errorf("failed to create template: %w", err)
+	}
+	return nil
+}
+
+// Update updates an existing schema template
+func (r *SQLiteSchemaTemplateRepository) Update(ctx context.Context, t *schematemplate.SchemaTemplate) error {
+	return r.update(ctx, r.db, t)
+}
+
+func (r *SQLiteSchemaTemplateRepository) update(ctx context.Context, ext sqlx.ExtContext, t *schematemplate.SchemaTemplate) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE templates
+		SET name = ?, description = ?, category = ?, icon = ?, schema = json(?), sample_data = json(?),
+		    workspace = ?, version = ?, checksum = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := ext.ExecContext(ctx, query, t.Name, t.Description, t.Category, t.Icon,
+		string(t.Schema), nullJSON(t.SampleData), t.Workspace, t.Version, t.Checksum, t.UpdatedAt.UTC(), t.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+	return affectedOne(result, t.ID)
+}
+
+// Delete deletes a schema template by ID
+func (r *SQLiteSchemaTemplateRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM templates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return affectedOne(result, id)
+}
+
+// Import creates and updates templates in one transaction
+func (r *SQLiteSchemaTemplateRepository) Import(ctx context.Context, creates, updates []*schematemplate.SchemaTemplate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, t := range creates {
+		if err := r.create(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	for _, t := range updates {
+		if err := r.update(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import: %w", err)
+	}
+	return nil
+}
+
+// affectedOne turns an update of no rows into ErrNotFound
+func affectedOne(result sql.Result, id string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", schematemplate.ErrNotFound, id)
+	}
+	return nil
+}
+
+// nullJSON stores empty sample data as NULL
+func nullJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
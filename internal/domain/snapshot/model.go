@@ -0,0 +1,189 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"progressive/internal/diff"
+)
+
+// Environment labels where a snapshot is deployed
+type Environment string
+
+// Environments in promotion order
+const (
+	EnvDev  Environment = "dev"
+	EnvQA   Environment = "qa"
+	EnvLive Environment = "live"
+)
+
+// Next returns the environment a snapshot is promoted to from e
+func (e Environment) Next() (Environment, bool) {
+	switch e {
+	case EnvDev:
+		return EnvQA, true
+	case EnvQA:
+		return EnvLive, true
+	}
+	return "", false
+}
+
+// Valid reports whether e is a known environment
+func (e Environment) Valid() bool {
+	return e == EnvDev || e == EnvQA || e == EnvLive
+}
+
+// Promotion statuses
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+var (
+	ErrNotFound         = errors.New("snapshot not found")
+	ErrNameTaken        = errors.New("snapshot name already exists")
+	ErrTableNotFound    = errors.New("table not found")
+	ErrFinalEnvironment = errors.New("snapshot is already live")
+	ErrPromotionPending = errors.New("a promotion is already pending for this snapshot")
+	ErrNotPending       = errors.New("promotion is not pending")
+	ErrSelfApproval     = errors.New("a promotion must be reviewed by someone other than the requester")
+	ErrStalePromotion   = errors.New("snapshot environment changed since the promotion was requested")
+	ErrInvalidInput     = errors.New("invalid input")
+)
+
+// Snapshot is a named, immutable copy of one or more tables
+type Snapshot struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Environment Environment `json:"environment"`
+	CreatedBy   string      `json:"created_by"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Tables      []Table     `json:"tables,omitempty"`
+}
+
+// Table is a table's schema and records as captured by a snapshot
+type Table struct {
+	TableID     string          `json:"table_id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	RecordCount int             `json:"record_count"`
+	Records     []Record        `json:"records,omitempty"`
+}
+
+// Record is a captured record, keeping the record ID it had in the live table
+type Record struct {
+	ID   int64                  `json:"_id"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// NewSnapshot creates a dev snapshot ready to capture the given tables
+func NewSnapshot(name, description, createdBy string) (*Snapshot, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: snapshot name is required", ErrInvalidInput)
+	}
+	return &Snapshot{
+		ID:          fmt.Sprintf("snapshot_%d", time.Now().UnixNano()),
+		Name:        name,
+		Description: description,
+		Environment: EnvDev,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// DiffTables converts the captured tables for comparison
+func (s *Snapshot) DiffTables() []diff.Table {
+	return DiffTables(s.Tables)
+}
+
+// DiffTables converts snapshot-shaped tables for comparison
+func DiffTables(source []Table) []diff.Table {
+	tables := make([]diff.Table, len(source))
+	for i, t := range source {
+		records := make([]diff.Record, len(t.Records))
+		for j, r := range t.Records {
+			records[j] = diff.Record{ID: r.ID, Data: r.Data}
+		}
+		tables[i] = diff.Table{ID: t.TableID, Name: t.Name, Schema: t.Schema, Records: records}
+	}
+	return tables
+}
+
+// TableIDs returns the IDs of the captured tables
+func (s *Snapshot) TableIDs() []string {
+	ids := make([]string, len(s.Tables))
+	for i, t := range s.Tables {
+		ids[i] = t.TableID
+	}
+	return ids
+}
+
+// Promotion is a request to move a snapshot to the next environment
+type Promotion struct {
+	ID              int64       `json:"id"`
+	SnapshotID      string      `json:"snapshot_id"`
+	FromEnvironment Environment `json:"from_environment"`
+	ToEnvironment   Environment `json:"to_environment"`
+	Status          string      `json:"status"`
+	RequestedBy     string      `json:"requested_by"`
+	RequestedAt     time.Time   `json:"requested_at"`
+	Comment         string      `json:"comment"`
+	ReviewedBy      string      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time  `json:"reviewed_at,omitempty"`
+	ReviewComment   string      `json:"review_comment,omitempty"`
+}
+
+// NewPromotion requests promotion of the snapshot to its next environment
+func NewPromotion(s *Snapshot, requestedBy, comment string) (*Promotion, error) {
+	if strings.TrimSpace(requestedBy) == "" {
+		return nil, fmt.Errorf("%w: requested_by is required", ErrInvalidInput)
+	}
+	next, ok := s.Environment.Next()
+	if !ok {
+		return nil, ErrFinalEnvironment
+	}
+	return &Promotion{
+		SnapshotID:      s.ID,
+		FromEnvironment: s.Environment,
+		ToEnvironment:   next,
+		Status:          StatusPending,
+		RequestedBy:     requestedBy,
+		RequestedAt:     time.Now(),
+		Comment:         comment,
+	}, nil
+}
+
+// Approve marks the promotion approved by a reviewer other than the requester
+func (p *Promotion) Approve(reviewer, comment string) error {
+	return p.review(StatusApproved, reviewer, comment)
+}
+
+// Reject marks the promotion rejected
+func (p *Promotion) Reject(reviewer, comment string) error {
+	return p.review(StatusRejected, reviewer, comment)
+}
+
+func (p *Promotion) review(status, reviewer, comment string) error {
+	if p.Status != StatusPending {
+		return ErrNotPending
+	}
+	if strings.TrimSpace(reviewer) == "" {
+		return fmt.Errorf("%w: reviewer is required", ErrInvalidInput)
+	}
+	if status == StatusApproved && reviewer == p.RequestedBy {
+		return ErrSelfApproval
+	}
+	now := time.Now()
+	p.Status = status
+	p.ReviewedBy = reviewer
+	p.ReviewedAt = &now
+	p.ReviewComment = comment
+	return nil
+}
@@ -0,0 +1,41 @@
+package snapshot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPromotionRequiresAnotherReviewer(t *testing.T) {
+	s, err := NewSnapshot("release-1.4", "", "alice")
+	if err != nil {
+		t.Fatalf("Expected snapshot, got: %v", err)
+	}
+
+	p, err := NewPromotion(s, "alice", "ready for QA")
+	if err != nil {
+		t.Fatalf("Expected promotion, got: %v", err)
+	}
+	if p.FromEnvironment != EnvDev || p.ToEnvironment != EnvQA {
+		t.Errorf("Expected dev -> qa, got: %s -> %s", p.FromEnvironment, p.ToEnvironment)
+	}
+
+	if err := p.Approve("alice", ""); !errors.Is(err, ErrSelfApproval) {
+		t.Errorf("Expected ErrSelfApproval, got: %v", err)
+	}
+	if err := p.Approve("bob", "looks good"); err != nil {
+		t.Fatalf("Expected approval, got: %v", err)
+	}
+	if p.Status != StatusApproved || p.ReviewedBy != "bob" || p.ReviewedAt == nil {
+		t.Errorf("Unexpected promotion after approval: %+v", p)
+	}
+	if err := p.Reject("carol", ""); !errors.Is(err, ErrNotPending) {
+		t.Errorf("Expected ErrNotPending, got: %v", err)
+	}
+}
+
+func TestLiveSnapshotCannotBePromoted(t *testing.T) {
+	s := &Snapshot{ID: "snapshot_1", Environment: EnvLive}
+	if _, err := NewPromotion(s, "alice", ""); !errors.Is(err, ErrFinalEnvironment) {
+		t.Errorf("Expected ErrFinalEnvironment, got: %v", err)
+	}
+}
@@ -0,0 +1,483 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"progressive/internal/domain/snapshot"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ReadRepository defines read operations for snapshots and promotions
+type ReadRepository interface {
+	FindAll(ctx context.Context) ([]*snapshot.Snapshot, error)
+	FindByID(ctx context.Context, id string) (*snapshot.Snapshot, error)
+	FindCurrent(ctx context.Context, tableIDs []string) ([]snapshot.Table, error)
+	FindPromotions(ctx context.Context, snapshotID string) ([]*snapshot.Promotion, error)
+	FindPromotionByID(ctx context.Context, id int64) (*snapshot.Promotion, error)
+}
+
+// WriteRepository defines write operations for snapshots and promotions.
+// Snapshots cannot be updated or deleted once created.
+type WriteRepository interface {
+	Create(ctx context.Context, s *snapshot.Snapshot, tableIDs []string) error
+	CreatePromotion(ctx context.Context, p *snapshot.Promotion) error
+	ReviewPromotion(ctx context.Context, id int64, review func(p *snapshot.Promotion) error) (*snapshot.Promotion, error)
+}
+
+// SnapshotRepository combines both ReadRepository and WriteRepository interfaces
+type SnapshotRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// snapshotDB represents the database model
+type snapshotDB struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Environment string    `db:"environment"`
+	CreatedBy   string    `db:"created_by"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type snapshotTableDB struct {
+	SnapshotID  string          `db:"snapshot_id"`
+	TableID     string          `db:"table_id"`
+	Name        string          `db:"name"`
+	Description string          `db:"description"`
+	Schema      json.RawMessage `db:"schema"`
+	RecordCount int             `db:"record_count"`
+}
+
+type snapshotRecordDB struct {
+	TableID  string          `db:"table_id"`
+	RecordID int64           `db:"record_id"`
+	Data     json.RawMessage `db:"data"`
+}
+
+type promotionDB struct {
+	ID              int64          `db:"id"`
+	SnapshotID      string         `db:"snapshot_id"`
+	FromEnvironment string         `db:"from_environment"`
+	ToEnvironment   string         `db:"to_environment"`
+	Status          string         `db:"status"`
+	RequestedBy     string         `db:"requested_by"`
+	RequestedAt     time.Time      `db:"requested_at"`
+	Comment         string         `db:"comment"`
+	ReviewedBy      sql.NullString `db:"reviewed_by"`
+	ReviewedAt      sql.NullTime   `db:"reviewed_at"`
+	ReviewComment   sql.NullString `db:"review_comment"`
+}
+
+const promotionColumns = `id, snapshot_id, from_environment, to_environment, status,
+	requested_by, requested_at, comment, reviewed_by, reviewed_at, review_comment`
+
+// PostgresSnapshotRepository implements SnapshotRepository using PostgreSQL
+type PostgresSnapshotRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL snapshot repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresSnapshotRepository {
+	return &PostgresSnapshotRepository{db: db}
+}
+
+// FindAll retrieves all snapshots with their table summaries but without records
+func (r *PostgresSnapshotRepository) FindAll(ctx context.Context) ([]*snapshot.Snapshot, error) {
+	var rows []snapshotDB
+	query := `SELECT id, name, description, environment, created_by, created_at FROM snapshots ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to find snapshots: %w", err)
+	}
+
+	var tables []snapshotTableDB
+	tablesQuery := `
+		SELECT snapshot_id, table_id, name, description, schema, record_count
+		FROM snapshot_tables
+		ORDER BY snapshot_id, name
+	`
+	if err := r.db.SelectContext(ctx, &tables, tablesQuery); err != nil {
+		return nil, fmt.Errorf("failed to find snapshot tables: %w", err)
+	}
+
+	byID := make(map[string]*snapshot.Snapshot, len(rows))
+	snapshots := make([]*snapshot.Snapshot, len(rows))
+	for i := range rows {
+		snapshots[i] = r.toDomain(&rows[i])
+		byID[rows[i].ID] = snapshots[i]
+	}
+	for _, t := range tables {
+		if s, ok := byID[t.SnapshotID]; ok {
+			s.Tables = append(s.Tables, r.toDomainTable(&t))
+		}
+	}
+
+	return snapshots, nil
+}
+
+// FindByID retrieves a snapshot, by ID or name, with all captured tables and
+// records. An ID match wins over a snapshot named like another's ID.
+func (r *PostgresSnapshotRepository) FindByID(ctx context.Context, id string) (*snapshot.Snapshot, error) {
+	var row snapshotDB
+	query := `SELECT id, name, description, environment, created_by, created_at FROM snapshots
+		WHERE id = $1 OR name = $1 ORDER BY id = $1 DESC LIMIT 1`
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", snapshot.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find snapshot by id %s: %w", id, err)
+	}
+	id = row.ID
+
+	var tables []snapshotTableDB
+	tablesQuery := `
+		SELECT snapshot_id, table_id, name, description, schema, record_count
+		FROM snapshot_tables
+		WHERE snapshot_id = $1
+		ORDER BY name
+	`
+	if err := r.db.SelectContext(ctx, &tables, tablesQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to find snapshot tables: %w", err)
+	}
+
+	var records []snapshotRecordDB
+	recordsQuery := `
+		SELECT table_id, record_id, data
+		FROM snapshot_records
+		WHERE snapshot_id = $1
+		ORDER BY table_id, record_id
+	`
+	if err := r.db.SelectContext(ctx, &records, recordsQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to find snapshot records: %w", err)
+	}
+
+	s := r.toDomain(&row)
+	index := make(map[string]int, len(tables))
+	for i, t := range tables {
+		s.Tables = append(s.Tables, r.toDomainTable(&t))
+		index[t.TableID] = i
+	}
+	if err := r.attachRecords(s.Tables, index, records); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// FindCurrent reads the live state of the given tables in the snapshot shape.
+// Tables that no longer exist are omitted so that diffs report them as removed.
+func (r *PostgresSnapshotRepository) FindCurrent(ctx context.Context, tableIDs []string) ([]snapshot.Table, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var tables []snapshotTableDB
+	tablesQuery := `
+		SELECT '' AS snapshot_id, id AS table_id, name, COALESCE(description, '') AS description, schema,
+		       (SELECT COUNT(*) FROM records r WHERE r.table_id = t.id) AS record_count
+		FROM tables t
+		WHERE id = ANY($1)
+		ORDER BY name
+	`
+	if err := tx.SelectContext(ctx, &tables, tablesQuery, pq.Array(tableIDs)); err != nil {
+		return nil, fmt.Errorf("failed to find current tables: %w", err)
+	}
+
+	var records []snapshotRecordDB
+	recordsQuery := `
+		SELECT table_id, id AS record_id, data
+		FROM records
+		WHERE table_id = ANY($1)
+		ORDER BY table_id, id
+	`
+	if err := tx.SelectContext(ctx, &records, recordsQuery, pq.Array(tableIDs)); err != nil {
+		return nil, fmt.Errorf("failed to find current records: %w", err)
+	}
+
+	result := make([]snapshot.Table, len(tables))
+	index := make(map[string]int, len(tables))
+	for i, t := range tables {
+		result[i] = r.toDomainTable(&t)
+		index[t.TableID] = i
+	}
+	if err := r.attachRecords(result, index, records); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Create stores the snapshot and captures the given tables (all tables when
+// empty) from a single consistent read of tables and records
+func (r *PostgresSnapshotRepository) Create(ctx context.Context, s *snapshot.Snapshot, tableIDs []string) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(tableIDs) == 0 {
+		if err := tx.SelectContext(ctx, &tableIDs, `SELECT id FROM tables ORDER BY id`); err != nil {
+			return fmt.Errorf("failed to list tables: %w", err)
+		}
+	} else {
+		var found []string
+		if err := tx.SelectContext(ctx, &found, `SELECT id FROM tables WHERE id = ANY($1)`, pq.Array(tableIDs)); err != nil {
+			return fmt.Errorf("failed to look up tables: %w", err)
+		}
+		if missing := missingIDs(tableIDs, found); len(missing) > 0 {
+			return fmt.Errorf("%w: %s", snapshot.ErrTableNotFound, strings.Join(missing, ", "))
+		}
+	}
+	if len(tableIDs) == 0 {
+		return fmt.Errorf("%w: there are no tables to snapshot", snapshot.ErrInvalidInput)
+	}
+
+	insertSnapshot := `
+		INSERT INTO snapshots (id, name, description, environment, created_by, created_at)
+		VALUES (:id, :name, :description, :environment, :created_by, :created_at)
+	`
+	if _, err := tx.NamedExecContext(ctx, insertSnapshot, r.toDBModel(s)); err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%w: %s", snapshot.ErrNameTaken, s.Name)
+		}
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	captureTables := `
+		INSERT INTO snapshot_tables (snapshot_id, table_id, name, description, schema, record_count)
+		SELECT $1, t.id, t.name, COALESCE(t.description, ''), t.schema,
+		       (SELECT COUNT(*) FROM records r WHERE r.table_id = t.id)
+		FROM tables t
+		WHERE t.id = ANY($2)
+	`
+	if _, err := tx.ExecContext(ctx, captureTables, s.ID, pq.Array(tableIDs)); err != nil {
+		return fmt.Errorf("failed to capture tables: %w", err)
+	}
+
+	captureRecords := `
+		INSERT INTO snapshot_records (snapshot_id, table_id, record_id, data)
+		SELECT $1, table_id, id, data
+		FROM records
+		WHERE table_id = ANY($2)
+	`
+	if _, err := tx.ExecContext(ctx, captureRecords, s.ID, pq.Array(tableIDs)); err != nil {
+		return fmt.Errorf("failed to capture records: %w", err)
+	}
+
+	var tables []snapshotTableDB
+	tablesQuery := `
+		SELECT snapshot_id, table_id, name, description, schema, record_count
+		FROM snapshot_tables
+		WHERE snapshot_id = $1
+		ORDER BY name
+	`
+	if err := tx.SelectContext(ctx, &tables, tablesQuery, s.ID); err != nil {
+		return fmt.Errorf("failed to read captured tables: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit snapshot: %w", err)
+	}
+
+	s.Tables = s.Tables[:0]
+	for _, t := range tables {
+		s.Tables = append(s.Tables, r.toDomainTable(&t))
+	}
+	return nil
+}
+
+// FindPromotions lists the promotion history of a snapshot, newest first
+func (r *PostgresSnapshotRepository) FindPromotions(ctx context.Context, snapshotID string) ([]*snapshot.Promotion, error) {
+	var rows []promotionDB
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE snapshot_id = $1 ORDER BY requested_at DESC, id DESC`
+	if err := r.db.SelectContext(ctx, &rows, query, snapshotID); err != nil {
+		return nil, fmt.Errorf("failed to find promotions: %w", err)
+	}
+
+	promotions := make([]*snapshot.Promotion, len(rows))
+	for i := range rows {
+		promotions[i] = r.toDomainPromotion(&rows[i])
+	}
+	return promotions, nil
+}
+
+// FindPromotionByID retrieves a single promotion
+func (r *PostgresSnapshotRepository) FindPromotionByID(ctx context.Context, id int64) (*snapshot.Promotion, error) {
+	var row promotionDB
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1`
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: promotion %d", snapshot.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find promotion %d: %w", id, err)
+	}
+	return r.toDomainPromotion(&row), nil
+}
+
+// CreatePromotion stores a pending promotion request
+func (r *PostgresSnapshotRepository) CreatePromotion(ctx context.Context, p *snapshot.Promotion) error {
+	query := `
+		INSERT INTO promotions (snapshot_id, from_environment, to_environment, status, requested_by, requested_at, comment)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+	err := r.db.QueryRowContext(ctx, query, p.SnapshotID, string(p.FromEnvironment), string(p.ToEnvironment),
+		p.Status, p.RequestedBy, p.RequestedAt, p.Comment).Scan(&p.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return snapshot.ErrPromotionPending
+		}
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+// ReviewPromotion locks the promotion, applies the review and, when approved,
+// moves the snapshot to the target environment in the same transaction
+func (r *PostgresSnapshotRepository) ReviewPromotion(ctx context.Context, id int64, review func(p *snapshot.Promotion) error) (*snapshot.Promotion, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var row promotionDB
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: promotion %d", snapshot.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find promotion %d: %w", id, err)
+	}
+
+	p := r.toDomainPromotion(&row)
+	if err := review(p); err != nil {
+		return nil, err
+	}
+
+	if p.Status == snapshot.StatusApproved {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE snapshots SET environment = $1 WHERE id = $2 AND environment = $3`,
+			string(p.ToEnvironment), p.SnapshotID, string(p.FromEnvironment))
+		if err != nil {
+			return nil, fmt.Errorf("failed to promote snapshot: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		} else if n == 0 {
+			return nil, snapshot.ErrStalePromotion
+		}
+	}
+
+	update := `
+		UPDATE promotions
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, review_comment = $4
+		WHERE id = $5
+	`
+	if _, err := tx.ExecContext(ctx, update, p.Status, p.ReviewedBy, p.ReviewedAt, p.ReviewComment, p.ID); err != nil {
+		return nil, fmt.Errorf("failed to update promotion: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit promotion review: %w", err)
+	}
+	return p, nil
+}
+
+// Helper methods for conversion between domain and database models
+
+func (r *PostgresSnapshotRepository) toDomain(db *snapshotDB) *snapshot.Snapshot {
+	return &snapshot.Snapshot{
+		ID:          db.ID,
+		Name:        db.Name,
+		Description: db.Description,
+		Environment: snapshot.Environment(db.Environment),
+		CreatedBy:   db.CreatedBy,
+		CreatedAt:   db.CreatedAt,
+	}
+}
+
+func (r *PostgresSnapshotRepository) toDomainTable(db *snapshotTableDB) snapshot.Table {
+	return snapshot.Table{
+		TableID:     db.TableID,
+		Name:        db.Name,
+		Description: db.Description,
+		Schema:      db.Schema,
+		RecordCount: db.RecordCount,
+	}
+}
+
+func (r *PostgresSnapshotRepository) toDBModel(domain *snapshot.Snapshot) *snapshotDB {
+	return &snapshotDB{
+		ID:          domain.ID,
+		Name:        domain.Name,
+		Description: domain.Description,
+		Environment: string(domain.Environment),
+		CreatedBy:   domain.CreatedBy,
+		CreatedAt:   domain.CreatedAt,
+	}
+}
+
+func (r *PostgresSnapshotRepository) toDomainPromotion(db *promotionDB) *snapshot.Promotion {
+	p := &snapshot.Promotion{
+		ID:              db.ID,
+		SnapshotID:      db.SnapshotID,
+		FromEnvironment: snapshot.Environment(db.FromEnvironment),
+		ToEnvironment:   snapshot.Environment(db.ToEnvironment),
+		Status:          db.Status,
+		RequestedBy:     db.RequestedBy,
+		RequestedAt:     db.RequestedAt,
+		Comment:         db.Comment,
+		ReviewedBy:      db.ReviewedBy.String,
+		ReviewComment:   db.ReviewComment.String,
+	}
+	if db.ReviewedAt.Valid {
+		reviewedAt := db.ReviewedAt.Time
+		p.ReviewedAt = &reviewedAt
+	}
+	return p
+}
+
+func (r *PostgresSnapshotRepository) attachRecords(tables []snapshot.Table, index map[string]int, records []snapshotRecordDB) error {
+	for _, rec := range records {
+		i, ok := index[rec.TableID]
+		if !ok {
+			continue
+		}
+		data := make(map[string]interface{})
+		if err := json.Unmarshal(rec.Data, &data); err != nil {
+			return fmt.Errorf("failed to decode record %d: %w", rec.RecordID, err)
+		}
+		tables[i].Records = append(tables[i].Records, snapshot.Record{ID: rec.RecordID, Data: data})
+	}
+	return nil
+}
+
+func missingIDs(requested, found []string) []string {
+	present := make(map[string]bool, len(found))
+	for _, id := range found {
+		present[id] = true
+	}
+	var missing []string
+	for _, id := range requested {
+		if !present[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,81 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("table not found")
+	ErrAlreadyExists = errors.New("table already exists")
+	ErrInvalidInput  = errors.New("invalid input")
+)
+
+// Table is a user-defined table whose rows are stored as records
+type Table struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	RecordCount int             `json:"record_count"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// NewTable creates a new, empty table
+func NewTable(id, name, description string, schema json.RawMessage) *Table {
+	now := time.Now()
+	return &Table{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Schema:      schema,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate checks the fields required to store a table
+func (t *Table) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("%w: table id is required", ErrInvalidInput)
+	}
+	if t.Name == "" {
+		return fmt.Errorf("%w: table name is required", ErrInvalidInput)
+	}
+	if len(t.Schema) == 0 || !json.Valid(t.Schema) {
+		return fmt.Errorf("%w: schema must be valid JSON", ErrInvalidInput)
+	}
+	return nil
+}
+
+// ValidateSchema checks that a schema is a JSON Schema object with at least one property
+func ValidateSchema(schema json.RawMessage) error {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(schema, &parsed); err != nil {
+		return fmt.Errorf("%w: invalid JSON schema syntax: %v", ErrInvalidInput, err)
+	}
+
+	schemaType, ok := parsed["type"]
+	if !ok {
+		return fmt.Errorf("%w: schema must have a 'type' field", ErrInvalidInput)
+	}
+	if schemaType != "object" {
+		return fmt.Errorf("%w: schema type must be 'object', got '%v'", ErrInvalidInput, schemaType)
+	}
+
+	propertiesField, ok := parsed["properties"]
+	if !ok {
+		return fmt.Errorf("%w: schema must contain 'properties' field", ErrInvalidInput)
+	}
+	properties, ok := propertiesField.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: schema 'properties' field must be an object", ErrInvalidInput)
+	}
+	if len(properties) == 0 {
+		return fmt.Errorf("%w: schema must contain at least one property", ErrInvalidInput)
+	}
+	return nil
+}
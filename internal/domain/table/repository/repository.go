@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"progressive/internal/domain/table"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ReadRepository defines read operations for tables
+type ReadRepository interface {
+	FindAll(ctx context.Context) ([]*table.Table, error)
+	FindByID(ctx context.Context, id string) (*table.Table, error)
+}
+
+// WriteRepository defines write operations for tables.
+// Deleting a table also deletes its records.
+type WriteRepository interface {
+	Create(ctx context.Context, t *table.Table) error
+	Delete(ctx context.Context, id string) error
+}
+
+// TableRepository combines both ReadRepository and WriteRepository interfaces
+type TableRepository interface {
+	ReadRepository
+	WriteRepository
+}
+
+// tableDB represents the database model
+type tableDB struct {
+	ID          string          `db:"id"`
+	Name        string          `db:"name"`
+	Description string          `db:"description"`
+	Schema      json.RawMessage `db:"schema"`
+	RecordCount int             `db:"record_count"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
+
+const tableColumns = `id, name, COALESCE(description, '') AS description, schema,
+	COALESCE(record_count, 0) AS record_count, created_at, updated_at`
+
+// PostgresTableRepository implements TableRepository using PostgreSQL
+type PostgresTableRepository struct {
+	db *sqlx.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL table repository
+func NewPostgresRepository(db *sqlx.DB) *PostgresTableRepository {
+	return &PostgresTableRepository{db: db}
+}
+
+// FindAll retrieves all tables, most recently updated first
+func (r *PostgresTableRepository) FindAll(ctx context.Context) ([]*table.Table, error) {
+	var rows []tableDB
+	query := `SELECT ` + tableColumns + ` FROM tables ORDER BY updated_at DESC`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to find tables: %w", err)
+	}
+
+	tables := make([]*table.Table, len(rows))
+	for i := range rows {
+		tables[i] = toDomain(&rows[i])
+	}
+	return tables, nil
+}
+
+// FindByID retrieves a table by ID
+func (r *PostgresTableRepository) FindByID(ctx context.Context, id string) (*table.Table, error) {
+	var row tableDB
+	query := `SELECT ` + tableColumns + ` FROM tables WHERE id = $1`
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", table.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find table by id %s: %w", id, err)
+	}
+	return toDomain(&row), nil
+}
+
+// Create inserts a new table
+func (r *PostgresTableRepository) Create(ctx context.Context, t *table.Table) error {
+	return InsertPostgres(ctx, r.db, t)
+}
+
+// InsertPostgres inserts a table through db, which may be a transaction
+func InsertPostgres(ctx context.Context, db sqlx.ExtContext, t *table.Table) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	row := toDBModel(t)
+	query := `
+		INSERT INTO tables (id, name, description, schema, record_count, created_at, updated_at)
+		VALUES (:id, :name, :description, :schema, :record_count, :created_at, :updated_at)
+	`
+	if _, err := sqlx.NamedExecContext(ctx, db, query, row); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return fmt.Errorf("%w: %s", table.ErrAlreadyExists, t.ID)
+		}
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a table; its records are removed by ON DELETE CASCADE
+func (r *PostgresTableRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tables WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete table: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", table.ErrNotFound, id)
+	}
+	return nil
+}
+
+// toDomain converts database model to domain model
+func toDomain(row *tableDB) *table.Table {
+	return &table.Table{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		Schema:      row.Schema,
+		RecordCount: row.RecordCount,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+// toDBModel converts domain model to database model
+func toDBModel(t *table.Table) *tableDB {
+	return &tableDB{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Schema:      t.Schema,
+		RecordCount: t.RecordCount,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
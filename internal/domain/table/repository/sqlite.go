@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"progressive/internal/domain/table"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteTableColumns reads schema as a BLOB so it scans into json.RawMessage
+const sqliteTableColumns = `id, name, COALESCE(description, '') AS description, CAST(schema AS BLOB) AS schema,
+	COALESCE(record_count, 0) AS record_count, created_at, updated_at`
+
+// SQLiteTableRepository implements TableRepository using SQLite.
+// Schemas are stored as TEXT and normalized with the JSON1 json() function.
+type SQLiteTableRepository struct {
+	db *sqlx.DB
+}
+
+// NewSQLiteRepository creates a new SQLite table repository
+func NewSQLiteRepository(db *sqlx.DB) *SQLiteTableRepository {
+	return &SQLiteTableRepository{db: db}
+}
+
+// FindAll retrieves all tables, most recently updated first
+func (r *SQLiteTableRepository) FindAll(ctx context.Context) ([]*table.Table, error) {
+	var rows []tableDB
+	query := `SELECT ` + sqliteTableColumns + ` FROM tables ORDER BY updated_at DESC, id`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to find tables: %w", err)
+	}
+
+	tables := make([]*table.Table, len(rows))
+	for i := range rows {
+		tables[i] = toDomain(&rows[i])
+	}
+	return tables, nil
+}
+
+// FindByID retrieves a table by ID
+func (r *SQLiteTableRepository) FindByID(ctx context.Context, id string) (*table.Table, error) {
+	var row tableDB
+	query := `SELECT ` + sqliteTableColumns + ` FROM tables WHERE id = ?`
+	if err := r.db.GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", table.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to find table by id %s: %w", id, err)
+	}
+	return toDomain(&row), nil
+}
+
+// Create inserts a new table
+func (r *SQLiteTableRepository) Create(ctx context.Context, t *table.Table) error {
+	return InsertSQLite(ctx, r.db, t)
+}
+
+// InsertSQLite inserts a table through db, which may be a transaction
+func InsertSQLite(ctx context.Context, db sqlx.ExecerContext, t *table.Table) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tables (id, name, description, schema, record_count, created_at, updated_at)
+		VALUES (?, ?, ?, json(?), ?, ?, ?)
+	`
+	_, err := db.ExecContext(ctx, query, t.ID, t.Name, t.Description, string(t.Schema),
+		t.RecordCount, t.CreatedAt.UTC(), t.UpdatedAt.UTC())
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && (sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique) {
+			return fmt.Errorf("%w: %s", table.ErrAlreadyExists, t.ID)
+		}
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a table; its records are removed by ON DELETE CASCADE
+func (r *SQLiteTableRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tables WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete table: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s", table.ErrNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+// Package fakeit generates fake records for a table schema with gofakeit.
+package fakeit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/validation"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+// FieldConfig selects how the values of one field are generated. Type names
+// a generator for the field's JSON type ("email", "price", "custom", ...)
+// and Params tunes it.
+type FieldConfig struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params"`
+}
+
+// maxAttempts bounds how often a value is regenerated until it validates
+const maxAttempts = 10
+
+// Dates are drawn from a fixed range so that seeded output does not change
+// with the current year
+var (
+	minDate = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDate = time.Date(2030, 12, 31, 23, 59, 59, 0, time.UTC)
+)
+
+// Generator generates fake records from its own source of randomness, so
+// that requests neither share state nor affect each other's output
+type Generator struct {
+	faker *gofakeit.Faker
+}
+
+// New returns a generator whose output is determined by seed: the same seed,
+// schema and configs always produce the same records. A zero seed is random.
+func New(seed int64) *Generator {
+	return &Generator{faker: gofakeit.New(uint64(seed))}
+}
+
+// NewSeed returns a random non-zero seed that JavaScript numbers hold exactly
+func NewSeed() int64 {
+	return rand.Int64N(1<<53-1) + 1
+}
+
+// Generate returns count records for the properties of schema. Fields with a
+// config use its generator; the others get a value inferred from the
+// property's enum, bounds, pattern, format and name. Every record passes the
+// schema's own validation: values that fail are regenerated from the
+// property, and optional fields that keep failing are left out.
+func (g *Generator) Generate(schema map[string]interface{}, fieldConfigs map[string]FieldConfig, count int) ([]map[string]interface{}, error) {
+	def, validator, err := compile(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]interface{}
+	fieldNames := def.PropertyNames()
+	for i := 0; i < count; i++ {
+		record := make(map[string]interface{})
+		for _, fieldName := range fieldNames {
+			value, ok := g.fieldValue(validator, fieldName, def.Properties[fieldName], fieldConfigs)
+			if !ok {
+				if def.IsRequired(fieldName) {
+					return nil, fmt.Errorf("cannot generate a valid value for required field %s", fieldName)
+				}
+				continue
+			}
+			record[fieldName] = value
+		}
+		results = append(results, record)
+	}
+
+	return results, nil
+}
+
+// compile decodes the properties of schema and builds their validator.
+// Unlike stored table schemas, "type": "object" may be left out.
+func compile(schema map[string]interface{}) (*repository.SchemaDefinition, *validation.Validator, error) {
+	if _, ok := schema["properties"].(map[string]interface{}); !ok {
+		return nil, nil, fmt.Errorf("invalid schema: properties not found")
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	var def repository.SchemaDefinition
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	validator, err := validation.New(&def)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &def, validator, nil
+}
+
+// fieldValue generates a value for one field that passes validation
+func (g *Generator) fieldValue(v *validation.Validator, name string, prop repository.PropertyDef, fieldConfigs map[string]FieldConfig) (interface{}, bool) {
+	if config, exists := fieldConfigs[name]; exists {
+		value, err := g.generateFieldValue(prop.Type, config)
+		if err == nil && len(v.ValidateField(name, value)) == 0 {
+			return value, true
+		}
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		value := g.inferValue(name, prop)
+		if len(v.ValidateField(name, value)) == 0 {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// inferValue generates a value from the property's constraints and name.
+// A property with an enum always gets one of its values, whatever its type.
+func (g *Generator) inferValue(name string, prop repository.PropertyDef) interface{} {
+	switch len(prop.Enum) {
+	case 0:
+	case 1:
+		return prop.Enum[0]
+	default:
+		return prop.Enum[g.faker.IntN(len(prop.Enum))]
+	}
+
+	lower := strings.ToLower(name)
+	switch prop.Type {
+	case "integer":
+		min, max := bounds(lower, prop, false)
+		lo, hi := toInt(math.Ceil(min)), toInt(math.Floor(max))
+		if lo > hi {
+			return lo
+		}
+		return g.intRange(lo, hi)
+	case "number":
+		min, max := bounds(lower, prop, true)
+		n := math.Round(g.faker.Float64Range(min, max)*100) / 100
+		return math.Min(math.Max(n, min), max)
+	case "boolean":
+		return g.faker.Bool()
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	default:
+		return g.inferString(lower, prop)
+	}
+}
+
+// intRange returns an integer in [lo, hi]. Unlike faker.Number it counts
+// the span in uint64, so bounds such as -9e18 and 9e18 do not overflow.
+func (g *Generator) intRange(lo, hi int) int {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	span := uint64(hi) - uint64(lo)
+	if span == math.MaxUint64 {
+		return int(g.faker.Uint64())
+	}
+	return int(uint64(lo) + uint64(g.faker.UintN(uint(span+1))))
+}
+
+// toInt converts a whole float to int, clamping it to the int range
+func toInt(f float64) int {
+	switch {
+	case f <= math.MinInt:
+		return math.MinInt
+	case f >= math.MaxInt:
+		return math.MaxInt
+	}
+	return int(f)
+}
+
+// bounds returns the range for a numeric property: its minimum and maximum,
+// with a range suited to its name filling in what is missing
+func bounds(name string, prop repository.PropertyDef, fractional bool) (float64, float64) {
+	min, max := 1.0, 1000.0
+	switch {
+	case strings.Contains(name, "price") && fractional:
+		min, max = 10, 1000
+	case strings.Contains(name, "price"):
+		min, max = 1000, 100000
+	case strings.Contains(name, "rating"):
+		min, max = 1, 5
+	case strings.Contains(name, "quantity"), strings.Contains(name, "stock"):
+		min, max = 1, 100
+	}
+
+	switch {
+	case prop.Minimum != nil && prop.Maximum != nil:
+		return *prop.Minimum, *prop.Maximum
+	case prop.Minimum != nil:
+		if max < *prop.Minimum {
+			max = *prop.Minimum + 1000
+		}
+		return *prop.Minimum, max
+	case prop.Maximum != nil:
+		if min > *prop.Maximum {
+			min = *prop.Maximum - 1000
+		}
+		return min, *prop.Maximum
+	}
+	return min, max
+}
+
+// nameHints maps words in property names to string generators
+var nameHints = []struct {
+	word string
+	typ  string
+}{
+	{"email", "email"},
+	{"phone", "phone"},
+	{"company", "company"},
+	{"address", "address"},
+	{"city", "city"},
+	{"country", "country"},
+	{"url", "url"},
+	{"username", "username"},
+	{"color", "color"},
+	{"description", "sentence"},
+	{"name", "name"},
+	{"title", "sentence"},
+}
+
+// inferString generates a string from the property's pattern, format or
+// name, in that order, and fits it to minLength and maxLength
+func (g *Generator) inferString(name string, prop repository.PropertyDef) string {
+	var s string
+	switch {
+	case prop.Pattern != "":
+		s = g.faker.Regex(prop.Pattern)
+	case prop.Format != "":
+		s = g.formatted(prop.Format)
+	}
+	if s == "" {
+		s = g.faker.Word() + " " + g.faker.Word()
+		for _, hint := range nameHints {
+			if strings.Contains(name, hint.word) {
+				s, _ = g.generateStringValue(FieldConfig{Type: hint.typ})
+				break
+			}
+		}
+	}
+
+	for prop.MinLength > 0 && utf8.RuneCountInString(s) < prop.MinLength {
+		s += " " + g.faker.Word()
+	}
+	if prop.MaxLength > 0 && utf8.RuneCountInString(s) > prop.MaxLength {
+		s = string([]rune(s)[:prop.MaxLength])
+	}
+	return s
+}
+
+// formatted generates a string in a JSON Schema format, or "" for formats
+// without a generator
+func (g *Generator) formatted(format string) string {
+	switch format {
+	case "date":
+		return g.faker.DateRange(minDate, maxDate).Format("2006-01-02")
+	case "date-time":
+		return g.faker.DateRange(minDate, maxDate).Format(time.RFC3339)
+	case "time":
+		return g.faker.DateRange(minDate, maxDate).Format("15:04:05")
+	case "email":
+		return g.faker.Email()
+	case "uri", "url":
+		return g.faker.URL()
+	case "uuid":
+		return g.faker.UUID()
+	default:
+		return ""
+	}
+}
+
+// generateFieldValue generates a fake value for a specific field
+func (g *Generator) generateFieldValue(fieldType string, config FieldConfig) (interface{}, error) {
+	switch fieldType {
+	case "string":
+		return g.generateStringValue(config)
+	case "integer":
+		return g.generateIntegerValue(config)
+	case "number":
+		return g.generateNumberValue(config)
+	case "boolean":
+		return g.generateBooleanValue(config)
+	default:
+		return g.generateStringValue(config)
+	}
+}
+
+// generateStringValue generates fake string values
+func (g *Generator) generateStringValue(config FieldConfig) (string, error) {
+	switch config.Type {
+	case "name":
+		return g.faker.Name(), nil
+	case "firstName":
+		return g.faker.FirstName(), nil
+	case "lastName":
+		return g.faker.LastName(), nil
+	case "email":
+		return g.faker.Email(), nil
+	case "phone":
+		return g.faker.Phone(), nil
+	case "address":
+		return g.faker.Address().Address, nil
+	case "company":
+		return g.faker.Company(), nil
+	case "jobTitle":
+		return g.faker.JobTitle(), nil
+	case "city":
+		return g.faker.City(), nil
+	case "country":
+		return g.faker.Country(), nil
+	case "lorem":
+		wordCount := 5
+		if wc, exists := config.Params["wordCount"]; exists {
+			if parsed, err := strconv.Atoi(wc); err == nil && parsed > 0 {
+				wordCount = parsed
+			}
+		}
+		return g.faker.LoremIpsumSentence(wordCount), nil
+	case "sentence":
+		return g.faker.Sentence(g.faker.IntN(10) + 5), nil
+	case "paragraph":
+		return g.faker.Paragraph(1, 3, g.faker.IntN(8)+5, " "), nil
+	case "uuid":
+		return g.faker.UUID(), nil
+	case "url":
+		return g.faker.URL(), nil
+	case "username":
+		return g.faker.Username(), nil
+	case "password":
+		return g.faker.Password(true, true, true, true, false, g.faker.IntN(8)+8), nil
+	case "color":
+		return g.faker.HexColor(), nil
+	case "custom":
+		if values, exists := config.Params["values"]; exists {
+			valueList := strings.Split(values, ",")
+			if len(valueList) > 0 {
+				trimmed := make([]string, len(valueList))
+				for i, v := range valueList {
+					trimmed[i] = strings.TrimSpace(v)
+				}
+				return g.faker.RandomString(trimmed), nil
+			}
+		}
+		return g.faker.Word(), nil
+	default:
+		return g.faker.Word(), nil
+	}
+}
+
+// generateIntegerValue generates fake integer values
+func (g *Generator) generateIntegerValue(config FieldConfig) (int, error) {
+	switch config.Type {
+	case "age":
+		return g.faker.Number(18, 80), nil
+	case "year":
+		return g.faker.Year(), nil
+	case "month":
+		return g.faker.Month(), nil
+	case "day":
+		return g.faker.Day(), nil
+	case "price":
+		return g.faker.Number(1000, 100000), nil
+	case "quantity":
+		return g.faker.Number(1, 100), nil
+	case "rating":
+		return g.faker.Number(1, 5), nil
+	case "custom":
+		min := 1
+		max := 100
+		if minStr, exists := config.Params["min"]; exists {
+			if parsed, err := strconv.Atoi(minStr); err == nil {
+				min = parsed
+			}
+		}
+		if maxStr, exists := config.Params["max"]; exists {
+			if parsed, err := strconv.Atoi(maxStr); err == nil {
+				max = parsed
+			}
+		}
+		return g.intRange(min, max), nil
+	default:
+		return g.faker.Number(1, 1000), nil
+	}
+}
+
+// generateNumberValue generates fake float values
+func (g *Generator) generateNumberValue(config FieldConfig) (float64, error) {
+	switch config.Type {
+	case "price":
+		return g.faker.Price(10.0, 1000.0), nil
+	case "latitude":
+		return g.faker.Latitude(), nil
+	case "longitude":
+		return g.faker.Longitude(), nil
+	case "percentage":
+		return g.faker.Float64Range(0.0, 100.0), nil
+	case "custom":
+		min := 0.0
+		max := 100.0
+		if minStr, exists := config.Params["min"]; exists {
+			if parsed, err := strconv.ParseFloat(minStr, 64); err == nil {
+				min = parsed
+			}
+		}
+		if maxStr, exists := config.Params["max"]; exists {
+			if parsed, err := strconv.ParseFloat(maxStr, 64); err == nil {
+				max = parsed
+			}
+		}
+		return g.faker.Float64Range(min, max), nil
+	default:
+		return g.faker.Float64Range(0.0, 100.0), nil
+	}
+}
+
+// generateBooleanValue generates fake boolean values
+func (g *Generator) generateBooleanValue(config FieldConfig) (bool, error) {
+	switch config.Type {
+	case "weighted":
+		probability := 50 // default 50%
+		if probStr, exists := config.Params["trueProbability"]; exists {
+			if parsed, err := strconv.Atoi(probStr); err == nil && parsed >= 0 && parsed <= 100 {
+				probability = parsed
+			}
+		}
+		return g.faker.IntN(100) < probability, nil
+	default:
+		return g.faker.Bool(), nil
+	}
+}
@@ -0,0 +1,154 @@
+// Package gql serves a GraphQL API over the user tables. Its schema is built
+// from the stored JSON Schemas: one object type per table, list and get
+// queries, create/update/delete mutations, and "x-ref" properties resolved
+// across tables. The schema is rebuilt whenever a table is added, removed or
+// changes its schema.
+package gql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"progressive/internal/apierror"
+	recordrepo "progressive/internal/domain/record/repository"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Service builds the schema from the tables and executes requests against it
+type Service struct {
+	tables  tablerepo.TableRepository
+	records recordrepo.RecordRepository
+
+	mu          sync.Mutex
+	fingerprint string
+	schema      *graphql.Schema
+}
+
+// New creates a Service on the given repositories
+func New(tables tablerepo.TableRepository, records recordrepo.RecordRepository) *Service {
+	return &Service{tables: tables, records: records}
+}
+
+// Schema returns the schema of the current tables, rebuilding the cached one
+// when their IDs, names or schemas changed since it was built
+func (s *Service) Schema(ctx context.Context) (*graphql.Schema, error) {
+	tables, err := s.tables.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].ID < tables[j].ID })
+	fingerprint := fingerprintOf(tables)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.schema != nil && s.fingerprint == fingerprint {
+		return s.schema, nil
+	}
+	schema, err := buildSchema(tables)
+	if err != nil {
+		return nil, err
+	}
+	s.schema, s.fingerprint = schema, fingerprint
+	return schema, nil
+}
+
+// fingerprintOf hashes what the schema is built from; record counts and
+// timestamps are left out so that writing records does not rebuild it
+func fingerprintOf(tables []*table.Table) string {
+	h := sha256.New()
+	for _, t := range tables {
+		h.Write([]byte(t.ID))
+		h.Write([]byte{0})
+		h.Write([]byte(t.Name))
+		h.Write([]byte{0})
+		h.Write(t.Schema)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Do executes a request. Errors in the query or its resolvers are part of
+// the result; the error is only for a schema that cannot be built.
+func (s *Service) Do(ctx context.Context, req Request) (*graphql.Result, error) {
+	schema, err := s.Schema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        withLoader(ctx, s.records),
+	}), nil
+}
+
+// Handler serves GraphQL over HTTP: POST with a JSON body, or GET with
+// query, operationName and variables in the query string. Results, including
+// GraphQL errors, are answered with 200 as the GraphQL over HTTP convention
+// expects.
+func (s *Service) Handler(w http.ResponseWriter, r *http.Request) error {
+	var req Request
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if vars := q.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return apierror.InvalidJSON(err)
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	if req.Query == "" {
+		return apierror.Validation("A query is required",
+			apierror.FieldError{Field: "query", Code: "required", Message: "query is required"})
+	}
+	if r.Method == http.MethodGet && isMutation(req) {
+		return apierror.New(http.StatusMethodNotAllowed, "mutation_over_get", "Mutations must be sent with POST")
+	}
+
+	result, err := s.Do(r.Context(), req)
+	if err != nil {
+		return apierror.Internal(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
+// isMutation reports whether the operation a request runs is a mutation.
+// A query that does not parse is not one; executing it reports the error.
+func isMutation(req Request) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || req.OperationName != "" && (op.Name == nil || op.Name.Value != req.OperationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
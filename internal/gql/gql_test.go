@@ -0,0 +1,213 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+func newTestService(t *testing.T) (*Service, *storage.Store) {
+	t.Helper()
+	store := storagetest.Open(t, storage.Memory)
+	ctx := context.Background()
+
+	tables := []*table.Table{
+		table.NewTable("game_item", "Items", "", json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"item_name": {"type": "string"},
+				"price": {"type": "integer"},
+				"tags": {"type": "array"}
+			},
+			"required": ["item_name"]
+		}`)),
+		table.NewTable("shop", "Shops", "", json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"title": {"type": "string"},
+				"featured": {"type": "string", "x-ref": "game_item.item_name"}
+			}
+		}`)),
+	}
+	for _, tbl := range tables {
+		if err := store.Tables.Create(ctx, tbl); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+	for _, data := range []map[string]interface{}{
+		{"item_name": "sword", "price": float64(100), "tags": []interface{}{"melee"}},
+		{"item_name": "bow", "price": float64(80), "tags": []interface{}{"ranged"}},
+		{"item_name": "axe", "price": float64(120), "tags": []interface{}{"melee"}},
+	} {
+		if err := store.Records.Create(ctx, record.NewRecord("game_item", data)); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+	}
+	if err := store.Records.Create(ctx, record.NewRecord("shop", map[string]interface{}{"title": "Armory", "featured": "bow"})); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+	return New(store.Tables, store.Records), store
+}
+
+// run executes a query and fails the test on GraphQL errors
+func run(t *testing.T, s *Service, query string, variables map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	result, err := s.Do(context.Background(), Request{Query: query, Variables: variables})
+	if err != nil {
+		t.Fatalf("Do failed: %v", err)
+	}
+	if result.HasErrors() {
+		t.Fatalf("Query failed: %v", result.Errors)
+	}
+	// Round trip through JSON to compare as a client would
+	body, _ := json.Marshal(result.Data)
+	var data map[string]interface{}
+	json.Unmarshal(body, &data)
+	return data
+}
+
+func TestListQueryFiltersSortsAndPages(t *testing.T) {
+	s, _ := newTestService(t)
+
+	data := run(t, s, `{
+		gameItemList(filter: [{field: tags, op: CONTAINS, value: "melee"}], sort: [{field: price, direction: DESC}], limit: 1) {
+			total
+			items { item_name price }
+		}
+	}`, nil)
+	page := data["gameItemList"].(map[string]interface{})
+	if page["total"] != float64(2) {
+		t.Errorf("Expected 2 melee items, got %v", page["total"])
+	}
+	items := page["items"].([]interface{})
+	if len(items) != 1 || items[0].(map[string]interface{})["item_name"] != "axe" {
+		t.Errorf("Expected the most expensive melee item, got %v", items)
+	}
+
+	data = run(t, s, `{ gameItemList(filter: [{field: price, op: LT, value: 110}, {field: item_name, op: IN, value: ["bow", "axe"]}]) { items { item_name } } }`, nil)
+	items = data["gameItemList"].(map[string]interface{})["items"].([]interface{})
+	if len(items) != 1 || items[0].(map[string]interface{})["item_name"] != "bow" {
+		t.Errorf("Expected only bow, got %v", items)
+	}
+}
+
+func TestReferencesResolveAcrossTables(t *testing.T) {
+	s, _ := newTestService(t)
+
+	data := run(t, s, `{ shopList { items { title featured featured_ref { item_name price } } } }`, nil)
+	shop := data["shopList"].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})
+	ref, ok := shop["featured_ref"].(map[string]interface{})
+	if !ok || ref["item_name"] != "bow" || ref["price"] != float64(80) {
+		t.Errorf("Expected the reference to resolve to bow, got %v", shop)
+	}
+}
+
+func TestMutations(t *testing.T) {
+	s, store := newTestService(t)
+
+	data := run(t, s, `mutation($input: GameItemInput!) { createGameItem(input: $input) { _id item_name } }`,
+		map[string]interface{}{"input": map[string]interface{}{"item_name": "staff", "price": 60}})
+	id := data["createGameItem"].(map[string]interface{})["_id"].(string)
+
+	data = run(t, s, `mutation($id: ID!) { updateGameItem(id: $id, input: {price: 65}) { item_name price } }`, map[string]interface{}{"id": id})
+	updated := data["updateGameItem"].(map[string]interface{})
+	if updated["item_name"] != "staff" || updated["price"] != float64(65) {
+		t.Errorf("Expected a merged update, got %v", updated)
+	}
+
+	data = run(t, s, `query($id: ID!) { gameItem(id: $id) { price } }`, map[string]interface{}{"id": id})
+	if data["gameItem"].(map[string]interface{})["price"] != float64(65) {
+		t.Errorf("Expected the stored update, got %v", data)
+	}
+
+	run(t, s, `mutation($id: ID!) { deleteGameItem(id: $id) }`, map[string]interface{}{"id": id})
+	data = run(t, s, `query($id: ID!) { gameItem(id: $id) { price } }`, map[string]interface{}{"id": id})
+	if data["gameItem"] != nil {
+		t.Errorf("Expected the record to be gone, got %v", data)
+	}
+
+	result, _ := s.Do(context.Background(), Request{Query: `mutation { createGameItem(input: {price: 1}) { _id } }`})
+	if !result.HasErrors() {
+		t.Error("Expected a missing required field to be rejected")
+	}
+	if tbl, _ := store.Tables.FindByID(context.Background(), "game_item"); tbl.RecordCount != 3 {
+		t.Errorf("Expected 3 records left, got %d", tbl.RecordCount)
+	}
+}
+
+func TestSchemaRebuildsWhenTablesChange(t *testing.T) {
+	s, store := newTestService(t)
+	ctx := context.Background()
+
+	first, err := s.Schema(ctx)
+	if err != nil {
+		t.Fatalf("Schema failed: %v", err)
+	}
+	store.Records.Create(ctx, record.NewRecord("shop", map[string]interface{}{"title": "Market"}))
+	if again, _ := s.Schema(ctx); again != first {
+		t.Error("Expected writing records to keep the schema")
+	}
+
+	store.Tables.Delete(ctx, "shop")
+	store.Tables.Create(ctx, table.NewTable("shop", "Shops", "", json.RawMessage(`{"type": "object", "properties": {"owner": {"type": "string"}}}`)))
+	rebuilt, _ := s.Schema(ctx)
+	if rebuilt == first {
+		t.Fatal("Expected a changed schema to rebuild")
+	}
+	run(t, s, `{ shopList { items { owner } } }`, nil)
+	if result, _ := s.Do(ctx, Request{Query: `{ shopList { items { title } } }`}); !result.HasErrors() {
+		t.Error("Expected the removed field to be gone")
+	}
+}
+
+func TestNames(t *testing.T) {
+	for in, want := range map[string]string{"game_item": "GameItem", "2024-events": "T2024Events", "shop": "Shop"} {
+		if got := typeName(in); got != want {
+			t.Errorf("typeName(%q) = %q, expected %q", in, got, want)
+		}
+	}
+	for in, want := range map[string]string{"item name": "item_name", "2nd": "_2nd", "__x": "x", "아이템": "_"} {
+		if got := fieldName(in); got != want {
+			t.Errorf("fieldName(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s, _ := newTestService(t)
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		apierror.HandlerFunc(s.Handler).ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := serve(httptest.NewRequest("POST", "/graphql", strings.NewReader(`{"query": "{ tables { id typeName } }"}`)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"typeName":"GameItem"`) {
+		t.Errorf("Expected the tables, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`{ gameItemList { total } }`), nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"total":3`) {
+		t.Errorf("Expected a query over GET, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`mutation { deleteGameItem(id: 1) }`), nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected mutations over GET to be refused, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serve(httptest.NewRequest("POST", "/graphql", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a missing query to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,420 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"progressive/internal/domain/record"
+	recordrepo "progressive/internal/domain/record/repository"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// JSON is the scalar of array, object and untyped properties: any JSON value
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Any JSON value",
+	Serialize:    func(v interface{}) interface{} { return v },
+	ParseValue:   func(v interface{}) interface{} { return v },
+	ParseLiteral: parseLiteral,
+})
+
+func parseLiteral(v ast.Value) interface{} {
+	switch v := v.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		n, _ := strconv.ParseInt(v.Value, 10, 64)
+		return n
+	case *ast.FloatValue:
+		n, _ := strconv.ParseFloat(v.Value, 64)
+		return n
+	case *ast.ListValue:
+		list := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			list[i] = parseLiteral(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(v.Fields))
+		for _, field := range v.Fields {
+			object[field.Name.Value] = parseLiteral(field.Value)
+		}
+		return object
+	default:
+		return nil
+	}
+}
+
+// Filter operators
+const (
+	opEq       = "EQ"
+	opNe       = "NE"
+	opGt       = "GT"
+	opGte      = "GTE"
+	opLt       = "LT"
+	opLte      = "LTE"
+	opContains = "CONTAINS"
+	opIn       = "IN"
+)
+
+var filterOpEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "FilterOp",
+	Values: graphql.EnumValueConfigMap{
+		opEq:       {Value: opEq},
+		opNe:       {Value: opNe},
+		opGt:       {Value: opGt},
+		opGte:      {Value: opGte},
+		opLt:       {Value: opLt},
+		opLte:      {Value: opLte},
+		opContains: {Value: opContains, Description: "Substring of a string, ignoring case, or element of an array"},
+		opIn:       {Value: opIn, Description: "Equal to one of the values of a list"},
+	},
+})
+
+var sortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "SortDirection",
+	Values: graphql.EnumValueConfigMap{
+		"ASC":  {Value: "ASC"},
+		"DESC": {Value: "DESC"},
+	},
+})
+
+type loaderKey struct{}
+
+// loader caches the records read during one request, so that references
+// and list queries read each table once instead of once per record
+type loader struct {
+	records recordrepo.RecordRepository
+
+	mu      sync.Mutex
+	tables  map[string][]*record.Record
+	indexes map[string]map[string]*record.Record
+}
+
+func withLoader(ctx context.Context, records recordrepo.RecordRepository) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &loader{
+		records: records,
+		tables:  make(map[string][]*record.Record),
+		indexes: make(map[string]map[string]*record.Record),
+	})
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+// all returns every record of a table, newest first. The slice is a copy
+// the caller may reorder.
+func (l *loader) all(ctx context.Context, tableID string) ([]*record.Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	recs, err := l.load(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*record.Record(nil), recs...), nil
+}
+
+func (l *loader) load(ctx context.Context, tableID string) ([]*record.Record, error) {
+	if recs, ok := l.tables[tableID]; ok {
+		return recs, nil
+	}
+	recs, err := l.records.FindByTable(ctx, tableID, record.Page{})
+	if err != nil {
+		return nil, err
+	}
+	l.tables[tableID] = recs
+	return recs, nil
+}
+
+// record returns the first record of a table whose field equals value, as
+// a resolver result: nil, rather than a nil *record.Record, when there is none
+func (l *loader) record(ctx context.Context, tableID, field string, value interface{}) (interface{}, error) {
+	rec, err := l.find(ctx, tableID, field, value)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// find returns the first record of a table whose field equals value, or nil
+func (l *loader) find(ctx context.Context, tableID, field string, value interface{}) (*record.Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := tableID + "\x00" + field
+	index, ok := l.indexes[key]
+	if !ok {
+		recs, err := l.load(ctx, tableID)
+		if err != nil {
+			return nil, err
+		}
+		index = make(map[string]*record.Record, len(recs))
+		for _, rec := range recs {
+			if v := valueOf(rec, field); v != nil {
+				if _, seen := index[keyString(v)]; !seen {
+					index[keyString(v)] = rec
+				}
+			}
+		}
+		l.indexes[key] = index
+	}
+	return index[keyString(value)], nil
+}
+
+// invalidate drops what was read of a table after a mutation wrote to it
+func (l *loader) invalidate(tableID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tables, tableID)
+	for key := range l.indexes {
+		if strings.HasPrefix(key, tableID+"\x00") {
+			delete(l.indexes, key)
+		}
+	}
+}
+
+// condition is one entry of a list query's filter argument
+type condition struct {
+	field string
+	op    string
+	value interface{}
+}
+
+func parseConditions(arg interface{}) ([]condition, error) {
+	list, _ := arg.([]interface{})
+	conditions := make([]condition, 0, len(list))
+	for _, item := range list {
+		fields, _ := item.(map[string]interface{})
+		c := condition{value: fields["value"]}
+		c.field, _ = fields["field"].(string)
+		c.op, _ = fields["op"].(string)
+		if c.op == "" {
+			c.op = opEq
+		}
+		if _, isList := c.value.([]interface{}); c.op == opIn && !isList {
+			return nil, errors.New("the value of an IN filter must be a list")
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// filterRecords keeps the records matching every condition
+func filterRecords(recs []*record.Record, conditions []condition) []*record.Record {
+	kept := recs[:0]
+	for _, rec := range recs {
+		ok := true
+		for _, c := range conditions {
+			if !c.matches(valueOf(rec, c.field)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, rec)
+		}
+	}
+	return kept
+}
+
+func (c condition) matches(v interface{}) bool {
+	switch c.op {
+	case opEq:
+		return equal(v, c.value)
+	case opNe:
+		return !equal(v, c.value)
+	case opContains:
+		if list, ok := v.([]interface{}); ok {
+			for _, item := range list {
+				if equal(item, c.value) {
+					return true
+				}
+			}
+			return false
+		}
+		s, ok := v.(string)
+		sub, subOK := c.value.(string)
+		return ok && subOK && strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+	case opIn:
+		for _, item := range c.value.([]interface{}) {
+			if equal(v, item) {
+				return true
+			}
+		}
+		return false
+	}
+
+	cmp, ok := compare(v, c.value)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case opGt:
+		return cmp > 0
+	case opGte:
+		return cmp >= 0
+	case opLt:
+		return cmp < 0
+	case opLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+// sortKey is one entry of a list query's sort argument
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+func parseSorts(arg interface{}) []sortKey {
+	list, _ := arg.([]interface{})
+	keys := make([]sortKey, 0, len(list))
+	for _, item := range list {
+		fields, _ := item.(map[string]interface{})
+		field, _ := fields["field"].(string)
+		keys = append(keys, sortKey{field: field, desc: fields["direction"] == "DESC"})
+	}
+	return keys
+}
+
+// sortRecords orders records by the keys; missing values sort last in
+// either direction, and ties keep the newest-first order
+func sortRecords(recs []*record.Record, keys []sortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(recs, func(i, j int) bool {
+		for _, key := range keys {
+			a, b := valueOf(recs[i], key.field), valueOf(recs[j], key.field)
+			if a == nil || b == nil {
+				if (a == nil) != (b == nil) {
+					return b == nil
+				}
+				continue
+			}
+			cmp, ok := compare(a, b)
+			if !ok || cmp == 0 {
+				continue
+			}
+			if key.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// valueOf returns a property of a record, or one of its system fields
+func valueOf(rec *record.Record, field string) interface{} {
+	switch field {
+	case fieldID:
+		return rec.ID
+	case fieldCreatedAt:
+		return rec.CreatedAt
+	case fieldUpdatedAt:
+		return rec.UpdatedAt
+	default:
+		return rec.Data[field]
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	cmp, ok := compare(a, b)
+	if ok {
+		return cmp == 0
+	}
+	return keyString(a) == keyString(b)
+}
+
+// compare orders two numbers, strings, booleans or times; a time compares
+// with an RFC 3339 string
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case y:
+				return -1, true
+			}
+			return 1, true
+		}
+	case time.Time:
+		y, ok := b.(time.Time)
+		if s, isString := b.(string); isString {
+			t, err := time.Parse(time.RFC3339, s)
+			y, ok = t, err == nil
+		}
+		if ok {
+			return x.Compare(y), true
+		}
+	}
+	return 0, false
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// keyString normalises a JSON value so that references compare by value
+func keyString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,471 @@
+package gql
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/domain/table"
+
+	"github.com/graphql-go/graphql"
+)
+
+// System fields every record type has, named as in the REST API
+const (
+	fieldID        = "_id"
+	fieldCreatedAt = "_created_at"
+	fieldUpdatedAt = "_updated_at"
+)
+
+// tableType is the GraphQL side of one table
+type tableType struct {
+	table  *table.Table
+	schema *repository.SchemaDefinition
+	// name is the object type name, e.g. "GameItem"; query is the
+	// lower-camel name the queries and mutations derive from, e.g. "gameItem"
+	name  string
+	query string
+	// properties maps GraphQL field names to schema property names
+	properties map[string]string
+	object     *graphql.Object
+}
+
+// buildSchema creates the schema of the given tables. Tables whose schema is
+// not an object schema are left out.
+func buildSchema(tables []*table.Table) (*graphql.Schema, error) {
+	// Built-in and shared type names
+	names := map[string]bool{"Query": true, "Mutation": true, "Table": true, "JSON": true, "FilterOp": true, "SortDirection": true,
+		"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true, "DateTime": true}
+	queries := map[string]bool{"tables": true}
+	var types []*tableType
+	byKey := make(map[string]*tableType)
+
+	for _, t := range tables {
+		def, err := repository.ParseSchemaDefinition(t.Schema)
+		if err != nil {
+			slog.Warn("table left out of the GraphQL schema", slog.String("table", t.ID), slog.Any("error", err))
+			continue
+		}
+		tt := &tableType{table: t, schema: def, properties: make(map[string]string)}
+		// The names derived from these must not collide with another table's
+		tt.name = reserve(names, typeName(t.ID), "Page", "Input", "Patch", "Field", "Filter", "Sort")
+		tt.query = reserve(queries, strings.ToLower(tt.name[:1])+tt.name[1:], "List")
+
+		taken := map[string]bool{fieldID: true, fieldCreatedAt: true, fieldUpdatedAt: true}
+		for _, prop := range def.PropertyNames() {
+			tt.properties[reserve(taken, fieldName(prop))] = prop
+		}
+		types = append(types, tt)
+		byKey[t.ID] = tt
+	}
+	// References may name the table instead of its ID
+	for _, tt := range types {
+		if _, taken := byKey[tt.table.Name]; !taken {
+			byKey[tt.table.Name] = tt
+		}
+	}
+
+	for _, tt := range types {
+		tt.object = newObjectType(tt, byKey)
+	}
+
+	query := graphql.Fields{"tables": tablesField(types)}
+	mutation := graphql.Fields{}
+	for _, tt := range types {
+		addQueries(query, tt)
+		addMutations(mutation, tt)
+	}
+
+	config := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: query}),
+	}
+	if len(mutation) > 0 {
+		config.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutation})
+	}
+	schema, err := graphql.NewSchema(config)
+	if err != nil {
+		return nil, fmt.Errorf("build GraphQL schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// newObjectType declares the record type of a table. A property with an
+// "x-ref" to a known table also gets a "<field>_ref" field resolving to the
+// referenced record.
+func newObjectType(tt *tableType, byKey map[string]*tableType) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        tt.name,
+		Description: describe(tt.table.Name, tt.table.Description),
+		// A thunk, since references may point at types declared later
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := graphql.Fields{
+				fieldID: {Type: graphql.NewNonNull(graphql.ID), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*record.Record).ID, nil
+				}},
+				fieldCreatedAt: {Type: graphql.NewNonNull(graphql.DateTime), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*record.Record).CreatedAt, nil
+				}},
+				fieldUpdatedAt: {Type: graphql.NewNonNull(graphql.DateTime), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*record.Record).UpdatedAt, nil
+				}},
+			}
+			for name, prop := range tt.properties {
+				def := tt.schema.Properties[prop]
+				fields[name] = &graphql.Field{
+					Type:        scalarType(def.Type),
+					Description: describe(def.Title, def.Description),
+					Resolve:     propertyResolver(prop),
+				}
+
+				if def.Ref == "" {
+					continue
+				}
+				target, field := resolveRef(byKey, def.Ref)
+				refName := name + "_ref"
+				if target == nil || fields[refName] != nil || tt.properties[refName] != "" {
+					continue
+				}
+				fields[refName] = &graphql.Field{
+					Type:        target.object,
+					Description: fmt.Sprintf("The %s record %s refers to", target.table.Name, prop),
+					Resolve:     referenceResolver(prop, target.table.ID, field),
+				}
+			}
+			return fields
+		}),
+	})
+}
+
+func tablesField(types []*tableType) *graphql.Field {
+	tableObject := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Table",
+		Description: "A table and the GraphQL type of its records",
+		Fields: graphql.Fields{
+			"id":          {Type: graphql.NewNonNull(graphql.String)},
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"recordCount": {Type: graphql.NewNonNull(graphql.Int)},
+			"typeName":    {Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+	list := make([]map[string]interface{}, 0, len(types))
+	for _, tt := range types {
+		list = append(list, map[string]interface{}{
+			"id":          tt.table.ID,
+			"name":        tt.table.Name,
+			"description": tt.table.Description,
+			"recordCount": tt.table.RecordCount,
+			"typeName":    tt.name,
+		})
+	}
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tableObject))),
+		Description: "The tables exposed in this schema",
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return list, nil
+		},
+	}
+}
+
+// addQueries adds "<table>(id)" and "<table>List(filter, sort, limit, offset)"
+func addQueries(query graphql.Fields, tt *tableType) {
+	tableID := tt.table.ID
+	query[tt.query] = &graphql.Field{
+		Type:        tt.object,
+		Description: fmt.Sprintf("A %s record by ID, or null", tt.table.Name),
+		Args:        graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := recordID(p.Args["id"])
+			if err != nil {
+				return nil, err
+			}
+			return loaderFrom(p.Context).record(p.Context, tableID, fieldID, id)
+		},
+	}
+
+	fieldEnum := newFieldEnum(tt)
+	page := graphql.NewObject(graphql.ObjectConfig{
+		Name: tt.name + "Page",
+		Fields: graphql.Fields{
+			"items": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(tt.object)))},
+			"total": {Type: graphql.NewNonNull(graphql.Int), Description: "Records matching the filter"},
+		},
+	})
+	filter := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: tt.name + "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field": {Type: graphql.NewNonNull(fieldEnum)},
+			"op":    {Type: filterOpEnum, DefaultValue: opEq},
+			"value": {Type: JSON, Description: "A list for IN"},
+		},
+	})
+	sortInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: tt.name + "Sort",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"field":     {Type: graphql.NewNonNull(fieldEnum)},
+			"direction": {Type: sortDirectionEnum, DefaultValue: "ASC"},
+		},
+	})
+
+	query[tt.query+"List"] = &graphql.Field{
+		Type:        graphql.NewNonNull(page),
+		Description: fmt.Sprintf("%s records; all filters must match, sorts apply in order", tt.table.Name),
+		Args: graphql.FieldConfigArgument{
+			"filter": {Type: graphql.NewList(graphql.NewNonNull(filter))},
+			"sort":   {Type: graphql.NewList(graphql.NewNonNull(sortInput))},
+			"limit":  {Type: graphql.Int, DefaultValue: 20},
+			"offset": {Type: graphql.Int, DefaultValue: 0},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			recs, err := loaderFrom(p.Context).all(p.Context, tableID)
+			if err != nil {
+				return nil, err
+			}
+			conditions, err := parseConditions(p.Args["filter"])
+			if err != nil {
+				return nil, err
+			}
+			recs = filterRecords(recs, conditions)
+			sortRecords(recs, parseSorts(p.Args["sort"]))
+
+			total := len(recs)
+			limit, _ := p.Args["limit"].(int)
+			offset, _ := p.Args["offset"].(int)
+			if limit < 0 || offset < 0 {
+				return nil, errors.New("limit and offset must not be negative")
+			}
+			recs = recs[min(offset, total):min(offset+limit, total)]
+			return map[string]interface{}{"items": recs, "total": total}, nil
+		},
+	}
+}
+
+// addMutations adds create, update and delete mutations of a table
+func addMutations(mutation graphql.Fields, tt *tableType) {
+	tableID := tt.table.ID
+	input := graphql.InputObjectConfigFieldMap{}
+	patch := graphql.InputObjectConfigFieldMap{}
+	for name, prop := range tt.properties {
+		def := tt.schema.Properties[prop]
+		var typ graphql.Input = scalarType(def.Type)
+		patch[name] = &graphql.InputObjectFieldConfig{Type: typ, Description: describe(def.Title, def.Description)}
+		if tt.schema.IsRequired(prop) {
+			typ = graphql.NewNonNull(typ)
+		}
+		input[name] = &graphql.InputObjectFieldConfig{Type: typ, Description: describe(def.Title, def.Description)}
+	}
+	inputType := graphql.NewInputObject(graphql.InputObjectConfig{Name: tt.name + "Input", Fields: input})
+	patchType := graphql.NewInputObject(graphql.InputObjectConfig{Name: tt.name + "Patch", Fields: patch})
+	upper := strings.ToUpper(tt.query[:1]) + tt.query[1:]
+
+	mutation["create"+upper] = &graphql.Field{
+		Type: graphql.NewNonNull(tt.object),
+		Args: graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(inputType)}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			rec := record.NewRecord(tableID, tt.data(p.Args["input"]))
+			l := loaderFrom(p.Context)
+			defer l.invalidate(tableID)
+			if err := l.records.Create(p.Context, rec); err != nil {
+				return nil, err
+			}
+			return rec, nil
+		},
+	}
+	mutation["update"+upper] = &graphql.Field{
+		Type:        graphql.NewNonNull(tt.object),
+		Description: "Fields in input replace those of the record; the others are kept",
+		Args: graphql.FieldConfigArgument{
+			"id":    {Type: graphql.NewNonNull(graphql.ID)},
+			"input": {Type: graphql.NewNonNull(patchType)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := recordID(p.Args["id"])
+			if err != nil {
+				return nil, err
+			}
+			l := loaderFrom(p.Context)
+			defer l.invalidate(tableID)
+			rec, err := l.records.FindByID(p.Context, tableID, id)
+			if err != nil {
+				return nil, err
+			}
+			rec.Merge(tt.data(p.Args["input"]))
+			if err := l.records.Update(p.Context, rec); err != nil {
+				return nil, err
+			}
+			return rec, nil
+		},
+	}
+	mutation["delete"+upper] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Boolean),
+		Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := recordID(p.Args["id"])
+			if err != nil {
+				return nil, err
+			}
+			l := loaderFrom(p.Context)
+			defer l.invalidate(tableID)
+			if err := l.records.Delete(p.Context, tableID, id); err != nil {
+				return nil, err
+			}
+			return true, nil
+		},
+	}
+}
+
+// data maps an input object's GraphQL field names back to property names
+func (tt *tableType) data(input interface{}) map[string]interface{} {
+	fields, _ := input.(map[string]interface{})
+	data := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		data[tt.properties[name]] = value
+	}
+	return data
+}
+
+// newFieldEnum lists the fields a table's records can be filtered and sorted by
+func newFieldEnum(tt *tableType) *graphql.Enum {
+	values := graphql.EnumValueConfigMap{
+		fieldID:        {Value: fieldID},
+		fieldCreatedAt: {Value: fieldCreatedAt},
+		fieldUpdatedAt: {Value: fieldUpdatedAt},
+	}
+	for name, prop := range tt.properties {
+		// Enum values cannot be named like the literals
+		if name != "true" && name != "false" && name != "null" {
+			values[name] = &graphql.EnumValueConfig{Value: prop}
+		}
+	}
+	return graphql.NewEnum(graphql.EnumConfig{Name: tt.name + "Field", Values: values})
+}
+
+func propertyResolver(prop string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return p.Source.(*record.Record).Data[prop], nil
+	}
+}
+
+// referenceResolver finds the record of tableID whose field equals the
+// property value; a dangling reference resolves to null
+func referenceResolver(prop, tableID, field string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value := p.Source.(*record.Record).Data[prop]
+		if value == nil {
+			return nil, nil
+		}
+		return loaderFrom(p.Context).record(p.Context, tableID, field, value)
+	}
+}
+
+// resolveRef resolves "table" or "table.field" against table IDs and names,
+// as publishing does
+func resolveRef(byKey map[string]*tableType, ref string) (*tableType, string) {
+	if tt, ok := byKey[ref]; ok {
+		return tt, fieldID
+	}
+	if i := strings.LastIndex(ref, "."); i > 0 {
+		if tt, ok := byKey[ref[:i]]; ok {
+			return tt, ref[i+1:]
+		}
+	}
+	return nil, ""
+}
+
+// scalarType maps a JSON Schema type to a GraphQL scalar; arrays, objects
+// and untyped properties are JSON
+func scalarType(schemaType string) *graphql.Scalar {
+	switch schemaType {
+	case "string":
+		return graphql.String
+	case "integer":
+		return graphql.Int
+	case "number":
+		return graphql.Float
+	case "boolean":
+		return graphql.Boolean
+	default:
+		return JSON
+	}
+}
+
+func recordID(v interface{}) (int64, error) {
+	id, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid record ID %v", v)
+	}
+	return id, nil
+}
+
+// typeName turns a table ID such as "game_item" into "GameItem"
+func typeName(id string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(id, func(r rune) bool { return !isNameRune(r) || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "T" + name
+	}
+	return name
+}
+
+// fieldName makes a property name a valid GraphQL name, which is ASCII
+// letters, digits and underscores not starting with a digit or "__"
+func fieldName(prop string) string {
+	name := []rune(prop)
+	for i, r := range name {
+		if !isNameRune(r) {
+			name[i] = '_'
+		}
+	}
+	s := strings.TrimLeft(string(name), "_")
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "_" + s
+	}
+	return s
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// reserve returns name, or name with a numeric suffix, such that neither it
+// nor it with any of the suffixes is taken, and marks them all as taken
+func reserve(taken map[string]bool, name string, suffixes ...string) string {
+	for i := 1; ; i++ {
+		candidate := name
+		if i > 1 {
+			candidate += strconv.Itoa(i)
+		}
+		free := !taken[candidate]
+		for _, suffix := range suffixes {
+			free = free && !taken[candidate+suffix]
+		}
+		if !free {
+			continue
+		}
+		taken[candidate] = true
+		for _, suffix := range suffixes {
+			taken[candidate+suffix] = true
+		}
+		return candidate
+	}
+}
+
+// describe combines a title and a description
+func describe(title, description string) string {
+	switch {
+	case title == "":
+		return description
+	case description == "":
+		return title
+	default:
+		return title + ": " + description
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"progressive/internal/pages"
+)
+
+// APIDocsPageHandler renders the API explorer for the API document or, with
+// ?table=, for the document of one table
+func (h *Handlers) APIDocsPageHandler(w http.ResponseWriter, r *http.Request) error {
+	tables, err := h.tableRepo.FindAll(r.Context())
+	if err != nil {
+		return tableError(err)
+	}
+	return pages.APIExplorer(tables, r.URL.Query().Get("table")).Render(r.Context(), w)
+}
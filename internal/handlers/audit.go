@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/audit"
+	"progressive/internal/pages"
+)
+
+// AuditAPIHandler lists audit entries, newest first.
+//
+//	GET /api/v1/audit?actor=&action=&target=&request_id=&from=&to=&before=&limit=
+//
+// action without a dot matches a whole group ("table" → table.*); from/to
+// accept RFC 3339 timestamps or dates; before pages by entry ID.
+func (h *Handlers) AuditAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		return apierror.BadRequest("invalid_filter", err.Error())
+	}
+	entries, err := h.auditRepo.Find(r.Context(), filter)
+	if err != nil {
+		return apierror.Internal(fmt.Errorf("load audit log: %w", err))
+	}
+
+	response := map[string]interface{}{"entries": entries}
+	if n := len(entries); n > 0 && n == auditLimit(filter) {
+		response["next_before"] = entries[n-1].ID
+	}
+	writeJSON(w, http.StatusOK, response)
+	return nil
+}
+
+// AuditVerifyAPIHandler recomputes the audit hash chain
+func (h *Handlers) AuditVerifyAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	result, err := h.auditRepo.Verify(r.Context())
+	if err != nil {
+		return apierror.Internal(fmt.Errorf("verify audit log: %w", err))
+	}
+	writeJSON(w, http.StatusOK, result)
+	return nil
+}
+
+// AuditPageHandler renders the audit log viewer
+func (h *Handlers) AuditPageHandler(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	filter, err := parseAuditFilter(query)
+	if err != nil {
+		return apierror.BadRequest("invalid_filter", err.Error())
+	}
+	entries, err := h.auditRepo.Find(r.Context(), filter)
+	if err != nil {
+		return apierror.Internal(fmt.Errorf("load audit log: %w", err))
+	}
+
+	var nextPage string
+	if n := len(entries); n > 0 && n == auditLimit(filter) {
+		next := url.Values{}
+		for k, v := range query {
+			next[k] = v
+		}
+		next.Set("before", strconv.FormatInt(entries[n-1].ID, 10))
+		nextPage = "/audit?" + next.Encode()
+	}
+
+	component := pages.AuditLog(entries, pages.AuditLogFilter{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Target:    query.Get("target"),
+		RequestID: query.Get("request_id"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+	}, nextPage)
+	return component.Render(r.Context(), w)
+}
+
+func parseAuditFilter(query url.Values) (audit.Filter, error) {
+	filter := audit.Filter{
+		Actor:     query.Get("actor"),
+		Action:    query.Get("action"),
+		Target:    query.Get("target"),
+		RequestID: query.Get("request_id"),
+	}
+
+	var err error
+	if filter.From, err = parseAuditTime(query.Get("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseAuditTime(query.Get("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if v := query.Get("before"); v != "" {
+		if filter.BeforeID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return filter, fmt.Errorf("invalid before: %w", err)
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit <= 0 || filter.Limit > 500 {
+			return filter, fmt.Errorf("invalid limit: must be between 1 and 500")
+		}
+	}
+	return filter, nil
+}
+
+// parseAuditTime accepts RFC 3339 or a date; a date used as the upper bound
+// includes the whole day
+func parseAuditTime(v string, end bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func auditLimit(filter audit.Filter) int {
+	if filter.Limit <= 0 {
+		return 100
+	}
+	return filter.Limit
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/branch"
+	"progressive/internal/pages"
+	"progressive/internal/router"
+)
+
+// CreateBranchRequest represents a request to branch a table
+type CreateBranchRequest struct {
+	TableID     string `json:"table_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"created_by"`
+}
+
+// CreateMergeRequestRequest represents a request to open a merge request
+type CreateMergeRequestRequest struct {
+	BranchID    string `json:"branch_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatedBy   string `json:"created_by"`
+}
+
+// MergeRequestMergeRequest carries the merger and conflict resolutions
+type MergeRequestMergeRequest struct {
+	MergedBy    string              `json:"merged_by"`
+	Resolutions []branch.Resolution `json:"resolutions"`
+}
+
+// ListBranchesAPIHandler lists branches, optionally of one table (?table_id=)
+func (h *Handlers) ListBranchesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	branches, err := h.branchRepo.FindBranches(r.Context(), r.URL.Query().Get("table_id"))
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, branches)
+	return nil
+}
+
+// CreateBranchAPIHandler branches a table
+func (h *Handlers) CreateBranchAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req CreateBranchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	b, err := branch.NewBranch(req.TableID, req.Name, req.Description, req.CreatedBy)
+	if err != nil {
+		return branchError(err)
+	}
+	if err := h.branchRepo.CreateBranch(r.Context(), b); err != nil {
+		return branchError(err)
+	}
+	slog.InfoContext(r.Context(), "branch created", slog.String("branch", b.Name), slog.String("table", b.TableID))
+	writeJSON(w, http.StatusCreated, b)
+	return nil
+}
+
+// BranchAPIHandler returns the branch {id} with its record view
+func (h *Handlers) BranchAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	b, err := h.branchRepo.FindBranchByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return branchError(err)
+	}
+	records, err := h.branchRepo.FindRecords(r.Context(), b)
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"branch":  b,
+		"records": records,
+	})
+	return nil
+}
+
+// CloseBranchAPIHandler closes the branch {id}
+func (h *Handlers) CloseBranchAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := h.branchRepo.CloseBranch(r.Context(), r.PathValue("id")); err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	return nil
+}
+
+// BranchChangesAPIHandler returns the overlay entries of the branch {id}
+func (h *Handlers) BranchChangesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	changes, err := h.branchRepo.FindChanges(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, changes)
+	return nil
+}
+
+// CreateBranchRecordAPIHandler adds a record on the branch {id}
+func (h *Handlers) CreateBranchRecordAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	change, err := h.branchRepo.CreateRecord(r.Context(), r.PathValue("id"), data)
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"success": true, "ref": change.Ref().String()})
+	return nil
+}
+
+// UpdateBranchRecordAPIHandler updates the record {ref} on the branch {id}
+func (h *Handlers) UpdateBranchRecordAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	ref, err := branch.ParseRef(r.PathValue("ref"))
+	if err != nil {
+		return branchError(err)
+	}
+	var patch map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	if _, err := h.branchRepo.UpdateRecord(r.Context(), r.PathValue("id"), ref, patch); err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	return nil
+}
+
+// DeleteBranchRecordAPIHandler deletes the record {ref} on the branch {id}
+func (h *Handlers) DeleteBranchRecordAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	ref, err := branch.ParseRef(r.PathValue("ref"))
+	if err != nil {
+		return branchError(err)
+	}
+	if err := h.branchRepo.DeleteRecord(r.Context(), r.PathValue("id"), ref); err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	return nil
+}
+
+// ListMergeRequestsAPIHandler lists merge requests, optionally by status (?status=)
+func (h *Handlers) ListMergeRequestsAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	requests, err := h.branchRepo.FindMergeRequests(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, requests)
+	return nil
+}
+
+// CreateMergeRequestAPIHandler opens a merge request for a branch
+func (h *Handlers) CreateMergeRequestAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req CreateMergeRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	b, err := h.branchRepo.FindBranchByID(r.Context(), req.BranchID)
+	if err != nil {
+		return branchError(err)
+	}
+	mr, err := branch.NewMergeRequest(b, req.Title, req.Description, req.CreatedBy)
+	if err != nil {
+		return branchError(err)
+	}
+	if err := h.branchRepo.CreateMergeRequest(r.Context(), mr); err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusCreated, mr)
+	return nil
+}
+
+// MergeRequestAPIHandler returns the merge request {id} with its branch and
+// merge preview
+func (h *Handlers) MergeRequestAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := router.PathInt64(r, "id")
+	if err != nil {
+		return err
+	}
+	view, err := h.mergeRequestView(r, id)
+	if err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, view)
+	return nil
+}
+
+// MergeAPIHandler merges the merge request {id} with optional conflict resolutions
+func (h *Handlers) MergeAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := router.PathInt64(r, "id")
+	if err != nil {
+		return err
+	}
+	var req MergeRequestMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	plan, err := h.branchRepo.Merge(r.Context(), id, req.MergedBy, req.Resolutions)
+	if err != nil {
+		return branchError(err)
+	}
+	slog.InfoContext(r.Context(), "merge request merged", slog.Int64("merge_request", id), slog.String("merged_by", req.MergedBy),
+		slog.Int("updated", len(plan.Updates)), slog.Int("added", len(plan.Inserts)), slog.Int("deleted", len(plan.Deletes)))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"updated": len(plan.Updates),
+		"added":   len(plan.Inserts),
+		"deleted": len(plan.Deletes),
+	})
+	return nil
+}
+
+// CloseMergeRequestAPIHandler closes the merge request {id} without merging
+func (h *Handlers) CloseMergeRequestAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := router.PathInt64(r, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.branchRepo.CloseMergeRequest(r.Context(), id); err != nil {
+		return branchError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+	return nil
+}
+
+// MergeRequestPageHandler renders the merge request review page
+func (h *Handlers) MergeRequestPageHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return apierror.NotFound("merge_request_not_found", "Merge request not found")
+	}
+
+	view, err := h.mergeRequestView(r, id)
+	if err != nil {
+		return branchError(err)
+	}
+
+	component := pages.MergeRequest(view.MergeRequest, view.Branch, view.Plan)
+	return component.Render(r.Context(), w)
+}
+
+type mergeRequestView struct {
+	MergeRequest *branch.MergeRequest `json:"merge_request"`
+	Branch       *branch.Branch       `json:"branch"`
+	Plan         *branch.MergePlan    `json:"plan"`
+	Mergeable    bool                 `json:"mergeable"`
+}
+
+func (h *Handlers) mergeRequestView(r *http.Request, id int64) (*mergeRequestView, error) {
+	mr, err := h.branchRepo.FindMergeRequestByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	b, err := h.branchRepo.FindBranchByID(r.Context(), mr.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &mergeRequestView{MergeRequest: mr, Branch: b}
+	if mr.Status == branch.StatusOpen {
+		if view.Plan, err = h.branchRepo.PlanMerge(r.Context(), mr, nil); err != nil {
+			return nil, err
+		}
+		view.Mergeable = view.Plan.Mergeable()
+	}
+	return view, nil
+}
+
+// branchError maps branch domain errors to API errors
+func branchError(err error) error {
+	var conflictErr *branch.ConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		return apierror.New(http.StatusConflict, "merge_conflict", conflictErr.Error()).
+			With("conflicts", conflictErr.Conflicts)
+	case errors.Is(err, branch.ErrNotFound):
+		return apierror.NotFound(apierror.CodeNotFound, err.Error())
+	case errors.Is(err, branch.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", err.Error())
+	case errors.Is(err, branch.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	case errors.Is(err, branch.ErrNameTaken):
+		return apierror.New(http.StatusConflict, "branch_name_taken", err.Error())
+	case errors.Is(err, branch.ErrNotOpen), errors.Is(err, branch.ErrAlreadyOpen):
+		return apierror.New(http.StatusConflict, apierror.CodeConflict, err.Error())
+	default:
+		return apierror.Internal(fmt.Errorf("branch operation: %w", err))
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+func TestFakeitGenerateIsReproducibleWithSeed(t *testing.T) {
+	h := NewHandlers(storagetest.Open(t, storage.Memory))
+	generate := func(seed int64) (data []interface{}, usedSeed int64) {
+		t.Helper()
+		payload, _ := json.Marshal(FakeitGenerateRequest{
+			Schema:       map[string]interface{}{"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}, "level": map[string]interface{}{"type": "integer"}}},
+			FieldConfigs: map[string]FakeFieldConfig{"name": {Type: "name"}},
+			Count:        5,
+			Seed:         seed,
+		})
+		rec := serve(t, h.FakeitGenerateAPIHandler, "POST /fakeit/generate", "/fakeit/generate", string(payload))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var body struct {
+			Data []interface{} `json:"data"`
+			Seed int64         `json:"seed"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return body.Data, body.Seed
+	}
+
+	first, seed := generate(42)
+	second, _ := generate(42)
+	if seed != 42 {
+		t.Errorf("Expected the given seed back, got %d", seed)
+	}
+	if a, b := mustJSON(t, first), mustJSON(t, second); a != b {
+		t.Errorf("Expected identical output for the same seed:\n%s\n%s", a, b)
+	}
+	if other, _ := generate(43); mustJSON(t, other) == mustJSON(t, first) {
+		t.Error("Expected different output for another seed")
+	}
+
+	random, randomSeed := generate(0)
+	if randomSeed == 0 {
+		t.Fatal("Expected the random seed to be returned")
+	}
+	if replay, _ := generate(randomSeed); mustJSON(t, replay) != mustJSON(t, random) {
+		t.Error("Expected the returned seed to reproduce the output")
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	return string(data)
+}
@@ -3,12 +3,21 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
-	"strings"
 
+	"progressive/internal/apierror"
+	auditrepo "progressive/internal/domain/audit/repository"
+	branchrepo "progressive/internal/domain/branch/repository"
+	"progressive/internal/domain/record"
+	recordrepo "progressive/internal/domain/record/repository"
 	"progressive/internal/domain/schematemplate/repository"
+	snapshotrepo "progressive/internal/domain/snapshot/repository"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
 	"progressive/internal/pages"
+	"progressive/internal/storage"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -17,25 +26,41 @@ import (
 type Handlers struct {
 	db           *sqlx.DB
 	templateRepo repository.SchemaTemplateRepository
+	snapshotRepo snapshotrepo.SnapshotRepository
+	branchRepo   branchrepo.BranchRepository
+	auditRepo    auditrepo.AuditRepository
+	tableRepo    tablerepo.TableRepository
+	recordRepo   recordrepo.RecordRepository
 	Table        *TableHandlers
 }
 
-// NewHandlers creates a new Handlers instance with database
-func NewHandlers(db *sqlx.DB) *Handlers {
-	// Initialize repository with default templates
-	templateRepo, err := repository.NewPostgresRepositoryWithDefaults(context.Background(), db)
+// NewHandlers creates a new Handlers instance on the given storage backend
+func NewHandlers(store *storage.Store) *Handlers {
+	db := store.DB
+
+	// Add new built-in templates and upgrade the ones nobody edited
+	report, err := repository.SyncDefaults(context.Background(), store.Templates)
 	if err != nil {
-		log.Printf("Failed to initialize template repository with defaults: %v", err)
-		// Fallback to basic repository without defaults
-		templateRepo = repository.NewPostgresRepository(db)
+		slog.Error("failed to initialize default templates", slog.Any("error", err))
 	} else {
-		log.Println("✅ Default templates initialized successfully")
+		slog.Info("default templates initialized", slog.Int("added", len(report.Created)), slog.Int("upgraded", len(report.Upgraded)))
+		if len(report.Kept) > 0 {
+			slog.Warn("kept edited templates without upgrading", slog.Any("templates", report.Kept))
+		}
+		if len(report.Shadowed) > 0 {
+			slog.Warn("built-in templates hidden by custom templates with the same ID", slog.Any("templates", report.Shadowed))
+		}
 	}
 
 	return &Handlers{
 		db:           db,
-		templateRepo: templateRepo,
-		Table:        NewTableHandlers(db),
+		templateRepo: store.Templates,
+		snapshotRepo: snapshotrepo.NewPostgresRepository(db),
+		branchRepo:   branchrepo.NewPostgresRepository(db),
+		auditRepo:    auditrepo.NewPostgresRepository(db),
+		tableRepo:    store.Tables,
+		recordRepo:   store.Records,
+		Table:        NewTableHandlers(store),
 	}
 }
 
@@ -49,78 +74,21 @@ func (h *Handlers) DashboardHandler(w http.ResponseWriter, r *http.Request) {
 	component.Render(r.Context(), w)
 }
 
-// TemplatesAPIHandler returns all templates as JSON
-func (h *Handlers) TemplatesAPIHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	templates, err := h.templateRepo.FindAll(r.Context())
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(templates)
-}
-
-// TablesAPIHandler handles table CRUD operations
-func (h *Handlers) TablesAPIHandler(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case "GET":
-		h.getTablesHandler(w, r)
-	case "POST":
-		h.createTableHandler(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
-
-func (h *Handlers) getTablesHandler(w http.ResponseWriter, r *http.Request) {
-	query := `
-		SELECT id, name, description, schema, record_count, created_at, updated_at 
-		FROM tables 
-		ORDER BY updated_at DESC
-	`
-
-	var tables []map[string]interface{}
-	rows, err := h.db.Query(query)
+// ListTablesAPIHandler returns all tables
+func (h *Handlers) ListTablesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	tables, err := h.tableRepo.FindAll(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		table := make(map[string]interface{})
-		var schemaJSON json.RawMessage
-		var id, name, description string
-		var recordCount int
-		var createdAt, updatedAt interface{}
-
-		err := rows.Scan(&id, &name, &description, &schemaJSON, &recordCount, &createdAt, &updatedAt)
-		if err != nil {
-			continue
-		}
-
-		table["id"] = id
-		table["name"] = name
-		table["description"] = description
-		table["schema"] = schemaJSON
-		table["record_count"] = recordCount
-		table["created_at"] = createdAt
-		table["updated_at"] = updatedAt
-
-		tables = append(tables, table)
+		return tableError(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tables)
+	return nil
 }
 
-func (h *Handlers) createTableHandler(w http.ResponseWriter, r *http.Request) {
+// CreateTableAPIHandler creates a table with a caller-chosen ID, taken from
+// the {id} path value or, on the old path, from the body
+func (h *Handlers) CreateTableAPIHandler(w http.ResponseWriter, r *http.Request) error {
 	var payload struct {
 		ID          string          `json:"id"`
 		Name        string          `json:"name"`
@@ -129,152 +97,100 @@ func (h *Handlers) createTableHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return apierror.InvalidJSON(err)
 	}
-
-	query := `
-		INSERT INTO tables (id, name, description, schema) 
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
-	`
-
-	var id string
-	err := h.db.QueryRow(query, payload.ID, payload.Name, payload.Description, payload.Schema).Scan(&id)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if id := r.PathValue("id"); id != "" {
+		if payload.ID != "" && payload.ID != id {
+			return apierror.Validation("Table ID does not match the path",
+				apierror.FieldError{Field: "id", Code: "mismatch", Message: "id must match the table ID in the path"})
+		}
+		payload.ID = id
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"id": id})
-}
-
-// TableAPIHandler handles single table operations
-func (h *Handlers) TableAPIHandler(w http.ResponseWriter, r *http.Request) {
-	// Extract table ID from path
-	path := strings.TrimPrefix(r.URL.Path, "/api/table/")
-	tableID := strings.TrimSuffix(path, "/")
-
-	if tableID == "" {
-		http.Error(w, "Table ID required", http.StatusBadRequest)
-		return
+	t := table.NewTable(payload.ID, payload.Name, payload.Description, payload.Schema)
+	if err := h.tableRepo.Create(r.Context(), t); err != nil {
+		return tableError(err)
 	}
 
-	switch r.Method {
-	case "GET":
-		h.getTableHandler(w, r, tableID)
-	case "PUT":
-		h.updateTableHandler(w, r, tableID)
-	case "DELETE":
-		h.deleteTableHandler(w, r, tableID)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": t.ID})
+	return nil
 }
 
-func (h *Handlers) getTableHandler(w http.ResponseWriter, r *http.Request, tableID string) {
-	query := `
-		SELECT id, name, description, schema, record_count, created_at, updated_at 
-		FROM tables 
-		WHERE id = $1
-	`
-
-	table := make(map[string]interface{})
-	var schemaJSON json.RawMessage
-	var id, name, description string
-	var recordCount int
-	var createdAt, updatedAt interface{}
-
-	err := h.db.QueryRow(query, tableID).Scan(&id, &name, &description, &schemaJSON, &recordCount, &createdAt, &updatedAt)
-
+// GetTableAPIHandler returns a table with all of its records
+func (h *Handlers) GetTableAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	t, err := h.tableRepo.FindByID(r.Context(), tableID)
 	if err != nil {
-		http.Error(w, "Table not found", http.StatusNotFound)
-		return
+		return tableError(err)
 	}
 
-	table["id"] = id
-	table["name"] = name
-	table["description"] = description
-	table["schema"] = schemaJSON
-	table["record_count"] = recordCount
-	table["created_at"] = createdAt
-	table["updated_at"] = updatedAt
-
-	// Get records for this table
-	recordsQuery := `SELECT id, data FROM records WHERE table_id = $1 ORDER BY created_at DESC`
-	rows, err := h.db.Query(recordsQuery, tableID)
-	if err == nil {
-		defer rows.Close()
+	recs, err := h.recordRepo.FindByTable(r.Context(), tableID, record.Page{})
+	if err != nil {
+		return tableError(err)
+	}
 
-		var records []map[string]interface{}
-		for rows.Next() {
-			var id int
-			var data json.RawMessage
-			if err := rows.Scan(&id, &data); err == nil {
-				record := make(map[string]interface{})
-				json.Unmarshal(data, &record)
-				record["_id"] = id
-				records = append(records, record)
-			}
-		}
-		table["records"] = records
+	records := make([]map[string]interface{}, 0, len(recs))
+	for _, rec := range recs {
+		records = append(records, rec.Flatten())
 	}
 
+	response := struct {
+		*table.Table
+		Records []map[string]interface{} `json:"records"`
+	}{t, records}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(table)
+	json.NewEncoder(w).Encode(response)
+	return nil
 }
 
-func (h *Handlers) updateTableHandler(w http.ResponseWriter, r *http.Request, tableID string) {
+// ReplaceRecordsAPIHandler replaces all records of a table
+func (h *Handlers) ReplaceRecordsAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
 	var payload struct {
-		Records []json.RawMessage `json:"records"`
+		Records []map[string]interface{} `json:"records"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return apierror.InvalidJSON(err)
 	}
 
-	// Start transaction
-	tx, err := h.db.Beginx()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	recs := make([]*record.Record, len(payload.Records))
+	for i, data := range payload.Records {
+		recs[i] = record.NewRecord(tableID, data)
 	}
-	defer tx.Rollback()
 
-	// Delete existing records
-	if _, err := tx.Exec("DELETE FROM records WHERE table_id = $1", tableID); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	// Replace all records in a single transaction
+	if err := h.recordRepo.Replace(r.Context(), tableID, recs); err != nil {
+		return tableError(err)
 	}
 
-	// Insert new records
-	for _, record := range payload.Records {
-		_, err := tx.Exec("INSERT INTO records (table_id, data) VALUES ($1, $2)", tableID, record)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	return nil
+}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+// DeleteTableAPIHandler deletes a table with its records
+func (h *Handlers) DeleteTableAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	if err := h.tableRepo.Delete(r.Context(), r.PathValue("id")); err != nil {
+		return tableError(err)
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	return nil
 }
 
-func (h *Handlers) deleteTableHandler(w http.ResponseWriter, r *http.Request, tableID string) {
-	_, err := h.db.Exec("DELETE FROM tables WHERE id = $1", tableID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+// tableError maps table and record domain errors to API errors
+func tableError(err error) error {
+	switch {
+	case errors.Is(err, table.ErrNotFound), errors.Is(err, record.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", "Table not found")
+	case errors.Is(err, table.ErrAlreadyExists):
+		return apierror.New(http.StatusConflict, "table_exists", err.Error())
+	case errors.Is(err, table.ErrInvalidInput), errors.Is(err, record.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	default:
+		return apierror.Internal(err)
 	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
 }
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"progressive/internal/apierror"
+	"progressive/internal/publish"
+)
+
+// PublishRequest represents a request to build a game data bundle
+type PublishRequest struct {
+	Tables    []string `json:"tables"`
+	Version   string   `json:"version"`
+	Formats   []string `json:"formats"`
+	GoPackage string   `json:"go_package"`
+}
+
+// PublishAPIHandler validates the requested tables and returns the bundle as a zip archive
+func (h *Handlers) PublishAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req PublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	formats, err := publish.ParseFormats(req.Formats)
+	if err != nil {
+		return apierror.Validation(err.Error(), apierror.FieldError{Field: "formats", Code: "invalid", Message: err.Error()})
+	}
+
+	bundle, err := publish.Build(r.Context(), publish.NewPostgresSource(h.db), publish.Options{
+		TableIDs:  req.Tables,
+		Version:   req.Version,
+		Formats:   formats,
+		GoPackage: req.GoPackage,
+	})
+	if err != nil {
+		return publishError(err)
+	}
+
+	slog.InfoContext(r.Context(), "published bundle", slog.String("version", bundle.Manifest.Version),
+		slog.Int("tables", len(bundle.Manifest.Tables)), slog.String("content_hash", bundle.Manifest.ContentHash))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=progressive-data-%s.zip", bundle.Manifest.Version))
+	w.Header().Set("X-Bundle-Content-Hash", bundle.Manifest.ContentHash)
+	if err := bundle.WriteZip(w); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write bundle archive", slog.Any("error", err))
+	}
+	return nil
+}
+
+// PublishValidateAPIHandler runs the publish validation without building a bundle
+func (h *Handlers) PublishValidateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req PublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	tables, err := publish.NewPostgresSource(h.db).LoadTables(r.Context(), req.Tables)
+	if err != nil {
+		return publishError(err)
+	}
+
+	report := publish.Validate(tables)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": report.OK(),
+		"report":  report,
+	})
+	return nil
+}
+
+// publishError maps publish errors to API errors; a failed validation
+// carries its report
+func publishError(err error) error {
+	var validationErr *publish.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return apierror.New(http.StatusUnprocessableEntity, "publish_validation_failed", validationErr.Error()).
+			With("report", validationErr.Report)
+	case errors.Is(err, publish.ErrInvalidOptions):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	case errors.Is(err, publish.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", err.Error())
+	default:
+		return apierror.Internal(fmt.Errorf("publish: %w", err))
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"progressive/internal/apierror"
+	"progressive/internal/diff"
+	"progressive/internal/domain/snapshot"
+	"progressive/internal/middleware"
+	"progressive/internal/router"
+)
+
+// CreateSnapshotRequest represents a request to capture a release snapshot.
+// The author is the request's actor, as in the audit log.
+type CreateSnapshotRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tables      []string `json:"tables"`
+}
+
+// PromotionRequest represents a request to promote a snapshot to the next
+// environment. The requester is the request's actor, as in the audit log.
+type PromotionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// PromotionReviewRequest represents an approval or rejection of a promotion.
+// The reviewer is the request's actor, as in the audit log.
+type PromotionReviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ListSnapshotsAPIHandler lists snapshots
+func (h *Handlers) ListSnapshotsAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	snapshots, err := h.snapshotRepo.FindAll(r.Context())
+	if err != nil {
+		return snapshotError(err)
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+	return nil
+}
+
+// CreateSnapshotAPIHandler captures a new snapshot
+func (h *Handlers) CreateSnapshotAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req CreateSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	s, err := snapshot.NewSnapshot(req.Name, req.Description, middleware.RequestActor(r))
+	if err != nil {
+		return snapshotError(err)
+	}
+	if err := h.snapshotRepo.Create(r.Context(), s, req.Tables); err != nil {
+		return snapshotError(err)
+	}
+
+	slog.InfoContext(r.Context(), "snapshot created", slog.String("snapshot", s.ID), slog.String("name", s.Name), slog.Int("tables", len(s.Tables)))
+	writeJSON(w, http.StatusCreated, s)
+	return nil
+}
+
+// SnapshotAPIHandler returns the snapshot {id}, by ID or name
+func (h *Handlers) SnapshotAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	s, err := h.snapshotRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return snapshotError(err)
+	}
+	writeJSON(w, http.StatusOK, s)
+	return nil
+}
+
+// PromotionsAPIHandler lists the promotions of the snapshot {id}
+func (h *Handlers) PromotionsAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	s, err := h.snapshotRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return snapshotError(err)
+	}
+	promotions, err := h.snapshotRepo.FindPromotions(r.Context(), s.ID)
+	if err != nil {
+		return snapshotError(err)
+	}
+	writeJSON(w, http.StatusOK, promotions)
+	return nil
+}
+
+// RequestPromotionAPIHandler requests the promotion of the snapshot {id} to
+// the next environment
+func (h *Handlers) RequestPromotionAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var req PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	s, err := h.snapshotRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return snapshotError(err)
+	}
+	promotion, err := snapshot.NewPromotion(s, middleware.RequestActor(r), req.Comment)
+	if err != nil {
+		return snapshotError(err)
+	}
+	if err := h.snapshotRepo.CreatePromotion(r.Context(), promotion); err != nil {
+		return snapshotError(err)
+	}
+
+	slog.InfoContext(r.Context(), "promotion requested", slog.String("snapshot", s.Name),
+		slog.String("from", string(promotion.FromEnvironment)), slog.String("to", string(promotion.ToEnvironment)), slog.String("requested_by", promotion.RequestedBy))
+	writeJSON(w, http.StatusCreated, promotion)
+	return nil
+}
+
+// PromotionAPIHandler returns the promotion {id}
+func (h *Handlers) PromotionAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	id, err := router.PathInt64(r, "id")
+	if err != nil {
+		return err
+	}
+	promotion, err := h.snapshotRepo.FindPromotionByID(r.Context(), id)
+	if err != nil {
+		return snapshotError(err)
+	}
+	writeJSON(w, http.StatusOK, promotion)
+	return nil
+}
+
+// ApprovePromotionAPIHandler approves the pending promotion {id}
+func (h *Handlers) ApprovePromotionAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.reviewPromotion(w, r, true)
+}
+
+// RejectPromotionAPIHandler rejects the pending promotion {id}
+func (h *Handlers) RejectPromotionAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.reviewPromotion(w, r, false)
+}
+
+func (h *Handlers) reviewPromotion(w http.ResponseWriter, r *http.Request, approve bool) error {
+	id, err := router.PathInt64(r, "id")
+	if err != nil {
+		return err
+	}
+	var req PromotionReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	reviewer := middleware.RequestActor(r)
+	promotion, err := h.snapshotRepo.ReviewPromotion(r.Context(), id, func(p *snapshot.Promotion) error {
+		if approve {
+			return p.Approve(reviewer, req.Comment)
+		}
+		return p.Reject(reviewer, req.Comment)
+	})
+	if err != nil {
+		return snapshotError(err)
+	}
+
+	slog.InfoContext(r.Context(), "promotion reviewed", slog.Int64("promotion", promotion.ID), slog.String("status", string(promotion.Status)),
+		slog.String("reviewed_by", promotion.ReviewedBy), slog.String("from", string(promotion.FromEnvironment)), slog.String("to", string(promotion.ToEnvironment)))
+	writeJSON(w, http.StatusOK, promotion)
+	return nil
+}
+
+// SnapshotDiffAPIHandler compares two snapshots, or a snapshot and the current tables
+//
+//	GET /api/v1/snapshots/diff?from={id|name}&to={id|name|current}
+func (h *Handlers) SnapshotDiffAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	fromRef, toRef := query.Get("from"), query.Get("to")
+	if fromRef == "" {
+		return apierror.Validation("from is required", apierror.FieldError{Field: "from", Code: "required", Message: "from is required"})
+	}
+	if toRef == "" {
+		toRef = "current"
+	}
+
+	from, err := h.snapshotRepo.FindByID(r.Context(), fromRef)
+	if err != nil {
+		return snapshotError(err)
+	}
+
+	var toTables []diff.Table
+	if toRef == "current" {
+		current, err := h.snapshotRepo.FindCurrent(r.Context(), from.TableIDs())
+		if err != nil {
+			return snapshotError(err)
+		}
+		toTables = snapshot.DiffTables(current)
+	} else {
+		to, err := h.snapshotRepo.FindByID(r.Context(), toRef)
+		if err != nil {
+			return snapshotError(err)
+		}
+		toTables = to.DiffTables()
+	}
+
+	result := diff.Tables(from.DiffTables(), toTables)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"from":    from.Name,
+		"to":      toRef,
+		"changed": result.HasChanges(),
+		"diff":    result,
+	})
+	return nil
+}
+
+// snapshotError maps snapshot domain errors to API errors
+func snapshotError(err error) error {
+	switch {
+	case errors.Is(err, snapshot.ErrNotFound):
+		return apierror.NotFound("snapshot_not_found", err.Error())
+	case errors.Is(err, snapshot.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", err.Error())
+	case errors.Is(err, snapshot.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	case errors.Is(err, snapshot.ErrSelfApproval):
+		return apierror.New(http.StatusForbidden, "self_approval", err.Error())
+	case errors.Is(err, snapshot.ErrNameTaken):
+		return apierror.New(http.StatusConflict, "snapshot_name_taken", err.Error())
+	case errors.Is(err, snapshot.ErrPromotionPending), errors.Is(err, snapshot.ErrNotPending),
+		errors.Is(err, snapshot.ErrFinalEnvironment), errors.Is(err, snapshot.ErrStalePromotion):
+		return apierror.New(http.StatusConflict, apierror.CodeConflict, err.Error())
+	default:
+		return apierror.Internal(fmt.Errorf("snapshot operation: %w", err))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -3,8 +3,7 @@ package handlers
 import (
 	"net/http"
 	"progressive/internal/handlers/table"
-
-	"github.com/jmoiron/sqlx"
+	"progressive/internal/storage"
 )
 
 // TableHandlers holds all table-related handlers
@@ -15,27 +14,27 @@ type TableHandlers struct {
 }
 
 // NewTableHandlers creates a new TableHandlers instance
-func NewTableHandlers(db *sqlx.DB) *TableHandlers {
+func NewTableHandlers(store *storage.Store) *TableHandlers {
 	return &TableHandlers{
-		Create: table.NewCreateHandler(db),
-		Editor: table.NewEditorHandler(db),
-		API:    table.NewAPIHandler(db),
+		Create: table.NewCreateHandler(store.Tables, store.Records, store.Templates),
+		Editor: table.NewEditorHandler(store.DB),
+		API:    table.NewAPIHandler(store.DB, store.Tables, store.Records),
 	}
 }
 
 // Legacy handlers for backward compatibility
-func (h *Handlers) TableCreatePageHandler(w http.ResponseWriter, r *http.Request) {
-	h.Table.Create.PageHandler(w, r)
+func (h *Handlers) TableCreatePageHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.Table.Create.PageHandler(w, r)
 }
 
-func (h *Handlers) TableCreateAPIHandler(w http.ResponseWriter, r *http.Request) {
-	h.Table.Create.APIHandler(w, r)
+func (h *Handlers) TableCreateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.Table.Create.APIHandler(w, r)
 }
 
-func (h *Handlers) TableEditorPageHandler(w http.ResponseWriter, r *http.Request) {
-	h.Table.Editor.PageHandler(w, r)
+func (h *Handlers) TableEditorPageHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.Table.Editor.PageHandler(w, r)
 }
 
-func (h *Handlers) TableDataAPIHandler(w http.ResponseWriter, r *http.Request) {
-	h.Table.API.DataHandler(w, r)
+func (h *Handlers) TableDataAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.Table.API.DataHandler(w, r)
 }
\ No newline at end of file
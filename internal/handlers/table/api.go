@@ -2,117 +2,77 @@ package table
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"strings"
-	"time"
+	"slices"
+
+	"progressive/internal/apierror"
+	commentrepo "progressive/internal/domain/comment/repository"
+	"progressive/internal/domain/record"
+	recordrepo "progressive/internal/domain/record/repository"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
+	"progressive/internal/metrics"
+	"progressive/internal/router"
 
 	"github.com/jmoiron/sqlx"
 )
 
 // APIHandler handles table data API related requests
 type APIHandler struct {
-	db *sqlx.DB
+	db       *sqlx.DB
+	tables   tablerepo.TableRepository
+	records  recordrepo.RecordRepository
+	comments commentrepo.CommentRepository
 }
 
 // NewAPIHandler creates a new APIHandler instance
-func NewAPIHandler(db *sqlx.DB) *APIHandler {
-	return &APIHandler{db: db}
+func NewAPIHandler(db *sqlx.DB, tables tablerepo.TableRepository, records recordrepo.RecordRepository) *APIHandler {
+	h := NewAPIHandlerWithRepositories(tables, records)
+	h.db = db
+	h.comments = commentrepo.NewPostgresRepository(db)
+	return h
 }
 
-// DataHandler handles table data API requests with pagination
-func (h *APIHandler) DataHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+// NewAPIHandlerWithRepositories creates an APIHandler for the table and record
+// endpoints only; the comment, codegen and revision endpoints need a database.
+func NewAPIHandlerWithRepositories(tables tablerepo.TableRepository, records recordrepo.RecordRepository) *APIHandler {
+	return &APIHandler{tables: tables, records: records}
+}
 
-	// Extract table ID from URL path
-	path := strings.TrimPrefix(r.URL.Path, "/api/table/")
-	parts := strings.Split(path, "/")
-	if len(parts) == 0 || parts[0] == "" {
-		http.Error(w, "Table ID required", http.StatusBadRequest)
-		return
-	}
-	tableID := parts[0]
+// DataHandler handles table data API requests with pagination
+func (h *APIHandler) DataHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
 
 	// Parse pagination parameters
 	page := parseInt(r.URL.Query().Get("page"), 1)
 	limit := parseInt(r.URL.Query().Get("limit"), 20)
 	offset := (page - 1) * limit
 
-	// Get table metadata
-	query := `
-		SELECT id, name, description, schema, record_count, created_at, updated_at 
-		FROM tables 
-		WHERE id = $1
-	`
-
-	var table struct {
-		ID          string          `db:"id"`
-		Name        string          `db:"name"`
-		Description string          `db:"description"`
-		Schema      json.RawMessage `db:"schema"`
-		RecordCount int             `db:"record_count"`
-		CreatedAt   time.Time       `db:"created_at"`
-		UpdatedAt   time.Time       `db:"updated_at"`
-	}
-
-	err := h.db.Get(&table, query, tableID)
+	t, err := h.tables.FindByID(r.Context(), tableID)
 	if err != nil {
-		http.Error(w, "Table not found", http.StatusNotFound)
-		return
+		return repositoryError(err)
 	}
 
-	// Get paginated records for this table
-	recordsQuery := `
-		SELECT id, data, created_at 
-		FROM records 
-		WHERE table_id = $1 
-		ORDER BY created_at DESC 
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := h.db.Query(recordsQuery, tableID, limit, offset)
+	recs, err := h.records.FindByTable(r.Context(), tableID, record.Page{Limit: limit, Offset: offset})
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to fetch records: %v", err), http.StatusInternalServerError)
-		return
+		return repositoryError(err)
 	}
-	defer rows.Close()
 
 	var records []map[string]interface{}
-	for rows.Next() {
-		var id int
-		var data json.RawMessage
-		var createdAt time.Time
-
-		if err := rows.Scan(&id, &data, &createdAt); err == nil {
-			record := make(map[string]interface{})
-			if err := json.Unmarshal(data, &record); err == nil {
-				record["_id"] = id
-				record["_created_at"] = createdAt
-				records = append(records, record)
-			}
-		}
+	for _, rec := range recs {
+		records = append(records, rec.Flatten())
 	}
 
 	// Create response
 	response := map[string]interface{}{
-		"table": map[string]interface{}{
-			"id":           table.ID,
-			"name":         table.Name,
-			"description":  table.Description,
-			"schema":       json.RawMessage(table.Schema),
-			"record_count": table.RecordCount,
-			"created_at":   table.CreatedAt,
-			"updated_at":   table.UpdatedAt,
-		},
+		"table":   t,
 		"records": records,
 		"pagination": map[string]interface{}{
 			"page":     page,
 			"limit":    limit,
-			"total":    table.RecordCount,
-			"has_more": (offset + len(records)) < table.RecordCount,
+			"total":    t.RecordCount,
+			"has_more": (offset + len(records)) < t.RecordCount,
 		},
 	}
 
@@ -121,60 +81,12 @@ func (h *APIHandler) DataHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	// Send JSON response
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to encode JSON response: %v", err), http.StatusInternalServerError)
-		return
-	}
-}
-
-// RecordHandler handles individual record operations
-func (h *APIHandler) RecordHandler(w http.ResponseWriter, r *http.Request) {
-	// Extract table ID from URL path
-	path := strings.TrimPrefix(r.URL.Path, "/api/table/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 3 || parts[0] == "" || parts[1] != "record" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
-		return
-	}
-	tableID := parts[0]
-
-	switch r.Method {
-	case "POST":
-		h.createRecord(w, r, tableID)
-	case "PATCH":
-		if len(parts) < 3 || parts[2] == "" {
-			http.Error(w, "Record ID required for PATCH", http.StatusBadRequest)
-			return
-		}
-		recordID := parts[2]
-		h.updateRecord(w, r, tableID, recordID)
-	case "DELETE":
-		if len(parts) < 3 || parts[2] == "" {
-			http.Error(w, "Record ID required for DELETE", http.StatusBadRequest)
-			return
-		}
-		recordID := parts[2]
-		h.deleteRecord(w, r, tableID, recordID)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+	return json.NewEncoder(w).Encode(response)
 }
 
 // ImportHandler handles data import requests
-func (h *APIHandler) ImportHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Extract table ID from URL path
-	path := strings.TrimPrefix(r.URL.Path, "/api/table/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 || parts[0] == "" || parts[1] != "import" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
-		return
-	}
-	tableID := parts[0]
+func (h *APIHandler) ImportHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
 
 	// Parse request body
 	var importRequest struct {
@@ -183,13 +95,12 @@ func (h *APIHandler) ImportHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&importRequest); err != nil {
-		http.Error(w, "Invalid JSON request", http.StatusBadRequest)
-		return
+		return apierror.InvalidJSON(err)
 	}
 
 	if len(importRequest.Data) == 0 {
-		http.Error(w, "No data to import", http.StatusBadRequest)
-		return
+		return apierror.Validation("No data to import",
+			apierror.FieldError{Field: "data", Code: "required", Message: "No data to import"})
 	}
 
 	// Validate mode
@@ -197,278 +108,171 @@ func (h *APIHandler) ImportHandler(w http.ResponseWriter, r *http.Request) {
 		importRequest.Mode = "replace" // default
 	}
 
-	// Start transaction
-	tx, err := h.db.Beginx()
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to start transaction: %v", err), http.StatusInternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	// If replace mode, delete existing records
-	if importRequest.Mode == "replace" {
-		_, err := tx.Exec("DELETE FROM records WHERE table_id = $1", tableID)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to clear existing records: %v", err), http.StatusInternalServerError)
-			return
+	recs := make([]*record.Record, 0, len(importRequest.Data))
+	for _, data := range importRequest.Data {
+		if data != nil {
+			recs = append(recs, record.NewRecord(tableID, data))
 		}
 	}
-
-	// Insert new records
-	insertQuery := `INSERT INTO records (table_id, data, created_at) VALUES ($1, $2, $3)`
-	importedCount := 0
-	now := time.Now()
-
-	for _, record := range importRequest.Data {
-		// Convert record to JSON
-		recordJSON, err := json.Marshal(record)
-		if err != nil {
-			continue // Skip invalid records
-		}
-
-		// Insert record
-		_, err = tx.Exec(insertQuery, tableID, json.RawMessage(recordJSON), now)
-		if err == nil {
-			importedCount++
-		}
+	if len(recs) == 0 {
+		return apierror.Validation("No valid records could be imported",
+			apierror.FieldError{Field: "data", Code: "invalid", Message: "No valid records could be imported"})
 	}
 
-	if importedCount == 0 {
-		http.Error(w, "No valid records could be imported", http.StatusBadRequest)
-		return
+	var err error
+	if importRequest.Mode == "replace" {
+		err = h.records.Replace(r.Context(), tableID, recs)
+	} else {
+		err = h.records.Append(r.Context(), tableID, recs)
 	}
-
-	// Update table record count
-	countQuery := `UPDATE tables SET record_count = (SELECT COUNT(*) FROM records WHERE table_id = $1), updated_at = $2 WHERE id = $1`
-	_, err = tx.Exec(countQuery, tableID, now)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to update table count: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to commit transaction: %v", err), http.StatusInternalServerError)
-		return
+		return repositoryError(err)
 	}
+	metrics.ImportedRows.Add(float64(len(recs)), importRequest.Mode)
 
 	// Send response
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":  true,
-		"imported": importedCount,
+		"imported": len(recs),
 		"mode":     importRequest.Mode,
 	})
+	return nil
 }
 
 // ExportHandler handles data export requests
-func (h *APIHandler) ExportHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Extract table ID from URL path
-	path := strings.TrimPrefix(r.URL.Path, "/api/table/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 2 || parts[0] == "" || parts[1] != "export" {
-		http.Error(w, "Invalid URL format", http.StatusBadRequest)
-		return
-	}
-	tableID := parts[0]
+func (h *APIHandler) ExportHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
 
-	format := r.URL.Query().Get("format")
+	format := record.Format(r.URL.Query().Get("format"))
 	if format == "" {
-		format = "json"
+		format = record.FormatJSON
+	}
+	if !slices.Contains(record.ExportFormats, format) {
+		return apierror.Validation("Unsupported format",
+			apierror.FieldError{Field: "format", Code: "unsupported", Message: "Unsupported format: " + string(format)})
 	}
 
 	// Get all records for the table
-	recordsQuery := `
-		SELECT data FROM records 
-		WHERE table_id = $1 
-		ORDER BY created_at DESC
-	`
-
-	rows, err := h.db.Query(recordsQuery, tableID)
+	recs, err := h.records.FindByTable(r.Context(), tableID, record.Page{})
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to fetch records: %v", err), http.StatusInternalServerError)
-		return
+		return repositoryError(err)
 	}
-	defer rows.Close()
 
-	var records []map[string]interface{}
-	for rows.Next() {
-		var data json.RawMessage
-		if err := rows.Scan(&data); err == nil {
-			record := make(map[string]interface{})
-			if err := json.Unmarshal(data, &record); err == nil {
-				records = append(records, record)
-			}
-		}
+	records := make([]map[string]interface{}, 0, len(recs))
+	for _, rec := range recs {
+		records = append(records, rec.Data)
 	}
 
-	// Export based on format
-	switch format {
-	case "json":
-		h.exportJSON(w, records)
-	case "csv":
-		h.exportCSV(w, records)
-	case "excel":
-		h.exportExcel(w, records)
-	default:
-		http.Error(w, "Unsupported format", http.StatusBadRequest)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename=export."+format.Extension())
+	if err := record.Write(w, format, records); err != nil {
+		return err
 	}
+	metrics.ExportedRows.Add(float64(len(records)), string(format))
+	return nil
 }
 
-// createRecord creates a new record
-func (h *APIHandler) createRecord(w http.ResponseWriter, r *http.Request, tableID string) {
-	var record map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
+// CreateRecordHandler creates a record in the table {id}
+func (h *APIHandler) CreateRecordHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	var data map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		return apierror.InvalidJSON(err)
 	}
 
-	// Insert record into database
-	data, _ := json.Marshal(record)
-	query := `INSERT INTO records (table_id, data, created_at) VALUES ($1, $2, $3) RETURNING id`
-
-	var recordID int
-	err := h.db.QueryRow(query, tableID, json.RawMessage(data), time.Now()).Scan(&recordID)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to create record: %v", err), http.StatusInternalServerError)
-		return
+	rec := record.NewRecord(tableID, data)
+	if err := h.records.Create(r.Context(), rec); err != nil {
+		return repositoryError(err)
 	}
 
-	// Update table record count
-	h.updateRecordCount(tableID)
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
-		"id":      recordID,
+		"id":      rec.ID,
 	})
+	return nil
 }
 
-// updateRecord updates an existing record
-func (h *APIHandler) updateRecord(w http.ResponseWriter, r *http.Request, tableID, recordID string) {
-	var updates map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
+// GetRecordHandler returns the record {record} with its "_id" and
+// "_created_at" fields
+func (h *APIHandler) GetRecordHandler(w http.ResponseWriter, r *http.Request) error {
+	recordID, err := router.PathInt64(r, "record")
+	if err != nil {
+		return err
 	}
-
-	// Get existing record
-	var existingData json.RawMessage
-	query := `SELECT data FROM records WHERE id = $1 AND table_id = $2`
-	err := h.db.QueryRow(query, recordID, tableID).Scan(&existingData)
+	rec, err := h.records.FindByID(r.Context(), r.PathValue("id"), recordID)
 	if err != nil {
-		http.Error(w, "Record not found", http.StatusNotFound)
-		return
+		return repositoryError(err)
 	}
 
-	// Merge updates with existing data
-	var record map[string]interface{}
-	json.Unmarshal(existingData, &record)
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(rec.Flatten())
+}
 
-	for key, value := range updates {
-		record[key] = value
+// UpdateRecordHandler merges a partial update into the record {record}
+func (h *APIHandler) UpdateRecordHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	recordID, err := router.PathInt64(r, "record")
+	if err != nil {
+		return err
+	}
+	var updates map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+		return apierror.InvalidJSON(err)
 	}
 
-	// Update record in database
-	newData, _ := json.Marshal(record)
-	updateQuery := `UPDATE records SET data = $1, updated_at = $2 WHERE id = $3 AND table_id = $4`
-	_, err = h.db.Exec(updateQuery, json.RawMessage(newData), time.Now(), recordID, tableID)
+	rec, err := h.records.FindByID(r.Context(), tableID, recordID)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to update record: %v", err), http.StatusInternalServerError)
-		return
+		return repositoryError(err)
+	}
+
+	rec.Merge(updates)
+	if err := h.records.Update(r.Context(), rec); err != nil {
+		return repositoryError(err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
 	})
+	return nil
 }
 
-// deleteRecord deletes a record
-func (h *APIHandler) deleteRecord(w http.ResponseWriter, r *http.Request, tableID, recordID string) {
-	query := `DELETE FROM records WHERE id = $1 AND table_id = $2`
-	result, err := h.db.Exec(query, recordID, tableID)
+// DeleteRecordHandler deletes the record {record}
+func (h *APIHandler) DeleteRecordHandler(w http.ResponseWriter, r *http.Request) error {
+	recordID, err := router.PathInt64(r, "record")
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to delete record: %v", err), http.StatusInternalServerError)
-		return
+		return err
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		http.Error(w, "Record not found", http.StatusNotFound)
-		return
+	if err := h.records.Delete(r.Context(), r.PathValue("id"), recordID); err != nil {
+		return repositoryError(err)
 	}
 
-	// Update table record count
-	h.updateRecordCount(tableID)
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
 	})
+	return nil
 }
 
-// updateRecordCount updates the record count for a table
-func (h *APIHandler) updateRecordCount(tableID string) {
-	query := `UPDATE tables SET record_count = (SELECT COUNT(*) FROM records WHERE table_id = $1) WHERE id = $1`
-	h.db.Exec(query, tableID)
-}
-
-// Export functions
-func (h *APIHandler) exportJSON(w http.ResponseWriter, records []map[string]interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Disposition", "attachment; filename=export.json")
-	json.NewEncoder(w).Encode(records)
-}
-
-func (h *APIHandler) exportCSV(w http.ResponseWriter, records []map[string]interface{}) {
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", "attachment; filename=export.csv")
-
-	if len(records) == 0 {
-		return
-	}
-
-	// Get headers from first record
-	var headers []string
-	for key := range records[0] {
-		headers = append(headers, key)
-	}
-
-	// Write CSV header
-	for i, header := range headers {
-		if i > 0 {
-			w.Write([]byte(","))
-		}
-		w.Write([]byte(fmt.Sprintf("\"%s\"", header)))
-	}
-	w.Write([]byte("\n"))
-
-	// Write CSV rows
-	for _, record := range records {
-		for i, header := range headers {
-			if i > 0 {
-				w.Write([]byte(","))
-			}
-			value := fmt.Sprintf("%v", record[header])
-			w.Write([]byte(fmt.Sprintf("\"%s\"", strings.ReplaceAll(value, "\"", "\"\""))))
-		}
-		w.Write([]byte("\n"))
+// repositoryError maps table and record domain errors to API errors
+func repositoryError(err error) error {
+	switch {
+	case errors.Is(err, table.ErrNotFound), errors.Is(err, record.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", "Table not found")
+	case errors.Is(err, record.ErrNotFound):
+		return apierror.NotFound("record_not_found", "Record not found")
+	case errors.Is(err, table.ErrAlreadyExists):
+		return apierror.New(http.StatusConflict, "table_exists", err.Error())
+	case errors.Is(err, table.ErrInvalidInput), errors.Is(err, record.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	default:
+		return apierror.Internal(err)
 	}
 }
 
-func (h *APIHandler) exportExcel(w http.ResponseWriter, records []map[string]interface{}) {
-	// For now, just export as CSV with Excel content type
-	w.Header().Set("Content-Type", "application/vnd.ms-excel")
-	w.Header().Set("Content-Disposition", "attachment; filename=export.xlsx")
-	h.exportCSV(w, records)
-}
-
+// Export functions
 // parseInt parses string to int with default value
 func parseInt(s string, defaultValue int) int {
 	if s == "" {
@@ -0,0 +1,307 @@
+package table
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/schematemplate"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+const testSchema = `{"type": "object", "properties": {"name": {"type": "string"}, "price": {"type": "integer"}}}`
+
+// forEachBackend runs fn with handlers on every storage backend
+func forEachBackend(t *testing.T, fn func(t *testing.T, create *CreateHandler, api *APIHandler)) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		fn(t, NewCreateHandler(store.Tables, store.Records, store.Templates), NewAPIHandlerWithRepositories(store.Tables, store.Records))
+	})
+}
+
+// serve sends a request to handler mounted at pattern, "METHOD /path", so
+// that its path values are set
+func serve(t *testing.T, handler apierror.HandlerFunc, pattern, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	method, _, _ := strings.Cut(pattern, " ")
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+const (
+	recordsPattern = "/api/v1/tables/{id}/records"
+	recordPattern  = "/api/v1/tables/{id}/records/{record}"
+)
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func createTestTable(t *testing.T, create *CreateHandler) string {
+	t.Helper()
+	payload, _ := json.Marshal(TableCreateRequest{TableName: "Game Item", Schema: testSchema})
+	rec := serve(t, create.APIHandler, "POST /api/v1/tables", "/api/v1/tables", string(payload))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	return decode(t, rec)["tableId"].(string)
+}
+
+func TestCreateRejectsInvalidSchema(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, create *CreateHandler, api *APIHandler) {
+		payload, _ := json.Marshal(TableCreateRequest{TableName: "Empty", Schema: `{"type": "object", "properties": {}}`})
+		rec := serve(t, create.APIHandler, "POST /api/v1/tables", "/api/v1/tables", string(payload))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "at least one property") {
+			t.Errorf("Expected 400 for empty properties, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestCreatePageListsWorkspaceTemplates(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		tmpl := schematemplate.NewSchemaTemplate("loot_table", "Loot table", "", schematemplate.CategoryCustom, "", json.RawMessage(testSchema))
+		tmpl.Workspace = "team-a"
+		if err := store.Templates.Create(context.Background(), tmpl); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+		create := NewCreateHandler(store.Tables, store.Records, store.Templates)
+
+		rec := serve(t, create.PageHandler, "GET /tables/new", "/tables/new?workspace=team-a", "")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `data-template="loot_table"`) {
+			t.Errorf("Expected the workspace template on the page, got %d", rec.Code)
+		}
+		rec = serve(t, create.PageHandler, "GET /tables/new", "/tables/new", "")
+		if strings.Contains(rec.Body.String(), "loot_table") {
+			t.Error("Expected no team-a templates outside the workspace")
+		}
+	})
+}
+
+func TestCreateHonorsDataOption(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		tmpl := schematemplate.NewSchemaTemplate("loot_table", "Loot table", "", schematemplate.CategoryCustom, "", json.RawMessage(testSchema))
+		tmpl.SampleData = json.RawMessage(`[{"name": "sword", "price": 100}, {"name": "shield", "price": 80}]`)
+		if err := store.Templates.Create(context.Background(), tmpl); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+		create := NewCreateHandler(store.Tables, store.Records, store.Templates)
+		api := NewAPIHandlerWithRepositories(store.Tables, store.Records)
+
+		tests := []struct {
+			req     TableCreateRequest
+			records int
+		}{
+			{TableCreateRequest{DataOption: "empty"}, 0},
+			{TableCreateRequest{DataOption: "sample", Template: "loot_table"}, 2},
+			{TableCreateRequest{DataOption: "generate", RowCount: 25}, 25},
+		}
+		for _, tt := range tests {
+			tt.req.TableName, tt.req.Schema = "Loot", testSchema
+			payload, _ := json.Marshal(tt.req)
+			rec := serve(t, create.APIHandler, "POST /api/v1/tables", "/api/v1/tables", string(payload))
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("%s: expected 201, got %d: %s", tt.req.DataOption, rec.Code, rec.Body.String())
+			}
+			body := decode(t, rec)
+			if body["records"] != float64(tt.records) {
+				t.Errorf("%s: expected %d records in the response, got %v", tt.req.DataOption, tt.records, body["records"])
+			}
+
+			path := "/api/v1/tables/" + body["tableId"].(string) + "/records?limit=100"
+			data := decode(t, serve(t, api.DataHandler, "GET "+recordsPattern, path, ""))
+			records, _ := data["records"].([]interface{})
+			if len(records) != tt.records {
+				t.Errorf("%s: expected %d stored records, got %d", tt.req.DataOption, tt.records, len(records))
+			}
+			for _, r := range records {
+				if _, ok := r.(map[string]interface{})["price"].(float64); !ok {
+					t.Errorf("%s: expected a numeric price, got %v", tt.req.DataOption, r)
+				}
+			}
+		}
+	})
+}
+
+func TestCreateRejectsInvalidDataOption(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		tmpl := schematemplate.NewSchemaTemplate("old_loot", "Old loot", "", schematemplate.CategoryCustom, "", json.RawMessage(testSchema))
+		tmpl.SampleData = json.RawMessage(`[{"name": "sword", "price": 100}, {"name": "shield", "price": "cheap"}]`)
+		if err := store.Templates.Create(context.Background(), tmpl); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+		create := NewCreateHandler(store.Tables, store.Records, store.Templates)
+		tests := []struct {
+			req    TableCreateRequest
+			status int
+		}{
+			{TableCreateRequest{DataOption: "everything"}, http.StatusBadRequest},
+			{TableCreateRequest{DataOption: "generate"}, http.StatusBadRequest},
+			{TableCreateRequest{DataOption: "generate", RowCount: 1001}, http.StatusBadRequest},
+			{TableCreateRequest{DataOption: "sample"}, http.StatusBadRequest},
+			{TableCreateRequest{DataOption: "sample", Template: "missing"}, http.StatusNotFound},
+			{TableCreateRequest{DataOption: "sample", Template: "old_loot"}, http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			tt.req.TableName, tt.req.Schema = "Loot", testSchema
+			payload, _ := json.Marshal(tt.req)
+			rec := serve(t, create.APIHandler, "POST /api/v1/tables", "/api/v1/tables", string(payload))
+			if rec.Code != tt.status {
+				t.Errorf("%+v: expected %d, got %d: %s", tt.req, tt.status, rec.Code, rec.Body.String())
+			}
+		}
+
+		if tables, err := store.Tables.FindAll(context.Background()); err != nil || len(tables) != 0 {
+			t.Errorf("Expected no table from rejected requests, got %d (%v)", len(tables), err)
+		}
+	})
+}
+
+func TestRecordLifecycle(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, create *CreateHandler, api *APIHandler) {
+		tableID := createTestTable(t, create)
+		base := "/api/v1/tables/" + tableID
+
+		rec := serve(t, api.CreateRecordHandler, "POST "+recordsPattern, base+"/records", `{"name": "sword", "price": 100}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected record to be created, got %d: %s", rec.Code, rec.Body.String())
+		}
+		id := int64(decode(t, rec)["id"].(float64))
+
+		rec = serve(t, api.UpdateRecordHandler, "PATCH "+recordPattern, base+"/records/"+strconv.FormatInt(id, 10), `{"price": 150}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected record to be updated, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		body := decode(t, serve(t, api.DataHandler, "GET "+recordsPattern, base+"/records?page=1&limit=20", ""))
+		records := body["records"].([]interface{})
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 record, got: %v", records)
+		}
+		first := records[0].(map[string]interface{})
+		if first["name"] != "sword" || first["price"] != float64(150) || first["_id"] != float64(id) {
+			t.Errorf("Expected merged record with _id, got: %v", first)
+		}
+		if total := body["pagination"].(map[string]interface{})["total"]; total != float64(1) {
+			t.Errorf("Expected total 1, got: %v", total)
+		}
+
+		rec = serve(t, api.DeleteRecordHandler, "DELETE "+recordPattern, base+"/records/"+strconv.FormatInt(id, 10), "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected record to be deleted, got %d: %s", rec.Code, rec.Body.String())
+		}
+		rec = serve(t, api.DeleteRecordHandler, "DELETE "+recordPattern, base+"/records/"+strconv.FormatInt(id, 10), "")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 deleting a missing record, got %d", rec.Code)
+		}
+	})
+}
+
+func TestImportModesAndPagination(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, create *CreateHandler, api *APIHandler) {
+		tableID := createTestTable(t, create)
+		base := "/api/v1/tables/" + tableID
+
+		rec := serve(t, api.ImportHandler, "POST /api/v1/tables/{id}/import", base+"/import", `{"mode": "append", "data": [{"name": "a"}, {"name": "b"}, {"name": "c"}]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected import to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		body := decode(t, serve(t, api.DataHandler, "GET "+recordsPattern, base+"/records?page=2&limit=2", ""))
+		pagination := body["pagination"].(map[string]interface{})
+		if len(body["records"].([]interface{})) != 1 || pagination["total"] != float64(3) || pagination["has_more"] != false {
+			t.Errorf("Unexpected second page: %v", body)
+		}
+
+		serve(t, api.ImportHandler, "POST /api/v1/tables/{id}/import", base+"/import", `{"mode": "replace", "data": [{"name": "z"}]}`)
+		rec = serve(t, api.ExportHandler, "GET /api/v1/tables/{id}/export", base+"/export?format=json", "")
+		var exported []map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&exported)
+		if len(exported) != 1 || exported[0]["name"] != "z" {
+			t.Errorf("Expected replace to leave only the imported record, got: %v", exported)
+		}
+	})
+}
+
+func TestMissingTable(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, create *CreateHandler, api *APIHandler) {
+		rec := serve(t, api.DataHandler, "GET "+recordsPattern, "/api/v1/tables/nope/records", "")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for missing table, got %d", rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != apierror.ContentType {
+			t.Errorf("Expected a problem document, got Content-Type %q", ct)
+		}
+		if body := decode(t, rec); body["code"] != "table_not_found" || body["status"] != float64(404) {
+			t.Errorf("Unexpected problem document: %v", body)
+		}
+		if rec := serve(t, api.CreateRecordHandler, "POST "+recordsPattern, "/api/v1/tables/nope/records", `{"name": "x"}`); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 creating a record in a missing table, got %d", rec.Code)
+		}
+		if rec := serve(t, api.UpdateRecordHandler, "PATCH "+recordPattern, "/api/v1/tables/nope/records/abc", `{}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a non-numeric record ID, got %d", rec.Code)
+		}
+	})
+}
+
+func TestCodegen(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, create *CreateHandler, api *APIHandler) {
+		base := "/api/v1/tables/" + createTestTable(t, create) + "/codegen"
+		const pattern = "GET /api/v1/tables/{id}/codegen"
+
+		tests := []struct {
+			query, filename, want string
+		}{
+			{"?lang=go&package=items", "Game Item.go", "package items"},
+			{"?lang=csharp&namespace=Game.Data", "Game Item.cs", "namespace Game.Data"},
+			{"?lang=ts", "Game Item.ts", "export interface"},
+		}
+		for _, tt := range tests {
+			rec := serve(t, api.CodegenHandler, pattern, base+tt.query, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: expected 200, got %d: %s", tt.query, rec.Code, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+				t.Errorf("%s: unexpected Content-Type %q", tt.query, ct)
+			}
+			if _, params, err := mime.ParseMediaType(rec.Header().Get("Content-Disposition")); err != nil || params["filename"] != tt.filename {
+				t.Errorf("%s: expected filename %q, got %v (%v)", tt.query, tt.filename, params, err)
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("%s: expected %q in the source, got:\n%s", tt.query, tt.want, rec.Body.String())
+			}
+		}
+
+		for query, field := range map[string]string{
+			"?lang=cobol":                          "lang",
+			"?lang=go&package=func":                "package",
+			"?lang=go&package=my-items":            "package",
+			"?lang=csharp&namespace=Game..X":       "namespace",
+			"?lang=csharp&namespace=Game%3B%7B%7D": "namespace",
+		} {
+			rec := serve(t, api.CodegenHandler, pattern, base+query, "")
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d: %s", query, rec.Code, rec.Body.String())
+				continue
+			}
+			errs, _ := decode(t, rec)["errors"].([]interface{})
+			if len(errs) != 1 || errs[0].(map[string]interface{})["field"] != field {
+				t.Errorf("%s: expected a %s field error, got %v", query, field, errs)
+			}
+		}
+	})
+}
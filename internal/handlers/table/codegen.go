@@ -0,0 +1,61 @@
+package table
+
+import (
+	"go/token"
+	"mime"
+	"net/http"
+	"regexp"
+
+	"progressive/internal/apierror"
+	"progressive/internal/codegen"
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// namespacePattern matches a dotted C# namespace such as Game.Data
+var namespacePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// CodegenHandler generates source code from a table's JSON Schema.
+//
+//	GET /api/v1/tables/{id}/codegen?lang=go|ts|csharp|sql[&package=name][&namespace=Name]
+func (h *APIHandler) CodegenHandler(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	lang, err := codegen.ParseLanguage(query.Get("lang"))
+	if err != nil {
+		return apierror.Validation(err.Error(), apierror.FieldError{Field: "lang", Code: "unsupported", Message: err.Error()})
+	}
+
+	pkg, namespace := query.Get("package"), query.Get("namespace")
+	if pkg != "" && !token.IsIdentifier(pkg) {
+		return apierror.Validation("Invalid package name", apierror.FieldError{Field: "package", Code: "invalid", Message: "must be a Go identifier"})
+	}
+	if namespace != "" && !namespacePattern.MatchString(namespace) {
+		return apierror.Validation("Invalid namespace", apierror.FieldError{Field: "namespace", Code: "invalid", Message: "must be dot-separated identifiers"})
+	}
+
+	table, err := h.tables.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return repositoryError(err)
+	}
+
+	schema, err := repository.ParseSchemaDefinition(table.Schema)
+	if err != nil {
+		return apierror.Newf(http.StatusUnprocessableEntity, "invalid_table_schema", "Invalid table schema: %v", err)
+	}
+
+	source, err := codegen.Generate(lang, []codegen.Table{{ID: table.ID, Name: table.Name, Schema: schema}}, codegen.Options{
+		Package:   pkg,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return apierror.Newf(http.StatusUnprocessableEntity, "codegen_failed", "Failed to generate code: %v", err)
+	}
+
+	filename := table.Name
+	if filename == "" {
+		filename = table.ID
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename + "." + lang.Extension()}))
+	w.Write(source)
+	return nil
+}
@@ -0,0 +1,266 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/comment"
+	"progressive/internal/router"
+)
+
+// CreateThreadRequest opens a thread on a table, record or cell
+type CreateThreadRequest struct {
+	RecordID int64  `json:"record_id"`
+	Field    string `json:"field"`
+	Author   string `json:"author"`
+	Body     string `json:"body"`
+}
+
+// CommentRequest is a reply or an edit of a comment
+type CommentRequest struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+// ResolveRequest names who resolved or reopened a thread
+type ResolveRequest struct {
+	By string `json:"by"`
+}
+
+// Comment threads of a table are served under /api/v1/tables/{id}/comments:
+//
+//	GET   /comments[?status=open|resolved|all][&record_id=][&field=][&mention=]
+//	POST  /comments                                   open a thread
+//	GET   /comments/open                              open thread counts per cell
+//	GET   /comments/export?format=csv|markdown|json   unresolved threads
+//	GET   /comments/{thread}
+//	POST  /comments/{thread}/replies
+//	POST  /comments/{thread}/resolve
+//	POST  /comments/{thread}/unresolve
+//	PATCH /comments/{thread}/comments/{comment}       edit (author only)
+//	GET   /comments/{thread}/comments/{comment}/edits edit history
+
+// OpenThreadCountsHandler counts open threads per cell of the table {id}
+func (h *APIHandler) OpenThreadCountsHandler(w http.ResponseWriter, r *http.Request) error {
+	counts, err := h.comments.CountOpen(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, counts)
+	return nil
+}
+
+// ThreadHandler returns the thread {thread} with its comments
+func (h *APIHandler) ThreadHandler(w http.ResponseWriter, r *http.Request) error {
+	threadID, err := router.PathInt64(r, "thread")
+	if err != nil {
+		return err
+	}
+	thread, err := h.comments.FindThreadByID(r.Context(), r.PathValue("id"), threadID)
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, thread)
+	return nil
+}
+
+// ReplyHandler adds a comment to the thread {thread}
+func (h *APIHandler) ReplyHandler(w http.ResponseWriter, r *http.Request) error {
+	threadID, err := router.PathInt64(r, "thread")
+	if err != nil {
+		return err
+	}
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	c, err := comment.NewComment(req.Author, req.Body)
+	if err != nil {
+		return commentError(err)
+	}
+	if err := h.comments.AddComment(r.Context(), r.PathValue("id"), threadID, c); err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusCreated, c)
+	return nil
+}
+
+// ResolveThreadHandler marks the thread {thread} resolved
+func (h *APIHandler) ResolveThreadHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.setResolved(w, r, true)
+}
+
+// UnresolveThreadHandler reopens the thread {thread}
+func (h *APIHandler) UnresolveThreadHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.setResolved(w, r, false)
+}
+
+func (h *APIHandler) setResolved(w http.ResponseWriter, r *http.Request, resolved bool) error {
+	threadID, err := router.PathInt64(r, "thread")
+	if err != nil {
+		return err
+	}
+	var req ResolveRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return apierror.InvalidJSON(err)
+		}
+	}
+	thread, err := h.comments.SetResolved(r.Context(), r.PathValue("id"), threadID, resolved, req.By)
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, thread)
+	return nil
+}
+
+// EditCommentHandler edits the comment {comment}; only its author may
+func (h *APIHandler) EditCommentHandler(w http.ResponseWriter, r *http.Request) error {
+	threadID, err := router.PathInt64(r, "thread")
+	if err != nil {
+		return err
+	}
+	commentID, err := router.PathInt64(r, "comment")
+	if err != nil {
+		return err
+	}
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	c, err := h.comments.EditComment(r.Context(), r.PathValue("id"), threadID, commentID, req.Author, req.Body)
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, c)
+	return nil
+}
+
+// CommentEditsHandler returns the edit history of the comment {comment}
+func (h *APIHandler) CommentEditsHandler(w http.ResponseWriter, r *http.Request) error {
+	threadID, err := router.PathInt64(r, "thread")
+	if err != nil {
+		return err
+	}
+	commentID, err := router.PathInt64(r, "comment")
+	if err != nil {
+		return err
+	}
+	edits, err := h.comments.FindEdits(r.Context(), r.PathValue("id"), threadID, commentID)
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, edits)
+	return nil
+}
+
+// ListThreadsHandler lists the threads of the table {id}
+func (h *APIHandler) ListThreadsHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	query := r.URL.Query()
+	filter := comment.Filter{
+		Status:  query.Get("status"),
+		Field:   query.Get("field"),
+		Mention: strings.TrimPrefix(query.Get("mention"), "@"),
+	}
+	if v := query.Get("record_id"); v != "" {
+		recordID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apierror.Validation("Invalid record_id",
+				apierror.FieldError{Field: "record_id", Code: "invalid", Message: "record_id must be an integer"})
+		}
+		filter.RecordID = recordID
+	}
+
+	threads, err := h.comments.FindThreads(r.Context(), tableID, filter)
+	if err != nil {
+		return commentError(err)
+	}
+	writeCommentJSON(w, http.StatusOK, threads)
+	return nil
+}
+
+// CreateThreadHandler opens a thread on the table {id}, a record or a cell
+func (h *APIHandler) CreateThreadHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	var req CreateThreadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+
+	thread, err := comment.NewThread(tableID, comment.Anchor{RecordID: req.RecordID, Field: req.Field}, req.Author, req.Body)
+	if err != nil {
+		return commentError(err)
+	}
+	if err := h.comments.CreateThread(r.Context(), thread); err != nil {
+		return commentError(err)
+	}
+	slog.InfoContext(r.Context(), "comment thread opened", slog.Int64("thread", thread.ID), slog.String("table", tableID), slog.String("anchor", thread.Anchor.Location()))
+	writeCommentJSON(w, http.StatusCreated, thread)
+	return nil
+}
+
+// ExportThreadsHandler exports the unresolved threads of the table {id}
+func (h *APIHandler) ExportThreadsHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	t, err := h.tables.FindByID(r.Context(), tableID)
+	if err != nil {
+		return repositoryError(err)
+	}
+	tableName := t.Name
+
+	threads, err := h.comments.FindThreads(r.Context(), tableID, comment.Filter{Status: comment.StatusOpen})
+	if err != nil {
+		return commentError(err)
+	}
+
+	filename := tableName + "-open-comments"
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "markdown", "md":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".md"))
+		err = comment.WriteMarkdown(w, tableName+" — unresolved comments", threads)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		err = comment.WriteCSV(w, threads)
+	case "json":
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		writeCommentJSON(w, http.StatusOK, threads)
+	default:
+		return apierror.Validation("Unsupported format",
+			apierror.FieldError{Field: "format", Code: "unsupported", Message: "Unsupported format: " + format})
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to export comments", slog.String("table", tableID), slog.Any("error", err))
+	}
+	return nil
+}
+
+func writeCommentJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// commentError maps comment domain errors to API errors
+func commentError(err error) error {
+	switch {
+	case errors.Is(err, comment.ErrNotFound):
+		return apierror.NotFound("comment_not_found", err.Error())
+	case errors.Is(err, comment.ErrTableNotFound):
+		return apierror.NotFound("table_not_found", err.Error())
+	case errors.Is(err, comment.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	case errors.Is(err, comment.ErrNotAuthor):
+		return apierror.New(http.StatusForbidden, "not_author", err.Error())
+	default:
+		return apierror.Internal(fmt.Errorf("comment operation: %w", err))
+	}
+}
@@ -2,69 +2,94 @@ package table
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"net/http"
-	"progressive/internal/models"
+	"progressive/internal/apierror"
+	"progressive/internal/fakeit"
 	"progressive/internal/pages"
+	"progressive/internal/validation"
 	"time"
 
-	"github.com/jmoiron/sqlx"
+	"progressive/internal/domain/record"
+	recordrepo "progressive/internal/domain/record/repository"
+	"progressive/internal/domain/schematemplate"
+	templaterepo "progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/domain/table"
+	tablerepo "progressive/internal/domain/table/repository"
 )
 
 // CreateHandler handles table creation related requests
 type CreateHandler struct {
-	db *sqlx.DB
+	tables    tablerepo.TableRepository
+	records   recordrepo.RecordRepository
+	templates templaterepo.SchemaTemplateRepository
 }
 
 // NewCreateHandler creates a new CreateHandler instance
-func NewCreateHandler(db *sqlx.DB) *CreateHandler {
-	return &CreateHandler{db: db}
+func NewCreateHandler(tables tablerepo.TableRepository, records recordrepo.RecordRepository, templates templaterepo.SchemaTemplateRepository) *CreateHandler {
+	return &CreateHandler{tables: tables, records: records, templates: templates}
 }
 
-// PageHandler renders the table creation page (GET only)
-func (h *CreateHandler) PageHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "GET" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+// PageHandler renders the table creation page (GET only) with the
+// templates of the ?workspace= workspace
+func (h *CreateHandler) PageHandler(w http.ResponseWriter, r *http.Request) error {
+	all, err := h.templates.FindAll(r.Context())
+	if err != nil {
+		return apierror.Internal(err)
+	}
+	workspace := r.URL.Query().Get("workspace")
+	templates := make([]*schematemplate.SchemaTemplate, 0, len(all))
+	for _, t := range all {
+		if t.VisibleIn(workspace) {
+			templates = append(templates, t)
+		}
 	}
-
-	// Get templates data from models
-	templates := models.GetTableTemplates()
 
 	// Render the table creation page
-	err := pages.TableCreatePage(templates).Render(r.Context(), w)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	return pages.TableCreatePage(templates).Render(r.Context(), w)
 }
 
 // APIHandler handles table creation API requests (POST only)
-func (h *CreateHandler) APIHandler(w http.ResponseWriter, r *http.Request) {
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	h.handleTableCreationAPI(w, r)
+func (h *CreateHandler) APIHandler(w http.ResponseWriter, r *http.Request) error {
+	return h.handleTableCreationAPI(w, r)
 }
 
+// Data options for the first records of a new table
+const (
+	DataOptionEmpty    = "empty"
+	DataOptionSample   = "sample"
+	DataOptionGenerate = "generate"
+	// DataOptionImport creates an empty table; the file is imported afterwards
+	DataOptionImport = "import"
+)
+
+const (
+	// defaultGeneratedRows is used for "sample" when the template has no sample data
+	defaultGeneratedRows = 10
+	maxGeneratedRows     = 1000
+)
+
 // TableCreateRequest represents the JSON request for table creation
 type TableCreateRequest struct {
-	TableName  string `json:"table_name"`
-	Schema     string `json:"schema"`
+	TableName string `json:"table_name"`
+	Schema    string `json:"schema"`
+	// DataOption is "empty" (default), "sample", "generate" or "import"
 	DataOption string `json:"data_option"`
+	// Template is the template whose sample data "sample" inserts
+	Template string `json:"template,omitempty"`
+	// RowCount is the number of rows "generate" inserts
+	RowCount int `json:"row_count,omitempty"`
 }
 
 // handleTableCreationAPI processes the table creation API request
-func (h *CreateHandler) handleTableCreationAPI(w http.ResponseWriter, r *http.Request) {
+func (h *CreateHandler) handleTableCreationAPI(w http.ResponseWriter, r *http.Request) error {
 	// Parse JSON request body
 	var req TableCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to parse JSON request: %v", err), http.StatusBadRequest)
-		return
+		return apierror.InvalidJSON(err)
 	}
 
 	// Extract table data from request
@@ -72,85 +97,71 @@ func (h *CreateHandler) handleTableCreationAPI(w http.ResponseWriter, r *http.Re
 	schemaJSON := req.Schema
 	dataOption := req.DataOption
 
-	// Debug: Log received JSON values
-	log.Printf("🔍 JSON data received - table_name: '%s', schema length: %d, data_option: '%s'",
-		tableName, len(schemaJSON), dataOption)
+	slog.DebugContext(r.Context(), "create table request",
+		slog.String("table_name", tableName), slog.Int("schema_length", len(schemaJSON)), slog.String("data_option", dataOption))
 
 	// Validate required fields
+	var fields []apierror.FieldError
 	if tableName == "" {
-		http.Error(w, "Table name is required", http.StatusBadRequest)
-		return
+		fields = append(fields, apierror.FieldError{Field: "table_name", Code: "required", Message: "Table name is required"})
 	}
-
 	if schemaJSON == "" {
-		http.Error(w, "Schema is required", http.StatusBadRequest)
-		return
-	}
-
-	// Validate JSON schema syntax
-	var schema map[string]interface{}
-	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid JSON schema syntax: %v", err), http.StatusBadRequest)
-		return
+		fields = append(fields, apierror.FieldError{Field: "schema", Code: "required", Message: "Schema is required"})
 	}
-
-	// Validate schema structure - must be object type
-	schemaType, typeExists := schema["type"]
-	if !typeExists {
-		http.Error(w, "Schema must have a 'type' field", http.StatusBadRequest)
-		return
+	switch dataOption {
+	case "", DataOptionEmpty, DataOptionImport, DataOptionSample:
+	case DataOptionGenerate:
+		if req.RowCount < 1 || req.RowCount > maxGeneratedRows {
+			fields = append(fields, apierror.FieldError{Field: "row_count", Code: "out_of_range",
+				Message: fmt.Sprintf("Row count must be between 1 and %d", maxGeneratedRows)})
+		}
+	default:
+		fields = append(fields, apierror.FieldError{Field: "data_option", Code: "unsupported",
+			Message: "Data option must be one of empty, sample, generate or import"})
 	}
-
-	if schemaType != "object" {
-		http.Error(w, fmt.Sprintf("Schema type must be 'object', got '%v'", schemaType), http.StatusBadRequest)
-		return
+	if len(fields) > 0 {
+		return apierror.Validation("Invalid table creation request", fields...)
 	}
 
-	// Validate properties exist
-	propertiesField, propertiesExists := schema["properties"]
-	if !propertiesExists {
-		http.Error(w, "Schema must contain 'properties' field", http.StatusBadRequest)
-		return
+	// Validate JSON schema structure - must be an object type with properties
+	if err := table.ValidateSchema(json.RawMessage(schemaJSON)); err != nil {
+		return repositoryError(err)
 	}
 
-	properties, ok := propertiesField.(map[string]interface{})
-	if !ok {
-		http.Error(w, "Schema 'properties' field must be an object", http.StatusBadRequest)
-		return
-	}
-
-	if len(properties) == 0 {
-		http.Error(w, "Schema must contain at least one property", http.StatusBadRequest)
-		return
+	rows, err := h.initialRows(r, &req)
+	if err != nil {
+		return err
 	}
 
 	// Save table to database
 	tableID := generateTableID(tableName)
-
-	// Create table in database
-	query := `
-		INSERT INTO tables (id, name, description, schema, record_count, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
-
-	now := time.Now()
 	description := "사용자가 생성한 테이블: " + tableName
 
-	_, err := h.db.Exec(query, tableID, tableName, description, json.RawMessage(schemaJSON), 0, now, now)
+	t := table.NewTable(tableID, tableName, description, json.RawMessage(schemaJSON))
+	if len(rows) == 0 {
+		err = h.tables.Create(r.Context(), t)
+	} else {
+		recs := make([]*record.Record, len(rows))
+		for i, row := range rows {
+			recs[i] = record.NewRecord(tableID, row)
+		}
+		err = h.records.CreateWithTable(r.Context(), t, recs)
+	}
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to save table to database: %v", err), http.StatusInternalServerError)
-		return
+		return repositoryError(err)
 	}
 
-	log.Printf("✅ Table created successfully: %s (ID: %s)", tableName, tableID)
+	slog.InfoContext(r.Context(), "table created",
+		slog.String("table", tableID), slog.String("name", tableName), slog.Int("records", len(rows)))
 
 	// Create response
 	response := map[string]interface{}{
 		"success":    true,
 		"tableId":    tableID,
 		"name":       tableName,
-		"schema":     schema,
+		"schema":     t.Schema,
 		"dataOption": dataOption,
+		"records":    len(rows),
 		"redirect":   "/table/" + tableID,
 	}
 
@@ -159,10 +170,72 @@ func (h *CreateHandler) handleTableCreationAPI(w http.ResponseWriter, r *http.Re
 	w.WriteHeader(http.StatusCreated)
 
 	// Send JSON response
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to encode JSON response: %v", err), http.StatusInternalServerError)
-		return
+	return json.NewEncoder(w).Encode(response)
+}
+
+// initialRows returns the records the data option asks for: the template's
+// sample data, or rows generated from the schema
+func (h *CreateHandler) initialRows(r *http.Request, req *TableCreateRequest) ([]map[string]interface{}, error) {
+	count := req.RowCount
+	switch req.DataOption {
+	case DataOptionSample:
+		if req.Template == "" {
+			return nil, apierror.Validation("A template is required for sample data",
+				apierror.FieldError{Field: "template", Code: "required", Message: "Template is required for the sample option"})
+		}
+		tmpl, err := h.templates.FindByID(r.Context(), req.Template)
+		if errors.Is(err, schematemplate.ErrNotFound) {
+			return nil, apierror.NotFound("template_not_found", "Template not found: "+req.Template)
+		}
+		if err != nil {
+			return nil, apierror.Internal(err)
+		}
+		if len(tmpl.SampleData) > 0 {
+			var rows []map[string]interface{}
+			if err := json.Unmarshal(tmpl.SampleData, &rows); err != nil {
+				return nil, apierror.Internal(fmt.Errorf("template %s: invalid sample data: %w", tmpl.ID, err))
+			}
+			if len(rows) > 0 {
+				return rows, validateSampleRows(req.Schema, rows)
+			}
+		}
+		count = defaultGeneratedRows
+	case DataOptionGenerate:
+	default:
+		return nil, nil
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Schema), &schema); err != nil {
+		return nil, apierror.Validation("Invalid schema",
+			apierror.FieldError{Field: "schema", Code: "invalid", Message: err.Error()})
+	}
+	rows, err := fakeit.New(fakeit.NewSeed()).Generate(schema, nil, count)
+	if err != nil {
+		return nil, apierror.Validation("Invalid schema",
+			apierror.FieldError{Field: "schema", Code: "invalid", Message: err.Error()})
+	}
+	return rows, nil
+}
+
+// validateSampleRows checks template sample rows against the schema of the
+// new table, which may have been edited after the template was picked
+func validateSampleRows(schema string, rows []map[string]interface{}) error {
+	validator, err := validation.NewFromJSON(json.RawMessage(schema))
+	if err != nil {
+		return apierror.Validation("Invalid schema",
+			apierror.FieldError{Field: "schema", Code: "invalid", Message: err.Error()})
+	}
+	var fields []apierror.FieldError
+	for i, row := range rows {
+		for _, e := range validator.Validate(row) {
+			fields = append(fields, apierror.FieldError{Field: fmt.Sprintf("sample_data[%d].%s", i, e.Field), Code: e.Code, Message: e.Message})
+		}
+	}
+	if len(fields) > 0 {
+		return apierror.Validation("Template sample data does not match the schema", fields...)
 	}
+	return nil
 }
 
 // generateTableID creates a unique table ID
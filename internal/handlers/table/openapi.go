@@ -0,0 +1,25 @@
+package table
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"progressive/internal/apierror"
+	"progressive/internal/openapi"
+)
+
+// OpenAPIHandler returns the OpenAPI document of the table {id}, with its
+// JSON Schema as the record model
+func (h *APIHandler) OpenAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	t, err := h.tables.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return repositoryError(err)
+	}
+	doc, err := openapi.ForTable(t, "/api/v1")
+	if err != nil {
+		return apierror.New(http.StatusUnprocessableEntity, "invalid_schema", "The table schema is not valid JSON").Wrap(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(doc)
+}
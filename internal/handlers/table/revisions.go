@@ -0,0 +1,63 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"progressive/internal/apierror"
+)
+
+// Revision is one entry of the record revision log
+type Revision struct {
+	ID        int64           `json:"id" db:"id"`
+	RecordID  int64           `json:"record_id" db:"record_id"`
+	Operation string          `json:"operation" db:"operation"`
+	OldData   json.RawMessage `json:"old_data,omitempty" db:"old_data"`
+	NewData   json.RawMessage `json:"new_data,omitempty" db:"new_data"`
+	Source    string          `json:"source" db:"source"`
+	Actor     string          `json:"actor" db:"actor"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// RevisionsHandler returns the revision history of a table, newest first.
+//
+//	GET /api/v1/tables/{id}/revisions[?record_id=123][&limit=100]
+func (h *APIHandler) RevisionsHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 1000 {
+			return apierror.Validation("Invalid limit",
+				apierror.FieldError{Field: "limit", Code: "out_of_range", Message: "limit must be between 1 and 1000"})
+		}
+		limit = n
+	}
+
+	query := `SELECT id, record_id, operation, old_data, new_data, source, actor, created_at
+		FROM record_revisions WHERE table_id = $1`
+	args := []interface{}{tableID}
+	if v := r.URL.Query().Get("record_id"); v != "" {
+		recordID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apierror.Validation("Invalid record_id",
+				apierror.FieldError{Field: "record_id", Code: "invalid", Message: "record_id must be an integer"})
+		}
+		query += ` AND record_id = $2`
+		args = append(args, recordID)
+	}
+	query += ` ORDER BY id DESC LIMIT ` + strconv.Itoa(limit)
+
+	revisions := []Revision{}
+	if err := h.db.SelectContext(r.Context(), &revisions, query, args...); err != nil {
+		return apierror.Internal(fmt.Errorf("load revisions: %w", err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+	return nil
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/schematemplate"
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// maxSampleRows caps the records copied into a template saved from a table
+const maxSampleRows = 50
+
+// TemplateRequest is the body of template create and update requests
+type TemplateRequest struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Icon        string          `json:"icon"`
+	Schema      json.RawMessage `json:"schema"`
+	SampleData  json.RawMessage `json:"sample_data"`
+	Workspace   string          `json:"workspace"`
+}
+
+// SaveTemplateRequest is the body of a save-table-as-template request.
+// ID, name and description default to the table's.
+type SaveTemplateRequest struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Workspace   string `json:"workspace"`
+	// SampleRows copies the first records as sample data
+	SampleRows int `json:"sample_rows"`
+}
+
+// TemplatesAPIHandler returns the templates offered in the ?workspace=
+// workspace: the built-in ones and the workspace's custom templates.
+// ?category= narrows the list.
+func (h *Handlers) TemplatesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	templates, err := h.visibleTemplates(r)
+	if err != nil {
+		return err
+	}
+	if category := r.URL.Query().Get("category"); category != "" {
+		filtered := templates[:0]
+		for _, t := range templates {
+			if t.Category == category {
+				filtered = append(filtered, t)
+			}
+		}
+		templates = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+	return nil
+}
+
+// visibleTemplates lists the templates of the request's workspace
+func (h *Handlers) visibleTemplates(r *http.Request) ([]*schematemplate.SchemaTemplate, error) {
+	all, err := h.templateRepo.FindAll(r.Context())
+	if err != nil {
+		return nil, apierror.Internal(err)
+	}
+	workspace := r.URL.Query().Get("workspace")
+	templates := make([]*schematemplate.SchemaTemplate, 0, len(all))
+	for _, t := range all {
+		if t.VisibleIn(workspace) {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+// GetTemplateAPIHandler returns one template
+func (h *Handlers) GetTemplateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	t, err := h.templateRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return templateError(err)
+	}
+	writeJSON(w, http.StatusOK, t)
+	return nil
+}
+
+// CreateTemplateAPIHandler creates a template; without a category it is a
+// custom template of the given workspace
+func (h *Handlers) CreateTemplateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var payload TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	if payload.Category == "" {
+		payload.Category = schematemplate.CategoryCustom
+	}
+
+	t := schematemplate.NewSchemaTemplate(payload.ID, payload.Name, payload.Description, payload.Category, payload.Icon, payload.Schema)
+	t.SampleData = payload.SampleData
+	t.Workspace = payload.Workspace
+	if err := h.templateRepo.Create(r.Context(), t); err != nil {
+		return templateError(err)
+	}
+
+	writeJSON(w, http.StatusCreated, t)
+	return nil
+}
+
+// UpdateTemplateAPIHandler replaces a template's fields; a missing
+// category keeps the current one
+func (h *Handlers) UpdateTemplateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	var payload TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	if payload.ID != "" && payload.ID != id {
+		return apierror.Validation("Template ID does not match the path",
+			apierror.FieldError{Field: "id", Code: "mismatch", Message: "id must match the template ID in the path"})
+	}
+
+	t, err := h.templateRepo.FindByID(r.Context(), id)
+	if err != nil {
+		return templateError(err)
+	}
+	t.Name = payload.Name
+	t.Description = payload.Description
+	t.Icon = payload.Icon
+	t.Schema = payload.Schema
+	t.SampleData = payload.SampleData
+	t.Workspace = payload.Workspace
+	if payload.Category != "" {
+		t.Category = payload.Category
+	}
+	t.UpdatedAt = time.Now()
+	if err := h.templateRepo.Update(r.Context(), t); err != nil {
+		return templateError(err)
+	}
+
+	writeJSON(w, http.StatusOK, t)
+	return nil
+}
+
+// DeleteTemplateAPIHandler deletes a template. Built-in templates are
+// refused, as the next startup would restore them.
+func (h *Handlers) DeleteTemplateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	t, err := h.templateRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return templateError(err)
+	}
+	builtIn, err := repository.IsBuiltIn(t)
+	if err != nil {
+		return apierror.Internal(err)
+	}
+	if builtIn {
+		return apierror.New(http.StatusConflict, "template_builtin", "Built-in templates cannot be deleted")
+	}
+	if err := h.templateRepo.Delete(r.Context(), t.ID); err != nil {
+		return templateError(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "success"})
+	return nil
+}
+
+// SaveTableAsTemplateAPIHandler saves the schema of the table {id} as a
+// custom template, optionally with up to 50 of its records as sample data
+func (h *Handlers) SaveTableAsTemplateAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	tableID := r.PathValue("id")
+	var payload SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		return apierror.InvalidJSON(err)
+	}
+	if payload.SampleRows < 0 || payload.SampleRows > maxSampleRows {
+		return apierror.Validation("Invalid sample_rows",
+			apierror.FieldError{Field: "sample_rows", Code: "out_of_range", Message: "sample_rows must be between 0 and 50"})
+	}
+
+	tbl, err := h.tableRepo.FindByID(r.Context(), tableID)
+	if err != nil {
+		return tableError(err)
+	}
+	if payload.ID == "" {
+		payload.ID = strings.ToLower(tbl.ID)
+	}
+	if payload.Name == "" {
+		payload.Name = tbl.Name
+	}
+	if payload.Description == "" {
+		payload.Description = tbl.Description
+	}
+
+	t := schematemplate.NewSchemaTemplate(payload.ID, payload.Name, payload.Description, schematemplate.CategoryCustom, payload.Icon, tbl.Schema)
+	t.Workspace = payload.Workspace
+	if payload.SampleRows > 0 {
+		recs, err := h.recordRepo.FindByTable(r.Context(), tableID, record.Page{Limit: payload.SampleRows})
+		if err != nil {
+			return tableError(err)
+		}
+		rows := make([]map[string]interface{}, len(recs))
+		for i, rec := range recs {
+			rows[i] = rec.Data
+		}
+		if t.SampleData, err = json.Marshal(rows); err != nil {
+			return apierror.Internal(err)
+		}
+	}
+	if err := h.templateRepo.Create(r.Context(), t); err != nil {
+		return templateError(err)
+	}
+
+	writeJSON(w, http.StatusCreated, t)
+	return nil
+}
+
+// ExportTemplatesAPIHandler downloads templates as a JSON file: the ?id=
+// templates, or every custom template of the ?workspace= workspace
+func (h *Handlers) ExportTemplatesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	var templates []*schematemplate.SchemaTemplate
+	if ids := r.URL.Query()["id"]; len(ids) > 0 {
+		for _, id := range ids {
+			t, err := h.templateRepo.FindByID(r.Context(), id)
+			if err != nil {
+				return templateError(err)
+			}
+			templates = append(templates, t)
+		}
+	} else {
+		visible, err := h.visibleTemplates(r)
+		if err != nil {
+			return err
+		}
+		for _, t := range visible {
+			if t.Category == schematemplate.CategoryCustom {
+				templates = append(templates, t)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=templates.json")
+	return schematemplate.WriteExport(w, templates)
+}
+
+// ImportTemplatesAPIHandler creates the templates of an export file as
+// custom templates of the ?workspace= workspace. Existing IDs are a
+// conflict unless ?overwrite=true, which replaces custom templates of the
+// same workspace. The templates are written in one transaction, so nothing
+// is imported when any of them is rejected.
+func (h *Handlers) ImportTemplatesAPIHandler(w http.ResponseWriter, r *http.Request) error {
+	templates, err := schematemplate.ReadExport(r.Body)
+	if err != nil {
+		return templateError(err)
+	}
+	if len(templates) == 0 {
+		return apierror.Validation("No templates to import",
+			apierror.FieldError{Field: "templates", Code: "required", Message: "the file contains no templates"})
+	}
+	workspace := r.URL.Query().Get("workspace")
+	overwrite := r.URL.Query().Get("overwrite") == "true"
+
+	// Check everything first so that a bad file imports nothing
+	var fields []apierror.FieldError
+	var conflicts []string
+	var creates, updates []*schematemplate.SchemaTemplate
+	seen := make(map[string]bool, len(templates))
+	now := time.Now()
+	for _, t := range templates {
+		t.Category = schematemplate.CategoryCustom
+		t.Workspace = workspace
+		t.Version, t.Checksum = 0, ""
+		t.CreatedAt, t.UpdatedAt = now, now
+		if err := t.Validate(); err != nil {
+			fields = append(fields, apierror.FieldError{Field: t.ID, Code: "invalid", Message: err.Error()})
+			continue
+		}
+		if seen[t.ID] {
+			fields = append(fields, apierror.FieldError{Field: t.ID, Code: "duplicate", Message: "the file contains this template ID more than once"})
+			continue
+		}
+		seen[t.ID] = true
+		existing, err := h.templateRepo.FindByID(r.Context(), t.ID)
+		switch {
+		case errors.Is(err, schematemplate.ErrNotFound):
+			creates = append(creates, t)
+		case err != nil:
+			return templateError(err)
+		case overwrite && existing.Category == schematemplate.CategoryCustom && existing.Workspace == workspace:
+			t.CreatedAt = existing.CreatedAt
+			updates = append(updates, t)
+		default:
+			conflicts = append(conflicts, t.ID)
+		}
+	}
+	if len(fields) > 0 {
+		return apierror.Validation("Invalid templates", fields...)
+	}
+	if len(conflicts) > 0 {
+		return apierror.Newf(http.StatusConflict, "template_exists",
+			"Templates already exist: %s", strings.Join(conflicts, ", ")).With("ids", conflicts)
+	}
+
+	if err := h.templateRepo.Import(r.Context(), creates, updates); err != nil {
+		return templateError(err)
+	}
+	imported := make([]string, len(templates))
+	for i, t := range templates {
+		imported[i] = t.ID
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"imported": imported, "updated": len(updates)})
+	return nil
+}
+
+// templateError maps template domain errors to API errors
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, schematemplate.ErrNotFound):
+		return apierror.NotFound("template_not_found", "Template not found")
+	case errors.Is(err, schematemplate.ErrAlreadyExists):
+		return apierror.New(http.StatusConflict, "template_exists", err.Error())
+	case errors.Is(err, schematemplate.ErrInvalidInput):
+		return apierror.New(http.StatusBadRequest, apierror.CodeValidation, err.Error())
+	default:
+		return apierror.Internal(err)
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/internal/apierror"
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/schematemplate"
+	"progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+const testSchema = `{"type": "object", "properties": {"name": {"type": "string"}}}`
+
+// serve sends a request to handler mounted at pattern, "METHOD /path", so
+// that its path values are set
+func serve(t *testing.T, handler apierror.HandlerFunc, pattern, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	method, _, _ := strings.Cut(pattern, " ")
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func listTemplateIDs(t *testing.T, h *Handlers, query string) map[string]bool {
+	t.Helper()
+	rec := serve(t, h.TemplatesAPIHandler, "GET /templates", "/templates"+query, "")
+	var templates []schematemplate.SchemaTemplate
+	if err := json.NewDecoder(rec.Body).Decode(&templates); err != nil {
+		t.Fatalf("Failed to decode templates: %v", err)
+	}
+	ids := make(map[string]bool, len(templates))
+	for _, tmpl := range templates {
+		ids[tmpl.ID] = true
+	}
+	return ids
+}
+
+func TestTemplateCRUD(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		h := NewHandlers(store)
+
+		body := `{"id": "items", "name": "Items", "schema": ` + testSchema + `, "workspace": "team-a"}`
+		rec := serve(t, h.CreateTemplateAPIHandler, "POST /templates", "/templates", body)
+		if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"category":"custom"`) {
+			t.Fatalf("Expected 201 with a custom template, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := serve(t, h.CreateTemplateAPIHandler, "POST /templates", "/templates", body); rec.Code != http.StatusConflict {
+			t.Errorf("Expected 409 for a duplicate ID, got %d", rec.Code)
+		}
+		invalid := `{"id": "empty", "name": "Empty", "schema": {"type": "object", "properties": {}}}`
+		if rec := serve(t, h.CreateTemplateAPIHandler, "POST /templates", "/templates", invalid); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for an invalid schema, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if ids := listTemplateIDs(t, h, "?workspace=team-a"); !ids["items"] || !ids["game_item"] {
+			t.Errorf("Expected the custom and built-in templates in team-a, got %v", ids)
+		}
+		if ids := listTemplateIDs(t, h, "?workspace=team-b"); ids["items"] {
+			t.Errorf("Expected no team-a templates in team-b, got %v", ids)
+		}
+		if ids := listTemplateIDs(t, h, "?workspace=team-a&category=custom"); len(ids) != 1 {
+			t.Errorf("Expected only the custom template, got %v", ids)
+		}
+
+		update := `{"name": "Renamed", "schema": ` + testSchema + `, "workspace": "team-a"}`
+		rec = serve(t, h.UpdateTemplateAPIHandler, "PUT /templates/{id}", "/templates/items", update)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Renamed") {
+			t.Errorf("Expected 200 with the new name, got %d: %s", rec.Code, rec.Body.String())
+		}
+		mismatch := `{"id": "other", "name": "Other", "schema": ` + testSchema + `}`
+		if rec := serve(t, h.UpdateTemplateAPIHandler, "PUT /templates/{id}", "/templates/items", mismatch); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for a mismatched ID, got %d", rec.Code)
+		}
+
+		if rec := serve(t, h.DeleteTemplateAPIHandler, "DELETE /templates/{id}", "/templates/items", ""); rec.Code != http.StatusOK {
+			t.Errorf("Expected 200 deleting, got %d: %s", rec.Code, rec.Body.String())
+		}
+		rec = serve(t, h.GetTemplateAPIHandler, "GET /templates/{id}", "/templates/items", "")
+		if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "template_not_found") {
+			t.Errorf("Expected template_not_found, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestDeleteTemplateThenSync(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		h := NewHandlers(store)
+		ctx := context.Background()
+
+		// Deleting a built-in would be undone by the next sync, so it is refused
+		rec := serve(t, h.DeleteTemplateAPIHandler, "DELETE /templates/{id}", "/templates/game_item", "")
+		if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "template_builtin") {
+			t.Errorf("Expected 409 deleting a built-in template, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		body := `{"id": "items", "name": "Items", "schema": ` + testSchema + `}`
+		if rec := serve(t, h.CreateTemplateAPIHandler, "POST /templates", "/templates", body); rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := serve(t, h.DeleteTemplateAPIHandler, "DELETE /templates/{id}", "/templates/items", ""); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200 deleting a custom template, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		report, err := repository.SyncDefaults(ctx, store.Templates)
+		if err != nil || len(report.Created) != 0 {
+			t.Errorf("Expected nothing to be re-created, got %+v (%v)", report, err)
+		}
+		if _, err := store.Templates.FindByID(ctx, "game_item"); err != nil {
+			t.Errorf("Expected the built-in template to remain, got: %v", err)
+		}
+		if _, err := store.Templates.FindByID(ctx, "items"); err == nil {
+			t.Error("Expected the deleted custom template to stay deleted")
+		}
+	})
+}
+
+func TestSaveTableAsTemplate(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		h := NewHandlers(store)
+		if err := store.Tables.Create(ctx, table.NewTable("Stock", "Stock", "Items in stock", json.RawMessage(testSchema))); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+		for _, name := range []string{"sword", "shield", "bow"} {
+			if err := store.Records.Create(ctx, record.NewRecord("Stock", map[string]interface{}{"name": name})); err != nil {
+				t.Fatalf("Failed to create record: %v", err)
+			}
+		}
+
+		if rec := serve(t, h.SaveTableAsTemplateAPIHandler, "POST /tables/{id}/template", "/tables/Stock/template", `{"sample_rows": 51}`); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for too many sample rows, got %d", rec.Code)
+		}
+		if rec := serve(t, h.SaveTableAsTemplateAPIHandler, "POST /tables/{id}/template", "/tables/missing/template", `{}`); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for a missing table, got %d", rec.Code)
+		}
+
+		rec := serve(t, h.SaveTableAsTemplateAPIHandler, "POST /tables/{id}/template", "/tables/Stock/template", `{"workspace": "team-a", "sample_rows": 2}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		saved, err := store.Templates.FindByID(ctx, "stock")
+		if err != nil {
+			t.Fatalf("Expected the template to be saved: %v", err)
+		}
+		var rows []map[string]interface{}
+		if saved.Name != "Stock" || saved.Description != "Items in stock" || saved.Workspace != "team-a" ||
+			json.Unmarshal(saved.SampleData, &rows) != nil || len(rows) != 2 {
+			t.Errorf("Unexpected template: %+v", saved)
+		}
+	})
+}
+
+func TestTemplateExportImport(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		h := NewHandlers(store)
+		body := `{"id": "items", "name": "Items", "schema": ` + testSchema + `, "workspace": "team-a"}`
+		if rec := serve(t, h.CreateTemplateAPIHandler, "POST /templates", "/templates", body); rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec := serve(t, h.ExportTemplatesAPIHandler, "GET /templates/export", "/templates/export?workspace=team-a", "")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Disposition"), "attachment") {
+			t.Fatalf("Expected an attachment, got %d: %v", rec.Code, rec.Header())
+		}
+		exported := rec.Body.String()
+		if !strings.Contains(exported, `"items"`) || strings.Contains(exported, `"game_item"`) {
+			t.Errorf("Expected only the workspace's custom templates: %s", exported)
+		}
+
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-b", exported)
+		if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "template_exists") {
+			t.Errorf("Expected 409 for an existing ID, got %d: %s", rec.Code, rec.Body.String())
+		}
+		// Overwriting is limited to the workspace's own custom templates
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-b&overwrite=true", exported)
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected 409 overwriting another workspace's template, got %d", rec.Code)
+		}
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-a&overwrite=true", exported)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"updated":1`) {
+			t.Errorf("Expected the template to be replaced, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		renamed := strings.Replace(exported, `"id": "items"`, `"id": "items_copy"`, 1)
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-b", renamed)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if ids := listTemplateIDs(t, h, "?workspace=team-b"); !ids["items_copy"] || ids["items"] {
+			t.Errorf("Expected the imported template in team-b, got %v", ids)
+		}
+
+		// A file with an invalid template imports nothing
+		bad := `[{"id": "good", "name": "Good", "schema": ` + testSchema + `}, {"id": "bad", "name": "Bad"}]`
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-b", bad)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if _, err := store.Templates.FindByID(context.Background(), "good"); err == nil {
+			t.Error("Expected nothing to be imported from an invalid file")
+		}
+
+		// A repeated ID is rejected before either copy is written
+		repeated := `[{"id": "twice", "name": "First", "schema": ` + testSchema + `}, {"id": "twice", "name": "Second", "schema": ` + testSchema + `}]`
+		rec = serve(t, h.ImportTemplatesAPIHandler, "POST /templates/import", "/templates/import?workspace=team-b", repeated)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "duplicate") {
+			t.Errorf("Expected 400 for a repeated ID, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if _, err := store.Templates.FindByID(context.Background(), "twice"); err == nil {
+			t.Error("Expected nothing to be imported from a file with a repeated ID")
+		}
+	})
+}
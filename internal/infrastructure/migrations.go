@@ -1,200 +1,476 @@
 package infrastructure
 
 import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
-// Migration represents a database migration
+// migrationFiles holds numbered up/down pairs per dialect:
+//
+//	migrations/<dialect>/NNN_name.up.sql
+//	migrations/<dialect>/NNN_name.down.sql
+//
+// The SQLite set mirrors the PostgreSQL set one to one. JSONB columns become
+// TEXT (checked with json_valid where the repositories write through json()),
+// arrays become JSON arrays and PL/pgSQL functions become triggers.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the advisory lock key held while migrating PostgreSQL
+const migrationLockKey = 0x6d696772 // "migr"
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrUnknownMigration = errors.New("applied migration has no file")
+)
+
+// Migration is one numbered up/down pair
 type Migration struct {
-	ID          int       `db:"id"`
-	Name        string    `db:"name"`
-	AppliedAt   time.Time `db:"applied_at"`
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
 }
 
-// RunMigrations executes all database migrations
-func RunMigrations(db *sqlx.DB) error {
-	log.Println("🔄 Running database migrations...")
+// MigrationStatus describes a migration and whether it is applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool
+}
 
-	// Create migrations table if not exists
-	if err := createMigrationsTable(db); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	Version   int       `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// dialect holds the per-backend SQL the migrator itself needs
+type dialect struct {
+	dir          string
+	createTable  string
+	legacyExists string
+	lock         func(ctx context.Context, db *sqlx.DB) (unlock func(), err error)
+}
+
+var postgresDialect = dialect{
+	dir: "migrations/postgres",
+	createTable: `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) UNIQUE NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+	)`,
+	legacyExists: `SELECT to_regclass('migrations') IS NOT NULL`,
+	lock:         postgresAdvisoryLock,
+}
+
+var sqliteDialect = dialect{
+	dir: "migrations/sqlite",
+	createTable: `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) UNIQUE NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`,
+	legacyExists: `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'migrations'`,
+	// SQLite serializes writers on the database file; each migration's
+	// transaction re-checks schema_migrations before applying.
+	lock: func(ctx context.Context, db *sqlx.DB) (func(), error) { return func() {}, nil },
+}
 
-	// Run each migration
-	migrations := getMigrations()
-	for _, migration := range migrations {
-		if err := runMigration(db, migration.name, migration.query); err != nil {
-			return fmt.Errorf("failed to run migration %s: %w", migration.name, err)
+// postgresAdvisoryLock holds a session-level advisory lock on a dedicated
+// connection so concurrently starting instances migrate one at a time
+func postgresAdvisoryLock(ctx context.Context, db *sqlx.DB) (func(), error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			slog.Warn("failed to release migration lock", slog.Any("error", err))
 		}
+		conn.Close()
+	}, nil
+}
+
+// Migrator applies and rolls back the embedded migrations
+type Migrator struct {
+	db         *sqlx.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations for the database's driver
+func NewMigrator(db *sqlx.DB) (*Migrator, error) {
+	d := postgresDialect
+	if db.DriverName() == SQLiteDriver {
+		d = sqliteDialect
 	}
+	migrations, err := loadMigrations(migrationFiles, d.dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
 
-	log.Println("✅ All migrations completed successfully")
+// RunMigrations applies every pending migration
+func RunMigrations(db *sqlx.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		return err
+	}
+
+	slog.Info("migrations completed")
 	return nil
 }
 
-func createMigrationsTable(db *sqlx.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS migrations (
-		id SERIAL PRIMARY KEY,
-		name VARCHAR(255) UNIQUE NOT NULL,
-		applied_at TIMESTAMP NOT NULL DEFAULT NOW()
-	)`
-	_, err := db.Exec(query)
-	return err
+// Migrations returns the embedded migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
 }
 
-func runMigration(db *sqlx.DB, name string, query string) error {
-	// Check if migration already applied
-	var count int
-	err := db.Get(&count, "SELECT COUNT(*) FROM migrations WHERE name = $1", name)
+// Up applies pending migrations up to and including target (0 for all)
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, true, func(applied map[int]appliedMigration) error {
+		for _, migration := range m.migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			ran, err := m.apply(ctx, migration)
+			if err != nil {
+				return fmt.Errorf("failed to run migration %s: %w", migration.Name, err)
+			}
+			if ran {
+				slog.InfoContext(ctx, "applied migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+				done = append(done, migration)
+			}
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the most recently applied migrations, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, true, func(applied map[int]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, migration); err != nil {
+				return fmt.Errorf("failed to roll back migration %s: %w", migration.Name, err)
+			}
+			slog.InfoContext(ctx, "rolled back migration", slog.Int("version", migration.Version), slog.String("name", migration.Name))
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo rolls back the most recently applied migration and applies it again
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	reverted, err := m.Down(ctx, 1)
+	if err != nil || len(reverted) == 0 {
+		return nil, err
+	}
+	if _, err := m.Up(ctx, reverted[0].Version); err != nil {
+		return nil, err
+	}
+	return &reverted[0], nil
+}
+
+// Status lists every migration with its applied state. Modified migrations
+// are reported rather than rejected so the status command can show them.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.locked(ctx, false, func(applied map[int]appliedMigration) error {
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if row, ok := applied[migration.Version]; ok {
+				appliedAt := row.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Modified = row.Checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Pending returns the migrations not applied yet. Unlike Status it takes no
+// lock and creates nothing, so it is cheap enough for readiness checks.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// locked runs fn under the migration lock with the applied migrations,
+// optionally rejecting modified or unknown ones first
+func (m *Migrator) locked(ctx context.Context, verify bool, fn func(applied map[int]appliedMigration) error) error {
+	// The table is created under the lock too: concurrent CREATE TABLE IF
+	// NOT EXISTS can fail on PostgreSQL
+	unlock, err := m.dialect.lock(ctx, m.db)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.ensureTable(ctx); err != nil {
+		return err
+	}
+
+	if err := m.importLegacy(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
 	if err != nil {
 		return err
 	}
+	if verify {
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+	}
+	return fn(applied)
+}
 
+// verify rejects applied migrations whose file changed or disappeared
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+	for version, row := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownMigration, row.Name)
+		}
+		if row.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %s (applied %s, file %s)", ErrChecksumMismatch, row.Name, row.Checksum[:12], migration.Checksum[:12])
+		}
+	}
+	return nil
+}
+
+// apply runs an up migration and records it in one transaction. It reports
+// false when another instance applied the migration first.
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.GetContext(ctx, &count, tx.Rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), migration.Version); err != nil {
+		return false, err
+	}
 	if count > 0 {
-		log.Printf("⏭️  Migration '%s' already applied, skipping", name)
-		return nil
+		return false, nil
 	}
 
-	// Run migration in transaction
-	tx, err := db.Beginx()
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return false, err
+	}
+	insert := `INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, tx.Rebind(insert), migration.Version, migration.Name, migration.Checksum, time.Now().UTC()); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// revert runs a down migration and removes its record in one transaction
+func (m *Migrator) revert(ctx context.Context, migration Migration) error {
+	tx, err := m.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	// Execute migration query
-	if _, err := tx.Exec(query); err != nil {
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(`DELETE FROM schema_migrations WHERE version = ?`), migration.Version); err != nil {
 		return err
 	}
+	return tx.Commit()
+}
 
-	// Record migration
-	if _, err := tx.Exec("INSERT INTO migrations (name) VALUES ($1)", name); err != nil {
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, m.dialect.createTable); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	var rows []appliedMigration
+	query := `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`
+	if err := m.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	applied := make(map[int]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// importLegacy adopts databases migrated before schema_migrations existed.
+// The old runner tracked migrations by name only in a "migrations" table;
+// matching names are recorded with the checksum of today's file.
+func (m *Migrator) importLegacy(ctx context.Context) error {
+	var count int
+	if err := m.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	var legacy bool
+	if err := m.db.GetContext(ctx, &legacy, m.dialect.legacyExists); err != nil {
+		return fmt.Errorf("failed to check legacy migrations table: %w", err)
+	}
+	if count > 0 || !legacy {
+		return nil
+	}
+
+	var rows []struct {
+		Name      string    `db:"name"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+	if err := m.db.SelectContext(ctx, &rows, `SELECT name, applied_at FROM migrations`); err != nil {
+		return fmt.Errorf("failed to read legacy migrations: %w", err)
+	}
+	byName := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byName[migration.Name] = migration
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
+	insert := tx.Rebind(`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`)
+	for _, row := range rows {
+		migration, ok := byName[row.Name]
+		if !ok {
+			return fmt.Errorf("%w: %s (legacy)", ErrUnknownMigration, row.Name)
+		}
+		if _, err := tx.ExecContext(ctx, insert, migration.Version, migration.Name, migration.Checksum, row.AppliedAt.UTC()); err != nil {
+			return fmt.Errorf("failed to import legacy migration %s: %w", row.Name, err)
+		}
+	}
 	if err := tx.Commit(); err != nil {
 		return err
 	}
-
-	log.Printf("✅ Applied migration: %s", name)
+	if len(rows) > 0 {
+		slog.InfoContext(ctx, "imported migrations from the legacy migrations table", slog.Int("count", len(rows)))
+	}
 	return nil
 }
 
-type migrationDef struct {
-	name  string
-	query string
-}
-
-func getMigrations() []migrationDef {
-	return []migrationDef{
-		{
-			name: "001_create_tables",
-			query: `
-				-- Tables table stores JSON Schema-based table definitions
-				CREATE TABLE IF NOT EXISTS tables (
-					id VARCHAR(255) PRIMARY KEY,
-					name VARCHAR(255) NOT NULL,
-					description TEXT,
-					schema JSONB NOT NULL,
-					created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-					updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-				);
-
-				-- Create index on name for faster lookups
-				CREATE INDEX IF NOT EXISTS idx_tables_name ON tables(name);
-
-				-- Records table stores the actual data for each table
-				CREATE TABLE IF NOT EXISTS records (
-					id SERIAL PRIMARY KEY,
-					table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
-					data JSONB NOT NULL,
-					created_at TIMESTAMP NOT NULL DEFAULT NOW(),
-					updated_at TIMESTAMP NOT NULL DEFAULT NOW()
-				);
-
-				-- Create index on table_id for faster queries
-				CREATE INDEX IF NOT EXISTS idx_records_table_id ON records(table_id);
-				
-				-- Create GIN index on data for efficient JSONB queries
-				CREATE INDEX IF NOT EXISTS idx_records_data_gin ON records USING GIN (data);
-			`,
-		},
-		{
-			name: "002_add_templates",
-			query: `
-				-- Templates table stores reusable table templates
-				CREATE TABLE IF NOT EXISTS templates (
-					id VARCHAR(255) PRIMARY KEY,
-					name VARCHAR(255) NOT NULL,
-					description TEXT,
-					category VARCHAR(100) NOT NULL,
-					icon VARCHAR(100),
-					schema JSONB NOT NULL,
-					sample_data JSONB,
-					created_at TIMESTAMP NOT NULL DEFAULT NOW()
-				);
-
-				-- Create index on category for filtering
-				CREATE INDEX IF NOT EXISTS idx_templates_category ON templates(category);
-			`,
-		},
-		{
-			name: "003_add_metadata",
-			query: `
-				-- Add metadata columns to tables
-				ALTER TABLE tables 
-				ADD COLUMN IF NOT EXISTS record_count INTEGER DEFAULT 0,
-				ADD COLUMN IF NOT EXISTS last_accessed TIMESTAMP,
-				ADD COLUMN IF NOT EXISTS tags TEXT[];
-
-				-- Create index on tags for searching
-				CREATE INDEX IF NOT EXISTS idx_tables_tags_gin ON tables USING GIN (tags);
-
-				-- Create function to update record count
-				CREATE OR REPLACE FUNCTION update_table_record_count() 
-				RETURNS TRIGGER AS $$
-				BEGIN
-					IF TG_OP = 'INSERT' THEN
-						UPDATE tables 
-						SET record_count = record_count + 1,
-						    last_accessed = NOW()
-						WHERE id = NEW.table_id;
-					ELSIF TG_OP = 'DELETE' THEN
-						UPDATE tables 
-						SET record_count = record_count - 1,
-						    last_accessed = NOW()
-						WHERE id = OLD.table_id;
-					END IF;
-					RETURN NULL;
-				END;
-				$$ LANGUAGE plpgsql;
-
-				-- Create triggers for record count
-				DROP TRIGGER IF EXISTS update_record_count_on_insert ON records;
-				CREATE TRIGGER update_record_count_on_insert
-					AFTER INSERT ON records
-					FOR EACH ROW
-					EXECUTE FUNCTION update_table_record_count();
-
-				DROP TRIGGER IF EXISTS update_record_count_on_delete ON records;
-				CREATE TRIGGER update_record_count_on_delete
-					AFTER DELETE ON records
-					FOR EACH ROW
-					EXECUTE FUNCTION update_table_record_count();
-
-				-- Update existing record counts
-				UPDATE tables t
-				SET record_count = (
-					SELECT COUNT(*) 
-					FROM records r 
-					WHERE r.table_id = t.id
-				);
-			`,
-		},
-	}
-}
\ No newline at end of file
+// loadMigrations reads NNN_name.up.sql / NNN_name.down.sql pairs from dir
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(file, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(file, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		name := strings.TrimSuffix(file, "."+direction+".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %s must start with a positive version number", file)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+		sqlText := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = sqlText
+			sum := sha256.Sum256([]byte(sqlText))
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = sqlText
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
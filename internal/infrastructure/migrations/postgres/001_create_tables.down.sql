@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS records;
+DROP TABLE IF EXISTS tables;
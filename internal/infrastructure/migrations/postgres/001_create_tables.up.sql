@@ -0,0 +1,27 @@
+-- Tables table stores JSON Schema-based table definitions
+CREATE TABLE IF NOT EXISTS tables (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	schema JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create index on name for faster lookups
+CREATE INDEX IF NOT EXISTS idx_tables_name ON tables(name);
+
+-- Records table stores the actual data for each table
+CREATE TABLE IF NOT EXISTS records (
+	id SERIAL PRIMARY KEY,
+	table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	data JSONB NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create index on table_id for faster queries
+CREATE INDEX IF NOT EXISTS idx_records_table_id ON records(table_id);
+
+-- Create GIN index on data for efficient JSONB queries
+CREATE INDEX IF NOT EXISTS idx_records_data_gin ON records USING GIN (data);
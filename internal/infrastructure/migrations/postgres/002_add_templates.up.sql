@@ -0,0 +1,14 @@
+-- Templates table stores reusable table templates
+CREATE TABLE IF NOT EXISTS templates (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	category VARCHAR(100) NOT NULL,
+	icon VARCHAR(100),
+	schema JSONB NOT NULL,
+	sample_data JSONB,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Create index on category for filtering
+CREATE INDEX IF NOT EXISTS idx_templates_category ON templates(category);
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS update_record_count_on_insert ON records;
+DROP TRIGGER IF EXISTS update_record_count_on_delete ON records;
+DROP FUNCTION IF EXISTS update_table_record_count();
+
+DROP INDEX IF EXISTS idx_tables_tags_gin;
+
+ALTER TABLE tables
+DROP COLUMN IF EXISTS record_count,
+DROP COLUMN IF EXISTS last_accessed,
+DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,48 @@
+-- Add metadata columns to tables
+ALTER TABLE tables 
+ADD COLUMN IF NOT EXISTS record_count INTEGER DEFAULT 0,
+ADD COLUMN IF NOT EXISTS last_accessed TIMESTAMP,
+ADD COLUMN IF NOT EXISTS tags TEXT[];
+
+-- Create index on tags for searching
+CREATE INDEX IF NOT EXISTS idx_tables_tags_gin ON tables USING GIN (tags);
+
+-- Create function to update record count
+CREATE OR REPLACE FUNCTION update_table_record_count() 
+RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		UPDATE tables 
+		SET record_count = record_count + 1,
+			last_accessed = NOW()
+		WHERE id = NEW.table_id;
+	ELSIF TG_OP = 'DELETE' THEN
+		UPDATE tables 
+		SET record_count = record_count - 1,
+			last_accessed = NOW()
+		WHERE id = OLD.table_id;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create triggers for record count
+DROP TRIGGER IF EXISTS update_record_count_on_insert ON records;
+CREATE TRIGGER update_record_count_on_insert
+	AFTER INSERT ON records
+	FOR EACH ROW
+	EXECUTE FUNCTION update_table_record_count();
+
+DROP TRIGGER IF EXISTS update_record_count_on_delete ON records;
+CREATE TRIGGER update_record_count_on_delete
+	AFTER DELETE ON records
+	FOR EACH ROW
+	EXECUTE FUNCTION update_table_record_count();
+
+-- Update existing record counts
+UPDATE tables t
+SET record_count = (
+	SELECT COUNT(*) 
+	FROM records r 
+	WHERE r.table_id = t.id
+);
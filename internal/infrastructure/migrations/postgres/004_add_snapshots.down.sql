@@ -0,0 +1,11 @@
+-- The immutability triggers would block dropping rows, so drop them first
+DROP TRIGGER IF EXISTS snapshots_guard ON snapshots;
+DROP TRIGGER IF EXISTS snapshot_records_immutable ON snapshot_records;
+DROP TRIGGER IF EXISTS snapshot_tables_immutable ON snapshot_tables;
+DROP FUNCTION IF EXISTS guard_snapshot_update();
+DROP FUNCTION IF EXISTS prevent_snapshot_content_change();
+
+DROP TABLE IF EXISTS promotions;
+DROP TABLE IF EXISTS snapshot_records;
+DROP TABLE IF EXISTS snapshot_tables;
+DROP TABLE IF EXISTS snapshots;
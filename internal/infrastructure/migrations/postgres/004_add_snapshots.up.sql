@@ -0,0 +1,101 @@
+-- Snapshots are named, immutable copies of one or more tables
+CREATE TABLE IF NOT EXISTS snapshots (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) UNIQUE NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	environment VARCHAR(20) NOT NULL DEFAULT 'dev'
+		CHECK (environment IN ('dev', 'qa', 'live')),
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Table definitions as captured (no FK to tables: snapshots outlive deleted tables)
+CREATE TABLE IF NOT EXISTS snapshot_tables (
+	snapshot_id VARCHAR(255) NOT NULL REFERENCES snapshots(id),
+	table_id VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	schema JSONB NOT NULL,
+	record_count INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (snapshot_id, table_id)
+);
+
+-- Records as captured, keeping their live record IDs for diffs
+CREATE TABLE IF NOT EXISTS snapshot_records (
+	snapshot_id VARCHAR(255) NOT NULL,
+	table_id VARCHAR(255) NOT NULL,
+	record_id INTEGER NOT NULL,
+	data JSONB NOT NULL,
+	PRIMARY KEY (snapshot_id, table_id, record_id),
+	FOREIGN KEY (snapshot_id, table_id) REFERENCES snapshot_tables(snapshot_id, table_id)
+);
+
+-- Promotion requests move a snapshot dev -> qa -> live after approval
+CREATE TABLE IF NOT EXISTS promotions (
+	id SERIAL PRIMARY KEY,
+	snapshot_id VARCHAR(255) NOT NULL REFERENCES snapshots(id),
+	from_environment VARCHAR(20) NOT NULL,
+	to_environment VARCHAR(20) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending'
+		CHECK (status IN ('pending', 'approved', 'rejected')),
+	requested_by VARCHAR(255) NOT NULL,
+	requested_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	comment TEXT NOT NULL DEFAULT '',
+	reviewed_by VARCHAR(255),
+	reviewed_at TIMESTAMP,
+	review_comment TEXT,
+	CHECK (status <> 'approved' OR reviewed_by <> requested_by)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_snapshot_id ON promotions(snapshot_id);
+
+-- Only one open promotion per snapshot
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_pending
+	ON promotions(snapshot_id) WHERE status = 'pending';
+
+-- Captured contents can never change
+CREATE OR REPLACE FUNCTION prevent_snapshot_content_change()
+RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'snapshot contents are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS snapshot_tables_immutable ON snapshot_tables;
+CREATE TRIGGER snapshot_tables_immutable
+	BEFORE UPDATE OR DELETE ON snapshot_tables
+	FOR EACH ROW
+	EXECUTE FUNCTION prevent_snapshot_content_change();
+
+DROP TRIGGER IF EXISTS snapshot_records_immutable ON snapshot_records;
+CREATE TRIGGER snapshot_records_immutable
+	BEFORE UPDATE OR DELETE ON snapshot_records
+	FOR EACH ROW
+	EXECUTE FUNCTION prevent_snapshot_content_change();
+
+-- Snapshots may only move forward one environment at a time
+CREATE OR REPLACE FUNCTION guard_snapshot_update()
+RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		RAISE EXCEPTION 'snapshots cannot be deleted';
+	END IF;
+	IF NEW.id <> OLD.id OR NEW.name <> OLD.name OR NEW.description <> OLD.description
+		OR NEW.created_by <> OLD.created_by OR NEW.created_at <> OLD.created_at THEN
+		RAISE EXCEPTION 'snapshot % is immutable', OLD.id;
+	END IF;
+	IF NEW.environment <> OLD.environment AND NOT (
+		(OLD.environment = 'dev' AND NEW.environment = 'qa') OR
+		(OLD.environment = 'qa' AND NEW.environment = 'live')
+	) THEN
+		RAISE EXCEPTION 'snapshot % cannot move from % to %', OLD.id, OLD.environment, NEW.environment;
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS snapshots_guard ON snapshots;
+CREATE TRIGGER snapshots_guard
+	BEFORE UPDATE OR DELETE ON snapshots
+	FOR EACH ROW
+	EXECUTE FUNCTION guard_snapshot_update();
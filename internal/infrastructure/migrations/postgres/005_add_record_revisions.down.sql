@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS record_revisions_log ON records;
+DROP FUNCTION IF EXISTS log_record_revision();
+DROP TABLE IF EXISTS record_revisions;
@@ -0,0 +1,44 @@
+-- Record revision log: every insert/update/delete on records
+CREATE TABLE IF NOT EXISTS record_revisions (
+	id BIGSERIAL PRIMARY KEY,
+	table_id VARCHAR(255) NOT NULL,
+	record_id INTEGER NOT NULL,
+	operation VARCHAR(10) NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+	old_data JSONB,
+	new_data JSONB,
+	source VARCHAR(255) NOT NULL DEFAULT 'editor',
+	actor VARCHAR(255) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_revisions_record ON record_revisions(table_id, record_id, id);
+
+-- Writers describe themselves with transaction-local settings:
+--   SELECT set_config('progressive.revision_source', 'merge_request:1', true)
+CREATE OR REPLACE FUNCTION log_record_revision()
+RETURNS TRIGGER AS $$
+DECLARE
+	rev_source TEXT := COALESCE(NULLIF(current_setting('progressive.revision_source', true), ''), 'editor');
+	rev_actor TEXT := COALESCE(current_setting('progressive.revision_actor', true), '');
+BEGIN
+	IF TG_OP = 'INSERT' THEN
+		INSERT INTO record_revisions (table_id, record_id, operation, new_data, source, actor)
+		VALUES (NEW.table_id, NEW.id, 'insert', NEW.data, rev_source, rev_actor);
+	ELSIF TG_OP = 'UPDATE' THEN
+		IF OLD.data IS DISTINCT FROM NEW.data THEN
+			INSERT INTO record_revisions (table_id, record_id, operation, old_data, new_data, source, actor)
+			VALUES (NEW.table_id, NEW.id, 'update', OLD.data, NEW.data, rev_source, rev_actor);
+		END IF;
+	ELSE
+		INSERT INTO record_revisions (table_id, record_id, operation, old_data, source, actor)
+		VALUES (OLD.table_id, OLD.id, 'delete', OLD.data, rev_source, rev_actor);
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS record_revisions_log ON records;
+CREATE TRIGGER record_revisions_log
+	AFTER INSERT OR UPDATE OR DELETE ON records
+	FOR EACH ROW
+	EXECUTE FUNCTION log_record_revision();
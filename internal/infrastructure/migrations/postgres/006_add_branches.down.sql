@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS merge_requests;
+DROP TABLE IF EXISTS branch_records;
+DROP TABLE IF EXISTS branches;
@@ -0,0 +1,43 @@
+-- Comment threads anchored to a table (record_id NULL), a record
+-- (field '') or a single cell. record_id is not a foreign key so
+-- threads survive the editor's replace-all save.
+CREATE TABLE IF NOT EXISTS comment_threads (
+	id BIGSERIAL PRIMARY KEY,
+	table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	record_id INTEGER,
+	field VARCHAR(255) NOT NULL DEFAULT '',
+	resolved BOOLEAN NOT NULL DEFAULT FALSE,
+	resolved_by VARCHAR(255),
+	resolved_at TIMESTAMP,
+	created_by VARCHAR(255) NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	CHECK (field = '' OR record_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_threads_anchor ON comment_threads(table_id, record_id, field);
+CREATE INDEX IF NOT EXISTS idx_comment_threads_open ON comment_threads(table_id) WHERE NOT resolved;
+
+CREATE TABLE IF NOT EXISTS comments (
+	id BIGSERIAL PRIMARY KEY,
+	thread_id BIGINT NOT NULL REFERENCES comment_threads(id) ON DELETE CASCADE,
+	author VARCHAR(255) NOT NULL,
+	body TEXT NOT NULL,
+	mentions TEXT[] NOT NULL DEFAULT '{}',
+	edited BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_thread_id ON comments(thread_id, id);
+CREATE INDEX IF NOT EXISTS idx_comments_mentions ON comments USING GIN (mentions);
+
+-- Previous bodies of edited comments
+CREATE TABLE IF NOT EXISTS comment_edits (
+	id BIGSERIAL PRIMARY KEY,
+	comment_id BIGINT NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	body TEXT NOT NULL,
+	edited_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_edits_comment_id ON comment_edits(comment_id, id);
@@ -0,0 +1,5 @@
+-- Rolling back discards the audit trail; take a backup first
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS prevent_audit_log_change();
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,2 @@
+-- Counts are derived data; nothing to undo
+SELECT 1;
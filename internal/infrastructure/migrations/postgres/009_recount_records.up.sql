@@ -0,0 +1,9 @@
+-- record_count is maintained only by the update_record_count_on_* triggers
+-- from 003. The application used to recount on top of them; repair any
+-- count that drifted while both ran.
+UPDATE tables t
+SET record_count = (
+	SELECT COUNT(*)
+	FROM records r
+	WHERE r.table_id = t.id
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_templates_workspace;
+
+ALTER TABLE templates
+DROP COLUMN IF EXISTS workspace,
+DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,9 @@
+-- Custom templates belong to a workspace; built-in templates have none and
+-- are offered in every workspace
+ALTER TABLE templates
+ADD COLUMN workspace VARCHAR(255) NOT NULL DEFAULT '',
+ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE templates SET updated_at = created_at;
+
+CREATE INDEX IF NOT EXISTS idx_templates_workspace ON templates(workspace);
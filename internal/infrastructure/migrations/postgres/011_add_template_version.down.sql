@@ -0,0 +1,3 @@
+ALTER TABLE templates
+DROP COLUMN IF EXISTS version,
+DROP COLUMN IF EXISTS checksum;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS tables (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	schema TEXT NOT NULL CHECK (json_valid(schema)),
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tables_name ON tables(name);
+
+CREATE TABLE IF NOT EXISTS records (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	data TEXT NOT NULL CHECK (json_valid(data)),
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_records_table_id ON records(table_id);
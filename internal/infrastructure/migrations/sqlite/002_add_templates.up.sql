@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS templates (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	description TEXT,
+	category VARCHAR(100) NOT NULL,
+	icon VARCHAR(100),
+	schema TEXT NOT NULL,
+	sample_data TEXT,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_templates_category ON templates(category);
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_record_count_on_insert;
+DROP TRIGGER IF EXISTS update_record_count_on_delete;
+
+ALTER TABLE tables DROP COLUMN tags;
+ALTER TABLE tables DROP COLUMN last_accessed;
+ALTER TABLE tables DROP COLUMN record_count;
@@ -0,0 +1,26 @@
+ALTER TABLE tables ADD COLUMN record_count INTEGER DEFAULT 0;
+ALTER TABLE tables ADD COLUMN last_accessed DATETIME;
+ALTER TABLE tables ADD COLUMN tags TEXT NOT NULL DEFAULT '[]' CHECK (json_valid(tags));
+
+DROP TRIGGER IF EXISTS update_record_count_on_insert;
+CREATE TRIGGER update_record_count_on_insert
+	AFTER INSERT ON records
+BEGIN
+	UPDATE tables
+	SET record_count = record_count + 1,
+		last_accessed = CURRENT_TIMESTAMP
+	WHERE id = NEW.table_id;
+END;
+
+DROP TRIGGER IF EXISTS update_record_count_on_delete;
+CREATE TRIGGER update_record_count_on_delete
+	AFTER DELETE ON records
+BEGIN
+	UPDATE tables
+	SET record_count = record_count - 1,
+		last_accessed = CURRENT_TIMESTAMP
+	WHERE id = OLD.table_id;
+END;
+
+UPDATE tables
+SET record_count = (SELECT COUNT(*) FROM records r WHERE r.table_id = tables.id);
@@ -0,0 +1,12 @@
+-- The immutability triggers would block dropping rows, so drop them first
+DROP TRIGGER IF EXISTS snapshots_guard;
+DROP TRIGGER IF EXISTS snapshots_no_delete;
+DROP TRIGGER IF EXISTS snapshot_records_immutable_delete;
+DROP TRIGGER IF EXISTS snapshot_records_immutable_update;
+DROP TRIGGER IF EXISTS snapshot_tables_immutable_delete;
+DROP TRIGGER IF EXISTS snapshot_tables_immutable_update;
+
+DROP TABLE IF EXISTS promotions;
+DROP TABLE IF EXISTS snapshot_records;
+DROP TABLE IF EXISTS snapshot_tables;
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS snapshots (
+	id VARCHAR(255) PRIMARY KEY,
+	name VARCHAR(255) UNIQUE NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	environment VARCHAR(20) NOT NULL DEFAULT 'dev'
+		CHECK (environment IN ('dev', 'qa', 'live')),
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_tables (
+	snapshot_id VARCHAR(255) NOT NULL REFERENCES snapshots(id),
+	table_id VARCHAR(255) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	schema TEXT NOT NULL,
+	record_count INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (snapshot_id, table_id)
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_records (
+	snapshot_id VARCHAR(255) NOT NULL,
+	table_id VARCHAR(255) NOT NULL,
+	record_id INTEGER NOT NULL,
+	data TEXT NOT NULL,
+	PRIMARY KEY (snapshot_id, table_id, record_id),
+	FOREIGN KEY (snapshot_id, table_id) REFERENCES snapshot_tables(snapshot_id, table_id)
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	snapshot_id VARCHAR(255) NOT NULL REFERENCES snapshots(id),
+	from_environment VARCHAR(20) NOT NULL,
+	to_environment VARCHAR(20) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending'
+		CHECK (status IN ('pending', 'approved', 'rejected')),
+	requested_by VARCHAR(255) NOT NULL,
+	requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	comment TEXT NOT NULL DEFAULT '',
+	reviewed_by VARCHAR(255),
+	reviewed_at DATETIME,
+	review_comment TEXT,
+	CHECK (status <> 'approved' OR reviewed_by <> requested_by)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_snapshot_id ON promotions(snapshot_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_promotions_pending
+	ON promotions(snapshot_id) WHERE status = 'pending';
+
+DROP TRIGGER IF EXISTS snapshot_tables_immutable_update;
+CREATE TRIGGER snapshot_tables_immutable_update
+	BEFORE UPDATE ON snapshot_tables
+BEGIN
+	SELECT RAISE(ABORT, 'snapshot contents are immutable');
+END;
+
+DROP TRIGGER IF EXISTS snapshot_tables_immutable_delete;
+CREATE TRIGGER snapshot_tables_immutable_delete
+	BEFORE DELETE ON snapshot_tables
+BEGIN
+	SELECT RAISE(ABORT, 'snapshot contents are immutable');
+END;
+
+DROP TRIGGER IF EXISTS snapshot_records_immutable_update;
+CREATE TRIGGER snapshot_records_immutable_update
+	BEFORE UPDATE ON snapshot_records
+BEGIN
+	SELECT RAISE(ABORT, 'snapshot contents are immutable');
+END;
+
+DROP TRIGGER IF EXISTS snapshot_records_immutable_delete;
+CREATE TRIGGER snapshot_records_immutable_delete
+	BEFORE DELETE ON snapshot_records
+BEGIN
+	SELECT RAISE(ABORT, 'snapshot contents are immutable');
+END;
+
+DROP TRIGGER IF EXISTS snapshots_no_delete;
+CREATE TRIGGER snapshots_no_delete
+	BEFORE DELETE ON snapshots
+BEGIN
+	SELECT RAISE(ABORT, 'snapshots cannot be deleted');
+END;
+
+DROP TRIGGER IF EXISTS snapshots_guard;
+CREATE TRIGGER snapshots_guard
+	BEFORE UPDATE ON snapshots
+BEGIN
+	SELECT RAISE(ABORT, 'snapshot is immutable')
+	WHERE NEW.id <> OLD.id OR NEW.name <> OLD.name OR NEW.description <> OLD.description
+		OR NEW.created_by <> OLD.created_by OR NEW.created_at <> OLD.created_at;
+	SELECT RAISE(ABORT, 'snapshot cannot skip or revert an environment')
+	WHERE NEW.environment <> OLD.environment AND NOT (
+		(OLD.environment = 'dev' AND NEW.environment = 'qa') OR
+		(OLD.environment = 'qa' AND NEW.environment = 'live')
+	);
+END;
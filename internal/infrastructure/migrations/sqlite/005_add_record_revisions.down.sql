@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS record_revisions_delete;
+DROP TRIGGER IF EXISTS record_revisions_update;
+DROP TRIGGER IF EXISTS record_revisions_insert;
+DROP TABLE IF EXISTS record_revisions;
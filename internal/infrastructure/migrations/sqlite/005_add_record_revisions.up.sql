@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS record_revisions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	table_id VARCHAR(255) NOT NULL,
+	record_id INTEGER NOT NULL,
+	operation VARCHAR(10) NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+	old_data TEXT,
+	new_data TEXT,
+	source VARCHAR(255) NOT NULL DEFAULT 'editor',
+	actor VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_revisions_record ON record_revisions(table_id, record_id, id);
+
+DROP TRIGGER IF EXISTS record_revisions_insert;
+CREATE TRIGGER record_revisions_insert
+	AFTER INSERT ON records
+BEGIN
+	INSERT INTO record_revisions (table_id, record_id, operation, new_data)
+	VALUES (NEW.table_id, NEW.id, 'insert', NEW.data);
+END;
+
+DROP TRIGGER IF EXISTS record_revisions_update;
+CREATE TRIGGER record_revisions_update
+	AFTER UPDATE ON records
+	WHEN json(OLD.data) IS NOT json(NEW.data)
+BEGIN
+	INSERT INTO record_revisions (table_id, record_id, operation, old_data, new_data)
+	VALUES (NEW.table_id, NEW.id, 'update', OLD.data, NEW.data);
+END;
+
+DROP TRIGGER IF EXISTS record_revisions_delete;
+CREATE TRIGGER record_revisions_delete
+	AFTER DELETE ON records
+BEGIN
+	INSERT INTO record_revisions (table_id, record_id, operation, old_data)
+	VALUES (OLD.table_id, OLD.id, 'delete', OLD.data);
+END;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS branches (
+	id VARCHAR(255) PRIMARY KEY,
+	table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	name VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'merged', 'closed')),
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (table_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS branch_records (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	branch_id VARCHAR(255) NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+	record_id INTEGER,
+	operation VARCHAR(10) NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+	base_data TEXT,
+	data TEXT,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK ((operation = 'insert') = (record_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_branch_records_branch_id ON branch_records(branch_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_branch_records_record
+	ON branch_records(branch_id, record_id) WHERE record_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS merge_requests (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	branch_id VARCHAR(255) NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'merged', 'closed')),
+	created_by VARCHAR(255) NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	merged_by VARCHAR(255),
+	merged_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_merge_requests_open
+	ON merge_requests(branch_id) WHERE status = 'open';
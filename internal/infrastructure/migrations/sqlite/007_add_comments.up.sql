@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS comment_threads (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	table_id VARCHAR(255) NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+	record_id INTEGER,
+	field VARCHAR(255) NOT NULL DEFAULT '',
+	resolved BOOLEAN NOT NULL DEFAULT FALSE,
+	resolved_by VARCHAR(255),
+	resolved_at DATETIME,
+	created_by VARCHAR(255) NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CHECK (field = '' OR record_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_threads_anchor ON comment_threads(table_id, record_id, field);
+CREATE INDEX IF NOT EXISTS idx_comment_threads_open ON comment_threads(table_id) WHERE NOT resolved;
+
+CREATE TABLE IF NOT EXISTS comments (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	thread_id INTEGER NOT NULL REFERENCES comment_threads(id) ON DELETE CASCADE,
+	author VARCHAR(255) NOT NULL,
+	body TEXT NOT NULL,
+	mentions TEXT NOT NULL DEFAULT '[]' CHECK (json_valid(mentions)),
+	edited BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_thread_id ON comments(thread_id, id);
+
+CREATE TABLE IF NOT EXISTS comment_edits (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+	body TEXT NOT NULL,
+	edited_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_edits_comment_id ON comment_edits(comment_id, id);
@@ -0,0 +1,4 @@
+-- Rolling back discards the audit trail; take a backup first
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+DROP TRIGGER IF EXISTS audit_log_no_update;
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_templates_workspace;
+
+ALTER TABLE templates DROP COLUMN updated_at;
+ALTER TABLE templates DROP COLUMN workspace;
@@ -0,0 +1,6 @@
+ALTER TABLE templates ADD COLUMN workspace VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE templates ADD COLUMN updated_at DATETIME;
+
+UPDATE templates SET updated_at = created_at;
+
+CREATE INDEX IF NOT EXISTS idx_templates_workspace ON templates(workspace);
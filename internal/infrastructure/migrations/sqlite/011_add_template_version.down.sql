@@ -0,0 +1,2 @@
+ALTER TABLE templates DROP COLUMN checksum;
+ALTER TABLE templates DROP COLUMN version;
@@ -0,0 +1,4 @@
+-- Built-in templates record the version they were seeded from and a
+-- checksum of that content, so upgrades can tell user edits apart
+ALTER TABLE templates ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE templates ADD COLUMN checksum VARCHAR(64) NOT NULL DEFAULT '';
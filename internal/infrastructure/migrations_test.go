@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestMigrator(t *testing.T) (*sqlx.DB, *Migrator) {
+	t.Helper()
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "progressive.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	return db, m
+}
+
+func TestMigrateDownAndUp(t *testing.T) {
+	ctx := context.Background()
+	db, m := newTestMigrator(t)
+	total := len(m.Migrations())
+
+	if applied, err := m.Up(ctx, 3); err != nil || len(applied) != 3 {
+		t.Fatalf("Expected 3 migrations up to version 3, got %d: %v", len(applied), err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read status: %v", err)
+	}
+	if len(statuses) != total || !statuses[2].Applied || statuses[3].Applied {
+		t.Errorf("Expected versions 1-3 applied only, got: %+v", statuses)
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	if _, err := m.Redo(ctx); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+
+	reverted, err := m.Down(ctx, total)
+	if err != nil {
+		t.Fatalf("Failed to migrate down: %v", err)
+	}
+	if len(reverted) != total || reverted[0].Version != total {
+		t.Errorf("Expected all migrations reverted newest first, got: %v", reverted)
+	}
+	var tables []string
+	db.Select(&tables, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')")
+	if len(tables) != 0 {
+		t.Errorf("Expected down migrations to drop every table, left: %v", tables)
+	}
+
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Failed to migrate up again: %v", err)
+	}
+}
+
+func TestMigrateRejectsModifiedMigration(t *testing.T) {
+	ctx := context.Background()
+	db, m := newTestMigrator(t)
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	db.MustExec(`UPDATE schema_migrations SET checksum = 'deadbeefdeadbeef' WHERE version = 2`)
+	if _, err := m.Up(ctx, 0); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected checksum mismatch, got: %v", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil || !statuses[1].Modified {
+		t.Errorf("Expected status to report version 2 as modified, got %+v: %v", statuses, err)
+	}
+}
+
+func TestMigrateImportsLegacyTable(t *testing.T) {
+	ctx := context.Background()
+	db, m := newTestMigrator(t)
+	if _, err := m.Up(ctx, 0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	db.MustExec(`CREATE TABLE migrations (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE, applied_at DATETIME DEFAULT CURRENT_TIMESTAMP)`)
+	db.MustExec(`INSERT INTO migrations (name) SELECT name FROM schema_migrations ORDER BY version`)
+	db.MustExec(`DELETE FROM schema_migrations`)
+
+	if applied, err := m.Up(ctx, 0); err != nil || len(applied) != 0 {
+		t.Errorf("Expected legacy migrations to be adopted without reapplying, got %v: %v", applied, err)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/002_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"m/002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"m/001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"m/001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"m/README.md":      {Data: []byte("ignored")},
+	}
+	migrations, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "001_a" || migrations[1].Version != 2 || migrations[0].Checksum == "" {
+		t.Errorf("Unexpected migrations: %+v", migrations)
+	}
+
+	delete(fsys, "m/002_b.down.sql")
+	if _, err := loadMigrations(fsys, "m"); err == nil {
+		t.Error("Expected a migration without a down file to be rejected")
+	}
+}
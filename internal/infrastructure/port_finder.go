@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
@@ -59,7 +60,7 @@ func NewMockPortFinder(availablePort uint32) *MockPortFinder {
 // FindAvailablePort returns the pre-configured available port
 func (mpf *MockPortFinder) FindAvailablePort(startPort uint32, maxAttempts int) (uint32, error) {
 	if mpf.ShouldError {
-		return 0, fmt.Errorf(mpf.ErrorMessage)
+		return 0, errors.New(mpf.ErrorMessage)
 	}
 	return mpf.AvailablePort, nil
 }
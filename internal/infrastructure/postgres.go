@@ -1,8 +1,14 @@
 package infrastructure
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
@@ -10,6 +16,14 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// EmbeddedPostgresVersion is pinned so that upgrading embedded-postgres
+// cannot silently change the major version of an existing data directory
+const EmbeddedPostgresVersion = embeddedpostgres.V17
+
+// ErrDataDirVersion is returned when the data directory was initialized by
+// another PostgreSQL major version. embedded-postgres would wipe it.
+var ErrDataDirVersion = errors.New("data directory belongs to another PostgreSQL version")
+
 // Config holds database configuration
 type Config struct {
 	Host     string
@@ -19,15 +33,45 @@ type Config struct {
 	Database string
 }
 
+// PoolConfig holds connection pool limits
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultPool returns the connection pool limits used when none are configured
+func DefaultPool() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+	}
+}
+
+// apply sets the pool limits on db
+func (p PoolConfig) apply(db *sqlx.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+}
+
 // Option defines a function type for configuring EmbeddedDB
 type Option func(*EmbeddedDBOptions)
 
 // EmbeddedDBOptions holds configuration options for EmbeddedDB
 type EmbeddedDBOptions struct {
-	Config             Config
-	PortFinder         PortFinder
-	AutoPortDiscovery  bool
-	MaxPortAttempts    int
+	Config            Config
+	PortFinder        PortFinder
+	AutoPortDiscovery bool
+	MaxPortAttempts   int
+	Pool              PoolConfig
+	// DataDir keeps the cluster across restarts; empty means a throwaway
+	// cluster under the runtime directory
+	DataDir string
+	// RuntimeDir is where the PostgreSQL binaries are extracted. It is
+	// wiped on every start, so it must not contain DataDir.
+	RuntimeDir string
 }
 
 // WithConfig sets the database configuration
@@ -52,6 +96,27 @@ func WithAutoPortDiscovery(maxAttempts int) Option {
 	}
 }
 
+// WithPool sets the connection pool limits
+func WithPool(pool PoolConfig) Option {
+	return func(opts *EmbeddedDBOptions) {
+		opts.Pool = pool
+	}
+}
+
+// WithDataDir stores the cluster in dir so data survives restarts
+func WithDataDir(dir string) Option {
+	return func(opts *EmbeddedDBOptions) {
+		opts.DataDir = dir
+	}
+}
+
+// WithRuntimeDir extracts the PostgreSQL binaries into dir
+func WithRuntimeDir(dir string) Option {
+	return func(opts *EmbeddedDBOptions) {
+		opts.RuntimeDir = dir
+	}
+}
+
 // getDefaultOptions returns default options for EmbeddedDB
 func getDefaultOptions() *EmbeddedDBOptions {
 	return &EmbeddedDBOptions{
@@ -65,6 +130,7 @@ func getDefaultOptions() *EmbeddedDBOptions {
 		PortFinder:        NewDefaultPortFinder(),
 		AutoPortDiscovery: true,
 		MaxPortAttempts:   10,
+		Pool:              DefaultPool(),
 	}
 }
 
@@ -73,6 +139,7 @@ type EmbeddedDB struct {
 	DB       *sqlx.DB
 	embedded *embeddedpostgres.EmbeddedPostgres
 	config   Config
+	stopped  atomic.Bool
 }
 
 // GetConfig returns the configuration used by the EmbeddedDB
@@ -86,14 +153,14 @@ func NewEmbeddedDB(options ...Option) (*EmbeddedDB, error) {
 	for _, option := range options {
 		option(opts)
 	}
-	
+
 	return NewEmbeddedDBWithOptions(opts)
 }
 
 // NewEmbeddedDBWithOptions creates and starts an embedded PostgreSQL with custom options
 func NewEmbeddedDBWithOptions(opts *EmbeddedDBOptions) (*EmbeddedDB, error) {
 	config := opts.Config
-	
+
 	// If auto port discovery is enabled, find an available port
 	if opts.AutoPortDiscovery {
 		availablePort, err := opts.PortFinder.FindAvailablePort(config.Port, opts.MaxPortAttempts)
@@ -101,35 +168,49 @@ func NewEmbeddedDBWithOptions(opts *EmbeddedDBOptions) (*EmbeddedDB, error) {
 			return nil, fmt.Errorf("failed to find available port: %w", err)
 		}
 		config.Port = availablePort
-		log.Printf("🔍 Found available port: %d", availablePort)
+		slog.Debug("found available port", slog.Int("port", int(availablePort)))
 	}
-	
-	return createEmbeddedDB(config)
+
+	return createEmbeddedDB(config, opts)
 }
 
 // NewEmbeddedDBWithConfig creates and starts an embedded PostgreSQL with custom config (deprecated)
 func NewEmbeddedDBWithConfig(config Config) (*EmbeddedDB, error) {
-	return createEmbeddedDB(config)
+	return createEmbeddedDB(config, getDefaultOptions())
 }
 
 // createEmbeddedDB is the internal function that creates the embedded database
-func createEmbeddedDB(config Config) (*EmbeddedDB, error) {
+func createEmbeddedDB(config Config, opts *EmbeddedDBOptions) (*EmbeddedDB, error) {
+	if err := checkDataDir(opts.DataDir, opts.RuntimeDir); err != nil {
+		return nil, err
+	}
+
 	// Configure embedded PostgreSQL
 	embeddedConfig := embeddedpostgres.DefaultConfig().
+		Version(EmbeddedPostgresVersion).
 		Username(config.Username).
 		Password(config.Password).
 		Database(config.Database).
 		Port(config.Port).
 		StartTimeout(45 * time.Second)
+	if opts.RuntimeDir != "" {
+		embeddedConfig = embeddedConfig.RuntimePath(opts.RuntimeDir)
+	}
+	if opts.DataDir != "" {
+		embeddedConfig = embeddedConfig.DataPath(opts.DataDir)
+		slog.Info("using persistent data directory", slog.String("data_dir", opts.DataDir))
+	} else {
+		slog.Warn("no data directory configured: data is lost when the server stops")
+	}
 
 	embedded := embeddedpostgres.NewDatabase(embeddedConfig)
 
 	// Start embedded PostgreSQL
-	log.Println("🚀 Starting embedded PostgreSQL...")
+	slog.Info("starting embedded PostgreSQL", slog.Int("port", int(config.Port)))
 	if err := embedded.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start embedded postgres: %w", err)
 	}
-	log.Println("✅ Embedded PostgreSQL started successfully")
+	slog.Info("embedded PostgreSQL started")
 
 	// Connect to the database
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
@@ -138,19 +219,17 @@ func createEmbeddedDB(config Config) (*EmbeddedDB, error) {
 	// Wait a bit for the database to be ready
 	time.Sleep(2 * time.Second)
 
-	db, err := sqlx.Connect("postgres", dsn)
+	db, err := connectLogged("postgres", dsn)
 	if err != nil {
 		embedded.Stop()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	opts.Pool.apply(db)
+
+	slog.Debug("database connection established")
 
-	log.Println("✅ Database connection established")
-	
 	return &EmbeddedDB{
 		DB:       db,
 		embedded: embedded,
@@ -158,40 +237,82 @@ func createEmbeddedDB(config Config) (*EmbeddedDB, error) {
 	}, nil
 }
 
+// checkDataDir refuses data directories that embedded-postgres would delete:
+// ones inside the runtime directory, which is wiped on start, and ones
+// initialized by another major version, which it wipes and re-initializes
+func checkDataDir(dataDir, runtimeDir string) error {
+	if dataDir == "" {
+		return nil
+	}
+	if runtimeDir != "" {
+		if rel, err := filepath.Rel(runtimeDir, dataDir); err == nil && !strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("data directory %s must not be inside the runtime directory %s", dataDir, runtimeDir)
+		}
+	}
+
+	version, err := os.ReadFile(filepath.Join(dataDir, "PG_VERSION"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read data directory version: %w", err)
+	}
+	if major := strings.TrimSpace(string(version)); !strings.HasPrefix(string(EmbeddedPostgresVersion), major+".") {
+		return fmt.Errorf("%w: %s was created by PostgreSQL %s, this build runs %s; restore a backup into an empty data directory",
+			ErrDataDirVersion, dataDir, major, EmbeddedPostgresVersion)
+	}
+	return nil
+}
+
 // NewDB creates a connection to an external PostgreSQL database
 func NewDB(config Config) (*sqlx.DB, error) {
 	// Connect to the database
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		config.Host, config.Port, config.Username, config.Password, config.Database)
 
-	db, err := sqlx.Connect("postgres", dsn)
+	return NewDBWithDSN(dsn, DefaultPool())
+}
+
+// NewDBWithDSN connects to an external PostgreSQL database by connection string
+func NewDBWithDSN(dsn string, pool PoolConfig) (*sqlx.DB, error) {
+	db, err := connectLogged("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
 	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	pool.apply(db)
 
-	log.Println("✅ Database connection established")
+	slog.Debug("database connection established")
 	return db, nil
 }
 
+// Ping checks that the embedded PostgreSQL is running and answers
+func (e *EmbeddedDB) Ping(ctx context.Context) error {
+	if e.stopped.Load() {
+		return fmt.Errorf("embedded PostgreSQL is stopped")
+	}
+	if err := e.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("embedded PostgreSQL is not answering: %w", err)
+	}
+	return nil
+}
+
 // Close stops the embedded PostgreSQL and closes the connection
 func (e *EmbeddedDB) Close() error {
+	e.stopped.Store(true)
 	if e.DB != nil {
 		if err := e.DB.Close(); err != nil {
-			log.Printf("Error closing database connection: %v", err)
+			slog.Error("failed to close database connection", slog.Any("error", err))
 		}
 	}
 
 	if e.embedded != nil {
-		log.Println("🛑 Stopping embedded PostgreSQL...")
+		slog.Info("stopping embedded PostgreSQL")
 		if err := e.embedded.Stop(); err != nil {
 			return fmt.Errorf("failed to stop embedded postgres: %w", err)
 		}
-		log.Println("✅ Embedded PostgreSQL stopped")
+		slog.Info("embedded PostgreSQL stopped")
 	}
 
 	return nil
@@ -1,6 +1,9 @@
 package infrastructure
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -109,4 +112,26 @@ func TestOptionsPattern(t *testing.T) {
 	if opts.MaxPortAttempts != 20 {
 		t.Errorf("Expected MaxPortAttempts to be 20, got: %d", opts.MaxPortAttempts)
 	}
-}
\ No newline at end of file
+}
+
+func TestCheckDataDir(t *testing.T) {
+	runtimeDir := t.TempDir()
+	if err := checkDataDir(filepath.Join(runtimeDir, "data"), runtimeDir); err == nil {
+		t.Error("Expected a data directory inside the runtime directory to be rejected")
+	}
+
+	dataDir := t.TempDir()
+	if err := checkDataDir(dataDir, runtimeDir); err != nil {
+		t.Errorf("Expected an empty data directory to be accepted, got: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dataDir, "PG_VERSION"), []byte("17\n"), 0o644)
+	if err := checkDataDir(dataDir, ""); err != nil {
+		t.Errorf("Expected a data directory of the pinned version to be accepted, got: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dataDir, "PG_VERSION"), []byte("16\n"), 0o644)
+	if err := checkDataDir(dataDir, ""); !errors.Is(err, ErrDataDirVersion) {
+		t.Errorf("Expected a data directory of another version to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"progressive/internal/logging"
+)
+
+func TestQueriesAreLoggedWithRequestID(t *testing.T) {
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "progressive.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, logging.FormatText, slog.LevelDebug))
+	defer slog.SetDefault(previous)
+
+	ctx := logging.WithRequestID(context.Background(), "req-7")
+	var s string
+	if err := db.GetContext(ctx, &s, "SELECT ?  ||\n 'x'", "hidden-arg"); err != nil || s != "hidden-argx" {
+		t.Fatalf("Expected hidden-argx, got %q: %v", s, err)
+	}
+	if _, err := db.ExecContext(ctx, "SELECT * FROM missing_table"); err == nil {
+		t.Fatal("Expected an error for a missing table")
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `msg="db query" query="SELECT ? || 'x'" args=1`) || !strings.Contains(out, "request_id=req-7") {
+		t.Errorf("Expected the query tagged with the request ID, got:\n%s", out)
+	}
+	if !strings.Contains(out, "missing_table") || !strings.Contains(out, "error=") {
+		t.Errorf("Expected the failing query with its error, got:\n%s", out)
+	}
+	if strings.Contains(out, "hidden-arg") {
+		t.Error("Expected argument values to stay out of the log")
+	}
+}
@@ -0,0 +1,42 @@
+package infrastructure
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteDriver is the database/sql driver name of the SQLite backend
+const SQLiteDriver = "sqlite3"
+
+// NewSQLiteDB opens the SQLite database at path, creating the file and its
+// directory if needed. Foreign keys are enforced and the journal runs in WAL
+// mode so the editor can read while a save is in progress.
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_busy_timeout", "5000")
+
+	db, err := connectLogged(SQLiteDriver, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is locked"
+	db.SetMaxOpenConns(1)
+
+	slog.Info("SQLite database opened", slog.String("path", path))
+	return db, nil
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteMigrations(t *testing.T) {
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "progressive.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	// Running twice must skip already applied migrations
+	for i := 0; i < 2; i++ {
+		if err := RunMigrations(db); err != nil {
+			t.Fatalf("Failed to run migrations (pass %d): %v", i+1, err)
+		}
+	}
+
+	var applied []string
+	if err := db.Select(&applied, "SELECT name FROM schema_migrations ORDER BY version"); err != nil {
+		t.Fatalf("Failed to read migrations: %v", err)
+	}
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	if expected := m.Migrations(); len(applied) != len(expected) {
+		t.Fatalf("Expected %d migrations, got: %v", len(expected), applied)
+	} else {
+		for i, migration := range expected {
+			if applied[i] != migration.Name {
+				t.Errorf("Expected migration %d to be %s, got %s", i, migration.Name, applied[i])
+			}
+		}
+	}
+
+	db.MustExec(`INSERT INTO tables (id, name, schema) VALUES ('t1', 'T1', '{"type": "object"}')`)
+	db.MustExec(`INSERT INTO records (table_id, data) VALUES ('t1', '{"a": 1}'), ('t1', '{"a": 2}')`)
+	db.MustExec(`UPDATE records SET data = '{"a": 3}' WHERE id = 1`)
+	db.MustExec(`UPDATE records SET data = '{ "a": 3 }' WHERE id = 1`)
+	db.MustExec(`DELETE FROM records WHERE id = 2`)
+
+	var count int
+	db.Get(&count, "SELECT record_count FROM tables WHERE id = 't1'")
+	if count != 1 {
+		t.Errorf("Expected record_count trigger to keep 1, got %d", count)
+	}
+	var revisions []string
+	db.Select(&revisions, "SELECT operation FROM record_revisions ORDER BY id")
+	if len(revisions) != 4 || revisions[2] != "update" || revisions[3] != "delete" {
+		t.Errorf("Expected insert, insert, update, delete revisions, got: %v", revisions)
+	}
+
+	if _, err := db.Exec(`INSERT INTO records (table_id, data) VALUES ('missing', '{}')`); err == nil {
+		t.Error("Expected foreign key violation for a missing table")
+	}
+	if _, err := db.Exec(`INSERT INTO records (table_id, data) VALUES ('t1', 'not json')`); err == nil {
+		t.Error("Expected invalid JSON to be rejected")
+	}
+}
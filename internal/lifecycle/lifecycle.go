@@ -0,0 +1,247 @@
+// Package lifecycle runs the HTTP server together with its background
+// workers and resources, and shuts them down in order: cancel the workers
+// and stop accepting connections, wait for in-flight requests and workers,
+// then release resources such as the database.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Phase is the stage of the application lifecycle
+type Phase int32
+
+const (
+	// Starting means resources are being opened and the server is not listening yet
+	Starting Phase = iota
+	// Running means the server accepts and serves requests
+	Running
+	// Draining means the server is shutting down: readiness fails, the
+	// listener closes after DrainDelay and in-flight requests and jobs finish
+	Draining
+	// Stopped means everything has been shut down
+	Stopped
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Starting:
+		return "starting"
+	case Running:
+		return "running"
+	case Draining:
+		return "draining"
+	case Stopped:
+		return "stopped"
+	default:
+		return fmt.Sprintf("phase(%d)", int32(p))
+	}
+}
+
+// DefaultShutdownTimeout bounds draining when App.ShutdownTimeout is zero
+const DefaultShutdownTimeout = 30 * time.Second
+
+// App owns the HTTP server, background workers and resources
+type App struct {
+	Server *http.Server
+	// TLSCert and TLSKey serve HTTPS when both are set
+	TLSCert string
+	TLSKey  string
+	// ShutdownTimeout bounds draining requests and workers together
+	ShutdownTimeout time.Duration
+	// DrainDelay keeps serving with readiness failing before the listener
+	// closes, giving load balancers time to stop routing new requests
+	DrainDelay time.Duration
+
+	phase   atomic.Int32
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+	closers []closer
+	checks  []check
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+type check struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// checkTimeout bounds the readiness checks of one request
+const checkTimeout = 2 * time.Second
+
+// New creates an App serving server
+func New(server *http.Server) *App {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &App{
+		Server:          server,
+		ShutdownTimeout: DefaultShutdownTimeout,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Phase returns the current phase
+func (a *App) Phase() Phase {
+	return Phase(a.phase.Load())
+}
+
+func (a *App) setPhase(p Phase) {
+	a.phase.Store(int32(p))
+	slog.Info("lifecycle phase", slog.String("phase", p.String()))
+}
+
+// Go runs a background worker. Its context is cancelled when the server
+// starts draining, and shutdown waits for it to return.
+func (a *App) Go(name string, worker func(ctx context.Context)) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		worker(a.ctx)
+		slog.Info("worker stopped", slog.String("worker", name))
+	}()
+}
+
+// OnStop registers a resource to release after requests and workers have
+// drained. Resources are released in reverse order of registration.
+func (a *App) OnStop(name string, close func() error) {
+	a.closers = append(a.closers, closer{name: name, close: close})
+}
+
+// AddReadinessCheck adds a dependency that must be healthy for the
+// application to report ready, such as the database
+func (a *App) AddReadinessCheck(name string, fn func(ctx context.Context) error) {
+	a.checks = append(a.checks, check{name: name, check: fn})
+}
+
+// Run serves until ctx is cancelled or the server fails, then shuts down.
+// A clean shutdown returns nil.
+func (a *App) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", a.Server.Addr)
+	if err != nil {
+		a.stop(context.Background())
+		return fmt.Errorf("failed to listen on %s: %w", a.Server.Addr, err)
+	}
+	return a.Serve(ctx, listener)
+}
+
+// Serve is Run on an existing listener
+func (a *App) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		if a.TLSCert != "" && a.TLSKey != "" {
+			serveErr <- a.Server.ServeTLS(listener, a.TLSCert, a.TLSKey)
+		} else {
+			serveErr <- a.Server.Serve(listener)
+		}
+	}()
+	a.setPhase(Running)
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = err
+		}
+	}
+
+	timeout := a.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return errors.Join(runErr, a.stop(shutdownCtx))
+}
+
+// stop drains the server and workers until ctx expires, then releases the
+// resources regardless so the database is always shut down. Workers are
+// cancelled as draining starts so they wind down alongside the requests.
+func (a *App) stop(ctx context.Context) error {
+	a.setPhase(Draining)
+	a.cancel()
+	var errs []error
+
+	if a.DrainDelay > 0 {
+		select {
+		case <-time.After(a.DrainDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	if err := a.Server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain requests: %w", err))
+		a.Server.Close()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background workers did not stop: %w", ctx.Err()))
+	}
+
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		c := a.closers[i]
+		if err := c.close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", c.name, err))
+		}
+	}
+	a.setPhase(Stopped)
+	return errors.Join(errs...)
+}
+
+// LivenessHandler answers 200 until the application has stopped
+func (a *App) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, a.Phase() != Stopped, a.Phase().String())
+}
+
+// ReadinessHandler answers 200 only while the server is running and every
+// readiness check passes, so load balancers stop routing to it as soon as
+// it starts draining or loses its database. The body lists each check.
+func (a *App) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	phase := a.Phase()
+	ok := phase == Running
+	lines := []string{phase.String()}
+	if ok {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+		for _, c := range a.checks {
+			if err := c.check(ctx); err != nil {
+				ok = false
+				lines = append(lines, c.name+": "+err.Error())
+			} else {
+				lines = append(lines, c.name+": ok")
+			}
+		}
+	}
+	writeStatus(w, ok, lines...)
+}
+
+func writeStatus(w http.ResponseWriter, ok bool, lines ...string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,153 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeDrainsBeforeClosing(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	app := New(&http.Server{Handler: mux})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})
+	mux.HandleFunc("/readyz", app.ReadinessHandler)
+
+	var stopped []string
+	app.OnStop("db", func() error { stopped = append(stopped, "db"); return nil })
+	app.OnStop("cache", func() error { stopped = append(stopped, "cache"); return nil })
+	workerDone := make(chan struct{})
+	app.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerDone)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Serve(ctx, listener) }()
+
+	base := "http://" + listener.Addr().String()
+	if resp, err := http.Get(base + "/readyz"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected ready while running, got %v: %v", resp, err)
+	}
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+	<-started
+
+	cancel()
+	for app.Phase() != Draining {
+		time.Sleep(time.Millisecond)
+	}
+	rec := httptest.NewRecorder()
+	app.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "draining") {
+		t.Errorf("Expected not ready while draining, got %d %q", rec.Code, rec.Body.String())
+	}
+	if len(stopped) != 0 {
+		t.Error("Expected resources to stay open while requests are in flight")
+	}
+	select {
+	case <-workerDone:
+	case <-time.After(time.Second):
+		t.Error("Expected workers to be cancelled while requests are in flight")
+	}
+
+	close(release)
+	if got := <-body; got != "done" {
+		t.Errorf("Expected the in-flight request to finish, got %q", got)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("Expected a clean shutdown, got: %v", err)
+	}
+	if strings.Join(stopped, ",") != "cache,db" {
+		t.Errorf("Expected resources closed in reverse order, got %v", stopped)
+	}
+
+	rec = httptest.NewRecorder()
+	app.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable || app.Phase() != Stopped {
+		t.Errorf("Expected stopped, got %d in phase %s", rec.Code, app.Phase())
+	}
+}
+
+func TestShutdownTimeoutStillClosesResources(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	app := New(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})})
+	app.ShutdownTimeout = 50 * time.Millisecond
+	closed := false
+	app.OnStop("db", func() error { closed = true; return nil })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- app.Serve(ctx, listener) }()
+	go http.Get("http://" + listener.Addr().String())
+	<-started
+
+	cancel()
+	if err := <-runErr; err == nil || !strings.Contains(err.Error(), "drain") {
+		t.Errorf("Expected a drain timeout error, got: %v", err)
+	}
+	if !closed {
+		t.Error("Expected the database to be closed after the deadline")
+	}
+}
+
+func TestReadinessChecks(t *testing.T) {
+	app := New(&http.Server{})
+	app.phase.Store(int32(Running))
+	var dbErr error
+	app.AddReadinessCheck("database", func(ctx context.Context) error { return dbErr })
+
+	rec := httptest.NewRecorder()
+	app.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "database: ok") {
+		t.Errorf("Expected ready, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	dbErr = errors.New("connection refused")
+	rec = httptest.NewRecorder()
+	app.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "database: connection refused") {
+		t.Errorf("Expected not ready with a failing check, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	app.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected liveness to ignore readiness checks, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,134 @@
+// Package logging carries request IDs and W3C trace context through
+// request contexts and builds the slog loggers that tag every record
+// logged with such a context.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// RequestIDHeader carries the request ID between clients, proxies and the server
+const RequestIDHeader = "X-Request-ID"
+
+// TraceparentHeader carries the W3C trace context
+const TraceparentHeader = "traceparent"
+
+// maxRequestIDLength bounds request IDs taken from clients
+const maxRequestIDLength = 128
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceKey
+)
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID of ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// NewRequestID returns a random request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether a client-supplied request ID is safe to
+// log and echo: short, printable ASCII without spaces
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Trace is a W3C trace context: the trace a request belongs to, the span
+// the server handles it in and the caller's span
+type Trace struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Sampled  bool
+}
+
+// NewTrace starts a new trace with a root span
+func NewTrace() Trace {
+	return Trace{TraceID: randomHex(16), SpanID: randomHex(8)}
+}
+
+// ParseTraceparent parses a traceparent header. Only version 00 fields are
+// read; later versions are accepted as long as they start the same way.
+func ParseTraceparent(header string) (Trace, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return Trace{}, false
+	}
+	traceID, spanID, flags := parts[1], parts[2], parts[3]
+	if !isHex(parts[0]) || len(traceID) != 32 || !isHex(traceID) || isZero(traceID) ||
+		len(spanID) != 16 || !isHex(spanID) || isZero(spanID) || len(flags) != 2 || !isHex(flags) {
+		return Trace{}, false
+	}
+	flagBits, _ := hex.DecodeString(flags)
+	return Trace{TraceID: traceID, SpanID: spanID, Sampled: flagBits[0]&1 == 1}, true
+}
+
+// Child returns the trace context of a new span whose parent is t's span
+func (t Trace) Child() Trace {
+	return Trace{TraceID: t.TraceID, SpanID: randomHex(8), ParentID: t.SpanID, Sampled: t.Sampled}
+}
+
+// Traceparent formats t for outgoing requests
+func (t Trace) Traceparent() string {
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return "00-" + t.TraceID + "-" + t.SpanID + "-" + flags
+}
+
+// WithTrace returns a context carrying the trace context
+func WithTrace(ctx context.Context, t Trace) context.Context {
+	return context.WithValue(ctx, traceKey, t)
+}
+
+// TraceFrom returns the trace context of ctx
+func TraceFrom(ctx context.Context) (Trace, bool) {
+	t, ok := ctx.Value(traceKey).(Trace)
+	return t, ok
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
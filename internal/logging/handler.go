@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log output formats
+const (
+	// FormatConsole is a compact, human-oriented format for development
+	FormatConsole = "console"
+	// FormatText is slog's key=value format
+	FormatText = "text"
+	// FormatJSON is one JSON object per line, for log collectors in production
+	FormatJSON = "json"
+)
+
+// New returns a logger writing format to w at level and above. Records
+// logged with a request context are tagged with its request and trace IDs.
+func New(w io.Writer, format string, level slog.Level) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		handler = NewConsoleHandler(w, opts)
+	}
+	return slog.New(ContextHandler{handler})
+}
+
+// ContextHandler adds request_id, trace_id and span_id from the record's
+// context to every record
+type ContextHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler
+func (h ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if t, ok := TraceFrom(ctx); ok {
+		record.AddAttrs(slog.String("trace_id", t.TraceID), slog.String("span_id", t.SpanID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs implements slog.Handler
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{h.Handler.WithGroup(name)}
+}
+
+// ConsoleHandler writes one line per record: time, level, message, then the
+// attributes as key=value. Meant for a terminal, not for parsing.
+type ConsoleHandler struct {
+	opts   slog.HandlerOptions
+	prefix string // preformatted attributes from WithAttrs
+	group  string // dotted group prefix for attribute keys
+	mu     *sync.Mutex
+	w      io.Writer
+}
+
+// NewConsoleHandler creates a ConsoleHandler writing to w
+func NewConsoleHandler(w io.Writer, opts *slog.HandlerOptions) *ConsoleHandler {
+	h := &ConsoleHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements slog.Handler
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler
+func (h *ConsoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	if !record.Time.IsZero() {
+		b.WriteString(record.Time.Format("15:04:05.000"))
+		b.WriteByte(' ')
+	}
+	b.WriteString(levelLabel(record.Level))
+	b.WriteByte(' ')
+	b.WriteString(record.Message)
+	b.WriteString(h.prefix)
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, h.group, attr)
+		return true
+	})
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// WithAttrs implements slog.Handler
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, attr := range attrs {
+		writeAttr(&b, h.group, attr)
+	}
+	copied := *h
+	copied.prefix += b.String()
+	return &copied
+}
+
+// WithGroup implements slog.Handler
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	copied := *h
+	copied.group += name + "."
+	return &copied
+}
+
+func levelLabel(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "ERR"
+	case level >= slog.LevelWarn:
+		return "WRN"
+	case level >= slog.LevelInfo:
+		return "INF"
+	default:
+		return "DBG"
+	}
+}
+
+func writeAttr(b *strings.Builder, group string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		prefix := group
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			writeAttr(b, prefix, member)
+		}
+		return
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(group)
+	b.WriteString(attr.Key)
+	b.WriteByte('=')
+	var value string
+	switch attr.Value.Kind() {
+	case slog.KindDuration:
+		value = attr.Value.Duration().Round(10 * time.Microsecond).String()
+	case slog.KindTime:
+		value = attr.Value.Time().Format(time.RFC3339)
+	default:
+		value = fmt.Sprint(attr.Value.Any())
+	}
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = fmt.Sprintf("%q", value)
+	}
+	b.WriteString(value)
+}
@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	trace, ok := ParseTraceparent(header)
+	if !ok || trace.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || trace.SpanID != "00f067aa0ba902b7" || !trace.Sampled {
+		t.Fatalf("Unexpected trace %+v (ok=%v)", trace, ok)
+	}
+	if trace.Traceparent() != header {
+		t.Errorf("Expected %q to round trip, got %q", header, trace.Traceparent())
+	}
+
+	child := trace.Child()
+	if child.TraceID != trace.TraceID || child.ParentID != trace.SpanID || child.SpanID == trace.SpanID {
+		t.Errorf("Expected a new span in the same trace, got %+v", child)
+	}
+
+	for _, bad := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, ok := ParseTraceparent(bad); ok {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	for id, want := range map[string]bool{
+		"abc-123":                 true,
+		"":                        false,
+		"has space":               false,
+		"line\nbreak":             false,
+		strings.Repeat("a", 129):  false,
+		"0f8fad5b-d9cb-469f-a165": true,
+	} {
+		if got := ValidRequestID(id); got != want {
+			t.Errorf("ValidRequestID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestLoggerTagsRecordsFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatJSON, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithTrace(ctx, Trace{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+	logger.InfoContext(ctx, "hello", "table", "items")
+	logger.Info("outside a request")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %q", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", lines[0], err)
+	}
+	if record["request_id"] != "req-1" || record["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" || record["table"] != "items" {
+		t.Errorf("Expected request attributes, got %v", record)
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("Expected no request ID outside a request, got %q", lines[1])
+	}
+}
+
+func TestConsoleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, FormatConsole, slog.LevelInfo)
+	logger.With("component", "api").WithGroup("db").Warn("slow query", "query", "SELECT 1", "ms", 120)
+	logger.Debug("hidden")
+
+	line := buf.String()
+	if !strings.Contains(line, `WRN slow query component=api db.query="SELECT 1" db.ms=120`) {
+		t.Errorf("Unexpected console line: %q", line)
+	}
+	if strings.Contains(line, "hidden") {
+		t.Error("Expected debug records to be filtered at info level")
+	}
+}
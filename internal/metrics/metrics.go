@@ -0,0 +1,276 @@
+// Package metrics keeps counters, histograms and scrape-time gauges and
+// renders them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are latency buckets in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// scrapeTimeout bounds the gauge functions of one scrape
+const scrapeTimeout = 5 * time.Second
+
+// Registry holds metric families by name
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]family
+}
+
+// family is one metric name with its help and type
+type family interface {
+	write(ctx context.Context, w *bufio.Writer, name string)
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]family)}
+}
+
+func (r *Registry) register(name string, f family) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.families[name]; exists {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.families[name] = f
+}
+
+// Counter registers a counter partitioned by labels
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{help: help, labels: labels, values: make(map[string]*counterValue)}
+	r.register(name, c)
+	return c
+}
+
+// Histogram registers a histogram partitioned by labels. nil buckets use
+// DefaultBuckets.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{help: help, labels: labels, buckets: buckets, values: make(map[string]*histogramValue)}
+	r.register(name, h)
+	return h
+}
+
+// GaugeFunc registers a gauge read by fn on every scrape. A failing fn
+// leaves the gauge out of that scrape.
+func (r *Registry) GaugeFunc(name, help string, fn func(ctx context.Context) (float64, error)) {
+	r.register(name, &funcFamily{help: help, kind: "gauge", fn: fn})
+}
+
+// CounterFunc registers a counter read by fn on every scrape, for totals
+// kept elsewhere such as database pool statistics
+func (r *Registry) CounterFunc(name, help string, fn func(ctx context.Context) (float64, error)) {
+	r.register(name, &funcFamily{help: help, kind: "counter", fn: fn})
+}
+
+// WriteText renders every family in name order
+func (r *Registry) WriteText(ctx context.Context, w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	families := make([]family, len(names))
+	sort.Strings(names)
+	for i, name := range names {
+		families[i] = r.families[name]
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for i, f := range families {
+		f.write(ctx, bw, names[i])
+	}
+	return bw.Flush()
+}
+
+// Handler serves the registry for Prometheus to scrape
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), scrapeTimeout)
+		defer cancel()
+		w.Header().Set("Content-Type", ContentType)
+		if err := r.WriteText(ctx, w); err != nil {
+			slog.WarnContext(req.Context(), "failed to write metrics", slog.Any("error", err))
+		}
+	})
+}
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	help   string
+	labels []string
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labels []string
+	value  float64
+}
+
+// Add increases the counter for labelValues by v
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	key := labelKey(c.labels, labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		value = &counterValue{labels: append([]string(nil), labelValues...)}
+		c.values[key] = value
+	}
+	value.value += v
+}
+
+// Inc increases the counter for labelValues by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) write(ctx context.Context, w *bufio.Writer, name string) {
+	writeHeader(w, name, c.help, "counter")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range sortedKeys(c.values) {
+		value := c.values[key]
+		writeSample(w, name, formatLabels(c.labels, value.labels), value.value)
+	}
+}
+
+// HistogramVec is a histogram partitioned by label values
+type HistogramVec struct {
+	help    string
+	labels  []string
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe records v for labelValues
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := labelKey(h.labels, labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.values[key]
+	if !ok {
+		value = &histogramValue{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.values[key] = value
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			value.counts[i]++
+		}
+	}
+	value.count++
+	value.sum += v
+}
+
+func (h *HistogramVec) write(ctx context.Context, w *bufio.Writer, name string) {
+	writeHeader(w, name, h.help, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.values) {
+		value := h.values[key]
+		bucketLabels := append(append([]string(nil), h.labels...), "le")
+		bucketValues := append(append([]string(nil), value.labels...), "")
+		for i, bound := range h.buckets {
+			bucketValues[len(bucketValues)-1] = formatFloat(bound)
+			writeSample(w, name+"_bucket", formatLabels(bucketLabels, bucketValues), float64(value.counts[i]))
+		}
+		bucketValues[len(bucketValues)-1] = "+Inf"
+		writeSample(w, name+"_bucket", formatLabels(bucketLabels, bucketValues), float64(value.count))
+		writeSample(w, name+"_sum", formatLabels(h.labels, value.labels), value.sum)
+		writeSample(w, name+"_count", formatLabels(h.labels, value.labels), float64(value.count))
+	}
+}
+
+// funcFamily is a single unlabeled value read at scrape time
+type funcFamily struct {
+	help string
+	kind string
+	fn   func(ctx context.Context) (float64, error)
+}
+
+func (f *funcFamily) write(ctx context.Context, w *bufio.Writer, name string) {
+	value, err := f.fn(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to collect metric", slog.String("metric", name), slog.Any("error", err))
+		return
+	}
+	writeHeader(w, name, f.help, f.kind)
+	writeSample(w, name, "", value)
+}
+
+// labelKey identifies a label combination and checks its arity
+func labelKey(names, values []string) string {
+	if len(names) != len(values) {
+		panic(fmt.Sprintf("metrics: %d label values for labels %v", len(values), names))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func writeSample(w *bufio.Writer, name, labels string, value float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(value))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
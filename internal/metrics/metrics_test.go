@@ -0,0 +1,135 @@
+package metrics_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/table"
+	"progressive/internal/metrics"
+	"progressive/internal/middleware"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+// scrape fetches url and parses the samples by "name{labels}"
+func scrape(t *testing.T, url string) map[string]float64 {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("Failed to scrape: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Expected content type %q, got %q", metrics.ContentType, ct)
+	}
+
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			t.Fatalf("Malformed sample %q: %v", line, err)
+		}
+		samples[line[:i]] = value
+	}
+	return samples
+}
+
+func TestRegistryText(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.Counter("jobs_total", "Jobs run.", "queue")
+	c.Inc("fast")
+	c.Add(2, `say "hi"`)
+	h := r.Histogram("job_seconds", "Job latency.", []float64{0.1, 1}, "queue")
+	h.Observe(0.05, "fast")
+	h.Observe(0.5, "fast")
+	r.GaugeFunc("up", "Always one.", func(context.Context) (float64, error) { return 1, nil })
+
+	var out strings.Builder
+	if err := r.WriteText(context.Background(), &out); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	want := `# HELP job_seconds Job latency.
+# TYPE job_seconds histogram
+job_seconds_bucket{queue="fast",le="0.1"} 1
+job_seconds_bucket{queue="fast",le="1"} 2
+job_seconds_bucket{queue="fast",le="+Inf"} 2
+job_seconds_sum{queue="fast"} 0.55
+job_seconds_count{queue="fast"} 2
+# HELP jobs_total Jobs run.
+# TYPE jobs_total counter
+jobs_total{queue="fast"} 1
+jobs_total{queue="say \"hi\""} 2
+# HELP up Always one.
+# TYPE up gauge
+up 1
+`
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.Open(t, storage.SQLite)
+	if err := store.Tables.Create(ctx, table.NewTable("items", "Items", "", []byte(`{"type": "object"}`))); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if err := store.Records.Create(ctx, record.NewRecord("items", map[string]interface{}{"name": "sword"})); err != nil {
+		t.Fatalf("Failed to create record: %v", err)
+	}
+
+	// The server's own registry is global; a fresh one keeps the gauges local to this test
+	reg := metrics.NewRegistry()
+	metrics.RegisterDBStats(reg, store.DB)
+	metrics.RegisterContentCounts(reg, store.Counts)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			http.NotFound(w, r)
+		}
+	})
+	mux.Handle("/metrics", metrics.Default.Handler())
+	mux.Handle("/store-metrics", reg.Handler())
+	server := httptest.NewServer(middleware.LoggingMiddleware(mux, metrics.RequestObserver(metrics.MuxRoute(mux))))
+	defer server.Close()
+
+	for _, id := range []string{"1", "2", "missing"} {
+		resp, err := http.Get(server.URL + "/items/" + id)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	samples := scrape(t, server.URL+"/metrics")
+	if got := samples[`progressive_http_requests_total{route="GET /items/{id}",method="GET",status="200"}`]; got != 2 {
+		t.Errorf("Expected 2 successful requests on the route pattern, got %v", got)
+	}
+	if got := samples[`progressive_http_requests_total{route="GET /items/{id}",method="GET",status="404"}`]; got != 1 {
+		t.Errorf("Expected 1 not found request, got %v", got)
+	}
+	if got := samples[`progressive_http_request_duration_seconds_count{route="GET /items/{id}",method="GET",status="200"}`]; got != 2 {
+		t.Errorf("Expected 2 latency observations, got %v", got)
+	}
+
+	samples = scrape(t, server.URL+"/store-metrics")
+	if samples["progressive_tables"] != 1 || samples["progressive_records"] != 1 {
+		t.Errorf("Expected 1 table and 1 record, got %v and %v", samples["progressive_tables"], samples["progressive_records"])
+	}
+	if _, ok := samples["progressive_db_open_connections"]; !ok {
+		t.Error("Expected database pool statistics")
+	}
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default is the registry served at /metrics
+var Default = NewRegistry()
+
+var (
+	// HTTPRequests counts requests by route pattern, method and status
+	HTTPRequests = Default.Counter("progressive_http_requests_total",
+		"HTTP requests by route, method and status.", "route", "method", "status")
+	// HTTPDuration measures request latency by route pattern, method and status
+	HTTPDuration = Default.Histogram("progressive_http_request_duration_seconds",
+		"HTTP request latency in seconds by route, method and status.", nil, "route", "method", "status")
+	// ImportedRows counts records written by the import API by mode
+	ImportedRows = Default.Counter("progressive_import_rows_total",
+		"Records imported through the import API by mode.", "mode")
+	// ExportedRows counts records returned by the export API by format
+	ExportedRows = Default.Counter("progressive_export_rows_total",
+		"Records exported through the export API by format.", "format")
+	// DeprecatedRequests counts requests to deprecated API paths by route
+	DeprecatedRequests = Default.Counter("progressive_deprecated_requests_total",
+		"Requests to deprecated API paths by route pattern.", "route")
+)
+
+// RequestObserver records a request LoggingMiddleware measured. route maps
+// a request to a bounded route label, normally its ServeMux pattern.
+func RequestObserver(route func(r *http.Request) string) func(r *http.Request, status int, duration time.Duration) {
+	return func(r *http.Request, status int, duration time.Duration) {
+		routeLabel, statusLabel := route(r), strconv.Itoa(status)
+		HTTPRequests.Inc(routeLabel, r.Method, statusLabel)
+		HTTPDuration.Observe(duration.Seconds(), routeLabel, r.Method, statusLabel)
+	}
+}
+
+// MuxRoute returns the pattern of mux that serves a request, so paths with
+// IDs in them collapse into one route label
+func MuxRoute(mux *http.ServeMux) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			return pattern
+		}
+		return "unmatched"
+	}
+}
+
+// RegisterDBStats exposes the connection pool statistics of db
+func RegisterDBStats(r *Registry, db interface{ Stats() sql.DBStats }) {
+	stat := func(get func(s sql.DBStats) float64) func(context.Context) (float64, error) {
+		return func(context.Context) (float64, error) {
+			return get(db.Stats()), nil
+		}
+	}
+	r.GaugeFunc("progressive_db_max_open_connections", "Maximum number of open database connections.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	r.GaugeFunc("progressive_db_open_connections", "Established database connections, in use and idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	r.GaugeFunc("progressive_db_in_use_connections", "Database connections currently in use.",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	r.GaugeFunc("progressive_db_idle_connections", "Idle database connections.",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	r.CounterFunc("progressive_db_wait_count_total", "Connections waited for because the pool was exhausted.",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	r.CounterFunc("progressive_db_wait_duration_seconds_total", "Time spent waiting for a database connection.",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	r.CounterFunc("progressive_db_max_idle_closed_total", "Connections closed because of max_idle_conns.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	r.CounterFunc("progressive_db_max_lifetime_closed_total", "Connections closed because of conn_max_lifetime.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
+
+// RegisterContentCounts exposes the number of tables and records, read by
+// count on every scrape
+func RegisterContentCounts(r *Registry, count func(ctx context.Context) (tables, records int, err error)) {
+	r.GaugeFunc("progressive_tables", "Number of tables.", func(ctx context.Context) (float64, error) {
+		tables, _, err := count(ctx)
+		return float64(tables), err
+	})
+	r.GaugeFunc("progressive_records", "Number of records across all tables.", func(ctx context.Context) (float64, error) {
+		_, records, err := count(ctx)
+		return float64(records), err
+	})
+}
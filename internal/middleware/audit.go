@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"progressive/internal/domain/audit"
+	"progressive/internal/logging"
+)
+
+// maxAuditCapture is how much of a request body is read for the payload summary
+const maxAuditCapture = 1 << 20
+
+// AuditRecorder stores audit entries
+type AuditRecorder interface {
+	Append(ctx context.Context, e *audit.Entry) error
+}
+
+// AuditMiddleware records every mutating request (and data exports) in the
+// audit log after it has been handled. Recording failures are logged and never
+// change the response. X-Forwarded-For is only read from trustedProxies.
+func AuditMiddleware(recorder AuditRecorder, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			action, target, audited := audit.Classify(r.Method, r.URL.Path)
+			// RequestContextMiddleware normally assigns the ID; on its own the
+			// audit log still needs one to link entries to requests
+			requestID := logging.RequestID(r.Context())
+			if requestID == "" {
+				requestID = r.Header.Get(logging.RequestIDHeader)
+			}
+			if requestID == "" {
+				requestID = logging.NewRequestID()
+				r.Header.Set(logging.RequestIDHeader, requestID)
+			}
+			w.Header().Set(logging.RequestIDHeader, requestID)
+
+			if !audited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, size, truncated := captureBody(r)
+			payload := audit.SummarizePayload(r.Header.Get("Content-Type"), r.URL.Query(), body, size, truncated)
+
+			occurredAt := time.Now()
+			rw, out, _ := sharedResponseWriter(w, r)
+			next.ServeHTTP(out, r)
+
+			entry := &audit.Entry{
+				OccurredAt: occurredAt,
+				Actor:      RequestActor(r),
+				IP:         clientIP(r, trustedProxies),
+				UserAgent:  r.UserAgent(),
+				RequestID:  requestID,
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Status:     rw.statusCode,
+				Action:     action,
+				Target:     target,
+				Payload:    payload,
+			}
+			// The request context may already be cancelled once the client has its response
+			if err := recorder.Append(context.WithoutCancel(r.Context()), entry); err != nil {
+				slog.ErrorContext(r.Context(), "failed to write audit entry",
+					slog.String("method", r.Method), slog.String("path", r.URL.Path), slog.Any("error", err))
+			}
+		})
+	}
+}
+
+// captureBody reads up to maxAuditCapture bytes of the body for the summary
+// and puts them back in front of the unread remainder
+func captureBody(r *http.Request) (captured []byte, size int64, truncated bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, 0, false
+	}
+	captured, _ = io.ReadAll(io.LimitReader(r.Body, maxAuditCapture+1))
+	truncated = len(captured) > maxAuditCapture
+	if truncated {
+		captured = captured[:maxAuditCapture]
+	}
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+
+	size = int64(len(captured))
+	if r.ContentLength > size {
+		size = r.ContentLength
+	}
+	return captured, size, truncated
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// RequestActor identifies who made the request. There is no login yet, so the
+// client names itself with X-Actor (or X-User); basic auth is also honoured.
+func RequestActor(r *http.Request) string {
+	for _, header := range []string{"X-Actor", "X-User"} {
+		if v := strings.TrimSpace(r.Header.Get(header)); v != "" {
+			return v
+		}
+	}
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return user
+	}
+	return "anonymous"
+}
+
+// clientIP returns the socket address, or when that is a trusted proxy the
+// rightmost X-Forwarded-For hop that is not; hops left of it are whatever
+// the client chose to send
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	if !trusted(ip, trustedProxies) {
+		return ip
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !trusted(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// trusted reports whether ip is within one of the proxy prefixes
+func trusted(ip string, proxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
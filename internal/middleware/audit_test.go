@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"progressive/internal/domain/audit"
+)
+
+type memoryRecorder struct {
+	entries []*audit.Entry
+}
+
+func (m *memoryRecorder) Append(ctx context.Context, e *audit.Entry) error {
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func TestAuditMiddlewareRecordsMutations(t *testing.T) {
+	recorder := &memoryRecorder{}
+	var handlerBody string
+	handler := AuditMiddleware(recorder, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		handlerBody = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	body := `{"name": "game_item", "schema": {}}`
+	req := httptest.NewRequest("POST", "/api/tables", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Actor", "alice")
+	req.Header.Set("X-Forwarded-For", "10.0.0.7, 172.16.0.1")
+	req.Header.Set("User-Agent", "test-agent")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if handlerBody != body {
+		t.Errorf("Expected handler to receive the full body, got: %q", handlerBody)
+	}
+	if len(recorder.entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got: %d", len(recorder.entries))
+	}
+	e := recorder.entries[0]
+	if e.Actor != "alice" || e.IP != "192.0.2.1" || e.UserAgent != "test-agent" || e.Status != http.StatusCreated {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+	if e.Action != "table.create" || e.RequestID == "" || rec.Header().Get("X-Request-ID") != e.RequestID {
+		t.Errorf("Expected action and request ID to be recorded, got: %+v", e)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")}
+	cases := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"no proxy", "203.0.113.9:4000", nil, "203.0.113.9"},
+		{"untrusted peer", "203.0.113.9:4000", []string{"198.51.100.1"}, "203.0.113.9"},
+		{"trusted peer without header", "192.0.2.1:4000", nil, "192.0.2.1"},
+		{"spoofed leftmost hop", "192.0.2.1:4000", []string{"1.2.3.4, 198.51.100.1, 10.0.0.5"}, "198.51.100.1"},
+		{"repeated headers", "192.0.2.1:4000", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"only proxies", "192.0.2.1:4000", []string{"10.0.0.6, 10.0.0.5"}, "10.0.0.6"},
+		{"mapped peer", "[::ffff:192.0.2.1]:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/tables", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for _, value := range tc.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if got := clientIP(req, proxies); got != tc.want {
+				t.Errorf("Expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRequestActor(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v1/promotions/1/approve", nil)
+	if got := RequestActor(req); got != "anonymous" {
+		t.Errorf("Expected anonymous, got %s", got)
+	}
+	req.SetBasicAuth("carol", "secret")
+	if got := RequestActor(req); got != "carol" {
+		t.Errorf("Expected the basic auth user, got %s", got)
+	}
+	req.Header.Set("X-User", "bob")
+	req.Header.Set("X-Actor", " alice ")
+	if got := RequestActor(req); got != "alice" {
+		t.Errorf("Expected X-Actor to win, got %s", got)
+	}
+}
+
+func TestAuditMiddlewareSkipsReads(t *testing.T) {
+	recorder := &memoryRecorder{}
+	handler := AuditMiddleware(recorder, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest("GET", "/api/tables", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if len(recorder.entries) != 0 {
+		t.Errorf("Expected reads not to be audited, got: %d entries", len(recorder.entries))
+	}
+	if rec.Header().Get("X-Request-ID") != "req-1" {
+		t.Errorf("Expected incoming request ID to be echoed, got: %q", rec.Header().Get("X-Request-ID"))
+	}
+}
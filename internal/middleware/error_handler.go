@@ -1,83 +1,64 @@
 package middleware
 
 import (
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"runtime"
 	"strings"
-)
-
-// ErrorContext holds error information with stack trace
-type ErrorContext struct {
-	Error      error
-	StatusCode int
-	StackTrace []string
-	RequestURI string
-	Method     string
-}
-
-// errorResponseWriter wraps http.ResponseWriter to capture errors
-type errorResponseWriter struct {
-	http.ResponseWriter
-	statusCode   int
-	errorContext *ErrorContext
-}
-
-func newErrorResponseWriter(w http.ResponseWriter, requestURI, method string) *errorResponseWriter {
-	return &errorResponseWriter{
-		ResponseWriter: w,
-		statusCode:     http.StatusOK,
-		errorContext: &ErrorContext{
-			RequestURI: requestURI,
-			Method:     method,
-		},
-	}
-}
-
-func (erw *errorResponseWriter) WriteHeader(code int) {
-	erw.statusCode = code
-	erw.errorContext.StatusCode = code
-	erw.ResponseWriter.WriteHeader(code)
-}
 
-func (erw *errorResponseWriter) Write(data []byte) (int, error) {
-	// If this is an error response, capture the error messaget
-	if erw.statusCode >= 400 && erw.errorContext.Error == nil {
-		erw.errorContext.Error = errors.New(string(data))
-		erw.errorContext.StackTrace = captureStackTrace(3) // Skip middleware frames
-	}
-	return erw.ResponseWriter.Write(data)
-}
+	"progressive/internal/apierror"
+)
 
-// ErrorHandlingMiddleware captures and logs detailed error information
+// ErrorHandlingMiddleware renders the errors handlers return as problem+json
+// documents (see apierror) and recovers from panics, logging them with a
+// stack trace. Causes of server errors go to the log only, so internal
+// details such as SQL errors never reach the client. It reuses the response
+// writer of LoggingMiddleware and puts the error summary in its request
+// record; on its own it logs server errors itself.
 func ErrorHandlingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Create error-aware response writer
-		erw := newErrorResponseWriter(w, r.RequestURI, r.Method)
+		rw, out, shared := sharedResponseWriter(w, r)
+		ctx, slot := apierror.WithSlot(r.Context())
+		r = r.WithContext(ctx)
 
-		// Defer error logging
 		defer func() {
 			if rec := recover(); rec != nil {
-				// Handle panics
-				erw.errorContext.Error = fmt.Errorf("panic: %v", rec)
-				erw.errorContext.StatusCode = http.StatusInternalServerError
-				erw.errorContext.StackTrace = captureStackTrace(0)
-				logError(erw.errorContext)
-
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				slog.ErrorContext(r.Context(), "panic",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.RequestURI()),
+					slog.Any("panic", rec),
+					slog.Any("stack", captureStackTrace(3)),
+				)
+				slot.Err = apierror.Internal(fmt.Errorf("panic: %v", rec))
+			}
+			if slot.Err == nil {
 				return
 			}
 
-			// Log errors for 4xx and 5xx status codes
-			if erw.statusCode >= 400 {
-				logError(erw.errorContext)
+			if rw.wroteHeader {
+				// Too late to change the response; keep the error for the log
+				slog.WarnContext(r.Context(), "error after response started",
+					slog.String("path", r.URL.RequestURI()), slog.String("error", apierror.Summary(slot.Err)))
+			} else {
+				apierror.Write(out, r, slot.Err)
+			}
+			rw.errorMessage = apierror.Summary(slot.Err)
+
+			if !shared && rw.statusCode >= 500 {
+				slog.ErrorContext(r.Context(), "server error",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.RequestURI()),
+					slog.Int("status", rw.statusCode),
+					slog.String("error", rw.errorMessage),
+				)
 			}
 		}()
 
-		// Process request
-		next.ServeHTTP(erw, r)
+		next.ServeHTTP(out, r)
 	})
 }
 
@@ -109,44 +90,6 @@ func captureStackTrace(skip int) []string {
 	return stackTrace
 }
 
-// logError logs detailed error information
-func logError(ctx *ErrorContext) {
-	if ctx == nil || ctx.Error == nil {
-		return
-	}
-
-	statusEmoji := getErrorEmoji(ctx.StatusCode)
-
-	log.Printf("\n"+
-		"🚨 ERROR TRACE %s\n"+
-		"├─ Request: %s %s\n"+
-		"├─ Status: %d %s\n"+
-		"├─ Error: %s\n"+
-		"└─ Stack Trace:",
-		statusEmoji, ctx.Method, ctx.RequestURI, ctx.StatusCode, statusEmoji, ctx.Error.Error())
-
-	for i, frame := range ctx.StackTrace {
-		if i == len(ctx.StackTrace)-1 {
-			log.Printf("   └─ %s", frame)
-		} else {
-			log.Printf("   ├─ %s", frame)
-		}
-	}
-	log.Println() // Empty line for readability
-}
-
-// getErrorEmoji returns emoji based on status code
-func getErrorEmoji(statusCode int) string {
-	switch {
-	case statusCode >= 500:
-		return "💥"
-	case statusCode >= 400:
-		return "⚠️"
-	default:
-		return "❓"
-	}
-}
-
 // shortenPath shortens file paths for better readability
 func shortenPath(path string) string {
 	parts := strings.Split(path, "/")
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/internal/apierror"
+)
+
+func TestErrorHandlingRendersProblems(t *testing.T) {
+	logs := captureLogs(t)
+	handler := RequestContextMiddleware(LoggingMiddleware(ErrorHandlingMiddleware(apierror.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/invalid" {
+			return apierror.Validation("Record is invalid", apierror.FieldError{Field: "name", Code: "required", Message: "name is required"})
+		}
+		return apierror.Internal(errors.New(`pq: relation "records" does not exist`))
+	}))))
+
+	req := httptest.NewRequest("GET", "/broken", nil)
+	req.Header.Set("X-Request-ID", "req-9")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get("Content-Type") != apierror.ContentType {
+		t.Fatalf("Expected a 500 problem document, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if strings.Contains(rec.Body.String(), "pq:") {
+		t.Errorf("Expected the cause to stay out of the response, got %s", rec.Body.String())
+	}
+	var problem apierror.Problem
+	json.Unmarshal(rec.Body.Bytes(), &problem)
+	if problem.Code != apierror.CodeInternal || problem.RequestID != "req-9" || problem.Instance != "/broken" || problem.Status != 500 {
+		t.Errorf("Unexpected problem: %+v", problem)
+	}
+	if !strings.Contains(logs.String(), `pq: relation`) {
+		t.Errorf("Expected the cause in the log, got %s", logs.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/invalid", nil))
+	problem = apierror.Problem{}
+	json.Unmarshal(rec.Body.Bytes(), &problem)
+	if rec.Code != http.StatusBadRequest || problem.Code != apierror.CodeValidation || len(problem.Errors) != 1 || problem.Errors[0].Field != "name" {
+		t.Errorf("Expected a validation problem with field errors, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandlerFuncWritesWithoutMiddleware(t *testing.T) {
+	handler := apierror.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return apierror.NotFound("table_not_found", "Table not found")
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/tables/x", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"code":"table_not_found"`) {
+		t.Errorf("Expected a 404 problem, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -1,82 +1,116 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
 	"time"
 )
 
-// responseWriter is a wrapper around http.ResponseWriter to capture status code
+// maxErrorCapture is how much of a 5xx response body is kept as the error message
+const maxErrorCapture = 512
+
+// responseWriter is a wrapper around http.ResponseWriter to capture the status
+// code, the response size and the message of server errors. LoggingMiddleware
+// creates one per request and shares it with the inner middleware through the
+// request context, so the response is wrapped once.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode   int
+	wroteHeader  bool
+	bytes        int64
+	errorMessage string
 }
 
 func newResponseWriter(w http.ResponseWriter) *responseWriter {
-	return &responseWriter{w, http.StatusOK}
+	return &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// LoggingMiddleware logs HTTP requests with method, path, status code, and duration
-func LoggingMiddleware(next http.Handler) http.Handler {
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	// Client errors are expected and their bodies are for the client; only
+	// server errors are worth keeping for the log
+	if rw.statusCode >= 500 && rw.errorMessage == "" {
+		message := data
+		if len(message) > maxErrorCapture {
+			message = message[:maxErrorCapture]
+		}
+		rw.errorMessage = string(message)
+	}
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+type responseWriterKey struct{}
+
+// sharedResponseWriter returns the writer LoggingMiddleware put in the
+// request context to read the response state from, and w to keep writing
+// to. When the middleware runs on its own, w is wrapped and both are the
+// new wrapper.
+func sharedResponseWriter(w http.ResponseWriter, r *http.Request) (rw *responseWriter, out http.ResponseWriter, shared bool) {
+	if rw, ok := r.Context().Value(responseWriterKey{}).(*responseWriter); ok {
+		return rw, w, true
+	}
+	rw = newResponseWriter(w)
+	return rw, rw, false
+}
+
+// RequestObserver receives what LoggingMiddleware measured for a request,
+// e.g. to record metrics
+type RequestObserver func(r *http.Request, status int, duration time.Duration)
+
+// LoggingMiddleware logs one structured record per request with method, path,
+// status code, size and duration, and passes the same measurements to
+// observers. Server errors are logged at error level, client errors at warn
+// level, both with the error ErrorHandlingMiddleware handled.
+func LoggingMiddleware(next http.Handler, observers ...RequestObserver) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		
-		// Create a custom ResponseWriter to capture status code
+
 		rw := newResponseWriter(w)
-		
-		// Process the request
+		r = r.WithContext(context.WithValue(r.Context(), responseWriterKey{}, rw))
+
 		next.ServeHTTP(rw, r)
-		
-		// Log the request
+
 		duration := time.Since(start)
-		statusEmoji := getStatusEmoji(rw.statusCode)
-		methodEmoji := getMethodEmoji(r.Method)
-		
-		log.Printf("%s %s %s %s - %d %s (%v)",
-			methodEmoji,
-			r.Method,
-			r.RequestURI,
-			r.RemoteAddr,
-			rw.statusCode,
-			statusEmoji,
-			duration,
-		)
-	})
-}
+		level := slog.LevelInfo
+		switch {
+		case rw.statusCode >= 500:
+			level = slog.LevelError
+		case rw.statusCode >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.RequestURI()),
+			slog.Int("status", rw.statusCode),
+			slog.Int64("bytes", rw.bytes),
+			slog.Duration("duration", duration),
+			slog.String("remote", r.RemoteAddr),
+		}
+		if rw.errorMessage != "" {
+			attrs = append(attrs, slog.String("error", rw.errorMessage))
+		}
+		slog.LogAttrs(r.Context(), level, "request", attrs...)
 
-func getStatusEmoji(statusCode int) string {
-	switch {
-	case statusCode >= 200 && statusCode < 300:
-		return "✅"
-	case statusCode >= 300 && statusCode < 400:
-		return "🔄"
-	case statusCode >= 400 && statusCode < 500:
-		return "⚠️"
-	case statusCode >= 500:
-		return "❌"
-	default:
-		return "❓"
-	}
+		for _, observe := range observers {
+			observe(r, rw.statusCode, duration)
+		}
+	})
 }
-
-func getMethodEmoji(method string) string {
-	switch method {
-	case "GET":
-		return "📄"
-	case "POST":
-		return "📝"
-	case "PUT":
-		return "✏️"
-	case "DELETE":
-		return "🗑️"
-	case "PATCH":
-		return "🔧"
-	default:
-		return "🔗"
-	}
-}
\ No newline at end of file
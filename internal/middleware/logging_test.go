@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/internal/logging"
+)
+
+// captureLogs sends the default logger to a JSON buffer for the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, logging.FormatJSON, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Malformed log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRequestLoggingWithRequestContext(t *testing.T) {
+	logs := captureLogs(t)
+	var handlerRequestID, handlerTraceID string
+	handler := RequestContextMiddleware(LoggingMiddleware(ErrorHandlingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = logging.RequestID(r.Context())
+		trace, _ := logging.TraceFrom(r.Context())
+		handlerTraceID = trace.TraceID
+		http.Error(w, "bad input", http.StatusBadRequest)
+	}))))
+
+	req := httptest.NewRequest("GET", "/api/tables?page=2", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if handlerRequestID != "req-42" || rec.Header().Get("X-Request-ID") != "req-42" {
+		t.Errorf("Expected the incoming request ID to be propagated, got %q / %q", handlerRequestID, rec.Header().Get("X-Request-ID"))
+	}
+	if handlerTraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the incoming trace to be continued, got %q", handlerTraceID)
+	}
+
+	records := decodeRecords(t, logs)
+	if len(records) != 1 {
+		t.Fatalf("Expected one record per request, got %d", len(records))
+	}
+	r := records[0]
+	if r["msg"] != "request" || r["level"] != "WARN" || r["status"] != float64(400) || r["path"] != "/api/tables?page=2" || r["request_id"] != "req-42" {
+		t.Errorf("Unexpected request record: %v", r)
+	}
+	if _, ok := r["error"]; ok {
+		t.Errorf("Expected client error bodies to stay out of the log, got %v", r["error"])
+	}
+}
+
+func TestRequestContextGeneratesIDs(t *testing.T) {
+	var requestID string
+	handler := RequestContextMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = logging.RequestID(r.Context())
+		if _, ok := logging.TraceFrom(r.Context()); !ok {
+			t.Error("Expected a new trace to be started")
+		}
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Request-ID", "not valid\n")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if requestID == "" || requestID == "not valid\n" || rec.Header().Get("X-Request-ID") != requestID {
+		t.Errorf("Expected an invalid request ID to be replaced, got %q", requestID)
+	}
+}
+
+func TestPanicIsLoggedOnce(t *testing.T) {
+	logs := captureLogs(t)
+	handler := LoggingMiddleware(ErrorHandlingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/api/tables", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", rec.Code)
+	}
+	records := decodeRecords(t, logs)
+	if len(records) != 2 || records[0]["msg"] != "panic" || records[0]["stack"] == nil {
+		t.Fatalf("Expected a panic record with a stack, got %v", records)
+	}
+	if records[1]["msg"] != "request" || records[1]["level"] != "ERROR" || records[1]["error"] == nil || !strings.Contains(records[1]["error"].(string), "panic: boom") {
+		t.Errorf("Unexpected request record: %v", records[1])
+	}
+}
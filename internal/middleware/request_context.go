@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"progressive/internal/logging"
+)
+
+// RequestContextMiddleware gives every request an ID and a trace context
+// before anything else runs. The ID is taken from X-Request-ID when the
+// client or a proxy sent a usable one and generated otherwise; it is echoed
+// in the response. An incoming W3C traceparent is continued in a new child
+// span, otherwise a new trace starts. Both are stored in the request
+// context, so everything logged with it is tagged.
+func RequestContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(logging.RequestIDHeader)
+		if !logging.ValidRequestID(requestID) {
+			requestID = logging.NewRequestID()
+			r.Header.Set(logging.RequestIDHeader, requestID)
+		}
+		w.Header().Set(logging.RequestIDHeader, requestID)
+
+		trace := logging.NewTrace()
+		if parent, ok := logging.ParseTraceparent(r.Header.Get(logging.TraceparentHeader)); ok {
+			trace = parent.Child()
+		}
+
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		ctx = logging.WithTrace(ctx, trace)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
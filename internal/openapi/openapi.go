@@ -0,0 +1,274 @@
+// Package openapi builds OpenAPI 3.1 documents: one for the static API,
+// whose payload schemas are reflected from the Go request and response
+// types, and one per table, whose record model is the table's JSON Schema.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version of the documents
+const Version = "3.1.0"
+
+// Schema is a JSON Schema (2020-12, as OpenAPI 3.1 uses)
+type Schema = map[string]interface{}
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	// types maps reflected Go types to their component names
+	types map[string]string
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL the paths are relative to
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower-case method
+type PathItem map[string]*Operation
+
+// Operation is one method on one path
+type Operation struct {
+	OperationID string               `json:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// RequestBody is the body of an operation
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response is one response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+// New creates a document whose paths are relative to server. The error
+// responses of every operation refer to the shared Problem schema.
+func New(title, version, server string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]Schema{"Problem": problemSchema()},
+		},
+		types: map[string]string{},
+	}
+	if server != "" {
+		d.Servers = []Server{{URL: server}}
+	}
+	return d
+}
+
+// Endpoint describes an operation compactly; Add expands it
+type Endpoint struct {
+	Summary     string
+	Description string
+	Tag         string
+	Query       []Parameter
+	// Request and Response are Go values whose types are reflected, or
+	// Schemas used as they are; nil means no JSON body
+	Request  interface{}
+	Response interface{}
+	// Status is the success status, 200 when zero
+	Status int
+	// ContentType is the success content type when it is not JSON
+	ContentType string
+	Deprecated  bool
+}
+
+// Add registers an operation for method and path, a pattern such as
+// "/tables/{id}". Path parameters are declared from the pattern.
+func (d *Document) Add(method, path string, e Endpoint) *Operation {
+	op := &Operation{
+		OperationID: OperationID(method, path),
+		Summary:     e.Summary,
+		Description: e.Description,
+		Parameters:  append(PathParameters(path), e.Query...),
+		Responses:   map[string]*Response{"default": problemResponse()},
+		Deprecated:  e.Deprecated,
+	}
+	if e.Tag != "" {
+		op.Tags = []string{e.Tag}
+		d.addTag(e.Tag)
+	}
+	if e.Request != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{"application/json": {Schema: d.SchemaOf(e.Request)}}}
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case e.ContentType != "":
+		success.Content = map[string]MediaType{e.ContentType: {Schema: Schema{"type": "string", "format": "binary"}}}
+	case e.Response != nil:
+		success.Content = map[string]MediaType{"application/json": {Schema: d.SchemaOf(e.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	d.AddOperation(method, path, op)
+	return op
+}
+
+// AddOperation registers a fully built operation
+func (d *Document) AddOperation(method, path string, op *Operation) {
+	item := d.Paths[path]
+	if item == nil {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+func (d *Document) addTag(name string) {
+	for _, tag := range d.Tags {
+		if tag.Name == name {
+			return
+		}
+	}
+	d.Tags = append(d.Tags, Tag{Name: name})
+}
+
+// PathParameters declares the {wildcards} of a path as required string
+// parameters
+func PathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.TrimSuffix(strings.Trim(segment, "{}"), "...")
+			if name == "$" {
+				continue
+			}
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: Schema{"type": "string"}})
+		}
+	}
+	return params
+}
+
+// QueryParameter declares an optional query parameter
+func QueryParameter(name, typ, description string) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: Schema{"type": typ}}
+}
+
+// OperationID derives a camel-case ID such as "getTablesIdRecords" from a
+// method and path
+func OperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, segment := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '_' || r == '.' || r == '$'
+	}) {
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
+
+// Ref refers to a component schema
+func Ref(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// Object is an object schema with the given properties
+func Object(properties Schema, required ...string) Schema {
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// ArrayOf is an array schema of items
+func ArrayOf(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+// Success is the {"success": true} acknowledgement most mutations return,
+// with extra properties
+func Success(extra Schema) Schema {
+	properties := Schema{"success": Schema{"type": "boolean"}}
+	for k, v := range extra {
+		properties[k] = v
+	}
+	return Object(properties, "success")
+}
+
+func problemSchema() Schema {
+	return Object(Schema{
+		"type":       Schema{"type": "string", "format": "uri"},
+		"title":      Schema{"type": "string"},
+		"status":     Schema{"type": "integer"},
+		"detail":     Schema{"type": "string"},
+		"instance":   Schema{"type": "string"},
+		"code":       Schema{"type": "string", "description": "Stable machine-readable error code"},
+		"request_id": Schema{"type": "string"},
+		"errors": ArrayOf(Object(Schema{
+			"field":   Schema{"type": "string"},
+			"code":    Schema{"type": "string"},
+			"message": Schema{"type": "string"},
+		}, "field", "message")),
+	}, "type", "title", "status", "code")
+}
+
+func problemResponse() *Response {
+	return &Response{
+		Description: "Error as an RFC 7807 problem document",
+		Content:     map[string]MediaType{"application/problem+json": {Schema: Ref("Problem")}},
+	}
+}
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"progressive/internal/domain/table"
+)
+
+type base struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created_at"`
+}
+
+type item struct {
+	base
+	Name     string          `json:"name"`
+	Tags     []string        `json:"tags,omitempty"`
+	Data     json.RawMessage `json:"data"`
+	Parent   *item           `json:"parent"`
+	Internal string          `json:"-"`
+	hidden   string
+}
+
+func TestSchemaOf(t *testing.T) {
+	d := New("Test", "1", "")
+
+	if s := d.SchemaOf(item{}); !reflect.DeepEqual(s, Ref("item")) {
+		t.Fatalf("Expected a reference to the item component, got %v", s)
+	}
+	properties := d.Components.Schemas["item"]["properties"].(Schema)
+
+	for _, name := range []string{"id", "created_at", "name", "tags", "data", "parent"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Expected property %q, got %v", name, properties)
+		}
+	}
+	for _, name := range []string{"Internal", "hidden", "base"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Unexpected property %q", name)
+		}
+	}
+	if got := properties["created_at"]; !reflect.DeepEqual(got, Schema{"type": "string", "format": "date-time"}) {
+		t.Errorf("Expected a date-time string, got %v", got)
+	}
+	if got := properties["parent"]; !reflect.DeepEqual(got, Ref("item")) {
+		t.Errorf("Expected the recursive field to refer to item, got %v", got)
+	}
+	if got := properties["data"]; len(got.(Schema)) != 0 {
+		t.Errorf("Expected raw JSON to accept anything, got %v", got)
+	}
+
+	s := Schema{"type": "string"}
+	if got := d.SchemaOf(s); !reflect.DeepEqual(got, s) {
+		t.Errorf("Expected a Schema to be used as it is, got %v", got)
+	}
+}
+
+func TestAddDeclaresParametersAndResponses(t *testing.T) {
+	d := New("Test", "1", "/api")
+	op := d.Add("POST", "/tables/{id}/records", Endpoint{Tag: "records", Request: Schema{"type": "object"}, Status: 201})
+
+	if op.OperationID != "postTablesIdRecords" {
+		t.Errorf("Unexpected operation ID %q", op.OperationID)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" || !op.Parameters[0].Required {
+		t.Errorf("Expected the id path parameter, got %+v", op.Parameters)
+	}
+	if op.Responses["201"] == nil || op.Responses["default"] == nil {
+		t.Errorf("Expected success and problem responses, got %v", op.Responses)
+	}
+	if d.Paths["/tables/{id}/records"]["post"] != op {
+		t.Error("Expected the operation to be registered")
+	}
+}
+
+func TestForTable(t *testing.T) {
+	tbl := &table.Table{
+		ID:   "items",
+		Name: "Items",
+		Schema: json.RawMessage(`{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"type": "object",
+			"properties": {"name": {"type": "string"}, "stats": {"$ref": "#/definitions/Stats"}},
+			"required": ["name"],
+			"definitions": {"Stats": {"type": "object", "properties": {"atk": {"type": "integer"}}}}
+		}`),
+	}
+
+	d, err := ForTable(tbl, "/api/v1")
+	if err != nil {
+		t.Fatalf("ForTable failed: %v", err)
+	}
+
+	record := d.Components.Schemas["Record"]
+	if record["$schema"] != nil || record["definitions"] != nil {
+		t.Errorf("Expected $schema and definitions to be removed, got %v", record)
+	}
+	stats := record["properties"].(map[string]interface{})["stats"].(map[string]interface{})
+	if stats["$ref"] != "#/components/schemas/Stats" {
+		t.Errorf("Expected the reference to point at components, got %v", stats)
+	}
+	if d.Components.Schemas["Stats"] == nil {
+		t.Error("Expected the Stats definition as a component")
+	}
+	if _, ok := d.Components.Schemas["RecordPatch"]["required"]; ok {
+		t.Error("Expected RecordPatch to have no required fields")
+	}
+
+	for path, methods := range map[string][]string{
+		"/tables/items/records":          {"get", "post"},
+		"/tables/items/records/{record}": {"get", "patch", "delete"},
+		"/tables/items/import":           {"post"},
+		"/tables/items/export":           {"get"},
+	} {
+		for _, method := range methods {
+			if d.Paths[path][method] == nil {
+				t.Errorf("Expected %s %s", method, path)
+			}
+		}
+	}
+	param := d.Paths["/tables/items/records/{record}"]["patch"].Parameters[0]
+	if param.Schema["type"] != "integer" {
+		t.Errorf("Expected an integer record ID, got %v", param.Schema)
+	}
+
+	if _, err := ForTable(&table.Table{ID: "bad", Schema: json.RawMessage(`{`)}, ""); err == nil {
+		t.Error("Expected an error for an invalid schema")
+	}
+}
@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaOf returns the schema of v: a Schema is used as it is, anything
+// else is described by reflecting its type the way encoding/json encodes it.
+// Named structs become components and are referenced.
+func (d *Document) SchemaOf(v interface{}) Schema {
+	if s, ok := v.(Schema); ok {
+		return s
+	}
+	if v == nil {
+		return Schema{}
+	}
+	return d.schemaOfType(reflect.TypeOf(v))
+}
+
+func (d *Document) schemaOfType(t reflect.Type) Schema {
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		// Arbitrary JSON
+		return Schema{}
+	case t.Kind() != reflect.Pointer && (t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType)):
+		return Schema{}
+	case t.Kind() != reflect.Pointer && (t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)):
+		return Schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return d.schemaOfType(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Schema{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return ArrayOf(d.schemaOfType(t.Elem()))
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": d.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		return Ref(d.component(t))
+	default:
+		return Schema{}
+	}
+}
+
+// component registers the named struct t and returns its component name
+func (d *Document) component(t reflect.Type) string {
+	key := t.PkgPath() + "." + t.Name()
+	if name, ok := d.types[key]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := d.Components.Schemas[name]; taken {
+		// Same name in another package, e.g. snapshot.Table and table.Table
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	d.types[key] = name
+	// Registered before the fields so recursive types refer to themselves
+	d.Components.Schemas[name] = Schema{}
+	d.Components.Schemas[name] = d.structSchema(t)
+	return name
+}
+
+// structSchema describes the JSON object of a struct; embedded structs
+// without a JSON name are flattened into it as encoding/json does
+func (d *Document) structSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+				addFields(fieldType)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = d.schemaOfType(field.Type)
+		}
+	}
+	addFields(t)
+	return Schema{"type": "object", "properties": properties}
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"progressive/internal/domain/table"
+)
+
+// ForTable builds the document of one table's record API. The table's JSON
+// Schema becomes the Record component; list, create, patch, delete, import
+// and export operations are typed with it. Paths are relative to server,
+// e.g. "/api/v1".
+func ForTable(t *table.Table, server string) (*Document, error) {
+	var record Schema
+	if err := json.Unmarshal(t.Schema, &record); err != nil {
+		return nil, fmt.Errorf("table %s has an invalid schema: %w", t.ID, err)
+	}
+
+	d := New(t.Name+" API", "1.0.0", server)
+	d.Info.Description = t.Description
+
+	// Definitions move to components so that the references to them still resolve
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := record[key].(map[string]interface{}); ok {
+			for name, def := range defs {
+				if def, ok := rewriteRefs(def).(map[string]interface{}); ok {
+					d.Components.Schemas[name] = def
+				}
+			}
+			delete(record, key)
+		}
+	}
+	delete(record, "$schema")
+	delete(record, "$id")
+	record = rewriteRefs(record).(map[string]interface{})
+	if record["title"] == nil {
+		record["title"] = t.Name
+	}
+	d.Components.Schemas["Record"] = record
+
+	// A patch may leave out any field
+	patch := make(Schema, len(record))
+	for k, v := range record {
+		if k != "required" {
+			patch[k] = v
+		}
+	}
+	patch["title"] = t.Name + " (partial)"
+	d.Components.Schemas["RecordPatch"] = patch
+
+	d.Components.Schemas["StoredRecord"] = Schema{"allOf": []Schema{
+		Ref("Record"),
+		Object(Schema{
+			"_id":         Schema{"type": "integer", "format": "int64", "readOnly": true},
+			"_created_at": Schema{"type": "string", "format": "date-time", "readOnly": true},
+		}, "_id"),
+	}}
+	d.Components.Schemas["RecordPage"] = Object(Schema{
+		"table":   d.SchemaOf(t),
+		"records": ArrayOf(Ref("StoredRecord")),
+		"pagination": Object(Schema{
+			"page":     Schema{"type": "integer"},
+			"limit":    Schema{"type": "integer"},
+			"total":    Schema{"type": "integer"},
+			"has_more": Schema{"type": "boolean"},
+		}, "page", "limit", "total", "has_more"),
+	}, "table", "records", "pagination")
+
+	base := "/tables/" + t.ID
+	tag := t.Name
+	d.addTag(tag)
+	add := func(method, path, id string, e Endpoint) *Operation {
+		e.Tag = tag
+		op := d.Add(method, path, e)
+		op.OperationID = id
+		return op
+	}
+
+	add("GET", base+"/records", "listRecords", Endpoint{
+		Summary:  "List records",
+		Query:    []Parameter{QueryParameter("page", "integer", "Page number, from 1"), QueryParameter("limit", "integer", "Records per page (default 20)")},
+		Response: Ref("RecordPage"),
+	})
+	add("POST", base+"/records", "createRecord", Endpoint{
+		Summary:  "Create a record",
+		Request:  Ref("Record"),
+		Response: Success(Schema{"id": Schema{"type": "integer", "format": "int64"}}),
+	})
+	add("GET", base+"/records/{record}", "getRecord", Endpoint{
+		Summary:  "Get a record",
+		Response: Ref("StoredRecord"),
+	})
+	add("PATCH", base+"/records/{record}", "updateRecord", Endpoint{
+		Summary:     "Update a record",
+		Description: "Fields in the body replace those of the record; the others are kept.",
+		Request:     Ref("RecordPatch"),
+		Response:    Success(nil),
+	})
+	add("DELETE", base+"/records/{record}", "deleteRecord", Endpoint{
+		Summary:  "Delete a record",
+		Response: Success(nil),
+	})
+	add("POST", base+"/import", "importRecords", Endpoint{
+		Summary: "Import records",
+		Request: Object(Schema{
+			"mode": Schema{"type": "string", "enum": []string{"append", "replace"}, "default": "replace"},
+			"data": ArrayOf(Ref("Record")),
+		}, "data"),
+		Response: Success(Schema{
+			"imported": Schema{"type": "integer"},
+			"mode":     Schema{"type": "string"},
+		}),
+	})
+
+	export := add("GET", base+"/export", "exportRecords", Endpoint{
+		Summary: "Export records",
+		Query:   []Parameter{{Name: "format", In: "query", Schema: Schema{"type": "string", "enum": []string{"json", "csv", "excel"}, "default": "json"}}},
+	})
+	export.Responses["200"].Content = map[string]MediaType{
+		"application/json": {Schema: ArrayOf(Ref("StoredRecord"))},
+		"text/csv":         {Schema: Schema{"type": "string"}},
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {Schema: Schema{"type": "string", "format": "binary"}},
+	}
+
+	// Record IDs are integers
+	for _, op := range d.Paths[base+"/records/{record}"] {
+		for i := range op.Parameters {
+			if op.Parameters[i].Name == "record" {
+				op.Parameters[i].Schema = Schema{"type": "integer", "format": "int64"}
+			}
+		}
+	}
+	return d, nil
+}
+
+// rewriteRefs points local references of a standalone JSON Schema
+// ("#/definitions/X", "#/$defs/X") at the document's components
+func rewriteRefs(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				for _, prefix := range []string{"#/definitions/", "#/$defs/"} {
+					if strings.HasPrefix(ref, prefix) {
+						ref = "#/components/schemas/" + strings.TrimPrefix(ref, prefix)
+					}
+				}
+				out[k] = ref
+				continue
+			}
+			out[k] = rewriteRefs(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = rewriteRefs(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
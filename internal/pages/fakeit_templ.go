@@ -46,7 +46,7 @@ func FakeitPage() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"h-full px-4 sm:pb-6 lg:pb-8 pb-6 flex flex-col\"><!-- Header --><div class=\"mb-6\"><div class=\"flex items-center justify-between\"><div><h1 class=\"text-2xl font-bold text-gray-900\">더미 데이터 생성기</h1><p class=\"mt-1 text-sm text-gray-600\">JSON Schema를 업로드하고 가짜 데이터를 생성하세요</p></div><div class=\"flex space-x-3\"><button id=\"clear-all-btn\" class=\"inline-flex items-center px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-4 h-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16\"></path></svg> 초기화</button> <button id=\"generate-data-btn\" class=\"inline-flex items-center px-4 py-2 border border-transparent text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-4 h-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 10V3L4 14h7v7l9-11h-7z\"></path></svg> 데이터 생성</button></div></div></div><div class=\"flex-1 grid grid-cols-12 gap-6 min-h-0\"><!-- Left Panel: Schema Upload & Configuration --><div class=\"col-span-5 flex flex-col space-y-6\"><!-- Schema Upload --><div class=\"bg-white rounded-lg border border-gray-200 p-6\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">JSON Schema 업로드</h3><div class=\"border-2 border-dashed border-gray-300 rounded-lg p-6 text-center hover:border-gray-400 transition-colors\"><input type=\"file\" id=\"schema-file-input\" accept=\".json\" class=\"hidden\"><div id=\"schema-drop-zone\" class=\"cursor-pointer\"><svg class=\"mx-auto h-12 w-12 text-gray-400 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-sm text-gray-600\"><span class=\"font-medium text-blue-600 hover:text-blue-500\">JSON Schema 파일을 선택하거나</span> 드래그하여 업로드</p><p class=\"text-xs text-gray-500 mt-2\">JSON Schema 파일만 지원됩니다 (최대 5MB)</p></div><!-- Selected file display --><div id=\"selected-schema-info\" class=\"hidden mt-4 p-3 bg-blue-50 rounded border border-blue-200\"><div class=\"flex items-center\"><svg class=\"w-5 h-5 text-blue-600 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><div><div id=\"selected-schema-name\" class=\"text-sm font-medium text-blue-900\"></div><div id=\"selected-schema-size\" class=\"text-xs text-blue-600\"></div></div><button id=\"remove-schema-btn\" class=\"ml-auto text-blue-400 hover:text-blue-600\"><svg class=\"w-4 h-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M6 18L18 6M6 6l12 12\"></path></svg></button></div></div></div><!-- Error display --><div id=\"schema-error\" class=\"hidden mt-4 p-3 bg-red-50 border border-red-200 rounded-md\"><div class=\"flex\"><svg class=\"w-5 h-5 text-red-400 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v4m0 4h.01M21 12a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg><div><h3 class=\"text-sm font-medium text-red-800\">스키마 오류</h3><div id=\"schema-error-message\" class=\"text-sm text-red-700 mt-1\"></div></div></div></div></div><!-- Field Configuration --><div class=\"bg-white rounded-lg border border-gray-200 p-6 flex-1 min-h-0\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">필드 설정</h3><div id=\"no-schema-message\" class=\"text-center text-gray-500 py-8\"><svg class=\"mx-auto h-8 w-8 text-gray-300 mb-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p class=\"text-sm\">먼저 JSON Schema를 업로드해주세요</p></div><div id=\"field-config-container\" class=\"hidden\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">생성할 데이터 개수</label> <input type=\"number\" id=\"data-count-input\" min=\"1\" max=\"10000\" value=\"10\" class=\"block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm\"></div><div class=\"space-y-4 overflow-y-auto max-h-96\" id=\"field-configs\"><!-- Field configurations will be generated here --></div></div></div></div><!-- Right Panel: Data Preview --><div class=\"col-span-7 flex flex-col\"><div class=\"bg-white rounded-lg border border-gray-200 flex-1 flex flex-col min-h-0\"><div class=\"flex items-center justify-between p-6 border-b border-gray-200\"><h3 class=\"text-lg font-medium text-gray-900\">데이터 미리보기</h3><div class=\"flex space-x-2\"><button id=\"export-json-btn\" class=\"inline-flex items-center px-3 py-1.5 border border-gray-300 text-xs font-medium rounded text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-3 h-3 mr-1\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-4l-4 4m0 0l-4-4m4 4V4\"></path></svg> JSON</button> <button id=\"export-csv-btn\" class=\"inline-flex items-center px-3 py-1.5 border border-gray-300 text-xs font-medium rounded text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-3 h-3 mr-1\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-4l-4 4m0 0l-4-4m4 4V4\"></path></svg> CSV</button></div></div><div class=\"flex-1 overflow-auto p-6\" id=\"data-preview\"><div class=\"text-center text-gray-500 py-12\"><svg class=\"mx-auto h-12 w-12 text-gray-300 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M3 14h18m-9-4v8m-7 0V4a1 1 0 011-1h14a1 1 0 011 1v16a1 1 0 01-1 1H5a1 1 0 01-1-1z\"></path></svg><p class=\"text-sm\">데이터를 생성하면 여기에 미리보기가 표시됩니다</p></div></div></div></div></div></div><!-- Loading Modal --> <div id=\"loading-modal\" class=\"hidden fixed inset-0 bg-gray-500 bg-opacity-75 flex items-center justify-center z-50\"><div class=\"bg-white rounded-lg p-8 max-w-sm w-full mx-4\"><div class=\"text-center\"><svg class=\"mx-auto h-12 w-12 animate-spin text-blue-600 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\"><circle class=\"opacity-25\" cx=\"12\" cy=\"12\" r=\"10\" stroke=\"currentColor\" stroke-width=\"4\"></circle> <path class=\"opacity-75\" fill=\"currentColor\" d=\"M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z\"></path></svg><h3 class=\"text-lg font-medium text-gray-900 mb-2\">데이터 생성 중...</h3><p class=\"text-sm text-gray-600\">잠시만 기다려주세요</p></div></div></div><!-- JavaScript --> <script src=\"/static/js/fakeit.js\"></script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"h-full px-4 sm:pb-6 lg:pb-8 pb-6 flex flex-col\"><!-- Header --><div class=\"mb-6\"><div class=\"flex items-center justify-between\"><div><h1 class=\"text-2xl font-bold text-gray-900\">더미 데이터 생성기</h1><p class=\"mt-1 text-sm text-gray-600\">JSON Schema를 업로드하고 가짜 데이터를 생성하세요</p></div><div class=\"flex space-x-3\"><button id=\"clear-all-btn\" class=\"inline-flex items-center px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-4 h-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M19 7l-.867 12.142A2 2 0 0116.138 21H7.862a2 2 0 01-1.995-1.858L5 7m5 4v6m4-6v6m1-10V4a1 1 0 00-1-1h-4a1 1 0 00-1 1v3M4 7h16\"></path></svg> 초기화</button> <button id=\"generate-data-btn\" class=\"inline-flex items-center px-4 py-2 border border-transparent text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-4 h-4 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M13 10V3L4 14h7v7l9-11h-7z\"></path></svg> 데이터 생성</button></div></div></div><div class=\"flex-1 grid grid-cols-12 gap-6 min-h-0\"><!-- Left Panel: Schema Upload & Configuration --><div class=\"col-span-5 flex flex-col space-y-6\"><!-- Schema Upload --><div class=\"bg-white rounded-lg border border-gray-200 p-6\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">JSON Schema 업로드</h3><div class=\"border-2 border-dashed border-gray-300 rounded-lg p-6 text-center hover:border-gray-400 transition-colors\"><input type=\"file\" id=\"schema-file-input\" accept=\".json\" class=\"hidden\"><div id=\"schema-drop-zone\" class=\"cursor-pointer\"><svg class=\"mx-auto h-12 w-12 text-gray-400 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-sm text-gray-600\"><span class=\"font-medium text-blue-600 hover:text-blue-500\">JSON Schema 파일을 선택하거나</span> 드래그하여 업로드</p><p class=\"text-xs text-gray-500 mt-2\">JSON Schema 파일만 지원됩니다 (최대 5MB)</p></div><!-- Selected file display --><div id=\"selected-schema-info\" class=\"hidden mt-4 p-3 bg-blue-50 rounded border border-blue-200\"><div class=\"flex items-center\"><svg class=\"w-5 h-5 text-blue-600 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><div><div id=\"selected-schema-name\" class=\"text-sm font-medium text-blue-900\"></div><div id=\"selected-schema-size\" class=\"text-xs text-blue-600\"></div></div><button id=\"remove-schema-btn\" class=\"ml-auto text-blue-400 hover:text-blue-600\"><svg class=\"w-4 h-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M6 18L18 6M6 6l12 12\"></path></svg></button></div></div></div><!-- Error display --><div id=\"schema-error\" class=\"hidden mt-4 p-3 bg-red-50 border border-red-200 rounded-md\"><div class=\"flex\"><svg class=\"w-5 h-5 text-red-400 mr-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M12 8v4m0 4h.01M21 12a9 9 0 11-18 0 9 9 0 0118 0z\"></path></svg><div><h3 class=\"text-sm font-medium text-red-800\">스키마 오류</h3><div id=\"schema-error-message\" class=\"text-sm text-red-700 mt-1\"></div></div></div></div></div><!-- Field Configuration --><div class=\"bg-white rounded-lg border border-gray-200 p-6 flex-1 min-h-0\"><h3 class=\"text-lg font-medium text-gray-900 mb-4\">필드 설정</h3><div id=\"no-schema-message\" class=\"text-center text-gray-500 py-8\"><svg class=\"mx-auto h-8 w-8 text-gray-300 mb-2\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M9 12h6m-6 4h6m2 5H7a2 2 0 01-2-2V5a2 2 0 012-2h5.586a1 1 0 01.707.293l5.414 5.414a1 1 0 01.293.707V19a2 2 0 01-2 2z\"></path></svg><p class=\"text-sm\">먼저 JSON Schema를 업로드해주세요</p></div><div id=\"field-config-container\" class=\"hidden\"><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">생성할 데이터 개수</label> <input type=\"number\" id=\"data-count-input\" min=\"1\" max=\"10000\" value=\"10\" class=\"block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm\"></div><div class=\"mb-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">시드 (선택)</label> <input type=\"number\" id=\"seed-input\" min=\"1\" placeholder=\"비우면 무작위\" class=\"block w-full rounded-md border-gray-300 shadow-sm focus:border-blue-500 focus:ring-blue-500 sm:text-sm\"><p id=\"used-seed\" class=\"hidden mt-1 text-xs text-gray-500\"></p></div><div class=\"space-y-4 overflow-y-auto max-h-96\" id=\"field-configs\"><!-- Field configurations will be generated here --></div></div></div></div><!-- Right Panel: Data Preview --><div class=\"col-span-7 flex flex-col\"><div class=\"bg-white rounded-lg border border-gray-200 flex-1 flex flex-col min-h-0\"><div class=\"flex items-center justify-between p-6 border-b border-gray-200\"><h3 class=\"text-lg font-medium text-gray-900\">데이터 미리보기</h3><div class=\"flex space-x-2\"><button id=\"export-json-btn\" class=\"inline-flex items-center px-3 py-1.5 border border-gray-300 text-xs font-medium rounded text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-3 h-3 mr-1\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-4l-4 4m0 0l-4-4m4 4V4\"></path></svg> JSON</button> <button id=\"export-csv-btn\" class=\"inline-flex items-center px-3 py-1.5 border border-gray-300 text-xs font-medium rounded text-gray-700 bg-white hover:bg-gray-50 disabled:opacity-50 disabled:cursor-not-allowed\" disabled><svg class=\"w-3 h-3 mr-1\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M4 16v1a3 3 0 003 3h10a3 3 0 003-3v-1m-4-4l-4 4m0 0l-4-4m4 4V4\"></path></svg> CSV</button></div></div><div class=\"flex-1 overflow-auto p-6\" id=\"data-preview\"><div class=\"text-center text-gray-500 py-12\"><svg class=\"mx-auto h-12 w-12 text-gray-300 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M3 14h18m-9-4v8m-7 0V4a1 1 0 011-1h14a1 1 0 011 1v16a1 1 0 01-1 1H5a1 1 0 01-1-1z\"></path></svg><p class=\"text-sm\">데이터를 생성하면 여기에 미리보기가 표시됩니다</p></div></div></div></div></div></div><!-- Loading Modal --> <div id=\"loading-modal\" class=\"hidden fixed inset-0 bg-gray-500 bg-opacity-75 flex items-center justify-center z-50\"><div class=\"bg-white rounded-lg p-8 max-w-sm w-full mx-4\"><div class=\"text-center\"><svg class=\"mx-auto h-12 w-12 animate-spin text-blue-600 mb-4\" fill=\"none\" viewBox=\"0 0 24 24\"><circle class=\"opacity-25\" cx=\"12\" cy=\"12\" r=\"10\" stroke=\"currentColor\" stroke-width=\"4\"></circle> <path class=\"opacity-75\" fill=\"currentColor\" d=\"M4 12a8 8 0 018-8V0C5.373 0 0 5.373 0 12h4zm2 5.291A7.962 7.962 0 014 12H0c0 3.042 1.135 5.824 3 7.938l3-2.647z\"></path></svg><h3 class=\"text-lg font-medium text-gray-900 mb-2\">데이터 생성 중...</h3><p class=\"text-sm text-gray-600\">잠시만 기다려주세요</p></div></div></div><!-- JavaScript --> <script src=\"/static/js/fakeit.js\"></script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
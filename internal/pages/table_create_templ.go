@@ -8,10 +8,22 @@ package pages
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "progressive/internal/components"
-import "progressive/internal/models"
+import (
+	"progressive/internal/components"
+	"progressive/internal/domain/schematemplate"
+)
 
-func TableCreatePage(templates []models.TableTemplate) templ.Component {
+// hasCategory reports whether any template is in the category
+func hasCategory(templates []*schematemplate.SchemaTemplate, category string) bool {
+	for _, t := range templates {
+		if t.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+func TableCreatePage(templates []*schematemplate.SchemaTemplate) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -49,7 +61,7 @@ func TableCreatePage(templates []models.TableTemplate) templ.Component {
 				return templ_7745c5c3_Err
 			}
 			for _, template := range templates {
-				if template.Category == "business" {
+				if template.Category == schematemplate.CategoryBusiness {
 					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<button class=\"template-btn p-4 border border-gray-200 rounded-lg text-left hover:border-blue-300 hover:bg-blue-50\" data-template=\"")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
@@ -57,7 +69,7 @@ func TableCreatePage(templates []models.TableTemplate) templ.Component {
 					var templ_7745c5c3_Var3 string
 					templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(template.ID)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 116, Col: 154}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 128, Col: 154}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 					if templ_7745c5c3_Err != nil {
@@ -68,85 +80,193 @@ func TableCreatePage(templates []models.TableTemplate) templ.Component {
 						return templ_7745c5c3_Err
 					}
 					var templ_7745c5c3_Var4 string
-					templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(template.Name)
+					templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(template.Icon)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 117, Col: 65}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 129, Col: 65}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</h5><p class=\"text-sm text-gray-500 mt-1\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, " ")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 					var templ_7745c5c3_Var5 string
-					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(template.Description)
+					templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(template.Name)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 118, Col: 72}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 129, Col: 83}
 					}
 					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</p></button>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "</h5><p class=\"text-sm text-gray-500 mt-1\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					var templ_7745c5c3_Var6 string
+					templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(template.Description)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 130, Col: 72}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</p></button>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div></div><!-- Game Category --><div class=\"mb-6\"><h4 class=\"text-sm font-medium text-gray-700 mb-3 flex items-center\"><svg class=\"w-4 h-4 mr-2 text-purple-600\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11.049 2.927c.3-.921 1.603-.921 1.902 0l1.519 4.674a1 1 0 00.95.69h4.915c.969 0 1.371 1.24.588 1.81l-3.976 2.888a1 1 0 00-.363 1.118l1.518 4.674c.3.922-.755 1.688-1.538 1.118l-3.976-2.888a1 1 0 00-1.176 0l-3.976 2.888c-.783.57-1.838-.197-1.538-1.118l1.518-4.674a1 1 0 00-.363-1.118l-3.976-2.888c-.784-.57-.38-1.81.588-1.81h4.914a1 1 0 00.951-.69l1.519-4.674z\"></path></svg> 게임 템플릿</h4><div class=\"grid grid-cols-1 gap-3\">")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "</div></div><!-- Game Category --><div class=\"mb-6\"><h4 class=\"text-sm font-medium text-gray-700 mb-3 flex items-center\"><svg class=\"w-4 h-4 mr-2 text-purple-600\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M11.049 2.927c.3-.921 1.603-.921 1.902 0l1.519 4.674a1 1 0 00.95.69h4.915c.969 0 1.371 1.24.588 1.81l-3.976 2.888a1 1 0 00-.363 1.118l1.518 4.674c.3.922-.755 1.688-1.538 1.118l-3.976-2.888a1 1 0 00-1.176 0l-3.976 2.888c-.783.57-1.838-.197-1.538-1.118l1.518-4.674a1 1 0 00-.363-1.118l-3.976-2.888c-.784-.57-.38-1.81.588-1.81h4.914a1 1 0 00.951-.69l1.519-4.674z\"></path></svg> 게임 템플릿</h4><div class=\"grid grid-cols-1 gap-3\">")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			for _, template := range templates {
-				if template.Category == "game" {
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<button class=\"template-btn p-4 border border-gray-200 rounded-lg text-left hover:border-purple-300 hover:bg-purple-50\" data-template=\"")
+				if template.Category == schematemplate.CategoryGame {
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<button class=\"template-btn p-4 border border-gray-200 rounded-lg text-left hover:border-purple-300 hover:bg-purple-50\" data-template=\"")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var6 string
-					templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(template.ID)
+					var templ_7745c5c3_Var7 string
+					templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(template.ID)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 136, Col: 158}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 148, Col: 158}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "\"><h5 class=\"font-medium text-gray-900\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "\"><h5 class=\"font-medium text-gray-900\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var7 string
-					templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(template.Name)
+					var templ_7745c5c3_Var8 string
+					templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(template.Icon)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 137, Col: 65}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 149, Col: 65}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "</h5><p class=\"text-sm text-gray-500 mt-1\">")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, " ")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					var templ_7745c5c3_Var8 string
-					templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(template.Description)
+					var templ_7745c5c3_Var9 string
+					templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(template.Name)
 					if templ_7745c5c3_Err != nil {
-						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 138, Col: 72}
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 149, Col: 83}
 					}
-					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "</p></button>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</h5><p class=\"text-sm text-gray-500 mt-1\">")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
+					var templ_7745c5c3_Var10 string
+					templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(template.Description)
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 150, Col: 72}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "</p></button>")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</div></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			if hasCategory(templates, schematemplate.CategoryCustom) {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<!-- Custom Category --> <div class=\"mb-6\"><h4 class=\"text-sm font-medium text-gray-700 mb-3 flex items-center\"><svg class=\"w-4 h-4 mr-2 text-green-600\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 5a2 2 0 012-2h10a2 2 0 012 2v16l-7-3.5L5 21V5z\"></path></svg> 내 템플릿</h4><div class=\"grid grid-cols-1 gap-3\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				for _, template := range templates {
+					if template.Category == schematemplate.CategoryCustom {
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<button class=\"template-btn p-4 border border-gray-200 rounded-lg text-left hover:border-green-300 hover:bg-green-50\" data-template=\"")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var11 string
+						templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(template.ID)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 169, Col: 157}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "\"><h5 class=\"font-medium text-gray-900\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var12 string
+						templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(template.Icon)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 170, Col: 66}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, " ")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var13 string
+						templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(template.Name)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 170, Col: 84}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</h5><p class=\"text-sm text-gray-500 mt-1\">")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						var templ_7745c5c3_Var14 string
+						templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(template.Description)
+						if templ_7745c5c3_Err != nil {
+							return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/table_create.templ`, Line: 171, Col: 73}
+						}
+						_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "</p></button>")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</div></div>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
 				}
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "</div></div></div></div><!-- Schema Status --><div id=\"schema-status\" class=\"hidden bg-green-50 border border-green-200 rounded-lg p-4\"><div class=\"flex items-center\"><svg class=\"h-5 w-5 text-green-600 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h4 class=\"text-sm font-medium text-green-800\">스키마 검증 완료</h4><p class=\"text-sm text-green-600 mt-1\" id=\"schema-fields-count\">필드 개수: 0개</p></div></div></div></div><!-- Right Column: Preview --><div class=\"space-y-6\"><div class=\"bg-white border border-gray-200 rounded-lg p-6\"><h2 class=\"text-lg font-semibold text-gray-900 mb-4\">2. 테이블 미리보기</h2><!-- Preview Container --><div id=\"table-preview\" class=\"border border-gray-200 rounded-lg overflow-hidden\"><div class=\"bg-gray-50 px-4 py-3 border-b border-gray-200\"><p class=\"text-sm text-gray-500 text-center\">스키마를 입력하면 테이블 구조가 여기에 표시됩니다</p></div><div class=\"p-8 text-center text-gray-400\"><svg class=\"mx-auto h-12 w-12\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M3 14h18m-9-4v8m-7 0V4a1 1 0 011-1h14a1 1 0 011 1v16a1 1 0 01-1 1H5a1 1 0 01-1-1z\"></path></svg><p class=\"mt-2\">테이블 미리보기</p></div></div></div><!-- Data Input Options --><div class=\"bg-white border border-gray-200 rounded-lg p-6\"><h2 class=\"text-lg font-semibold text-gray-900 mb-4\">3. 초기 데이터 설정</h2><div class=\"space-y-4\"><div class=\"flex items-center space-x-3\"><input id=\"empty-table\" type=\"radio\" name=\"data-option\" value=\"empty\" class=\"text-blue-600 focus:ring-blue-500\" checked> <label for=\"empty-table\" class=\"text-sm text-gray-700\">빈 테이블로 시작</label></div><div class=\"flex items-center space-x-3\"><input id=\"sample-data\" type=\"radio\" name=\"data-option\" value=\"sample\" class=\"text-blue-600 focus:ring-blue-500\"> <label for=\"sample-data\" class=\"text-sm text-gray-700\">샘플 데이터 포함</label></div><div class=\"flex items-center space-x-3\"><input id=\"import-data\" type=\"radio\" name=\"data-option\" value=\"import\" class=\"text-blue-600 focus:ring-blue-500\"> <label for=\"import-data\" class=\"text-sm text-gray-700\">CSV/JSON 파일 가져오기</label></div></div><div id=\"import-section\" class=\"mt-4 p-4 bg-gray-50 rounded-md hidden\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">데이터 파일 선택</label> <input type=\"file\" class=\"block w-full text-sm text-gray-500 file:mr-4 file:py-2 file:px-4 file:rounded-md file:border-0 file:text-sm file:font-semibold file:bg-blue-50 file:text-blue-700 hover:file:bg-blue-100\" accept=\".csv,.json\"></div></div><!-- Action Buttons --><div class=\"flex space-x-3\"><button id=\"create-table\" class=\"flex-1 bg-blue-600 hover:bg-blue-700 text-white px-6 py-3 rounded-md font-medium disabled:bg-gray-300 disabled:cursor-not-allowed\" disabled>테이블 생성</button> <button class=\"px-6 py-3 border border-gray-300 text-gray-700 rounded-md font-medium hover:bg-gray-50\">취소</button></div></div></div></div><!-- JavaScript for interactivity --> <script>\n\t\t\t// Templates data - hardcoded for now to avoid JSON parsing issues\n\t\t\tconst schemaTemplates = {\n\t\t\t\t// Business templates\n\t\t\t\t\"customer\": {\n\t\t\t\t\tname: \"customer_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\tname: { type: \"string\", title: \"이름\", minLength: 1 },\n\t\t\t\t\t\t\temail: { type: \"string\", format: \"email\", title: \"이메일\" },\n\t\t\t\t\t\t\tcompany: { type: \"string\", title: \"회사명\" },\n\t\t\t\t\t\t\tphone: { type: \"string\", title: \"연락처\", pattern: \"^[0-9-+()\\\\s]+$\" },\n\t\t\t\t\t\t\tinterest_level: { type: \"string\", title: \"관심도\", enum: [\"높음\", \"중간\", \"낮음\"] },\n\t\t\t\t\t\t\tregistration_date: { type: \"string\", format: \"date\", title: \"등록일\" }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"name\", \"email\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t\"project\": {\n\t\t\t\t\tname: \"project_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\ttitle: { type: \"string\", title: \"제목\", minLength: 1 },\n\t\t\t\t\t\t\tdescription: { type: \"string\", title: \"설명\" },\n\t\t\t\t\t\t\tassignee: { type: \"string\", title: \"담당자\" },\n\t\t\t\t\t\t\tstatus: { type: \"string\", title: \"상태\", enum: [\"TODO\", \"진행중\", \"완료\"] },\n\t\t\t\t\t\t\tpriority: { type: \"integer\", title: \"우선순위\", minimum: 1, maximum: 5 },\n\t\t\t\t\t\t\tdue_date: { type: \"string\", format: \"date\", title: \"마감일\" }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"title\", \"status\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t\"inventory\": {\n\t\t\t\t\tname: \"inventory_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\tproduct_name: { type: \"string\", title: \"상품명\", minLength: 1 },\n\t\t\t\t\t\t\tcategory: { type: \"string\", title: \"카테고리\" },\n\t\t\t\t\t\t\tquantity: { type: \"integer\", title: \"수량\", minimum: 0 },\n\t\t\t\t\t\t\tprice: { type: \"number\", title: \"가격\", minimum: 0 },\n\t\t\t\t\t\t\tsupplier: { type: \"string\", title: \"공급업체\" },\n\t\t\t\t\t\t\tlast_updated: { type: \"string\", format: \"date-time\", title: \"최종 업데이트\" }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"product_name\", \"quantity\", \"price\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t\"event\": {\n\t\t\t\t\tname: \"event_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\ttitle: { type: \"string\", title: \"제목\", minLength: 1 },\n\t\t\t\t\t\t\tdate: { type: \"string\", format: \"date\", title: \"날짜\" },\n\t\t\t\t\t\t\ttime: { type: \"string\", title: \"시간\", pattern: \"^([01]?[0-9]|2[0-3]):[0-5][0-9]$\" },\n\t\t\t\t\t\t\tlocation: { type: \"string\", title: \"장소\" },\n\t\t\t\t\t\t\tattendees: { type: \"integer\", title: \"참석자 수\", minimum: 0 },\n\t\t\t\t\t\t\ttype: { type: \"string\", title: \"이벤트 유형\", enum: [\"회의\", \"워크샵\", \"세미나\", \"파티\", \"기타\"] }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"title\", \"date\", \"time\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t// Game templates\n\t\t\t\t\"quest\": {\n\t\t\t\t\tname: \"quest_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\tquest_name: { type: \"string\", title: \"퀘스트명\", minLength: 1 },\n\t\t\t\t\t\t\tdescription: { type: \"string\", title: \"설명\" },\n\t\t\t\t\t\t\tquest_type: { type: \"string\", title: \"퀘스트 유형\", enum: [\"메인\", \"서브\", \"일일\", \"주간\", \"이벤트\"] },\n\t\t\t\t\t\t\tdifficulty: { type: \"string\", title: \"난이도\", enum: [\"쉬움\", \"보통\", \"어려움\", \"매우어려움\"] },\n\t\t\t\t\t\t\tlevel_requirement: { type: \"integer\", title: \"필요 레벨\", minimum: 1, maximum: 100 },\n\t\t\t\t\t\t\treward_exp: { type: \"integer\", title: \"보상 경험치\", minimum: 0 },\n\t\t\t\t\t\t\treward_gold: { type: \"integer\", title: \"보상 골드\", minimum: 0 },\n\t\t\t\t\t\t\treward_items: { type: \"string\", title: \"보상 아이템\" },\n\t\t\t\t\t\t\tcompletion_condition: { type: \"string\", title: \"완료 조건\" },\n\t\t\t\t\t\t\tstatus: { type: \"string\", title: \"상태\", enum: [\"활성\", \"비활성\", \"테스트중\"] }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"quest_name\", \"quest_type\", \"difficulty\", \"level_requirement\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t\"shop_item\": {\n\t\t\t\t\tname: \"shop_item_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\titem_name: { type: \"string\", title: \"상품명\", minLength: 1 },\n\t\t\t\t\t\t\tdescription: { type: \"string\", title: \"설명\" },\n\t\t\t\t\t\t\tcategory: { type: \"string\", title: \"카테고리\", enum: [\"무기\", \"방어구\", \"소모품\", \"장식품\", \"재료\", \"기타\"] },\n\t\t\t\t\t\t\trarity: { type: \"string\", title: \"등급\", enum: [\"일반\", \"고급\", \"희귀\", \"영웅\", \"전설\"] },\n\t\t\t\t\t\t\tprice_gold: { type: \"integer\", title: \"골드 가격\", minimum: 0 },\n\t\t\t\t\t\t\tprice_gem: { type: \"integer\", title: \"보석 가격\", minimum: 0 },\n\t\t\t\t\t\t\tstock: { type: \"integer\", title: \"재고\", minimum: -1 },\n\t\t\t\t\t\t\tlevel_requirement: { type: \"integer\", title: \"필요 레벨\", minimum: 1, maximum: 100 },\n\t\t\t\t\t\t\tis_limited: { type: \"boolean\", title: \"한정 상품\" },\n\t\t\t\t\t\t\tsale_start_date: { type: \"string\", format: \"date\", title: \"판매 시작일\" },\n\t\t\t\t\t\t\tsale_end_date: { type: \"string\", format: \"date\", title: \"판매 종료일\" }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"item_name\", \"category\", \"rarity\"]\n\t\t\t\t\t}\n\t\t\t\t},\n\t\t\t\t\"game_item\": {\n\t\t\t\t\tname: \"game_item_management\",\n\t\t\t\t\tschema: {\n\t\t\t\t\t\ttype: \"object\",\n\t\t\t\t\t\tproperties: {\n\t\t\t\t\t\t\titem_name: { type: \"string\", title: \"아이템명\", minLength: 1 },\n\t\t\t\t\t\t\tdescription: { type: \"string\", title: \"설명\" },\n\t\t\t\t\t\t\titem_type: { type: \"string\", title: \"아이템 유형\", enum: [\"무기\", \"방어구\", \"악세서리\", \"소모품\", \"재료\", \"퀘스트\", \"기타\"] },\n\t\t\t\t\t\t\trarity: { type: \"string\", title: \"등급\", enum: [\"일반\", \"고급\", \"희귀\", \"영웅\", \"전설\", \"신화\"] },\n\t\t\t\t\t\t\tlevel_requirement: { type: \"integer\", title: \"필요 레벨\", minimum: 1, maximum: 100 },\n\t\t\t\t\t\t\tattack_power: { type: \"integer\", title: \"공격력\", minimum: 0 },\n\t\t\t\t\t\t\tdefense_power: { type: \"integer\", title: \"방어력\", minimum: 0 },\n\t\t\t\t\t\t\thp_bonus: { type: \"integer\", title: \"체력 보너스\", minimum: 0 },\n\t\t\t\t\t\t\tmp_bonus: { type: \"integer\", title: \"마나 보너스\", minimum: 0 },\n\t\t\t\t\t\t\tspecial_effect: { type: \"string\", title: \"특수 효과\" },\n\t\t\t\t\t\t\tdurability: { type: \"integer\", title: \"내구도\", minimum: 0, maximum: 100 },\n\t\t\t\t\t\t\tmax_stack: { type: \"integer\", title: \"최대 중첩\", minimum: 1, maximum: 999 },\n\t\t\t\t\t\t\tdrop_location: { type: \"string\", title: \"획득 장소\" },\n\t\t\t\t\t\t\tcrafting_materials: { type: \"string\", title: \"제작 재료\" }\n\t\t\t\t\t\t},\n\t\t\t\t\t\trequired: [\"item_name\", \"item_type\", \"rarity\"]\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t};\n\t\t\t\n\n\t\t\t// Tab functionality\n\t\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t\tconst tabButtons = document.querySelectorAll('.tab-button');\n\t\t\t\tconst tabContents = document.querySelectorAll('.tab-content');\n\t\t\t\t\n\t\t\t\ttabButtons.forEach(button => {\n\t\t\t\t\tbutton.addEventListener('click', () => {\n\t\t\t\t\t\tconst tabId = button.id.replace('tab-', '');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Update tab buttons\n\t\t\t\t\t\ttabButtons.forEach(btn => {\n\t\t\t\t\t\t\tbtn.classList.remove('tab-active', 'border-blue-500', 'text-blue-600');\n\t\t\t\t\t\t\tbtn.classList.add('border-transparent', 'text-gray-500');\n\t\t\t\t\t\t});\n\t\t\t\t\t\tbutton.classList.add('tab-active', 'border-blue-500', 'text-blue-600');\n\t\t\t\t\t\tbutton.classList.remove('border-transparent', 'text-gray-500');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Update tab content\n\t\t\t\t\t\ttabContents.forEach(content => {\n\t\t\t\t\t\t\tcontent.classList.add('hidden');\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdocument.getElementById(`content-${tabId}`).classList.remove('hidden');\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// Template selection\n\t\t\t\tdocument.querySelectorAll('.template-btn').forEach(btn => {\n\t\t\t\t\tbtn.addEventListener('click', () => {\n\t\t\t\t\t\tconst template = btn.dataset.template;\n\t\t\t\t\t\tconst templateData = schemaTemplates[template];\n\t\t\t\t\t\t\n\t\t\t\t\t\tdocument.getElementById('table-name').value = templateData.name;\n\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(templateData.schema, null, 2);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Switch to editor tab\n\t\t\t\t\t\tdocument.getElementById('tab-editor').click();\n\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// Example schema loading\n\t\t\t\tdocument.getElementById('load-example').addEventListener('click', () => {\n\t\t\t\t\t// Use the first available template as example\n\t\t\t\t\tconst firstTemplate = Object.keys(schemaTemplates)[0];\n\t\t\t\t\tif (firstTemplate) {\n\t\t\t\t\t\tconst templateData = schemaTemplates[firstTemplate];\n\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(templateData.schema, null, 2);\n\t\t\t\t\t\tdocument.getElementById('table-name').value = templateData.name;\n\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\t// Schema validation\n\t\t\t\tdocument.getElementById('validate-schema').addEventListener('click', validateSchema);\n\t\t\t\tdocument.getElementById('schema-editor').addEventListener('input', debounce(validateSchema, 500));\n\n\t\t\t\t// Data options\n\t\t\t\tdocument.querySelectorAll('input[name=\"data-option\"]').forEach(radio => {\n\t\t\t\t\tradio.addEventListener('change', (e) => {\n\t\t\t\t\t\tconst importSection = document.getElementById('import-section');\n\t\t\t\t\t\tif (e.target.value === 'import') {\n\t\t\t\t\t\t\timportSection.classList.remove('hidden');\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\timportSection.classList.add('hidden');\n\t\t\t\t\t\t}\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// File upload drag and drop\n\t\t\t\tconst fileUpload = document.getElementById('file-upload');\n\t\t\t\tconst dropZone = fileUpload.parentElement.parentElement.parentElement;\n\n\t\t\t\t['dragenter', 'dragover', 'dragleave', 'drop'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, preventDefaults, false);\n\t\t\t\t});\n\n\t\t\t\tfunction preventDefaults(e) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\te.stopPropagation();\n\t\t\t\t}\n\n\t\t\t\t['dragenter', 'dragover'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, highlight, false);\n\t\t\t\t});\n\n\t\t\t\t['dragleave', 'drop'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, unhighlight, false);\n\t\t\t\t});\n\n\t\t\t\tfunction highlight(e) {\n\t\t\t\t\tdropZone.classList.add('border-blue-400', 'bg-blue-50');\n\t\t\t\t}\n\n\t\t\t\tfunction unhighlight(e) {\n\t\t\t\t\tdropZone.classList.remove('border-blue-400', 'bg-blue-50');\n\t\t\t\t}\n\n\t\t\t\tdropZone.addEventListener('drop', handleDrop, false);\n\n\t\t\t\tfunction handleDrop(e) {\n\t\t\t\t\tconst dt = e.dataTransfer;\n\t\t\t\t\tconst files = dt.files;\n\t\t\t\t\thandleFiles(files);\n\t\t\t\t}\n\n\t\t\t\tfileUpload.addEventListener('change', (e) => {\n\t\t\t\t\thandleFiles(e.target.files);\n\t\t\t\t});\n\n\t\t\t\tfunction handleFiles(files) {\n\t\t\t\t\tif (files.length > 0) {\n\t\t\t\t\t\tconst file = files[0];\n\t\t\t\t\t\tif (file.type === 'application/json' || file.name.endsWith('.json')) {\n\t\t\t\t\t\t\tconst reader = new FileReader();\n\t\t\t\t\t\t\treader.onload = (e) => {\n\t\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\t\tconst schema = JSON.parse(e.target.result);\n\t\t\t\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(schema, null, 2);\n\t\t\t\t\t\t\t\t\tdocument.getElementById('tab-editor').click();\n\t\t\t\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t\t\t\t} catch (error) {\n\t\t\t\t\t\t\t\t\talert('JSON 파일을 파싱할 수 없습니다: ' + error.message);\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t};\n\t\t\t\t\t\t\treader.readAsText(file);\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Create table button event listener\n\t\t\t\tdocument.getElementById('create-table').addEventListener('click', function() {\n\t\t\t\t\tconsole.log('🚀 테이블 생성 버튼 클릭됨');\n\t\t\t\t\t\n\t\t\t\t\tconst tableName = document.getElementById('table-name').value.trim();\n\t\t\t\t\tconst schemaText = document.getElementById('schema-editor').value.trim();\n\t\t\t\t\tconst dataOption = document.querySelector('input[name=\"data-option\"]:checked')?.value || 'empty';\n\t\t\t\t\t\n\t\t\t\t\tconsole.log('📝 입력 데이터:', {\n\t\t\t\t\t\ttableName: tableName,\n\t\t\t\t\t\tschemaText: schemaText.substring(0, 100) + '...',\n\t\t\t\t\t\tdataOption: dataOption\n\t\t\t\t\t});\n\t\t\t\t\t\n\t\t\t\t\tif (!tableName || !schemaText) {\n\t\t\t\t\t\tconsole.warn('⚠️ 필수 입력 값이 누락됨');\n\t\t\t\t\t\talert('테이블 이름과 JSON Schema를 입력해주세요.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\t// Validate table name format\n\t\t\t\t\tconst tableNamePattern = /^[a-zA-Z0-9_]+$/;\n\t\t\t\t\tif (!tableNamePattern.test(tableName)) {\n\t\t\t\t\t\talert('테이블 이름은 영어, 숫자, 언더스코어(_)만 사용할 수 있습니다.\\n띄어쓰기나 특수문자는 사용할 수 없습니다.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconsole.log('🔍 JSON Schema 파싱 중...');\n\t\t\t\t\t\tconst schema = JSON.parse(schemaText);\n\t\t\t\t\t\tconsole.log('✅ JSON Schema 파싱 성공:', schema);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// 서버 API로 테이블 생성 요청\n\t\t\t\t\t\tconst requestData = {\n\t\t\t\t\t\t\ttable_name: tableName,\n\t\t\t\t\t\t\tschema: schemaText,\n\t\t\t\t\t\t\tdata_option: dataOption\n\t\t\t\t\t\t};\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Debug: Log JSON data being sent\n\t\t\t\t\t\tconsole.log('📤 Sending JSON data:', requestData);\n\t\t\t\t\t\t\n\t\t\t\t\t\tconsole.log('🌐 서버로 테이블 생성 요청 전송 중...');\n\t\t\t\t\t\tfetch('/api/table/create', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: {\n\t\t\t\t\t\t\t\t'Content-Type': 'application/json'\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tbody: JSON.stringify(requestData)\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.then(response => {\n\t\t\t\t\t\t\tconsole.log('📡 서버 응답 받음:', response.status);\n\t\t\t\t\t\t\tif (!response.ok) {\n\t\t\t\t\t\t\t\tthrow new Error(`서버 오류: ${response.status}`);\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\treturn response.json();\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.then(data => {\n\t\t\t\t\t\t\tconsole.log('✅ 테이블 생성 성공:', data);\n\t\t\t\t\t\t\tif (data.success && data.redirect) {\n\t\t\t\t\t\t\t\tconsole.log('🔄 테이블 편집 페이지로 리다이렉트:', data.redirect);\n\t\t\t\t\t\t\t\twindow.location.href = data.redirect;\n\t\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\t\tthrow new Error('서버 응답에 오류가 있습니다.');\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.catch(error => {\n\t\t\t\t\t\t\tconsole.error('❌ 테이블 생성 오류:', error);\n\t\t\t\t\t\t\talert('테이블 생성 중 오류가 발생했습니다: ' + error.message);\n\t\t\t\t\t\t});\n\t\t\t\t\t\t\n\t\t\t\t\t} catch (error) {\n\t\t\t\t\t\tconsole.error('❌ JSON Schema 파싱 오류:', error);\n\t\t\t\t\t\talert('JSON Schema 형식이 올바르지 않습니다: ' + error.message);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\t\n\t\t\t});\n\n\t\t\tfunction validateSchema() {\n\t\t\t\tconst schemaText = document.getElementById('schema-editor').value.trim();\n\t\t\t\tconst tableName = document.getElementById('table-name').value.trim();\n\t\t\t\tconst statusDiv = document.getElementById('schema-status');\n\t\t\t\tconst createButton = document.getElementById('create-table');\n\t\t\t\tconst preview = document.getElementById('table-preview');\n\n\t\t\t\tif (!schemaText || !tableName) {\n\t\t\t\t\tstatusDiv.classList.add('hidden');\n\t\t\t\t\tcreateButton.disabled = true;\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\ttry {\n\t\t\t\t\tconst schema = JSON.parse(schemaText);\n\t\t\t\t\t\n\t\t\t\t\tif (schema.type === 'object' && schema.properties) {\n\t\t\t\t\t\tconst fieldCount = Object.keys(schema.properties).length;\n\t\t\t\t\t\t\n\t\t\t\t\t\tstatusDiv.classList.remove('hidden');\n\t\t\t\t\t\tstatusDiv.classList.remove('bg-red-50', 'border-red-200');\n\t\t\t\t\t\tstatusDiv.classList.add('bg-green-50', 'border-green-200');\n\t\t\t\t\t\tstatusDiv.querySelector('h4').textContent = '스키마 검증 완료';\n\t\t\t\t\t\tstatusDiv.querySelector('h4').className = 'text-sm font-medium text-green-800';\n\t\t\t\t\t\tstatusDiv.querySelector('svg').className = 'h-5 w-5 text-green-600 mr-3';\n\t\t\t\t\t\tdocument.getElementById('schema-fields-count').textContent = `필드 개수: ${fieldCount}개`;\n\t\t\t\t\t\t\n\t\t\t\t\t\tcreateButton.disabled = false;\n\t\t\t\t\t\tupdatePreview(schema, tableName);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tthrow new Error('스키마는 object 타입이어야 하며 properties를 포함해야 합니다.');\n\t\t\t\t\t}\n\t\t\t\t} catch (error) {\n\t\t\t\t\tstatusDiv.classList.remove('hidden');\n\t\t\t\t\tstatusDiv.classList.remove('bg-green-50', 'border-green-200');\n\t\t\t\t\tstatusDiv.classList.add('bg-red-50', 'border-red-200');\n\t\t\t\t\tstatusDiv.querySelector('h4').textContent = '스키마 오류';\n\t\t\t\t\tstatusDiv.querySelector('h4').className = 'text-sm font-medium text-red-800';\n\t\t\t\t\tstatusDiv.querySelector('svg').className = 'h-5 w-5 text-red-600 mr-3';\n\t\t\t\t\tstatusDiv.querySelector('svg').innerHTML = '<path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zM8.707 7.293a1 1 0 00-1.414 1.414L8.586 10l-1.293 1.293a1 1 0 101.414 1.414L10 11.414l1.293 1.293a1 1 0 001.414-1.414L11.414 10l1.293-1.293a1 1 0 00-1.414-1.414L10 8.586 8.707 7.293z\" clip-rule=\"evenodd\"/>';\n\t\t\t\t\tdocument.getElementById('schema-fields-count').textContent = error.message;\n\t\t\t\t\t\n\t\t\t\t\tcreateButton.disabled = true;\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction updatePreview(schema, tableName) {\n\t\t\t\tconst preview = document.getElementById('table-preview');\n\t\t\t\t\n\t\t\t\tlet html = `\n\t\t\t\t\t<div class=\"bg-gray-50 px-4 py-3 border-b border-gray-200\">\n\t\t\t\t\t\t<h3 class=\"text-sm font-medium text-gray-900\">${tableName}</h3>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"overflow-x-auto\">\n\t\t\t\t\t\t<table class=\"min-w-full divide-y divide-gray-200\">\n\t\t\t\t\t\t\t<thead class=\"bg-gray-50\">\n\t\t\t\t\t\t\t\t<tr>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\t// Add headers\n\t\t\t\tfor (const [fieldName, fieldDef] of Object.entries(schema.properties)) {\n\t\t\t\t\tconst title = fieldDef.title || fieldName;\n\t\t\t\t\tconst required = schema.required && schema.required.includes(fieldName) ? '*' : '';\n\t\t\t\t\thtml += `<th class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">${title}${required}</th>`;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\thtml += `\n\t\t\t\t\t\t\t\t</tr>\n\t\t\t\t\t\t\t</thead>\n\t\t\t\t\t\t\t<tbody class=\"bg-white divide-y divide-gray-200\">\n\t\t\t\t\t\t\t\t<tr>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\t// Add sample row\n\t\t\t\tfor (const [fieldName, fieldDef] of Object.entries(schema.properties)) {\n\t\t\t\t\tlet sampleValue = '';\n\t\t\t\t\tswitch (fieldDef.type) {\n\t\t\t\t\t\tcase 'string':\n\t\t\t\t\t\t\tif (fieldDef.format === 'email') sampleValue = 'example@email.com';\n\t\t\t\t\t\t\telse if (fieldDef.format === 'date') sampleValue = '2024-01-01';\n\t\t\t\t\t\t\telse if (fieldDef.enum) sampleValue = fieldDef.enum[0];\n\t\t\t\t\t\t\telse sampleValue = '샘플 텍스트';\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tcase 'integer':\n\t\t\t\t\t\tcase 'number':\n\t\t\t\t\t\t\tsampleValue = fieldDef.minimum || 1;\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tcase 'boolean':\n\t\t\t\t\t\t\tsampleValue = 'true';\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tdefault:\n\t\t\t\t\t\t\tsampleValue = '샘플';\n\t\t\t\t\t}\n\t\t\t\t\thtml += `<td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\">${sampleValue}</td>`;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\thtml += `\n\t\t\t\t\t\t\t\t</tr>\n\t\t\t\t\t\t\t</tbody>\n\t\t\t\t\t\t</table>\n\t\t\t\t\t</div>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\tpreview.innerHTML = html;\n\t\t\t}\n\n\t\t\tfunction debounce(func, wait) {\n\t\t\t\tlet timeout;\n\t\t\t\treturn function executedFunction(...args) {\n\t\t\t\t\tconst later = () => {\n\t\t\t\t\t\tclearTimeout(timeout);\n\t\t\t\t\t\tfunc(...args);\n\t\t\t\t\t};\n\t\t\t\t\tclearTimeout(timeout);\n\t\t\t\t\ttimeout = setTimeout(later, wait);\n\t\t\t\t};\n\t\t\t}\n\t\t</script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "</div></div><!-- Schema Status --><div id=\"schema-status\" class=\"hidden bg-green-50 border border-green-200 rounded-lg p-4\"><div class=\"flex items-center\"><svg class=\"h-5 w-5 text-green-600 mr-3\" fill=\"currentColor\" viewBox=\"0 0 20 20\"><path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zm3.707-9.293a1 1 0 00-1.414-1.414L9 10.586 7.707 9.293a1 1 0 00-1.414 1.414l2 2a1 1 0 001.414 0l4-4z\" clip-rule=\"evenodd\"></path></svg><div><h4 class=\"text-sm font-medium text-green-800\">스키마 검증 완료</h4><p class=\"text-sm text-green-600 mt-1\" id=\"schema-fields-count\">필드 개수: 0개</p></div></div></div></div><!-- Right Column: Preview --><div class=\"space-y-6\"><div class=\"bg-white border border-gray-200 rounded-lg p-6\"><h2 class=\"text-lg font-semibold text-gray-900 mb-4\">2. 테이블 미리보기</h2><!-- Preview Container --><div id=\"table-preview\" class=\"border border-gray-200 rounded-lg overflow-hidden\"><div class=\"bg-gray-50 px-4 py-3 border-b border-gray-200\"><p class=\"text-sm text-gray-500 text-center\">스키마를 입력하면 테이블 구조가 여기에 표시됩니다</p></div><div class=\"p-8 text-center text-gray-400\"><svg class=\"mx-auto h-12 w-12\" fill=\"none\" viewBox=\"0 0 24 24\" stroke=\"currentColor\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M3 14h18m-9-4v8m-7 0V4a1 1 0 011-1h14a1 1 0 011 1v16a1 1 0 01-1 1H5a1 1 0 01-1-1z\"></path></svg><p class=\"mt-2\">테이블 미리보기</p></div></div></div><!-- Data Input Options --><div class=\"bg-white border border-gray-200 rounded-lg p-6\"><h2 class=\"text-lg font-semibold text-gray-900 mb-4\">3. 초기 데이터 설정</h2><div class=\"space-y-4\"><div class=\"flex items-center space-x-3\"><input id=\"empty-table\" type=\"radio\" name=\"data-option\" value=\"empty\" class=\"text-blue-600 focus:ring-blue-500\" checked> <label for=\"empty-table\" class=\"text-sm text-gray-700\">빈 테이블로 시작</label></div><div class=\"flex items-center space-x-3\"><input id=\"sample-data\" type=\"radio\" name=\"data-option\" value=\"sample\" class=\"text-blue-600 focus:ring-blue-500\"> <label for=\"sample-data\" class=\"text-sm text-gray-700\">템플릿 샘플 데이터 포함</label></div><div class=\"flex items-center space-x-3\"><input id=\"generate-data\" type=\"radio\" name=\"data-option\" value=\"generate\" class=\"text-blue-600 focus:ring-blue-500\"> <label for=\"generate-data\" class=\"text-sm text-gray-700\">가짜 데이터 생성</label> <input id=\"row-count\" type=\"number\" min=\"1\" max=\"1000\" value=\"20\" class=\"w-24 px-2 py-1 border border-gray-300 rounded-md text-sm\" disabled> <span class=\"text-sm text-gray-500\">행</span></div><div class=\"flex items-center space-x-3\"><input id=\"import-data\" type=\"radio\" name=\"data-option\" value=\"import\" class=\"text-blue-600 focus:ring-blue-500\"> <label for=\"import-data\" class=\"text-sm text-gray-700\">CSV/JSON 파일 가져오기</label></div></div><div id=\"import-section\" class=\"mt-4 p-4 bg-gray-50 rounded-md hidden\"><label class=\"block text-sm font-medium text-gray-700 mb-2\">데이터 파일 선택</label> <input type=\"file\" class=\"block w-full text-sm text-gray-500 file:mr-4 file:py-2 file:px-4 file:rounded-md file:border-0 file:text-sm file:font-semibold file:bg-blue-50 file:text-blue-700 hover:file:bg-blue-100\" accept=\".csv,.json\"></div></div><!-- Action Buttons --><div class=\"flex space-x-3\"><button id=\"create-table\" class=\"flex-1 bg-blue-600 hover:bg-blue-700 text-white px-6 py-3 rounded-md font-medium disabled:bg-gray-300 disabled:cursor-not-allowed\" disabled>테이블 생성</button> <button class=\"px-6 py-3 border border-gray-300 text-gray-700 rounded-md font-medium hover:bg-gray-50\">취소</button></div></div></div></div><!-- JavaScript for interactivity --> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templ.JSONScript("schema-templates", templates).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, " <script>\n\t\t\t// Templates come from the template repository, keyed by ID\n\t\t\tconst schemaTemplates = Object.fromEntries(\n\t\t\t\tJSON.parse(document.getElementById('schema-templates').textContent).map(t => [t.id, t])\n\t\t\t);\n\n\t\t\t// Template IDs make good table names once dashes are underscores\n\t\t\tfunction tableNameFor(template) {\n\t\t\t\treturn template.id.replace(/-/g, '_');\n\t\t\t}\n\n\t\t\t// Tab functionality\n\t\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t\tconst tabButtons = document.querySelectorAll('.tab-button');\n\t\t\t\tconst tabContents = document.querySelectorAll('.tab-content');\n\t\t\t\t\n\t\t\t\ttabButtons.forEach(button => {\n\t\t\t\t\tbutton.addEventListener('click', () => {\n\t\t\t\t\t\tconst tabId = button.id.replace('tab-', '');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Update tab buttons\n\t\t\t\t\t\ttabButtons.forEach(btn => {\n\t\t\t\t\t\t\tbtn.classList.remove('tab-active', 'border-blue-500', 'text-blue-600');\n\t\t\t\t\t\t\tbtn.classList.add('border-transparent', 'text-gray-500');\n\t\t\t\t\t\t});\n\t\t\t\t\t\tbutton.classList.add('tab-active', 'border-blue-500', 'text-blue-600');\n\t\t\t\t\t\tbutton.classList.remove('border-transparent', 'text-gray-500');\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Update tab content\n\t\t\t\t\t\ttabContents.forEach(content => {\n\t\t\t\t\t\t\tcontent.classList.add('hidden');\n\t\t\t\t\t\t});\n\t\t\t\t\t\tdocument.getElementById(`content-${tabId}`).classList.remove('hidden');\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// Template selection\n\t\t\t\tlet selectedTemplate = null;\n\t\t\t\tdocument.querySelectorAll('.template-btn').forEach(btn => {\n\t\t\t\t\tbtn.addEventListener('click', () => {\n\t\t\t\t\t\tconst template = btn.dataset.template;\n\t\t\t\t\t\tconst templateData = schemaTemplates[template];\n\t\t\t\t\t\tselectedTemplate = template;\n\t\t\t\t\t\t\n\t\t\t\t\t\tdocument.getElementById('table-name').value = tableNameFor(templateData);\n\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(templateData.schema, null, 2);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Switch to editor tab\n\t\t\t\t\t\tdocument.getElementById('tab-editor').click();\n\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// Example schema loading\n\t\t\t\tdocument.getElementById('load-example').addEventListener('click', () => {\n\t\t\t\t\t// Use the first available template as example\n\t\t\t\t\tconst firstTemplate = Object.keys(schemaTemplates)[0];\n\t\t\t\t\tif (firstTemplate) {\n\t\t\t\t\t\tconst templateData = schemaTemplates[firstTemplate];\n\t\t\t\t\t\tselectedTemplate = firstTemplate;\n\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(templateData.schema, null, 2);\n\t\t\t\t\t\tdocument.getElementById('table-name').value = tableNameFor(templateData);\n\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\t// Schema validation\n\t\t\t\tdocument.getElementById('validate-schema').addEventListener('click', validateSchema);\n\t\t\t\tdocument.getElementById('schema-editor').addEventListener('input', debounce(validateSchema, 500));\n\n\t\t\t\t// Data options\n\t\t\t\tdocument.querySelectorAll('input[name=\"data-option\"]').forEach(radio => {\n\t\t\t\t\tradio.addEventListener('change', (e) => {\n\t\t\t\t\t\tconst importSection = document.getElementById('import-section');\n\t\t\t\t\t\tdocument.getElementById('row-count').disabled = e.target.value !== 'generate';\n\t\t\t\t\t\tif (e.target.value === 'import') {\n\t\t\t\t\t\t\timportSection.classList.remove('hidden');\n\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\timportSection.classList.add('hidden');\n\t\t\t\t\t\t}\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\t// File upload drag and drop\n\t\t\t\tconst fileUpload = document.getElementById('file-upload');\n\t\t\t\tconst dropZone = fileUpload.parentElement.parentElement.parentElement;\n\n\t\t\t\t['dragenter', 'dragover', 'dragleave', 'drop'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, preventDefaults, false);\n\t\t\t\t});\n\n\t\t\t\tfunction preventDefaults(e) {\n\t\t\t\t\te.preventDefault();\n\t\t\t\t\te.stopPropagation();\n\t\t\t\t}\n\n\t\t\t\t['dragenter', 'dragover'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, highlight, false);\n\t\t\t\t});\n\n\t\t\t\t['dragleave', 'drop'].forEach(eventName => {\n\t\t\t\t\tdropZone.addEventListener(eventName, unhighlight, false);\n\t\t\t\t});\n\n\t\t\t\tfunction highlight(e) {\n\t\t\t\t\tdropZone.classList.add('border-blue-400', 'bg-blue-50');\n\t\t\t\t}\n\n\t\t\t\tfunction unhighlight(e) {\n\t\t\t\t\tdropZone.classList.remove('border-blue-400', 'bg-blue-50');\n\t\t\t\t}\n\n\t\t\t\tdropZone.addEventListener('drop', handleDrop, false);\n\n\t\t\t\tfunction handleDrop(e) {\n\t\t\t\t\tconst dt = e.dataTransfer;\n\t\t\t\t\tconst files = dt.files;\n\t\t\t\t\thandleFiles(files);\n\t\t\t\t}\n\n\t\t\t\tfileUpload.addEventListener('change', (e) => {\n\t\t\t\t\thandleFiles(e.target.files);\n\t\t\t\t});\n\n\t\t\t\tfunction handleFiles(files) {\n\t\t\t\t\tif (files.length > 0) {\n\t\t\t\t\t\tconst file = files[0];\n\t\t\t\t\t\tif (file.type === 'application/json' || file.name.endsWith('.json')) {\n\t\t\t\t\t\t\tconst reader = new FileReader();\n\t\t\t\t\t\t\treader.onload = (e) => {\n\t\t\t\t\t\t\t\ttry {\n\t\t\t\t\t\t\t\t\tconst schema = JSON.parse(e.target.result);\n\t\t\t\t\t\t\t\t\tdocument.getElementById('schema-editor').value = JSON.stringify(schema, null, 2);\n\t\t\t\t\t\t\t\t\tdocument.getElementById('tab-editor').click();\n\t\t\t\t\t\t\t\t\tvalidateSchema();\n\t\t\t\t\t\t\t\t} catch (error) {\n\t\t\t\t\t\t\t\t\talert('JSON 파일을 파싱할 수 없습니다: ' + error.message);\n\t\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\t};\n\t\t\t\t\t\t\treader.readAsText(file);\n\t\t\t\t\t\t}\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\t// Create table button event listener\n\t\t\t\tdocument.getElementById('create-table').addEventListener('click', function() {\n\t\t\t\t\tconsole.log('🚀 테이블 생성 버튼 클릭됨');\n\t\t\t\t\t\n\t\t\t\t\tconst tableName = document.getElementById('table-name').value.trim();\n\t\t\t\t\tconst schemaText = document.getElementById('schema-editor').value.trim();\n\t\t\t\t\tconst dataOption = document.querySelector('input[name=\"data-option\"]:checked')?.value || 'empty';\n\t\t\t\t\tconst rowCount = parseInt(document.getElementById('row-count').value, 10);\n\t\t\t\t\t\n\t\t\t\t\tconsole.log('📝 입력 데이터:', {\n\t\t\t\t\t\ttableName: tableName,\n\t\t\t\t\t\tschemaText: schemaText.substring(0, 100) + '...',\n\t\t\t\t\t\tdataOption: dataOption\n\t\t\t\t\t});\n\t\t\t\t\t\n\t\t\t\t\tif (!tableName || !schemaText) {\n\t\t\t\t\t\tconsole.warn('⚠️ 필수 입력 값이 누락됨');\n\t\t\t\t\t\talert('테이블 이름과 JSON Schema를 입력해주세요.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\tif (dataOption === 'sample' && !selectedTemplate) {\n\t\t\t\t\t\talert('샘플 데이터를 넣으려면 먼저 템플릿을 선택해주세요.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\tif (dataOption === 'generate' && !(rowCount >= 1 && rowCount <= 1000)) {\n\t\t\t\t\t\talert('생성할 행 수는 1에서 1000 사이여야 합니다.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\t// Validate table name format\n\t\t\t\t\tconst tableNamePattern = /^[a-zA-Z0-9_]+$/;\n\t\t\t\t\tif (!tableNamePattern.test(tableName)) {\n\t\t\t\t\t\talert('테이블 이름은 영어, 숫자, 언더스코어(_)만 사용할 수 있습니다.\\n띄어쓰기나 특수문자는 사용할 수 없습니다.');\n\t\t\t\t\t\treturn;\n\t\t\t\t\t}\n\t\t\t\t\t\n\t\t\t\t\ttry {\n\t\t\t\t\t\tconsole.log('🔍 JSON Schema 파싱 중...');\n\t\t\t\t\t\tconst schema = JSON.parse(schemaText);\n\t\t\t\t\t\tconsole.log('✅ JSON Schema 파싱 성공:', schema);\n\t\t\t\t\t\t\n\t\t\t\t\t\t// 서버 API로 테이블 생성 요청\n\t\t\t\t\t\tconst requestData = {\n\t\t\t\t\t\t\ttable_name: tableName,\n\t\t\t\t\t\t\tschema: schemaText,\n\t\t\t\t\t\t\tdata_option: dataOption\n\t\t\t\t\t\t};\n\t\t\t\t\t\tif (dataOption === 'sample') {\n\t\t\t\t\t\t\trequestData.template = selectedTemplate;\n\t\t\t\t\t\t} else if (dataOption === 'generate') {\n\t\t\t\t\t\t\trequestData.row_count = rowCount;\n\t\t\t\t\t\t}\n\t\t\t\t\t\t\n\t\t\t\t\t\t// Debug: Log JSON data being sent\n\t\t\t\t\t\tconsole.log('📤 Sending JSON data:', requestData);\n\t\t\t\t\t\t\n\t\t\t\t\t\tconsole.log('🌐 서버로 테이블 생성 요청 전송 중...');\n\t\t\t\t\t\tfetch('/api/v1/tables', {\n\t\t\t\t\t\t\tmethod: 'POST',\n\t\t\t\t\t\t\theaders: {\n\t\t\t\t\t\t\t\t'Content-Type': 'application/json'\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\tbody: JSON.stringify(requestData)\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.then(response => {\n\t\t\t\t\t\t\tconsole.log('📡 서버 응답 받음:', response.status);\n\t\t\t\t\t\t\tif (!response.ok) {\n\t\t\t\t\t\t\t\treturn throwProblem(response);\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t\treturn response.json();\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.then(data => {\n\t\t\t\t\t\t\tconsole.log('✅ 테이블 생성 성공:', data);\n\t\t\t\t\t\t\tif (data.success && data.redirect) {\n\t\t\t\t\t\t\t\tconsole.log('🔄 테이블 편집 페이지로 리다이렉트:', data.redirect);\n\t\t\t\t\t\t\t\twindow.location.href = data.redirect;\n\t\t\t\t\t\t\t} else {\n\t\t\t\t\t\t\t\tthrow new Error('서버 응답에 오류가 있습니다.');\n\t\t\t\t\t\t\t}\n\t\t\t\t\t\t})\n\t\t\t\t\t\t.catch(error => {\n\t\t\t\t\t\t\tconsole.error('❌ 테이블 생성 오류:', error);\n\t\t\t\t\t\t\talert('테이블 생성 중 오류가 발생했습니다: ' + error.message);\n\t\t\t\t\t\t});\n\t\t\t\t\t\t\n\t\t\t\t\t} catch (error) {\n\t\t\t\t\t\tconsole.error('❌ JSON Schema 파싱 오류:', error);\n\t\t\t\t\t\talert('JSON Schema 형식이 올바르지 않습니다: ' + error.message);\n\t\t\t\t\t}\n\t\t\t\t});\n\t\t\t\t\n\t\t\t});\n\n\t\t\tfunction validateSchema() {\n\t\t\t\tconst schemaText = document.getElementById('schema-editor').value.trim();\n\t\t\t\tconst tableName = document.getElementById('table-name').value.trim();\n\t\t\t\tconst statusDiv = document.getElementById('schema-status');\n\t\t\t\tconst createButton = document.getElementById('create-table');\n\t\t\t\tconst preview = document.getElementById('table-preview');\n\n\t\t\t\tif (!schemaText || !tableName) {\n\t\t\t\t\tstatusDiv.classList.add('hidden');\n\t\t\t\t\tcreateButton.disabled = true;\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\ttry {\n\t\t\t\t\tconst schema = JSON.parse(schemaText);\n\t\t\t\t\t\n\t\t\t\t\tif (schema.type === 'object' && schema.properties) {\n\t\t\t\t\t\tconst fieldCount = Object.keys(schema.properties).length;\n\t\t\t\t\t\t\n\t\t\t\t\t\tstatusDiv.classList.remove('hidden');\n\t\t\t\t\t\tstatusDiv.classList.remove('bg-red-50', 'border-red-200');\n\t\t\t\t\t\tstatusDiv.classList.add('bg-green-50', 'border-green-200');\n\t\t\t\t\t\tstatusDiv.querySelector('h4').textContent = '스키마 검증 완료';\n\t\t\t\t\t\tstatusDiv.querySelector('h4').className = 'text-sm font-medium text-green-800';\n\t\t\t\t\t\tstatusDiv.querySelector('svg').className = 'h-5 w-5 text-green-600 mr-3';\n\t\t\t\t\t\tdocument.getElementById('schema-fields-count').textContent = `필드 개수: ${fieldCount}개`;\n\t\t\t\t\t\t\n\t\t\t\t\t\tcreateButton.disabled = false;\n\t\t\t\t\t\tupdatePreview(schema, tableName);\n\t\t\t\t\t} else {\n\t\t\t\t\t\tthrow new Error('스키마는 object 타입이어야 하며 properties를 포함해야 합니다.');\n\t\t\t\t\t}\n\t\t\t\t} catch (error) {\n\t\t\t\t\tstatusDiv.classList.remove('hidden');\n\t\t\t\t\tstatusDiv.classList.remove('bg-green-50', 'border-green-200');\n\t\t\t\t\tstatusDiv.classList.add('bg-red-50', 'border-red-200');\n\t\t\t\t\tstatusDiv.querySelector('h4').textContent = '스키마 오류';\n\t\t\t\t\tstatusDiv.querySelector('h4').className = 'text-sm font-medium text-red-800';\n\t\t\t\t\tstatusDiv.querySelector('svg').className = 'h-5 w-5 text-red-600 mr-3';\n\t\t\t\t\tstatusDiv.querySelector('svg').innerHTML = '<path fill-rule=\"evenodd\" d=\"M10 18a8 8 0 100-16 8 8 0 000 16zM8.707 7.293a1 1 0 00-1.414 1.414L8.586 10l-1.293 1.293a1 1 0 101.414 1.414L10 11.414l1.293 1.293a1 1 0 001.414-1.414L11.414 10l1.293-1.293a1 1 0 00-1.414-1.414L10 8.586 8.707 7.293z\" clip-rule=\"evenodd\"/>';\n\t\t\t\t\tdocument.getElementById('schema-fields-count').textContent = error.message;\n\t\t\t\t\t\n\t\t\t\t\tcreateButton.disabled = true;\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction updatePreview(schema, tableName) {\n\t\t\t\tconst preview = document.getElementById('table-preview');\n\t\t\t\t\n\t\t\t\tlet html = `\n\t\t\t\t\t<div class=\"bg-gray-50 px-4 py-3 border-b border-gray-200\">\n\t\t\t\t\t\t<h3 class=\"text-sm font-medium text-gray-900\">${tableName}</h3>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"overflow-x-auto\">\n\t\t\t\t\t\t<table class=\"min-w-full divide-y divide-gray-200\">\n\t\t\t\t\t\t\t<thead class=\"bg-gray-50\">\n\t\t\t\t\t\t\t\t<tr>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\t// Add headers\n\t\t\t\tfor (const [fieldName, fieldDef] of Object.entries(schema.properties)) {\n\t\t\t\t\tconst title = fieldDef.title || fieldName;\n\t\t\t\t\tconst required = schema.required && schema.required.includes(fieldName) ? '*' : '';\n\t\t\t\t\thtml += `<th class=\"px-6 py-3 text-left text-xs font-medium text-gray-500 uppercase tracking-wider\">${title}${required}</th>`;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\thtml += `\n\t\t\t\t\t\t\t\t</tr>\n\t\t\t\t\t\t\t</thead>\n\t\t\t\t\t\t\t<tbody class=\"bg-white divide-y divide-gray-200\">\n\t\t\t\t\t\t\t\t<tr>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\t// Add sample row\n\t\t\t\tfor (const [fieldName, fieldDef] of Object.entries(schema.properties)) {\n\t\t\t\t\tlet sampleValue = '';\n\t\t\t\t\tswitch (fieldDef.type) {\n\t\t\t\t\t\tcase 'string':\n\t\t\t\t\t\t\tif (fieldDef.format === 'email') sampleValue = 'example@email.com';\n\t\t\t\t\t\t\telse if (fieldDef.format === 'date') sampleValue = '2024-01-01';\n\t\t\t\t\t\t\telse if (fieldDef.enum) sampleValue = fieldDef.enum[0];\n\t\t\t\t\t\t\telse sampleValue = '샘플 텍스트';\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tcase 'integer':\n\t\t\t\t\t\tcase 'number':\n\t\t\t\t\t\t\tsampleValue = fieldDef.minimum || 1;\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tcase 'boolean':\n\t\t\t\t\t\t\tsampleValue = 'true';\n\t\t\t\t\t\t\tbreak;\n\t\t\t\t\t\tdefault:\n\t\t\t\t\t\t\tsampleValue = '샘플';\n\t\t\t\t\t}\n\t\t\t\t\thtml += `<td class=\"px-6 py-4 whitespace-nowrap text-sm text-gray-500\">${sampleValue}</td>`;\n\t\t\t\t}\n\t\t\t\t\n\t\t\t\thtml += `\n\t\t\t\t\t\t\t\t</tr>\n\t\t\t\t\t\t\t</tbody>\n\t\t\t\t\t\t</table>\n\t\t\t\t\t</div>\n\t\t\t\t`;\n\t\t\t\t\n\t\t\t\tpreview.innerHTML = html;\n\t\t\t}\n\n\t\t\tfunction debounce(func, wait) {\n\t\t\t\tlet timeout;\n\t\t\t\treturn function executedFunction(...args) {\n\t\t\t\t\tconst later = () => {\n\t\t\t\t\t\tclearTimeout(timeout);\n\t\t\t\t\t\tfunc(...args);\n\t\t\t\t\t};\n\t\t\t\t\tclearTimeout(timeout);\n\t\t\t\t\ttimeout = setTimeout(later, wait);\n\t\t\t\t};\n\t\t\t}\n\t\t</script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
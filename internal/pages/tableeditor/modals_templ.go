@@ -60,7 +60,7 @@ func editCellModal() templ.Component {
 			templ_7745c5c3_Var2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div id=\"edit-cell-modal\" class=\"hidden fixed inset-0 bg-gray-500 bg-opacity-75 flex items-center justify-center z-50\"><div class=\"bg-white rounded-lg shadow-xl max-w-lg w-full mx-4\"><div class=\"bg-gray-50 px-6 py-4 border-b border-gray-200\"><h3 class=\"text-lg font-medium text-gray-900\">셀 편집</h3></div><div class=\"px-6 py-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\" id=\"edit-cell-label\">값 편집</label><div id=\"edit-cell-input-container\"><!-- Dynamic input will be inserted here --></div></div><div class=\"bg-gray-50 px-6 py-4 border-t border-gray-200 flex justify-end space-x-3\"><button type=\"button\" onclick=\"closeEditCellModal()\" class=\"px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50\">취소</button> <button type=\"button\" onclick=\"saveEditedCell()\" class=\"px-4 py-2 border border-transparent text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700\">저장</button></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div id=\"edit-cell-modal\" class=\"hidden fixed inset-0 bg-gray-500 bg-opacity-75 flex items-center justify-center z-50\"><div class=\"bg-white rounded-lg shadow-xl max-w-lg w-full mx-4\"><div class=\"bg-gray-50 px-6 py-4 border-b border-gray-200\"><h3 class=\"text-lg font-medium text-gray-900\">셀 편집</h3></div><div class=\"px-6 py-4\"><label class=\"block text-sm font-medium text-gray-700 mb-2\" id=\"edit-cell-label\">값 편집</label><div id=\"edit-cell-input-container\"><!-- Dynamic input will be inserted here --></div></div><div class=\"bg-gray-50 px-6 py-4 border-t border-gray-200 flex justify-end space-x-3\"><button type=\"button\" onclick=\"openCommentsForEditingCell()\" class=\"mr-auto px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50\">댓글</button> <button type=\"button\" onclick=\"closeEditCellModal()\" class=\"px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50\">취소</button> <button type=\"button\" onclick=\"saveEditedCell()\" class=\"px-4 py-2 border border-transparent text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700\">저장</button></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -173,7 +173,7 @@ func exportOption(format string, label string, description string) templ.Compone
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(format)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 226, Col: 22}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 233, Col: 22}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
@@ -186,7 +186,7 @@ func exportOption(format string, label string, description string) templ.Compone
 		var templ_7745c5c3_Var7 string
 		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(label)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 228, Col: 48}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 235, Col: 48}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 		if templ_7745c5c3_Err != nil {
@@ -199,7 +199,7 @@ func exportOption(format string, label string, description string) templ.Compone
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(description)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 229, Col: 50}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `internal/pages/tableeditor/modals.templ`, Line: 236, Col: 50}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
@@ -213,4 +213,34 @@ func exportOption(format string, label string, description string) templ.Compone
 	})
 }
 
+// Comment threads modal for a table, record or cell
+func commentsModal() templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var9 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var9 == nil {
+			templ_7745c5c3_Var9 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div id=\"comments-modal\" class=\"hidden fixed inset-0 bg-gray-500 bg-opacity-75 flex items-center justify-center z-50\"><div class=\"bg-white rounded-lg shadow-xl max-w-2xl w-full mx-4 max-h-[90vh] flex flex-col\"><div class=\"bg-gray-50 px-6 py-4 border-b border-gray-200 flex items-center justify-between\"><h3 class=\"text-lg font-medium text-gray-900\" id=\"comments-modal-title\">댓글</h3><div class=\"flex items-center space-x-3 text-sm\"><label class=\"flex items-center text-gray-600\"><input type=\"checkbox\" id=\"comments-show-resolved\" onchange=\"loadCommentThreads()\" class=\"mr-1\"> 해결됨 포함</label> <a id=\"comments-export-md\" class=\"text-blue-600 hover:text-blue-800\">Markdown</a> <a id=\"comments-export-csv\" class=\"text-blue-600 hover:text-blue-800\">CSV</a></div></div><div class=\"px-6 py-4 overflow-y-auto flex-1 space-y-4\" id=\"comment-threads\"><!-- Threads will be inserted here --></div><div class=\"bg-gray-50 px-6 py-4 border-t border-gray-200 space-y-2\"><div class=\"flex space-x-2\"><input type=\"text\" id=\"comment-author\" placeholder=\"작성자\" class=\"w-32 border border-gray-300 rounded-md px-3 py-2 text-sm\"> <textarea id=\"comment-body\" rows=\"2\" placeholder=\"새 스레드 시작 (@이름 으로 멘션)\" class=\"flex-1 border border-gray-300 rounded-md px-3 py-2 text-sm\"></textarea></div><div class=\"flex justify-end space-x-3\"><button type=\"button\" onclick=\"closeCommentsModal()\" class=\"px-4 py-2 border border-gray-300 text-sm font-medium rounded-md text-gray-700 bg-white hover:bg-gray-50\">닫기</button> <button type=\"button\" onclick=\"submitCommentThread()\" class=\"px-4 py-2 border border-transparent text-sm font-medium rounded-md text-white bg-blue-600 hover:bg-blue-700\">댓글 달기</button></div></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
 var _ = templruntime.GeneratedTemplate
@@ -0,0 +1,360 @@
+package publish
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// The compact binary table format (.bin) is laid out as:
+//
+//	magic    "PRGB"
+//	version  u8 (BinaryFormatVersion)
+//	strings  uvarint count, then count × (uvarint length, UTF-8 bytes)
+//	columns  uvarint count, then count × (uvarint name string index, u8 column type)
+//	rows     uvarint count, then count × row
+//	row      uvarint record id, presence bitmap (ceil(columns/8) bytes, LSB first),
+//	         then one value per present column
+//
+// Values are encoded by column type: strings and JSON values as a uvarint
+// index into the string table, integers as zig-zag varints, numbers as
+// little-endian float64 and booleans as a single byte. Every distinct string
+// is stored once, which keeps enum-heavy game tables small.
+
+// BinaryFormatVersion is the version byte written after the magic
+const BinaryFormatVersion = 1
+
+var binaryMagic = []byte("PRGB")
+
+// ColumnType identifies how a column's values are encoded
+type ColumnType byte
+
+// Column types used by the binary format
+const (
+	ColumnString  ColumnType = 1
+	ColumnInteger ColumnType = 2
+	ColumnNumber  ColumnType = 3
+	ColumnBoolean ColumnType = 4
+	ColumnJSON    ColumnType = 5
+)
+
+// BinaryColumn describes one column of a binary table
+type BinaryColumn struct {
+	Name string
+	Type ColumnType
+}
+
+// BinaryTable is the decoded form of a .bin file
+type BinaryTable struct {
+	Columns []BinaryColumn
+	Rows    []Record
+}
+
+func columnTypeFor(prop repository.PropertyDef) ColumnType {
+	switch prop.Type {
+	case "integer":
+		return ColumnInteger
+	case "number":
+		return ColumnNumber
+	case "boolean":
+		return ColumnBoolean
+	case "array", "object":
+		return ColumnJSON
+	default:
+		return ColumnString
+	}
+}
+
+// stringTable interns strings in insertion order
+type stringTable struct {
+	index map[string]uint64
+	list  []string
+}
+
+func (st *stringTable) add(s string) uint64 {
+	if i, ok := st.index[s]; ok {
+		return i
+	}
+	i := uint64(len(st.list))
+	st.index[s] = i
+	st.list = append(st.list, s)
+	return i
+}
+
+// EncodeBinary encodes normalised records using the table schema's columns
+func EncodeBinary(schema *repository.SchemaDefinition, records []Record) ([]byte, error) {
+	names := schema.PropertyNames()
+	columns := make([]BinaryColumn, len(names))
+	for i, name := range names {
+		columns[i] = BinaryColumn{Name: name, Type: columnTypeFor(schema.Properties[name])}
+	}
+
+	strs := &stringTable{index: make(map[string]uint64)}
+	for _, col := range columns {
+		strs.add(col.Name)
+	}
+
+	var body bytes.Buffer
+	putUvarint(&body, uint64(len(records)))
+	bitmapLen := (len(columns) + 7) / 8
+
+	for _, record := range records {
+		putUvarint(&body, uint64(record.ID))
+
+		bitmap := make([]byte, bitmapLen)
+		var values bytes.Buffer
+		for i, col := range columns {
+			value, ok := record.Data[col.Name]
+			if !ok || value == nil {
+				continue
+			}
+			if err := encodeValue(&values, strs, col, value); err != nil {
+				return nil, fmt.Errorf("record %d: %w", record.ID, err)
+			}
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+		body.Write(bitmap)
+		body.Write(values.Bytes())
+	}
+
+	var out bytes.Buffer
+	out.Write(binaryMagic)
+	out.WriteByte(BinaryFormatVersion)
+	putUvarint(&out, uint64(len(strs.list)))
+	for _, s := range strs.list {
+		putUvarint(&out, uint64(len(s)))
+		out.WriteString(s)
+	}
+	putUvarint(&out, uint64(len(columns)))
+	for _, col := range columns {
+		putUvarint(&out, strs.index[col.Name])
+		out.WriteByte(byte(col.Type))
+	}
+	out.Write(body.Bytes())
+
+	return out.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, strs *stringTable, col BinaryColumn, value interface{}) error {
+	switch col.Type {
+	case ColumnString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("field %s: expected string, got %T", col.Name, value)
+		}
+		putUvarint(buf, strs.add(s))
+	case ColumnInteger:
+		n, ok := toInt64(value)
+		if !ok {
+			return fmt.Errorf("field %s: expected integer, got %T", col.Name, value)
+		}
+		putVarint(buf, n)
+	case ColumnNumber:
+		f, ok := toFloat64(value)
+		if !ok {
+			return fmt.Errorf("field %s: expected number, got %T", col.Name, value)
+		}
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(f))
+		buf.Write(b[:])
+	case ColumnBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("field %s: expected boolean, got %T", col.Name, value)
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case ColumnJSON:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", col.Name, err)
+		}
+		putUvarint(buf, strs.add(string(data)))
+	default:
+		return fmt.Errorf("field %s: unknown column type %d", col.Name, col.Type)
+	}
+	return nil
+}
+
+// DecodeBinary decodes a .bin file produced by EncodeBinary
+func DecodeBinary(data []byte) (*BinaryTable, error) {
+	r := bytes.NewReader(data)
+
+	magic := make([]byte, len(binaryMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, binaryMagic) {
+		return nil, errors.New("not a progressive binary table")
+	}
+	version, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if version != BinaryFormatVersion {
+		return nil, fmt.Errorf("unsupported binary format version %d", version)
+	}
+
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	var strs []string
+	for i := uint64(0); i < count; i++ {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(r.Len()) {
+			return nil, errors.New("string length exceeds data")
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		strs = append(strs, string(b))
+	}
+	str := func(i uint64) (string, error) {
+		if i >= uint64(len(strs)) {
+			return "", fmt.Errorf("string index %d out of range", i)
+		}
+		return strs[i], nil
+	}
+
+	colCount, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	table := &BinaryTable{Columns: make([]BinaryColumn, colCount)}
+	for i := range table.Columns {
+		nameIdx, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		name, err := str(nameIdx)
+		if err != nil {
+			return nil, err
+		}
+		typ, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		table.Columns[i] = BinaryColumn{Name: name, Type: ColumnType(typ)}
+	}
+
+	rowCount, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	bitmapLen := (len(table.Columns) + 7) / 8
+	for i := uint64(0); i < rowCount; i++ {
+		id, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		bitmap := make([]byte, bitmapLen)
+		if _, err := io.ReadFull(r, bitmap); err != nil {
+			return nil, err
+		}
+
+		record := Record{ID: int64(id), Data: make(map[string]interface{})}
+		for c, col := range table.Columns {
+			if bitmap[c/8]&(1<<(c%8)) == 0 {
+				continue
+			}
+			value, err := decodeValue(r, col, str)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", i, err)
+			}
+			record.Data[col.Name] = value
+		}
+		table.Rows = append(table.Rows, record)
+	}
+
+	return table, nil
+}
+
+func decodeValue(r *bytes.Reader, col BinaryColumn, str func(uint64) (string, error)) (interface{}, error) {
+	switch col.Type {
+	case ColumnString:
+		i, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		return str(i)
+	case ColumnInteger:
+		return binary.ReadVarint(r)
+	case ColumnNumber:
+		var b [8]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b[:])), nil
+	case ColumnBoolean:
+		b, err := r.ReadByte()
+		return b == 1, err
+	case ColumnJSON:
+		i, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		s, err := str(i)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(s), &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unknown column type %d", col.Type)
+	}
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+func putVarint(buf *bytes.Buffer, v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v)
+	buf.Write(b[:n])
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch n := value.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, false
+		}
+		return int64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,398 @@
+package publish
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"progressive/internal/codegen"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Format is an output format for table data
+type Format string
+
+// Supported data formats
+const (
+	FormatJSON    Format = "json"
+	FormatMsgPack Format = "msgpack"
+	FormatBinary  Format = "binary"
+)
+
+// AllFormats lists every data format in the order files are emitted
+var AllFormats = []Format{FormatJSON, FormatMsgPack, FormatBinary}
+
+// ManifestVersion is bumped whenever the bundle layout changes
+const ManifestVersion = 1
+
+// ErrInvalidOptions is returned when build options are rejected before any data is loaded
+var ErrInvalidOptions = errors.New("invalid publish options")
+
+var versionPattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z._-]{0,63}$`)
+
+// ParseFormats parses format names, defaulting to all formats when empty
+func ParseFormats(names []string) ([]Format, error) {
+	if len(names) == 0 {
+		return AllFormats, nil
+	}
+	var formats []Format
+	seen := make(map[Format]bool)
+	for _, name := range names {
+		f := Format(strings.ToLower(strings.TrimSpace(name)))
+		switch f {
+		case FormatJSON, FormatMsgPack, FormatBinary:
+		default:
+			return nil, fmt.Errorf("%w: unsupported format %s", ErrInvalidOptions, name)
+		}
+		if !seen[f] {
+			seen[f] = true
+			formats = append(formats, f)
+		}
+	}
+	return formats, nil
+}
+
+// Options controls a bundle build
+type Options struct {
+	// TableIDs selects the tables to publish; empty publishes every table
+	TableIDs []string
+	// Version labels the bundle; it defaults to the build timestamp
+	Version string
+	// Formats selects data formats; empty emits all of them
+	Formats []Format
+	// GoPackage is the package name of the generated Go types
+	GoPackage string
+	// BuiltAt overrides the build time recorded in the manifest
+	BuiltAt time.Time
+}
+
+// File is one file of a bundle, addressed by its slash-separated path
+type File struct {
+	Path string
+	Data []byte
+}
+
+// ManifestFile records the size and content hash of a bundle file
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestTable describes one published table
+type ManifestTable struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	TypeName   string            `json:"type_name"`
+	Records    int               `json:"records"`
+	SchemaHash string            `json:"schema_hash"`
+	Files      map[Format]string `json:"files"`
+}
+
+// Manifest is written as manifest.json at the root of every bundle
+type Manifest struct {
+	ManifestVersion int             `json:"manifest_version"`
+	Version         string          `json:"version"`
+	BuiltAt         time.Time       `json:"built_at"`
+	ContentHash     string          `json:"content_hash"`
+	Formats         []Format        `json:"formats"`
+	Tables          []ManifestTable `json:"tables"`
+	Files           []ManifestFile  `json:"files"`
+}
+
+// Bundle is a validated, versioned set of data and type files
+type Bundle struct {
+	Manifest Manifest
+	Files    []File
+}
+
+// Build loads the requested tables, validates them and produces a bundle.
+// A *ValidationError is returned when any record or reference is invalid.
+func Build(ctx context.Context, src Source, opts Options) (*Bundle, error) {
+	formats, err := normalizeOptions(&opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, err := src.LoadTables(ctx, opts.TableIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%w: no tables to publish", ErrInvalidOptions)
+	}
+
+	prepared, report := prepare(tables)
+	if !report.OK() {
+		return nil, &ValidationError{Report: report}
+	}
+
+	bundle := &Bundle{}
+	manifest := Manifest{
+		ManifestVersion: ManifestVersion,
+		Version:         opts.Version,
+		BuiltAt:         opts.BuiltAt.UTC(),
+		Formats:         formats,
+	}
+
+	codegenTables := make([]codegen.Table, len(prepared))
+	baseNames := fileBaseNames(prepared)
+	for i, t := range prepared {
+		codegenTables[i] = codegen.Table{ID: t.ID, Name: t.Name, Schema: t.schema}
+		records := normalizeRecords(t)
+		base := baseNames[i]
+
+		schemaHash := sha256.Sum256(t.Schema)
+		entry := ManifestTable{
+			ID:         t.ID,
+			Name:       t.Name,
+			Records:    len(records),
+			SchemaHash: hex.EncodeToString(schemaHash[:]),
+			Files:      make(map[Format]string),
+		}
+
+		schemaFile, err := json.MarshalIndent(json.RawMessage(t.Schema), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("table %s: failed to encode schema: %w", t.Name, err)
+		}
+		bundle.add("schemas/"+base+".schema.json", schemaFile)
+
+		for _, format := range formats {
+			data, ext, err := encodeTable(format, t, records)
+			if err != nil {
+				return nil, fmt.Errorf("table %s: failed to encode %s: %w", t.Name, format, err)
+			}
+			path := "tables/" + base + ext
+			bundle.add(path, data)
+			entry.Files[format] = path
+		}
+
+		manifest.Tables = append(manifest.Tables, entry)
+	}
+
+	goSource, err := codegen.Go(codegenTables, codegen.Options{Package: opts.GoPackage})
+	if err != nil {
+		return nil, err
+	}
+	bundle.add("types/gamedata.go", goSource)
+	bundle.add("types/gamedata.ts", codegen.TypeScript(codegenTables))
+
+	for i, name := range codegen.TypeNames(codegenTables) {
+		manifest.Tables[i].TypeName = name
+	}
+
+	contentHash := sha256.New()
+	for _, f := range bundle.Files {
+		sum := sha256.Sum256(f.Data)
+		mf := ManifestFile{Path: f.Path, Size: len(f.Data), SHA256: hex.EncodeToString(sum[:])}
+		manifest.Files = append(manifest.Files, mf)
+		fmt.Fprintf(contentHash, "%s  %s\n", mf.SHA256, mf.Path)
+	}
+	manifest.ContentHash = hex.EncodeToString(contentHash.Sum(nil))
+	bundle.Manifest = manifest
+
+	return bundle, nil
+}
+
+func normalizeOptions(opts *Options) ([]Format, error) {
+	formats, err := ParseFormats(formatNames(opts.Formats))
+	if err != nil {
+		return nil, err
+	}
+	if opts.BuiltAt.IsZero() {
+		opts.BuiltAt = time.Now()
+	}
+	if opts.Version == "" {
+		opts.Version = opts.BuiltAt.UTC().Format("20060102-150405")
+	}
+	if !versionPattern.MatchString(opts.Version) {
+		return nil, fmt.Errorf("%w: version %q may only use letters, digits, '.', '_' or '-'", ErrInvalidOptions, opts.Version)
+	}
+	return formats, nil
+}
+
+func formatNames(formats []Format) []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+func (b *Bundle) add(path string, data []byte) {
+	b.Files = append(b.Files, File{Path: path, Data: data})
+}
+
+// ManifestJSON returns the indented manifest.json content
+func (b *Bundle) ManifestJSON() ([]byte, error) {
+	return json.MarshalIndent(b.Manifest, "", "  ")
+}
+
+// WriteDir writes the bundle to dir/<version>/ and returns that directory
+func (b *Bundle) WriteDir(dir string) (string, error) {
+	root := filepath.Join(dir, b.Manifest.Version)
+	if _, err := os.Stat(root); err == nil {
+		return "", fmt.Errorf("bundle version %s already exists at %s", b.Manifest.Version, root)
+	}
+
+	for _, f := range b.Files {
+		path := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, f.Data, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	manifest, err := b.ManifestJSON()
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(root, "manifest.json"), manifest, 0o644); err != nil {
+		return "", err
+	}
+	return root, nil
+}
+
+// WriteZip writes the bundle as a zip archive rooted at <version>/
+func (b *Bundle) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	modified := b.Manifest.BuiltAt
+
+	write := func(path string, data []byte) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     b.Manifest.Version + "/" + path,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+	}
+
+	manifest, err := b.ManifestJSON()
+	if err != nil {
+		return err
+	}
+	if err := write("manifest.json", manifest); err != nil {
+		return err
+	}
+	for _, f := range b.Files {
+		if err := write(f.Path, f.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// encodeTable encodes a table's records in the given format
+func encodeTable(format Format, t *preparedTable, records []Record) ([]byte, string, error) {
+	switch format {
+	case FormatJSON:
+		rows := recordMaps(records)
+		data, err := json.MarshalIndent(rows, "", "  ")
+		return data, ".json", err
+	case FormatMsgPack:
+		data, err := encodeMsgPack(recordMaps(records))
+		return data, ".msgpack", err
+	case FormatBinary:
+		data, err := EncodeBinary(t.schema, records)
+		return data, ".bin", err
+	default:
+		return nil, "", fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func encodeMsgPack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	// Sorted keys keep the output, and therefore its hash, deterministic
+	enc.SetSortMapKeys(true)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// recordMaps flattens records into maps carrying the record id as "_id"
+func recordMaps(records []Record) []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(records))
+	for i, r := range records {
+		row := make(map[string]interface{}, len(r.Data)+1)
+		for k, v := range r.Data {
+			row[k] = v
+		}
+		row["_id"] = r.ID
+		rows[i] = row
+	}
+	return rows
+}
+
+// normalizeRecords sorts records by id, drops undeclared fields and converts
+// integer fields to int64 so every format encodes them as integers
+func normalizeRecords(t *preparedTable) []Record {
+	records := make([]Record, len(t.Records))
+	for i, r := range t.Records {
+		data := make(map[string]interface{}, len(t.schema.Properties))
+		for name, prop := range t.schema.Properties {
+			value, ok := r.Data[name]
+			if !ok || value == nil {
+				continue
+			}
+			if f, isFloat := value.(float64); isFloat && prop.Type == "integer" && f == math.Trunc(f) {
+				value = int64(f)
+			}
+			data[name] = value
+		}
+		records[i] = Record{ID: r.ID, Data: data}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// fileBaseNames derives unique, filesystem-safe file names from table
+// names, falling back to the table ID and then to a numeric suffix
+func fileBaseNames(tables []*preparedTable) []string {
+	names := make([]string, len(tables))
+	used := make(map[string]bool)
+	for i, t := range tables {
+		base := safeFileName(t.Name)
+		if base == "" || used[base] {
+			base = safeFileName(t.ID)
+		}
+		if base == "" {
+			base = "table"
+		}
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func safeFileName(s string) string {
+	return strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
@@ -0,0 +1,192 @@
+package publish
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// staticSource serves fixed tables for tests
+type staticSource []TableData
+
+func (s staticSource) LoadTables(ctx context.Context, tableIDs []string) ([]TableData, error) {
+	return s, nil
+}
+
+func gameTables() staticSource {
+	return staticSource{
+		{
+			ID:   "table_item",
+			Name: "game_item",
+			Schema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"code": { "type": "string", "minLength": 1 },
+					"rarity": { "type": "string", "enum": ["일반", "전설"] },
+					"attack_power": { "type": "integer", "minimum": 0 }
+				},
+				"required": ["code", "rarity"]
+			}`),
+			Records: []Record{
+				{ID: 2, Data: map[string]interface{}{"code": "sword", "rarity": "전설", "attack_power": float64(120)}},
+				{ID: 1, Data: map[string]interface{}{"code": "potion", "rarity": "일반"}},
+			},
+		},
+		{
+			ID:   "table_quest",
+			Name: "quest",
+			Schema: json.RawMessage(`{
+				"type": "object",
+				"properties": {
+					"quest_name": { "type": "string", "title": "퀘스트명" },
+					"reward_item": { "type": "string", "x-ref": "game_item.code" },
+					"reward_gold": { "type": "integer", "minimum": 0 },
+					"repeatable": { "type": "boolean" }
+				},
+				"required": ["quest_name"]
+			}`),
+			Records: []Record{
+				{ID: 7, Data: map[string]interface{}{"quest_name": "첫 모험", "reward_item": "sword", "reward_gold": float64(50), "repeatable": false}},
+			},
+		},
+	}
+}
+
+func TestBuildProducesVersionedBundle(t *testing.T) {
+	builtAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	opts := Options{Version: "1.2.0", BuiltAt: builtAt}
+
+	bundle, err := Build(context.Background(), gameTables(), opts)
+	if err != nil {
+		t.Fatalf("Expected bundle to build, got: %v", err)
+	}
+
+	if bundle.Manifest.Version != "1.2.0" {
+		t.Errorf("Expected version 1.2.0, got: %s", bundle.Manifest.Version)
+	}
+	if len(bundle.Manifest.Tables) != 2 {
+		t.Fatalf("Expected 2 tables in manifest, got: %d", len(bundle.Manifest.Tables))
+	}
+
+	paths := make(map[string][]byte)
+	for _, f := range bundle.Files {
+		paths[f.Path] = f.Data
+	}
+	for _, expected := range []string{
+		"tables/game_item.json", "tables/game_item.msgpack", "tables/game_item.bin",
+		"tables/quest.json", "schemas/quest.schema.json", "types/gamedata.go", "types/gamedata.ts",
+	} {
+		if _, ok := paths[expected]; !ok {
+			t.Errorf("Expected bundle to contain %s", expected)
+		}
+	}
+
+	for _, mf := range bundle.Manifest.Files {
+		sum := sha256.Sum256(paths[mf.Path])
+		if hex.EncodeToString(sum[:]) != mf.SHA256 {
+			t.Errorf("Manifest hash mismatch for %s", mf.Path)
+		}
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(paths["tables/game_item.json"], &items); err != nil {
+		t.Fatalf("Expected valid JSON table, got: %v", err)
+	}
+	if items[0]["code"] != "potion" {
+		t.Errorf("Expected records sorted by id, got first record: %v", items[0])
+	}
+
+	goTypes := strings.Join(strings.Fields(string(paths["types/gamedata.go"])), " ")
+	if !strings.Contains(goTypes, "RewardGold *int64 `json:\"reward_gold,omitempty\"`") {
+		t.Errorf("Expected optional integer field in Go types, got:\n%s", paths["types/gamedata.go"])
+	}
+	if !strings.Contains(string(paths["types/gamedata.ts"]), `rarity: "일반" | "전설";`) {
+		t.Errorf("Expected enum union in TypeScript types, got:\n%s", paths["types/gamedata.ts"])
+	}
+
+	again, err := Build(context.Background(), gameTables(), opts)
+	if err != nil {
+		t.Fatalf("Expected second build to succeed, got: %v", err)
+	}
+	if again.Manifest.ContentHash != bundle.Manifest.ContentHash {
+		t.Error("Expected identical input to produce an identical content hash")
+	}
+}
+
+func TestBuildRejectsInvalidData(t *testing.T) {
+	tables := gameTables()
+	tables[1].Records = append(tables[1].Records,
+		Record{ID: 8, Data: map[string]interface{}{"quest_name": "깨진 참조", "reward_item": "shield"}},
+		Record{ID: 9, Data: map[string]interface{}{"reward_gold": float64(-5)}},
+	)
+
+	_, err := Build(context.Background(), tables, Options{Version: "bad"})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got: %v", err)
+	}
+
+	codes := make(map[string]int)
+	for _, issue := range validationErr.Report.Issues {
+		codes[issue.Code]++
+	}
+	if codes[CodeBrokenRef] != 1 {
+		t.Errorf("Expected 1 broken reference, got: %v", validationErr.Report.Issues)
+	}
+	if codes["required"] != 1 || codes["minimum"] != 1 {
+		t.Errorf("Expected required and minimum violations, got: %v", validationErr.Report.Issues)
+	}
+}
+
+func TestBuildRejectsInvalidVersion(t *testing.T) {
+	_, err := Build(context.Background(), gameTables(), Options{Version: "../escape"})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("Expected ErrInvalidOptions, got: %v", err)
+	}
+}
+
+func TestFileBaseNamesAreUnique(t *testing.T) {
+	tables := []*preparedTable{
+		{TableData: TableData{ID: "t1", Name: "Items"}},
+		{TableData: TableData{ID: "t2", Name: "t1"}},
+		{TableData: TableData{ID: "T1", Name: "items"}},
+		{TableData: TableData{ID: "t4", Name: "!!!"}},
+	}
+	got := fileBaseNames(tables)
+	want := []string{"items", "t1", "t1_2", "t4"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	tables := gameTables()
+	prepared, report := prepare(tables)
+	if !report.OK() {
+		t.Fatalf("Expected fixture to validate, got: %v", report.Issues)
+	}
+
+	records := normalizeRecords(prepared[1])
+	data, err := EncodeBinary(prepared[1].schema, records)
+	if err != nil {
+		t.Fatalf("Expected encode to succeed, got: %v", err)
+	}
+
+	decoded, err := DecodeBinary(data)
+	if err != nil {
+		t.Fatalf("Expected decode to succeed, got: %v", err)
+	}
+	if len(decoded.Rows) != 1 || decoded.Rows[0].ID != 7 {
+		t.Fatalf("Expected one row with id 7, got: %+v", decoded.Rows)
+	}
+
+	row := decoded.Rows[0].Data
+	if row["quest_name"] != "첫 모험" || row["reward_gold"] != int64(50) || row["repeatable"] != false {
+		t.Errorf("Unexpected decoded row: %v", row)
+	}
+}
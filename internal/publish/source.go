@@ -0,0 +1,118 @@
+package publish
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ErrTableNotFound is returned when a requested table does not exist
+var ErrTableNotFound = errors.New("table not found")
+
+// TableData is a table's schema together with all of its records
+type TableData struct {
+	ID        string
+	Name      string
+	Schema    json.RawMessage
+	UpdatedAt time.Time
+	Records   []Record
+}
+
+// Record is a single table row as stored in the records table
+type Record struct {
+	ID   int64
+	Data map[string]interface{}
+}
+
+// Source loads tables and their records for publishing
+type Source interface {
+	// LoadTables returns the given tables, or every table when tableIDs is empty
+	LoadTables(ctx context.Context, tableIDs []string) ([]TableData, error)
+}
+
+// PostgresSource reads tables and records from PostgreSQL
+type PostgresSource struct {
+	db *sqlx.DB
+}
+
+// NewPostgresSource creates a new PostgresSource
+func NewPostgresSource(db *sqlx.DB) *PostgresSource {
+	return &PostgresSource{db: db}
+}
+
+type tableRow struct {
+	ID        string          `db:"id"`
+	Name      string          `db:"name"`
+	Schema    json.RawMessage `db:"schema"`
+	UpdatedAt time.Time       `db:"updated_at"`
+}
+
+type recordRow struct {
+	ID      int64           `db:"id"`
+	TableID string          `db:"table_id"`
+	Data    json.RawMessage `db:"data"`
+}
+
+// LoadTables loads the requested tables inside a read-only repeatable-read
+// transaction so that every table in a bundle comes from the same snapshot
+func (s *PostgresSource) LoadTables(ctx context.Context, tableIDs []string) ([]TableData, error) {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var tables []tableRow
+	if len(tableIDs) == 0 {
+		err = tx.SelectContext(ctx, &tables, `SELECT id, name, schema, updated_at FROM tables ORDER BY name, id`)
+	} else {
+		err = tx.SelectContext(ctx, &tables, `SELECT id, name, schema, updated_at FROM tables WHERE id = ANY($1) ORDER BY name, id`, pq.Array(tableIDs))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tables: %w", err)
+	}
+
+	if len(tables) < len(tableIDs) {
+		found := make(map[string]bool, len(tables))
+		for _, t := range tables {
+			found[t.ID] = true
+		}
+		for _, id := range tableIDs {
+			if !found[id] {
+				return nil, fmt.Errorf("%w: %s", ErrTableNotFound, id)
+			}
+		}
+	}
+
+	ids := make([]string, len(tables))
+	result := make([]TableData, len(tables))
+	index := make(map[string]int, len(tables))
+	for i, t := range tables {
+		ids[i] = t.ID
+		index[t.ID] = i
+		result[i] = TableData{ID: t.ID, Name: t.Name, Schema: t.Schema, UpdatedAt: t.UpdatedAt}
+	}
+
+	var records []recordRow
+	err = tx.SelectContext(ctx, &records, `SELECT id, table_id, data FROM records WHERE table_id = ANY($1) ORDER BY table_id, id`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load records: %w", err)
+	}
+
+	for _, r := range records {
+		var data map[string]interface{}
+		if err := json.Unmarshal(r.Data, &data); err != nil {
+			return nil, fmt.Errorf("record %d in table %s has invalid data: %w", r.ID, r.TableID, err)
+		}
+		i := index[r.TableID]
+		result[i].Records = append(result[i].Records, Record{ID: r.ID, Data: data})
+	}
+
+	return result, nil
+}
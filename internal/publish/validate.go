@@ -0,0 +1,188 @@
+package publish
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/validation"
+)
+
+// Issue codes reported for problems that span a whole table or several tables
+const (
+	CodeInvalidSchema   = "invalid_schema"
+	CodeUnknownRefTable = "unknown_reference_table"
+	CodeBrokenRef       = "broken_reference"
+)
+
+// Issue is a single problem found while validating a bundle
+type Issue struct {
+	TableID  string `json:"table_id"`
+	Table    string `json:"table"`
+	RecordID int64  `json:"record_id,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// Report summarises the validation of a set of tables
+type Report struct {
+	Tables  int     `json:"tables"`
+	Records int     `json:"records"`
+	Issues  []Issue `json:"issues"`
+}
+
+// OK reports whether validation found no issues
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidationError is returned by Build when the tables do not validate
+type ValidationError struct {
+	Report *Report
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("bundle validation failed with %d issue(s)", len(e.Report.Issues))
+}
+
+// preparedTable is a table whose schema has been parsed and compiled
+type preparedTable struct {
+	TableData
+	schema    *repository.SchemaDefinition
+	validator *validation.Validator
+}
+
+// Validate checks every record against its table schema and resolves all
+// cross-table references (properties with "x-ref") within the given tables
+func Validate(tables []TableData) *Report {
+	_, report := prepare(tables)
+	return report
+}
+
+func prepare(tables []TableData) ([]*preparedTable, *Report) {
+	report := &Report{Tables: len(tables), Issues: []Issue{}}
+	prepared := make([]*preparedTable, 0, len(tables))
+
+	for _, table := range tables {
+		report.Records += len(table.Records)
+
+		validator, err := validation.NewFromJSON(table.Schema)
+		if err != nil {
+			report.Issues = append(report.Issues, Issue{
+				TableID: table.ID, Table: table.Name, Code: CodeInvalidSchema, Message: err.Error(),
+			})
+			continue
+		}
+
+		pt := &preparedTable{TableData: table, schema: validator.Schema(), validator: validator}
+		prepared = append(prepared, pt)
+
+		for _, record := range table.Records {
+			for _, fe := range validator.Validate(record.Data) {
+				report.Issues = append(report.Issues, Issue{
+					TableID: table.ID, Table: table.Name, RecordID: record.ID,
+					Field: fe.Field, Code: fe.Code, Message: fe.Message,
+				})
+			}
+		}
+	}
+
+	report.Issues = append(report.Issues, checkReferences(prepared)...)
+	return prepared, report
+}
+
+// checkReferences verifies that every x-ref value points at an existing record
+func checkReferences(tables []*preparedTable) []Issue {
+	byKey := make(map[string]*preparedTable, len(tables)*2)
+	for _, t := range tables {
+		byKey[t.ID] = t
+	}
+	for _, t := range tables {
+		if _, taken := byKey[t.Name]; !taken {
+			byKey[t.Name] = t
+		}
+	}
+
+	keySets := make(map[string]map[string]bool)
+	keysFor := func(target *preparedTable, field string) map[string]bool {
+		cacheKey := target.ID + "\x00" + field
+		if keys, ok := keySets[cacheKey]; ok {
+			return keys
+		}
+		keys := make(map[string]bool, len(target.Records))
+		for _, record := range target.Records {
+			if field == "_id" {
+				keys[strconv.FormatInt(record.ID, 10)] = true
+			} else if value, ok := record.Data[field]; ok && value != nil {
+				keys[keyString(value)] = true
+			}
+		}
+		keySets[cacheKey] = keys
+		return keys
+	}
+
+	var issues []Issue
+	for _, t := range tables {
+		for _, name := range t.schema.PropertyNames() {
+			ref := t.schema.Properties[name].Ref
+			if ref == "" {
+				continue
+			}
+
+			target, field := resolveRef(byKey, ref)
+			if target == nil {
+				issues = append(issues, Issue{
+					TableID: t.ID, Table: t.Name, Field: name, Code: CodeUnknownRefTable,
+					Message: fmt.Sprintf("reference %q does not point at a table included in the bundle", ref),
+				})
+				continue
+			}
+
+			keys := keysFor(target, field)
+			for _, record := range t.Records {
+				value, ok := record.Data[name]
+				if !ok || value == nil {
+					continue
+				}
+				if !keys[keyString(value)] {
+					issues = append(issues, Issue{
+						TableID: t.ID, Table: t.Name, RecordID: record.ID, Field: name, Code: CodeBrokenRef,
+						Message: fmt.Sprintf("value %v has no matching %s in table %s", value, field, target.Name),
+					})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// resolveRef resolves "table" or "table.field" against table IDs and names
+func resolveRef(tables map[string]*preparedTable, ref string) (*preparedTable, string) {
+	if t, ok := tables[ref]; ok {
+		return t, "_id"
+	}
+	if i := strings.LastIndex(ref, "."); i > 0 {
+		if t, ok := tables[ref[:i]]; ok {
+			return t, ref[i+1:]
+		}
+	}
+	return nil, ""
+}
+
+// keyString normalises a JSON value so that references compare by value
+func keyString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
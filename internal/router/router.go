@@ -0,0 +1,194 @@
+// Package router mounts handlers on a Go 1.22 ServeMux with method-aware
+// patterns ("GET /tables/{id}"), per-route middleware, prefix groups such as
+// /api/v1 and deprecated aliases for old paths. Unmatched requests get
+// problem documents instead of the mux's plain-text 404 and 405.
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"progressive/internal/apierror"
+	"progressive/internal/metrics"
+)
+
+// Middleware wraps a route's handler
+type Middleware func(next apierror.HandlerFunc) apierror.HandlerFunc
+
+// Router registers routes under a prefix with shared middleware. Groups
+// share the mux and route list of the router they come from.
+type Router struct {
+	mux        *http.ServeMux
+	routes     *[]Route
+	prefix     string
+	middleware []Middleware
+}
+
+// Route is a registered route, e.g. for generating API documentation
+type Route struct {
+	// Method is empty for routes that match every method
+	Method string
+	// Path is the full path pattern, e.g. "/api/v1/tables/{id}"
+	Path string
+	// Successor is the path replacing a deprecated route
+	Successor string
+}
+
+// New creates a router with an empty mux
+func New() *Router {
+	return &Router{mux: http.NewServeMux(), routes: &[]Route{}}
+}
+
+// Routes lists the registered routes in registration order
+func (rt *Router) Routes() []Route {
+	return append([]Route(nil), *rt.routes...)
+}
+
+// Mux returns the underlying mux, e.g. to look up route patterns
+func (rt *Router) Mux() *http.ServeMux {
+	return rt.mux
+}
+
+// Group returns a router for routes under prefix that run mw after the
+// middleware of rt
+func (rt *Router) Group(prefix string, mw ...Middleware) *Router {
+	return &Router{
+		mux:        rt.mux,
+		routes:     rt.routes,
+		prefix:     rt.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: append(append([]Middleware(nil), rt.middleware...), mw...),
+	}
+}
+
+// With returns a router for the same prefix that also runs mw
+func (rt *Router) With(mw ...Middleware) *Router {
+	return rt.Group("", mw...)
+}
+
+// Handle registers h for pattern, "METHOD /path" relative to the prefix.
+// mw runs inside the router's middleware.
+func (rt *Router) Handle(pattern string, h apierror.HandlerFunc, mw ...Middleware) {
+	rt.register(rt.pattern(pattern), "", rt.wrap(h, mw))
+}
+
+// Path returns the full path of a path relative to the prefix
+func (rt *Router) Path(path string) string {
+	if path == "/" && rt.prefix != "" {
+		return rt.prefix
+	}
+	return rt.prefix + path
+}
+
+// HandleHTTP registers a plain http.Handler; the router's middleware does
+// not apply to it
+func (rt *Router) HandleHTTP(pattern string, h http.Handler) {
+	rt.register(rt.pattern(pattern), "", h)
+}
+
+// Deprecated registers h for an old pattern. Responses carry a Deprecation
+// header and a Link to successor, a full path whose {wildcards} are filled
+// from the request, and requests are counted by pattern.
+func (rt *Router) Deprecated(pattern, successor string, h apierror.HandlerFunc, mw ...Middleware) {
+	full := rt.pattern(pattern)
+	route := full
+	if i := strings.IndexByte(route, ' '); i >= 0 {
+		route = route[i+1:]
+	}
+	next := rt.wrap(h, mw)
+	rt.register(full, successor, apierror.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+expand(successor, r)+`>; rel="successor-version"`)
+		metrics.DeprecatedRequests.Inc(route)
+		return next(w, r)
+	}))
+}
+
+// ServeHTTP dispatches to the matching route, answering 404 and 405 with
+// problem documents
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := rt.mux.Handler(r); pattern != "" {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+	if allowed := rt.allowed(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		apierror.Report(w, r, apierror.MethodNotAllowed())
+		return
+	}
+	apierror.Report(w, r, apierror.NotFound(apierror.CodeNotFound, "No route for "+r.URL.Path))
+}
+
+// allowed lists the methods some route accepts for r's path
+func (rt *Router) allowed(r *http.Request) []string {
+	var methods []string
+	for _, method := range []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"} {
+		if method == r.Method {
+			continue
+		}
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := rt.mux.Handler(probe); pattern != "" {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+func (rt *Router) register(pattern, successor string, h http.Handler) {
+	rt.mux.Handle(pattern, h)
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		method, path = "", pattern
+	}
+	*rt.routes = append(*rt.routes, Route{Method: method, Path: path, Successor: successor})
+}
+
+func (rt *Router) pattern(pattern string) string {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return rt.Path(pattern)
+	}
+	return method + " " + rt.Path(path)
+}
+
+func (rt *Router) wrap(h apierror.HandlerFunc, mw []Middleware) apierror.HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	for i := len(rt.middleware) - 1; i >= 0; i-- {
+		h = rt.middleware[i](h)
+	}
+	return h
+}
+
+// expand fills the {wildcards} of path with the path values of r; those
+// without a value are kept as they are
+func expand(path string, r *http.Request) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		end := strings.IndexByte(path, '}')
+		if start < 0 || end < start {
+			b.WriteString(path)
+			return b.String()
+		}
+		b.WriteString(path[:start])
+		name := strings.TrimSuffix(path[start+1:end], "...")
+		if v := r.PathValue(name); v != "" {
+			b.WriteString(v)
+		} else {
+			b.WriteString(path[start : end+1])
+		}
+		path = path[end+1:]
+	}
+}
+
+// PathInt64 parses the integer path value name
+func PathInt64(r *http.Request, name string) (int64, error) {
+	v, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, apierror.Newf(http.StatusBadRequest, "invalid_"+name, "Invalid %s: %q is not an integer", name, r.PathValue(name))
+	}
+	return v, nil
+}
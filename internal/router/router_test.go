@@ -0,0 +1,112 @@
+package router
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"progressive/internal/apierror"
+)
+
+func send(rt *Router, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func write(body string) apierror.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		io.WriteString(w, body)
+		return nil
+	}
+}
+
+func TestUnmatchedRoutesAreProblems(t *testing.T) {
+	rt := New()
+	api := rt.Group("/api/v1")
+	api.Handle("GET /items", write("list"))
+	api.Handle("POST /items", write("create"))
+
+	rec := send(rt, "DELETE", "/api/v1/items")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected 405, got %d", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD, POST" {
+		t.Errorf("Unexpected Allow header: %q", allow)
+	}
+	var problem map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if rec.Header().Get("Content-Type") != apierror.ContentType || problem["code"] != apierror.CodeMethodNotAllowed {
+		t.Errorf("Expected a method_not_allowed problem, got %q: %v", rec.Header().Get("Content-Type"), problem)
+	}
+
+	rec = send(rt, "GET", "/api/v1/nothing")
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != apierror.ContentType {
+		t.Errorf("Expected a 404 problem, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next apierror.HandlerFunc) apierror.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) error {
+				order = append(order, name)
+				return next(w, r)
+			}
+		}
+	}
+
+	rt := New()
+	rt.Group("/api", mark("group")).With(mark("with")).Handle("GET /items", write("ok"), mark("route"))
+	send(rt, "GET", "/api/items")
+	if strings.Join(order, ",") != "group,with,route" {
+		t.Errorf("Unexpected middleware order: %v", order)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	rt := New()
+	api := rt.Group("/api/v1")
+	api.Handle("GET /tables/{id}/records", write("records"))
+	rt.Deprecated("GET /api/table/{id}", api.Path("/tables/{id}/records"), write("records"))
+
+	rec := send(rt, "GET", "/api/table/items")
+	if rec.Code != http.StatusOK || rec.Body.String() != "records" {
+		t.Fatalf("Expected the legacy path to be served, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected Deprecation header, got: %v", rec.Header())
+	}
+	if link := rec.Header().Get("Link"); link != `</api/v1/tables/items/records>; rel="successor-version"` {
+		t.Errorf("Unexpected Link header: %q", link)
+	}
+	if rec := send(rt, "GET", "/api/v1/tables/items/records"); rec.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected no Deprecation header on the current path")
+	}
+}
+
+func TestPathInt64(t *testing.T) {
+	rt := New()
+	rt.Handle("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		id, err := PathInt64(r, "id")
+		if err != nil {
+			return err
+		}
+		io.WriteString(w, "item "+strings.Repeat("*", int(id)))
+		return nil
+	})
+
+	if rec := send(rt, "GET", "/items/3"); rec.Body.String() != "item ***" {
+		t.Errorf("Unexpected body: %q", rec.Body.String())
+	}
+	rec := send(rt, "GET", "/items/abc")
+	var problem map[string]interface{}
+	json.NewDecoder(rec.Body).Decode(&problem)
+	if rec.Code != http.StatusBadRequest || problem["code"] != "invalid_id" {
+		t.Errorf("Expected 400 invalid_id, got %d: %v", rec.Code, problem)
+	}
+}
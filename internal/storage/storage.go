@@ -0,0 +1,184 @@
+// Package storage selects the database backend and builds the table and
+// record repositories on top of it.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	recordrepo "progressive/internal/domain/record/repository"
+	templaterepo "progressive/internal/domain/schematemplate/repository"
+	tablerepo "progressive/internal/domain/table/repository"
+	"progressive/internal/infrastructure"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Backend names accepted by the -storage flag
+const (
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+	Memory   = "memory"
+)
+
+// Options configures Open
+type Options struct {
+	Backend    string
+	SQLitePath string
+	// DSN connects to an external PostgreSQL instead of starting the embedded one
+	DSN      string
+	Pool     infrastructure.PoolConfig
+	Postgres []infrastructure.Option
+	// SkipMigrations leaves the schema alone, for the migrate command
+	SkipMigrations bool
+}
+
+// Store is an opened, migrated backend
+type Store struct {
+	Backend string
+	DB      *sqlx.DB
+	Tables  tablerepo.TableRepository
+	Records recordrepo.RecordRepository
+	// Templates holds the schema templates offered when creating a table
+	Templates templaterepo.SchemaTemplateRepository
+	close     func() error
+	ping      func(ctx context.Context) error
+}
+
+// Open connects to the selected backend and runs the migrations
+func Open(opts Options) (*Store, error) {
+	var store *Store
+	pool := opts.Pool
+	if pool == (infrastructure.PoolConfig{}) {
+		pool = infrastructure.DefaultPool()
+	}
+
+	switch {
+	case (opts.Backend == "" || opts.Backend == Postgres) && opts.DSN != "":
+		db, err := infrastructure.NewDBWithDSN(opts.DSN, pool)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("using external PostgreSQL")
+
+		store = &Store{
+			Backend:   Postgres,
+			DB:        db,
+			Tables:    tablerepo.NewPostgresRepository(db),
+			Records:   recordrepo.NewPostgresRepository(db),
+			Templates: templaterepo.NewPostgresRepository(db),
+			close:     db.Close,
+			ping:      db.PingContext,
+		}
+	case opts.Backend == "" || opts.Backend == Postgres:
+		postgresOpts := append([]infrastructure.Option{infrastructure.WithPool(pool)}, opts.Postgres...)
+		embeddedDB, err := infrastructure.NewEmbeddedDB(postgresOpts...)
+		if err != nil {
+			return nil, err
+		}
+		config := embeddedDB.GetConfig()
+		slog.Info("PostgreSQL running", slog.String("host", config.Host), slog.Int("port", int(config.Port)))
+
+		store = &Store{
+			Backend:   Postgres,
+			DB:        embeddedDB.DB,
+			Tables:    tablerepo.NewPostgresRepository(embeddedDB.DB),
+			Records:   recordrepo.NewPostgresRepository(embeddedDB.DB),
+			Templates: templaterepo.NewPostgresRepository(embeddedDB.DB),
+			close:     embeddedDB.Close,
+			ping:      embeddedDB.Ping,
+		}
+	case opts.Backend == SQLite:
+		if opts.SQLitePath == "" {
+			return nil, fmt.Errorf("sqlite backend requires a database path")
+		}
+		db, err := infrastructure.NewSQLiteDB(opts.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		store = &Store{
+			Backend:   SQLite,
+			DB:        db,
+			Tables:    tablerepo.NewSQLiteRepository(db),
+			Records:   recordrepo.NewSQLiteRepository(db),
+			Templates: templaterepo.NewSQLiteRepository(db),
+			close:     db.Close,
+			ping:      db.PingContext,
+		}
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (expected %s or %s)", opts.Backend, Postgres, SQLite)
+	}
+
+	if opts.SkipMigrations {
+		return store, nil
+	}
+	if err := infrastructure.RunMigrations(store.DB); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return store, nil
+}
+
+// NewMemory creates a store without a database for tests. Only the table,
+// record and template repositories are available.
+func NewMemory() *Store {
+	tables := tablerepo.NewMemoryRepository()
+	return &Store{
+		Backend:   Memory,
+		Tables:    tables,
+		Records:   recordrepo.NewMemoryRepository(tables),
+		Templates: templaterepo.NewMemoryRepository(),
+		close:     func() error { return nil },
+		ping:      func(context.Context) error { return nil },
+	}
+}
+
+// SupportsAllFeatures reports whether snapshots, branches, comments and the
+// audit log can run; their repositories use PostgreSQL-only SQL.
+func (s *Store) SupportsAllFeatures() bool {
+	return s.Backend == Postgres
+}
+
+// Ping checks that the database answers; with embedded PostgreSQL it also
+// checks that the server process is still running
+func (s *Store) Ping(ctx context.Context) error {
+	return s.ping(ctx)
+}
+
+// CheckMigrations reports pending migrations, e.g. after a rollback with
+// the migrate command while the server was running
+func (s *Store) CheckMigrations(ctx context.Context) error {
+	if s.DB == nil {
+		return nil
+	}
+	m, err := infrastructure.NewMigrator(s.DB)
+	if err != nil {
+		return err
+	}
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d pending migrations, first %03d_%s", len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// Counts returns the number of tables and of records across them
+func (s *Store) Counts(ctx context.Context) (tables, records int, err error) {
+	all, err := s.Tables.FindAll(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, t := range all {
+		records += t.RecordCount
+	}
+	return len(all), records, nil
+}
+
+// Close releases the backend
+func (s *Store) Close() error {
+	return s.close()
+}
@@ -0,0 +1,286 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"progressive/internal/domain/record"
+	"progressive/internal/domain/schematemplate"
+	templaterepo "progressive/internal/domain/schematemplate/repository"
+	"progressive/internal/domain/table"
+	"progressive/internal/storage"
+	"progressive/internal/storage/storagetest"
+)
+
+func TestTableRepositoryContract(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		schema := json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+
+		if err := store.Tables.Create(ctx, table.NewTable("items", "Items", "", schema)); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+		err := store.Tables.Create(ctx, table.NewTable("items", "Items again", "", schema))
+		if !errors.Is(err, table.ErrAlreadyExists) {
+			t.Errorf("Expected ErrAlreadyExists, got: %v", err)
+		}
+		if err := store.Tables.Create(ctx, table.NewTable("", "No ID", "", schema)); !errors.Is(err, table.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got: %v", err)
+		}
+
+		found, err := store.Tables.FindByID(ctx, "items")
+		if err != nil {
+			t.Fatalf("Failed to find table: %v", err)
+		}
+		var parsed map[string]interface{}
+		if found.Name != "Items" || json.Unmarshal(found.Schema, &parsed) != nil || parsed["type"] != "object" {
+			t.Errorf("Unexpected table: %+v", found)
+		}
+
+		all, err := store.Tables.FindAll(ctx)
+		if err != nil || len(all) != 1 {
+			t.Errorf("Expected 1 table, got %d (%v)", len(all), err)
+		}
+
+		if err := store.Tables.Delete(ctx, "items"); err != nil {
+			t.Fatalf("Failed to delete table: %v", err)
+		}
+		if _, err := store.Tables.FindByID(ctx, "items"); !errors.Is(err, table.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+		}
+		if err := store.Tables.Delete(ctx, "items"); !errors.Is(err, table.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got: %v", err)
+		}
+	})
+}
+
+func TestTemplateRepositoryContract(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		if _, err := templaterepo.SyncDefaults(ctx, store.Templates); err != nil {
+			t.Fatalf("Failed to initialize defaults: %v", err)
+		}
+		defaults, err := store.Templates.FindAll(ctx)
+		if err != nil || len(defaults) == 0 {
+			t.Fatalf("Expected default templates, got %d (%v)", len(defaults), err)
+		}
+		// A second sync finds every stored built-in up to date, so the
+		// version and checksum survive the round trip
+		report, err := templaterepo.SyncDefaults(ctx, store.Templates)
+		if err != nil || len(report.Created)+len(report.Upgraded)+len(report.Kept) != 0 {
+			t.Errorf("Expected nothing to sync twice, got %+v (%v)", report, err)
+		}
+
+		schema := json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+		tmpl := schematemplate.NewSchemaTemplate("items", "Items", "", schematemplate.CategoryCustom, "", schema)
+		tmpl.Workspace = "team-a"
+		tmpl.SampleData = json.RawMessage(`[{"name": "sword"}]`)
+		if err := store.Templates.Create(ctx, tmpl); err != nil {
+			t.Fatalf("Failed to create template: %v", err)
+		}
+		if err := store.Templates.Create(ctx, tmpl); !errors.Is(err, schematemplate.ErrAlreadyExists) {
+			t.Errorf("Expected ErrAlreadyExists, got: %v", err)
+		}
+		invalid := schematemplate.NewSchemaTemplate("Bad ID", "Bad", "", schematemplate.CategoryCustom, "", schema)
+		if err := store.Templates.Create(ctx, invalid); !errors.Is(err, schematemplate.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got: %v", err)
+		}
+
+		found, err := store.Templates.FindByID(ctx, "items")
+		if err != nil {
+			t.Fatalf("Failed to find template: %v", err)
+		}
+		var rows []map[string]interface{}
+		if found.Workspace != "team-a" || json.Unmarshal(found.SampleData, &rows) != nil || len(rows) != 1 {
+			t.Errorf("Unexpected template: %+v", found)
+		}
+
+		found.Name = "Renamed"
+		found.SampleData = nil
+		if err := store.Templates.Update(ctx, found); err != nil {
+			t.Fatalf("Failed to update template: %v", err)
+		}
+		if updated, err := store.Templates.FindByID(ctx, "items"); err != nil || updated.Name != "Renamed" || len(updated.SampleData) != 0 {
+			t.Errorf("Unexpected template after update: %+v (%v)", updated, err)
+		}
+		custom, err := store.Templates.FindByCategory(ctx, schematemplate.CategoryCustom)
+		if err != nil || len(custom) != 1 {
+			t.Errorf("Expected 1 custom template, got %d (%v)", len(custom), err)
+		}
+
+		if err := store.Templates.Delete(ctx, "items"); err != nil {
+			t.Fatalf("Failed to delete template: %v", err)
+		}
+		if _, err := store.Templates.FindByID(ctx, "items"); !errors.Is(err, schematemplate.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+		}
+		missing := schematemplate.NewSchemaTemplate("missing", "Missing", "", schematemplate.CategoryCustom, "", schema)
+		if err := store.Templates.Update(ctx, missing); !errors.Is(err, schematemplate.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound updating a missing template, got: %v", err)
+		}
+
+		// Import writes all templates or none of them
+		fresh := schematemplate.NewSchemaTemplate("fresh", "Fresh", "", schematemplate.CategoryCustom, "", schema)
+		if err := store.Templates.Import(ctx, []*schematemplate.SchemaTemplate{fresh}, []*schematemplate.SchemaTemplate{missing}); !errors.Is(err, schematemplate.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound importing over a missing template, got: %v", err)
+		}
+		if _, err := store.Templates.FindByID(ctx, "fresh"); !errors.Is(err, schematemplate.ErrNotFound) {
+			t.Errorf("Expected a failed import to write nothing, got: %v", err)
+		}
+		if err := store.Templates.Import(ctx, []*schematemplate.SchemaTemplate{fresh}, nil); err != nil {
+			t.Errorf("Failed to import template: %v", err)
+		}
+	})
+}
+
+func TestRecordRepositoryContract(t *testing.T) {
+	storagetest.ForEachBackend(t, func(t *testing.T, store *storage.Store) {
+		ctx := context.Background()
+		schema := json.RawMessage(`{"type": "object", "properties": {"name": {"type": "string"}}}`)
+		if err := store.Tables.Create(ctx, table.NewTable("items", "Items", "", schema)); err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+
+		first := record.NewRecord("items", map[string]interface{}{"name": "sword", "price": 100})
+		if err := store.Records.Create(ctx, first); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		if first.ID == 0 {
+			t.Fatal("Expected record ID to be set")
+		}
+		if err := store.Records.Create(ctx, record.NewRecord("missing", map[string]interface{}{})); !errors.Is(err, record.ErrTableNotFound) {
+			t.Errorf("Expected ErrTableNotFound, got: %v", err)
+		}
+
+		found, err := store.Records.FindByID(ctx, "items", first.ID)
+		if err != nil {
+			t.Fatalf("Failed to find record: %v", err)
+		}
+		before, _ := store.Tables.FindByID(ctx, "items")
+		time.Sleep(10 * time.Millisecond)
+		found.Merge(map[string]interface{}{"price": 150})
+		if err := store.Records.Update(ctx, found); err != nil {
+			t.Fatalf("Failed to update record: %v", err)
+		}
+		if after, _ := store.Tables.FindByID(ctx, "items"); !after.UpdatedAt.After(before.UpdatedAt) {
+			t.Errorf("Expected an update to touch the table, got %v then %v", before.UpdatedAt, after.UpdatedAt)
+		}
+		found, _ = store.Records.FindByID(ctx, "items", first.ID)
+		if found.Data["name"] != "sword" || found.Data["price"] != float64(150) {
+			t.Errorf("Expected merged data, got: %v", found.Data)
+		}
+
+		batch := []*record.Record{
+			record.NewRecord("items", map[string]interface{}{"name": "a"}),
+			record.NewRecord("items", map[string]interface{}{"name": "b"}),
+		}
+		if err := store.Records.Append(ctx, "items", batch); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+		page, err := store.Records.FindByTable(ctx, "items", record.Page{Limit: 2})
+		if err != nil || len(page) != 2 || page[0].Data["name"] != "b" || page[1].Data["name"] != "a" {
+			t.Errorf("Expected newest two records first, got %v (%v)", page, err)
+		}
+		if tbl, _ := store.Tables.FindByID(ctx, "items"); tbl.RecordCount != 3 {
+			t.Errorf("Expected record count 3, got %d", tbl.RecordCount)
+		}
+
+		replacement := []*record.Record{record.NewRecord("items", map[string]interface{}{"name": "z"})}
+		if err := store.Records.Replace(ctx, "items", replacement); err != nil {
+			t.Fatalf("Failed to replace: %v", err)
+		}
+		for name, write := range map[string]func() error{
+			"append":  func() error { return store.Records.Append(ctx, "missing", nil) },
+			"replace": func() error { return store.Records.Replace(ctx, "missing", nil) },
+		} {
+			if err := write(); !errors.Is(err, table.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound from an empty %s to a missing table, got: %v", name, err)
+			}
+		}
+		all, _ := store.Records.FindByTable(ctx, "items", record.Page{})
+		if len(all) != 1 || all[0].Data["name"] != "z" {
+			t.Errorf("Expected only the replacement, got: %v", all)
+		}
+
+		// Apply writes all changes or none of them
+		kept := record.NewRecord("items", map[string]interface{}{"name": "kept"})
+		if err := store.Records.Create(ctx, kept); err != nil {
+			t.Fatalf("Failed to create record: %v", err)
+		}
+		ghost := &record.Record{ID: kept.ID + 1000, Data: map[string]interface{}{"name": "ghost"}}
+		failed := record.Changes{
+			Create: []*record.Record{record.NewRecord("items", map[string]interface{}{"name": "new"})},
+			Delete: []int64{kept.ID},
+			Update: []*record.Record{ghost},
+		}
+		if err := store.Records.Apply(ctx, "items", failed); !errors.Is(err, record.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound applying to a missing record, got: %v", err)
+		}
+		if recs, _ := store.Records.FindByTable(ctx, "items", record.Page{}); len(recs) != 2 {
+			t.Errorf("Expected a failed apply to change nothing, got: %v", recs)
+		}
+		kept.Merge(map[string]interface{}{"name": "changed"})
+		changes := record.Changes{
+			Create: []*record.Record{record.NewRecord("items", map[string]interface{}{"name": "new"})},
+			Update: []*record.Record{kept},
+		}
+		if err := store.Records.Apply(ctx, "items", changes); err != nil {
+			t.Fatalf("Failed to apply changes: %v", err)
+		}
+		if err := store.Records.Apply(ctx, "items", record.Changes{Delete: []int64{kept.ID, changes.Create[0].ID}}); err != nil {
+			t.Fatalf("Failed to apply deletes: %v", err)
+		}
+		if tbl, _ := store.Tables.FindByID(ctx, "items"); tbl.RecordCount != 1 {
+			t.Errorf("Expected record count 1 after apply, got %d", tbl.RecordCount)
+		}
+
+		if err := store.Records.Delete(ctx, "items", all[0].ID); err != nil {
+			t.Fatalf("Failed to delete record: %v", err)
+		}
+		if err := store.Records.Delete(ctx, "items", all[0].ID); !errors.Is(err, record.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound deleting twice, got: %v", err)
+		}
+		if tbl, _ := store.Tables.FindByID(ctx, "items"); tbl.RecordCount != 0 {
+			t.Errorf("Expected record count 0, got %d", tbl.RecordCount)
+		}
+
+		seeded := table.NewTable("seeded", "Seeded", "", schema)
+		rows := []*record.Record{
+			record.NewRecord("", map[string]interface{}{"name": "a"}),
+			record.NewRecord("", map[string]interface{}{"name": "b"}),
+		}
+		if err := store.Records.CreateWithTable(ctx, seeded, rows); err != nil {
+			t.Fatalf("Failed to create table with records: %v", err)
+		}
+		if tbl, err := store.Tables.FindByID(ctx, "seeded"); err != nil || tbl.RecordCount != 2 {
+			t.Errorf("Expected seeded table with 2 records, got %+v (%v)", tbl, err)
+		}
+
+		broken := []*record.Record{record.NewRecord("", map[string]interface{}{"name": "a"}), {CreatedAt: time.Now()}}
+		if err := store.Records.CreateWithTable(ctx, table.NewTable("broken", "Broken", "", schema), broken); !errors.Is(err, record.ErrInvalidInput) {
+			t.Errorf("Expected ErrInvalidInput, got: %v", err)
+		}
+		if _, err := store.Tables.FindByID(ctx, "broken"); !errors.Is(err, table.ErrNotFound) {
+			t.Errorf("Expected no table after a failed create, got: %v", err)
+		}
+	})
+}
+
+func TestReadinessChecks(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.Open(t, storage.SQLite)
+	if err := store.Ping(ctx); err != nil {
+		t.Errorf("Expected the database to answer, got: %v", err)
+	}
+	if err := store.CheckMigrations(ctx); err != nil {
+		t.Errorf("Expected no pending migrations, got: %v", err)
+	}
+
+	store.DB.MustExec(`DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)`)
+	if err := store.CheckMigrations(ctx); err == nil {
+		t.Error("Expected a pending migration to be reported")
+	}
+}
@@ -0,0 +1,70 @@
+// Package storagetest runs tests against every storage backend.
+package storagetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"progressive/internal/infrastructure"
+	"progressive/internal/storage"
+)
+
+// PostgresEnv enables the embedded PostgreSQL backend in tests. It is off by
+// default because starting PostgreSQL downloads binaries and takes seconds.
+const PostgresEnv = "PROGRESSIVE_TEST_POSTGRES"
+
+// ForEachBackend runs fn as a subtest against a fresh store for each backend
+func ForEachBackend(t *testing.T, fn func(t *testing.T, store *storage.Store)) {
+	t.Helper()
+	for _, backend := range []string{storage.Memory, storage.SQLite, storage.Postgres} {
+		t.Run(backend, func(t *testing.T) {
+			store := Open(t, backend)
+			fn(t, store)
+		})
+	}
+}
+
+// Open opens a fresh store for backend and closes it when the test ends
+func Open(t *testing.T, backend string) *storage.Store {
+	t.Helper()
+	var store *storage.Store
+	switch backend {
+	case storage.Memory:
+		store = storage.NewMemory()
+	case storage.SQLite:
+		var err error
+		store, err = storage.Open(storage.Options{
+			Backend:    storage.SQLite,
+			SQLitePath: filepath.Join(t.TempDir(), "progressive.db"),
+		})
+		if err != nil {
+			t.Fatalf("Failed to open sqlite store: %v", err)
+		}
+	case storage.Postgres:
+		if os.Getenv(PostgresEnv) == "" {
+			t.Skipf("set %s=1 to run against embedded PostgreSQL", PostgresEnv)
+		}
+		var err error
+		store, err = storage.Open(storage.Options{
+			Backend: storage.Postgres,
+			Postgres: []infrastructure.Option{
+				infrastructure.WithConfig(infrastructure.Config{
+					Host:     "localhost",
+					Port:     15432,
+					Username: "postgres",
+					Password: "postgres",
+					Database: "progressive_test",
+				}),
+				infrastructure.WithAutoPortDiscovery(20),
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to open postgres store: %v", err)
+		}
+	default:
+		t.Fatalf("Unknown backend %q", backend)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
@@ -0,0 +1,323 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"progressive/internal/domain/schematemplate/repository"
+)
+
+// FieldError describes a single constraint violation on a record field
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Error codes reported in FieldError.Code
+const (
+	CodeRequired  = "required"
+	CodeType      = "type"
+	CodeEnum      = "enum"
+	CodeMinLength = "min_length"
+	CodeMaxLength = "max_length"
+	CodePattern   = "pattern"
+	CodeFormat    = "format"
+	CodeMinimum   = "minimum"
+	CodeMaximum   = "maximum"
+	CodeUnknown   = "unknown_field"
+)
+
+// Validator checks records against a table's JSON Schema
+type Validator struct {
+	schema   *repository.SchemaDefinition
+	patterns map[string]*regexp.Regexp
+	// AllowUnknown keeps fields that are not declared in the schema from being reported
+	AllowUnknown bool
+}
+
+// New compiles a Validator for the given schema definition
+func New(schema *repository.SchemaDefinition) (*Validator, error) {
+	patterns := make(map[string]*regexp.Regexp)
+	for name, prop := range schema.Properties {
+		if prop.Pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(prop.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for field %s: %w", name, err)
+		}
+		patterns[name] = re
+	}
+
+	return &Validator{
+		schema:       schema,
+		patterns:     patterns,
+		AllowUnknown: true,
+	}, nil
+}
+
+// NewFromJSON parses a stored JSON Schema and compiles a Validator for it
+func NewFromJSON(raw json.RawMessage) (*Validator, error) {
+	schema, err := repository.ParseSchemaDefinition(raw)
+	if err != nil {
+		return nil, err
+	}
+	return New(schema)
+}
+
+// Schema returns the schema definition the validator was built from
+func (v *Validator) Schema() *repository.SchemaDefinition {
+	return v.schema
+}
+
+// Validate checks a full record and returns every violation found
+func (v *Validator) Validate(record map[string]interface{}) []FieldError {
+	var errs []FieldError
+
+	for _, name := range v.schema.Required {
+		if value, ok := record[name]; !ok || value == nil {
+			errs = append(errs, FieldError{Field: name, Code: CodeRequired, Message: "field is required"})
+		}
+	}
+
+	for _, name := range v.schema.PropertyNames() {
+		value, ok := record[name]
+		if !ok || value == nil {
+			continue
+		}
+		errs = append(errs, v.ValidateField(name, value)...)
+	}
+
+	if !v.AllowUnknown {
+		for name := range record {
+			if _, declared := v.schema.Properties[name]; !declared {
+				errs = append(errs, FieldError{Field: name, Code: CodeUnknown, Message: "field is not defined in the schema"})
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidatePartial checks only the fields present in a patch; required fields are not enforced
+func (v *Validator) ValidatePartial(patch map[string]interface{}) []FieldError {
+	var errs []FieldError
+	for name, value := range patch {
+		if value == nil {
+			if v.schema.IsRequired(name) {
+				errs = append(errs, FieldError{Field: name, Code: CodeRequired, Message: "field is required"})
+			}
+			continue
+		}
+		if _, declared := v.schema.Properties[name]; !declared {
+			if !v.AllowUnknown {
+				errs = append(errs, FieldError{Field: name, Code: CodeUnknown, Message: "field is not defined in the schema"})
+			}
+			continue
+		}
+		errs = append(errs, v.ValidateField(name, value)...)
+	}
+	return errs
+}
+
+// ValidateField checks a single non-nil value against its property definition
+func (v *Validator) ValidateField(name string, value interface{}) []FieldError {
+	prop, ok := v.schema.Properties[name]
+	if !ok {
+		return nil
+	}
+
+	fail := func(code, format string, args ...interface{}) []FieldError {
+		return []FieldError{{Field: name, Code: code, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	var errs []FieldError
+	switch prop.Type {
+	case "string", "":
+		s, ok := value.(string)
+		if !ok {
+			return fail(CodeType, "expected string, got %s", typeName(value))
+		}
+		errs = v.validateString(name, prop, s)
+	case "integer":
+		n, ok := toFloat(value)
+		if !ok || n != math.Trunc(n) {
+			return fail(CodeType, "expected integer, got %s", typeName(value))
+		}
+		errs = validateNumber(name, prop, n)
+	case "number":
+		n, ok := toFloat(value)
+		if !ok {
+			return fail(CodeType, "expected number, got %s", typeName(value))
+		}
+		errs = validateNumber(name, prop, n)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail(CodeType, "expected boolean, got %s", typeName(value))
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return fail(CodeType, "expected array, got %s", typeName(value))
+		}
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			return fail(CodeType, "expected object, got %s", typeName(value))
+		}
+	}
+
+	// Enum values may be of any JSON type, so they are checked once the type is
+	if len(prop.Enum) > 0 && !prop.EnumIncludes(value) {
+		errs = append(fail(CodeEnum, "value %s is not one of %s", jsonText(value), jsonText(prop.Enum)), errs...)
+	}
+	return errs
+}
+
+// Coerce converts string values, as read from CSV, to the types their
+// properties declare. Values that do not parse are kept for Validate to report.
+func (v *Validator) Coerce(record map[string]interface{}) {
+	for name, value := range record {
+		s, ok := value.(string)
+		prop, declared := v.schema.Properties[name]
+		if !ok || !declared {
+			continue
+		}
+		s = strings.TrimSpace(s)
+		switch prop.Type {
+		case "integer", "number":
+			if n, err := strconv.ParseFloat(s, 64); err == nil {
+				record[name] = n
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(s); err == nil {
+				record[name] = b
+			}
+		case "array", "object":
+			var parsed interface{}
+			if json.Unmarshal([]byte(s), &parsed) == nil {
+				record[name] = parsed
+			}
+		}
+	}
+}
+
+func (v *Validator) validateString(name string, prop repository.PropertyDef, s string) []FieldError {
+	var errs []FieldError
+	add := func(code, format string, args ...interface{}) {
+		errs = append(errs, FieldError{Field: name, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := utf8.RuneCountInString(s)
+	if prop.MinLength > 0 && length < prop.MinLength {
+		add(CodeMinLength, "must be at least %d characters", prop.MinLength)
+	}
+	if prop.MaxLength > 0 && length > prop.MaxLength {
+		add(CodeMaxLength, "must be at most %d characters", prop.MaxLength)
+	}
+
+	if re, ok := v.patterns[name]; ok && !re.MatchString(s) {
+		add(CodePattern, "value %q does not match pattern %s", s, prop.Pattern)
+	}
+
+	if prop.Format != "" && !CheckFormat(prop.Format, s) {
+		add(CodeFormat, "value %q is not a valid %s", s, prop.Format)
+	}
+
+	return errs
+}
+
+func validateNumber(name string, prop repository.PropertyDef, n float64) []FieldError {
+	var errs []FieldError
+	if prop.Minimum != nil && n < *prop.Minimum {
+		errs = append(errs, FieldError{Field: name, Code: CodeMinimum, Message: fmt.Sprintf("must be >= %v", *prop.Minimum)})
+	}
+	if prop.Maximum != nil && n > *prop.Maximum {
+		errs = append(errs, FieldError{Field: name, Code: CodeMaximum, Message: fmt.Sprintf("must be <= %v", *prop.Maximum)})
+	}
+	return errs
+}
+
+// CheckFormat reports whether s satisfies a JSON Schema "format". Unknown formats always pass.
+func CheckFormat(format, s string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "time":
+		if _, err := time.Parse("15:04:05", s); err == nil {
+			return true
+		}
+		_, err := time.Parse("15:04", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri", "url":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	default:
+		return true
+	}
+}
+
+// toFloat converts JSON-decoded and Go numeric values to float64
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// jsonText formats a value as JSON for error messages
+func jsonText(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(raw)
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
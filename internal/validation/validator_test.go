@@ -0,0 +1,232 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const itemSchema = `{"type": "object", "required": ["name", "price"], "properties": {
+	"name": {"type": "string", "minLength": 2, "maxLength": 5},
+	"code": {"type": "string", "pattern": "^[A-Z]{2}[0-9]$"},
+	"rarity": {"type": "string", "enum": ["common", "rare"]},
+	"price": {"type": "integer", "minimum": 1, "maximum": 100},
+	"weight": {"type": "number", "minimum": 0.5},
+	"active": {"type": "boolean"},
+	"tags": {"type": "array"},
+	"stats": {"type": "object"},
+	"released": {"type": "string", "format": "date"},
+	"updated": {"type": "string", "format": "date-time"},
+	"contact": {"type": "string", "format": "email"}
+}}`
+
+func newValidator(t *testing.T) *Validator {
+	t.Helper()
+	v, err := NewFromJSON(json.RawMessage(itemSchema))
+	if err != nil {
+		t.Fatalf("Failed to compile validator: %v", err)
+	}
+	return v
+}
+
+// codes flattens errors to sorted "field:code" pairs
+func codes(errs []FieldError) []string {
+	out := []string{}
+	for _, e := range errs {
+		out = append(out, e.Field+":"+e.Code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	v := newValidator(t)
+	tests := []struct {
+		name   string
+		record map[string]interface{}
+		want   []string
+	}{
+		{"valid", map[string]interface{}{"name": "sword", "price": float64(10)}, []string{}},
+		{"every field valid", map[string]interface{}{
+			"name": "bow", "price": 100, "code": "AB1", "rarity": "rare", "weight": 0.5, "active": true,
+			"tags": []interface{}{"x"}, "stats": map[string]interface{}{}, "released": "2024-02-29",
+			"updated": "2024-02-29T10:00:00+09:00", "contact": "a@example.com",
+		}, []string{}},
+		{"missing required", map[string]interface{}{"name": "sword"}, []string{"price:required"}},
+		{"null required", map[string]interface{}{"name": nil, "price": 1}, []string{"name:required"}},
+		{"string type", map[string]interface{}{"name": 5, "price": 1}, []string{"name:type"}},
+		{"integer type", map[string]interface{}{"name": "ab", "price": "5"}, []string{"price:type"}},
+		{"fractional integer", map[string]interface{}{"name": "ab", "price": 1.5}, []string{"price:type"}},
+		{"number type", map[string]interface{}{"name": "ab", "price": 1, "weight": "heavy"}, []string{"weight:type"}},
+		{"boolean type", map[string]interface{}{"name": "ab", "price": 1, "active": "yes"}, []string{"active:type"}},
+		{"array type", map[string]interface{}{"name": "ab", "price": 1, "tags": "x"}, []string{"tags:type"}},
+		{"object type", map[string]interface{}{"name": "ab", "price": 1, "stats": []interface{}{}}, []string{"stats:type"}},
+		{"enum", map[string]interface{}{"name": "ab", "price": 1, "rarity": "epic"}, []string{"rarity:enum"}},
+		{"minimum", map[string]interface{}{"name": "ab", "price": 0, "weight": 0.25}, []string{"price:minimum", "weight:minimum"}},
+		{"maximum", map[string]interface{}{"name": "ab", "price": json.Number("101")}, []string{"price:maximum"}},
+		{"min length", map[string]interface{}{"name": "a", "price": 1}, []string{"name:min_length"}},
+		{"max length counts runes", map[string]interface{}{"name": "검검검검검", "price": 1}, []string{}},
+		{"max length", map[string]interface{}{"name": "sword!", "price": 1}, []string{"name:max_length"}},
+		{"pattern", map[string]interface{}{"name": "ab", "price": 1, "code": "ab1"}, []string{"code:pattern"}},
+		{"date", map[string]interface{}{"name": "ab", "price": 1, "released": "2023-02-29"}, []string{"released:format"}},
+		{"date-time", map[string]interface{}{"name": "ab", "price": 1, "updated": "2024-02-29 10:00"}, []string{"updated:format"}},
+		{"email", map[string]interface{}{"name": "ab", "price": 1, "contact": "Ann <a@example.com>"}, []string{"contact:format"}},
+		{"unknown fields allowed", map[string]interface{}{"name": "ab", "price": 1, "extra": 1}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(v.Validate(tt.record)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsUnknownFields(t *testing.T) {
+	v := newValidator(t)
+	v.AllowUnknown = false
+
+	want := []string{"extra:unknown_field"}
+	if got := codes(v.Validate(map[string]interface{}{"name": "ab", "price": 1, "extra": 1})); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v from Validate, got %v", want, got)
+	}
+	if got := codes(v.ValidatePartial(map[string]interface{}{"extra": 1})); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v from ValidatePartial, got %v", want, got)
+	}
+}
+
+func TestValidatePartial(t *testing.T) {
+	v := newValidator(t)
+	tests := []struct {
+		name  string
+		patch map[string]interface{}
+		want  []string
+	}{
+		{"required fields may be omitted", map[string]interface{}{"rarity": "rare"}, []string{}},
+		{"required fields cannot be cleared", map[string]interface{}{"price": nil}, []string{"price:required"}},
+		{"optional fields can be cleared", map[string]interface{}{"rarity": nil}, []string{}},
+		{"present fields are checked", map[string]interface{}{"price": 500, "code": "x"}, []string{"code:pattern", "price:maximum"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(v.ValidatePartial(tt.patch)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	v := newValidator(t)
+	if errs := v.ValidateField("price", 5); len(errs) != 0 {
+		t.Errorf("Expected a valid price, got %v", errs)
+	}
+	if errs := v.ValidateField("undeclared", 5); len(errs) != 0 {
+		t.Errorf("Expected undeclared fields to be ignored, got %v", errs)
+	}
+
+	errs := v.ValidateField("name", "abcdefg")
+	if len(errs) != 1 || errs[0].Field != "name" || errs[0].Code != CodeMaxLength || errs[0].Message == "" {
+		t.Fatalf("Expected one max_length error, got %v", errs)
+	}
+	if errs[0].Error() != "name: "+errs[0].Message {
+		t.Errorf("Unexpected error string %q", errs[0].Error())
+	}
+}
+
+func TestValidateNonStringEnums(t *testing.T) {
+	v, err := NewFromJSON(json.RawMessage(`{"type": "object", "properties": {
+		"level": {"type": "integer", "enum": [1, 2, 3]},
+		"ratio": {"type": "number", "enum": [0.5, 1.5]},
+		"flag": {"type": "boolean", "enum": [true]}
+	}}`))
+	if err != nil {
+		t.Fatalf("Expected numeric enums to parse, got: %v", err)
+	}
+
+	valid := map[string]interface{}{"level": 2, "ratio": float64(1.5), "flag": true}
+	if got := codes(v.Validate(valid)); len(got) != 0 {
+		t.Errorf("Expected a valid record, got %v", got)
+	}
+	invalid := map[string]interface{}{"level": json.Number("4"), "ratio": 1, "flag": false}
+	want := []string{"flag:enum", "level:enum", "ratio:enum"}
+	if got := codes(v.Validate(invalid)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if errs := v.ValidateField("level", "2"); len(errs) != 1 || errs[0].Code != CodeType {
+		t.Errorf("Expected only a type error for a string level, got %v", errs)
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	v := newValidator(t)
+	record := map[string]interface{}{
+		"name":   "42",
+		"price":  " 7 ",
+		"weight": "1.5",
+		"active": "true",
+		"tags":   `["a", "b"]`,
+		"stats":  `{"hp": 3}`,
+		"code":   "AB1",
+		"extra":  "9",
+		"rarity": nil,
+	}
+	v.Coerce(record)
+
+	want := map[string]interface{}{
+		"name":   "42",
+		"price":  float64(7),
+		"weight": 1.5,
+		"active": true,
+		"tags":   []interface{}{"a", "b"},
+		"stats":  map[string]interface{}{"hp": float64(3)},
+		"code":   "AB1",
+		"extra":  "9",
+		"rarity": nil,
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("Expected %v, got %v", want, record)
+	}
+	if errs := v.Validate(record); len(errs) != 0 {
+		t.Errorf("Expected the coerced record to be valid, got %v", errs)
+	}
+
+	bad := map[string]interface{}{"name": "ab", "price": "seven", "active": "maybe"}
+	v.Coerce(bad)
+	if got, want := codes(v.Validate(bad)), []string{"active:type", "price:type"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected unparsable values to be kept and reported as %v, got %v", want, got)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		format, value string
+		want          bool
+	}{
+		{"date", "2024-01-31", true},
+		{"date", "2024-1-31", false},
+		{"date", "2024-02-30", false},
+		{"date-time", "2024-01-31T23:59:59Z", true},
+		{"date-time", "2024-01-31T23:59:59", false},
+		{"time", "23:59:59", true},
+		{"time", "23:59", true},
+		{"time", "24:00", false},
+		{"email", "a.b@example.com", true},
+		{"email", "example.com", false},
+		{"uri", "https://example.com/x", true},
+		{"uri", "/relative", false},
+		{"color", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := CheckFormat(tt.format, tt.value); got != tt.want {
+			t.Errorf("CheckFormat(%q, %q) = %v, expected %v", tt.format, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidPattern(t *testing.T) {
+	if _, err := NewFromJSON(json.RawMessage(`{"properties": {"code": {"type": "string", "pattern": "["}}}`)); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
@@ -0,0 +1,180 @@
+// API explorer: renders an OpenAPI document and sends requests from it
+
+const METHOD_COLORS = {
+    get: 'bg-blue-100 text-blue-800',
+    post: 'bg-green-100 text-green-800',
+    put: 'bg-yellow-100 text-yellow-800',
+    patch: 'bg-yellow-100 text-yellow-800',
+    delete: 'bg-red-100 text-red-800',
+};
+
+document.addEventListener('DOMContentLoaded', async () => {
+    const container = document.getElementById('api-explorer');
+    try {
+        const response = await fetch(container.dataset.specUrl);
+        if (!response.ok) {
+            await throwProblem(response);
+        }
+        renderSpec(container, await response.json());
+    } catch (error) {
+        console.error('Error loading OpenAPI document:', error);
+        container.innerHTML = '';
+        container.appendChild(el('p', 'text-sm text-red-600', `문서를 불러오지 못했습니다: ${error.message}`));
+    }
+});
+
+function el(tag, className, text) {
+    const node = document.createElement(tag);
+    if (className) node.className = className;
+    if (text !== undefined) node.textContent = text;
+    return node;
+}
+
+// resolve follows a "#/components/schemas/X" reference
+function resolve(spec, schema) {
+    if (schema && schema.$ref && schema.$ref.startsWith('#/')) {
+        const target = schema.$ref.slice(2).split('/').reduce((node, key) => node && node[key], spec);
+        return resolve(spec, target || {});
+    }
+    return schema || {};
+}
+
+// exampleOf builds a sample value of a schema for the request body
+function exampleOf(spec, schema, depth = 0) {
+    schema = resolve(spec, schema);
+    if (depth > 5) return null;
+    if (schema.example !== undefined) return schema.example;
+    if (schema.default !== undefined) return schema.default;
+    if (schema.enum) return schema.enum[0];
+    if (schema.allOf) {
+        return Object.assign({}, ...schema.allOf.map(s => exampleOf(spec, s, depth + 1)));
+    }
+    const type = Array.isArray(schema.type) ? schema.type[0] : schema.type;
+    switch (type) {
+        case 'object': {
+            const value = {};
+            for (const [name, property] of Object.entries(schema.properties || {})) {
+                if (resolve(spec, property).readOnly) continue;
+                value[name] = exampleOf(spec, property, depth + 1);
+            }
+            return value;
+        }
+        case 'array':
+            return [exampleOf(spec, schema.items, depth + 1)];
+        case 'integer':
+        case 'number':
+            return 0;
+        case 'boolean':
+            return false;
+        case 'string':
+            return schema.format === 'date-time' ? new Date().toISOString() : '';
+        default:
+            return schema.properties ? exampleOf(spec, { ...schema, type: 'object' }, depth) : null;
+    }
+}
+
+function renderSpec(container, spec) {
+    document.getElementById('api-explorer-info').textContent =
+        `${spec.info.title} ${spec.info.version} · OpenAPI ${spec.openapi}`;
+    const server = (spec.servers && spec.servers[0] && spec.servers[0].url) || '';
+
+    // Operations grouped by their first tag, in the order of spec.tags
+    const groups = new Map((spec.tags || []).map(tag => [tag.name, []]));
+    for (const [path, item] of Object.entries(spec.paths || {})) {
+        for (const [method, operation] of Object.entries(item)) {
+            const tag = (operation.tags && operation.tags[0]) || 'default';
+            if (!groups.has(tag)) groups.set(tag, []);
+            groups.get(tag).push({ path, method, operation });
+        }
+    }
+
+    container.innerHTML = '';
+    for (const [tag, operations] of groups) {
+        if (operations.length === 0) continue;
+        const section = el('section', 'bg-white shadow rounded-lg');
+        section.appendChild(el('h2', 'px-4 py-3 border-b border-gray-200 text-lg font-medium text-gray-900', tag));
+        for (const op of operations) {
+            section.appendChild(renderOperation(spec, server, op));
+        }
+        container.appendChild(section);
+    }
+}
+
+function renderOperation(spec, server, { path, method, operation }) {
+    const details = el('details', 'border-b border-gray-100 last:border-b-0');
+    const summary = el('summary', 'flex items-center px-4 py-2 cursor-pointer hover:bg-gray-50');
+    summary.appendChild(el('span', `w-16 mr-3 px-2 py-0.5 rounded text-xs font-semibold text-center uppercase ${METHOD_COLORS[method] || 'bg-gray-100 text-gray-800'}`, method));
+    summary.appendChild(el('code', `text-sm ${operation.deprecated ? 'line-through text-gray-400' : 'text-gray-900'}`, path));
+    summary.appendChild(el('span', 'ml-3 text-sm text-gray-500', operation.summary || ''));
+    details.appendChild(summary);
+
+    const body = el('div', 'px-4 py-3 space-y-3 bg-gray-50');
+    if (operation.description) {
+        body.appendChild(el('p', 'text-sm text-gray-600', operation.description));
+    }
+
+    const inputs = {};
+    for (const parameter of operation.parameters || []) {
+        const label = el('label', 'block text-sm');
+        label.appendChild(el('span', 'text-gray-700', `${parameter.name} (${parameter.in})${parameter.required ? ' *' : ''}`));
+        const input = el('input', 'mt-1 block w-full px-3 py-1.5 border border-gray-300 rounded-md text-sm');
+        input.placeholder = parameter.description || '';
+        label.appendChild(input);
+        body.appendChild(label);
+        inputs[parameter.name] = { parameter, input };
+    }
+
+    let textarea = null;
+    const content = operation.requestBody && operation.requestBody.content;
+    if (content && content['application/json']) {
+        textarea = el('textarea', 'block w-full h-40 px-3 py-2 border border-gray-300 rounded-md font-mono text-xs');
+        textarea.value = JSON.stringify(exampleOf(spec, content['application/json'].schema), null, 2);
+        body.appendChild(textarea);
+    }
+
+    const button = el('button', 'px-4 py-1.5 rounded-md text-sm font-medium text-white bg-blue-600 hover:bg-blue-700', '요청 보내기');
+    button.type = 'button';
+    const output = el('pre', 'hidden p-3 rounded-md bg-gray-900 text-gray-100 text-xs overflow-auto max-h-96');
+    button.addEventListener('click', () => sendRequest(server, path, method, inputs, textarea, output));
+    body.appendChild(button);
+    body.appendChild(output);
+
+    details.appendChild(body);
+    return details;
+}
+
+async function sendRequest(server, path, method, inputs, textarea, output) {
+    let url = server + path;
+    const query = new URLSearchParams();
+    for (const { parameter, input } of Object.values(inputs)) {
+        if (input.value === '') continue;
+        if (parameter.in === 'path') {
+            url = url.replace(`{${parameter.name}}`, encodeURIComponent(input.value));
+        } else if (parameter.in === 'query') {
+            query.set(parameter.name, input.value);
+        }
+    }
+    if ([...query].length > 0) url += `?${query}`;
+
+    const options = { method: method.toUpperCase(), headers: {} };
+    if (textarea) {
+        options.headers['Content-Type'] = 'application/json';
+        options.body = textarea.value;
+    }
+
+    output.classList.remove('hidden');
+    output.textContent = '요청 중...';
+    try {
+        const response = await fetch(url, options);
+        const text = await response.text();
+        let formatted = text;
+        try {
+            formatted = JSON.stringify(JSON.parse(text), null, 2);
+        } catch (_) {
+            // Not JSON, shown as it is
+        }
+        output.textContent = `${response.status} ${response.statusText}\n\n${formatted}`;
+    } catch (error) {
+        output.textContent = `요청 실패: ${error.message}`;
+    }
+}
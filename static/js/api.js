@@ -0,0 +1,38 @@
+// API error helpers
+//
+// Every API answers errors with an RFC 7807 problem document:
+//   { "type", "title", "status", "detail", "code", "errors": [{ "field", "message" }], "request_id" }
+
+// Reads the problem document of a failed response, or builds one from plain text
+async function readProblem(response) {
+    const contentType = response.headers.get('Content-Type') || '';
+    if (contentType.includes('json')) {
+        try {
+            return await response.json();
+        } catch (error) {
+            // fall through to the status line
+        }
+    }
+    const text = contentType.includes('json') ? '' : await response.text();
+    return { status: response.status, detail: text.trim() || response.statusText, code: 'unknown' };
+}
+
+// Formats a problem for the user: the detail, the field errors and the request ID
+function problemMessage(problem) {
+    let message = problem.detail || problem.title || `HTTP ${problem.status}`;
+    if (problem.errors && problem.errors.length > 0) {
+        message += '\n' + problem.errors.map(e => `- ${e.field}: ${e.message}`).join('\n');
+    }
+    if (problem.request_id) {
+        message += `\n(요청 ID: ${problem.request_id})`;
+    }
+    return message;
+}
+
+// Throws an Error describing a failed response
+async function throwProblem(response) {
+    const problem = await readProblem(response);
+    const error = new Error(problemMessage(problem));
+    error.problem = problem;
+    throw error;
+}
@@ -0,0 +1,26 @@
+// Audit log viewer
+
+async function verifyAuditChain() {
+    const result = document.getElementById('audit-verify-result');
+    result.textContent = '검증 중...';
+    result.className = 'text-sm text-gray-500';
+
+    try {
+        const response = await fetch('/api/v1/audit/verify');
+        if (!response.ok) {
+            await throwProblem(response);
+        }
+        const data = await response.json();
+        if (data.valid) {
+            result.textContent = `✅ ${data.checked}건 검증됨`;
+            result.className = 'text-sm text-green-700';
+        } else {
+            result.textContent = `❌ #${data.broken_at}: ${data.reason}`;
+            result.className = 'text-sm text-red-600';
+        }
+    } catch (error) {
+        console.error('Error verifying audit log:', error);
+        result.textContent = '검증 실패';
+        result.className = 'text-sm text-red-600';
+    }
+}
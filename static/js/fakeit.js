@@ -491,6 +491,7 @@
 
     async function generateData() {
         const count = parseInt(document.getElementById('data-count-input').value) || 10;
+        const seed = parseInt(document.getElementById('seed-input').value) || undefined;
         
         if (count < 1 || count > 10000) {
             alert('데이터 개수는 1개에서 10,000개 사이여야 합니다.');
@@ -520,7 +521,7 @@
             });
 
             // Generate data via API
-            const response = await fetch('/api/fakeit/generate', {
+            const response = await fetch('/api/v1/fakeit/generate', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
@@ -528,17 +529,22 @@
                 body: JSON.stringify({
                     schema: fakeitData.schema,
                     fieldConfigs: fieldConfigs,
-                    count: count
+                    count: count,
+                    seed: seed
                 })
             });
 
             if (!response.ok) {
-                const error = await response.text();
-                throw new Error(error || 'Failed to generate data');
+                await throwProblem(response);
             }
 
             const generatedData = await response.json();
             fakeitData.generatedData = generatedData.data;
+
+            // Show the seed so the same dataset can be generated again
+            const usedSeed = document.getElementById('used-seed');
+            usedSeed.textContent = `사용한 시드: ${generatedData.seed} (같은 시드·스키마·설정이면 같은 데이터가 생성됩니다)`;
+            usedSeed.classList.remove('hidden');
             
             displayGeneratedData(fakeitData.generatedData);
             
@@ -0,0 +1,55 @@
+// Merge request review page
+
+function mergeRequestId() {
+    return document.getElementById('merge-request').dataset.id;
+}
+
+function collectResolutions() {
+    const resolutions = [];
+    document.querySelectorAll('[data-conflict-ref]').forEach(group => {
+        const checked = group.querySelector('input[type="radio"]:checked');
+        if (checked) {
+            resolutions.push({
+                ref: group.dataset.conflictRef,
+                field: group.dataset.conflictField,
+                use: checked.value
+            });
+        }
+    });
+    return resolutions;
+}
+
+async function mergeRequest() {
+    const response = await fetch(`/api/v1/merge-requests/${mergeRequestId()}/merge`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+            merged_by: document.getElementById('merged-by').value,
+            resolutions: collectResolutions()
+        })
+    });
+
+    if (response.ok) {
+        window.location.reload();
+        return;
+    }
+
+    const problem = await readProblem(response);
+    if (problem.code === 'merge_conflict') {
+        alert(`해결되지 않은 충돌이 ${problem.conflicts.length}건 있습니다.`);
+        return;
+    }
+    alert(`병합 실패: ${problemMessage(problem)}`);
+}
+
+async function closeMergeRequest() {
+    if (!confirm('병합하지 않고 닫으시겠습니까?')) {
+        return;
+    }
+    const response = await fetch(`/api/v1/merge-requests/${mergeRequestId()}/close`, { method: 'POST' });
+    if (response.ok) {
+        window.location.reload();
+    } else {
+        alert(`닫기 실패: ${problemMessage(await readProblem(response))}`);
+    }
+}
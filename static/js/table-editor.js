@@ -10,7 +10,9 @@
         currentPage: 1,
         isLoading: false,
         hasMore: true,
-        editingCell: null
+        editingCell: null,
+        openThreads: {},
+        commentAnchor: null
     };
 
     // Initialize on page load
@@ -30,6 +32,13 @@
     window.closeImportModal = closeImportModal;
     window.processImport = processImport;
     window.toggleBulkEditMode = toggleBulkEditMode;
+    window.openCommentsModal = openCommentsModal;
+    window.openCommentsForEditingCell = openCommentsForEditingCell;
+    window.closeCommentsModal = closeCommentsModal;
+    window.loadCommentThreads = loadCommentThreads;
+    window.submitCommentThread = submitCommentThread;
+    window.replyToThread = replyToThread;
+    window.setThreadResolved = setThreadResolved;
 
     // Initialize table editor
     async function initializeTableEditor() {
@@ -45,6 +54,7 @@
         setupEventListeners();
         
         // Load initial data
+        await loadOpenThreads();
         await loadTableData(1, false);
         
         // Setup infinite scroll
@@ -70,7 +80,7 @@
         }
 
         // Modal close on backdrop click
-        ['add-record-modal', 'edit-cell-modal', 'export-modal', 'import-modal'].forEach(modalId => {
+        ['add-record-modal', 'edit-cell-modal', 'export-modal', 'import-modal', 'comments-modal'].forEach(modalId => {
             const modal = document.getElementById(modalId);
             if (modal) {
                 modal.addEventListener('click', function(e) {
@@ -172,7 +182,7 @@
         showLoadingState(!append);
 
         try {
-            const response = await fetch(`/api/table/${tableData.tableId}?page=${page}&limit=20`);
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/records?page=${page}&limit=20`);
             if (!response.ok) {
                 throw new Error('Failed to fetch table data');
             }
@@ -271,12 +281,13 @@
 
         const rowsHTML = records.map(record => {
             const cells = [
-                `<div class="px-6 py-4 text-sm text-gray-900 border-r border-gray-200">${record._id || ''}</div>`,
+                `<div class="relative px-6 py-4 text-sm text-gray-900 border-r border-gray-200" data-record-id="${record._id}" data-field="">${record._id || ''}</div>`,
                 ...properties.map(prop => {
                     const value = record[prop] || '';
                     const displayValue = formatCellValue(value, tableData.schema.properties[prop]);
                     return `
-                        <div class="px-6 py-4 text-sm text-gray-900 border-r border-gray-200 cursor-pointer hover:bg-gray-50"
+                        <div class="relative px-6 py-4 text-sm text-gray-900 border-r border-gray-200 cursor-pointer hover:bg-gray-50"
+                             data-record-id="${record._id}" data-field="${escapeHtml(prop)}"
                              onclick="openEditCellModal('${record._id}', '${prop}', '${escapeHtml(JSON.stringify(value))}')">
                             ${displayValue}
                         </div>
@@ -308,6 +319,8 @@
             bodyContainer.innerHTML = rowsHTML;
         }
 
+        applyCommentIndicators();
+
         // Add infinite scroll loader if there's more data
         if (tableData.hasMore) {
             bodyContainer.insertAdjacentHTML('beforeend', `
@@ -427,7 +440,7 @@
         }
 
         try {
-            const response = await fetch(`/api/table/${tableData.tableId}/record`, {
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/records`, {
                 method: 'POST',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify(record)
@@ -475,7 +488,7 @@
         const newValue = parseFormValue(input.value, prop.type);
 
         try {
-            const response = await fetch(`/api/table/${tableData.tableId}/record/${tableData.editingCell.recordId}`, {
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/records/${tableData.editingCell.recordId}`, {
                 method: 'PATCH',
                 headers: { 'Content-Type': 'application/json' },
                 body: JSON.stringify({ [tableData.editingCell.field]: newValue })
@@ -509,7 +522,7 @@
 
     async function exportData(format) {
         try {
-            const response = await fetch(`/api/table/${tableData.tableId}/export?format=${format}`);
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/export?format=${format}`);
             if (!response.ok) throw new Error('Export failed');
 
             const blob = await response.blob();
@@ -957,7 +970,7 @@
             submitBtn.disabled = true;
             submitBtn.textContent = '처리 중...';
 
-            const response = await fetch(`/api/table/${tableData.tableId}/import`, {
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/import`, {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
@@ -969,8 +982,7 @@
             });
 
             if (!response.ok) {
-                const error = await response.text();
-                throw new Error(error || 'Import failed');
+                await throwProblem(response);
             }
 
             const result = await response.json();
@@ -998,6 +1010,208 @@
         return parseFloat((bytes / Math.pow(k, i)).toFixed(2)) + ' ' + sizes[i];
     }
 
+    // Comment threads
+    async function loadOpenThreads() {
+        try {
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/comments/open`);
+            if (!response.ok) throw new Error('Failed to fetch open threads');
+
+            const counts = await response.json();
+            tableData.openThreads = {};
+            let total = 0;
+            counts.forEach(c => {
+                tableData.openThreads[`${c.record_id}:${c.field}`] = c.open;
+                total += c.open;
+            });
+
+            const countElement = document.getElementById('open-thread-count');
+            if (countElement) countElement.textContent = total;
+        } catch (error) {
+            console.error('Error loading open threads:', error);
+        }
+    }
+
+    function applyCommentIndicators() {
+        const template = document.getElementById('comment-indicator-template');
+        if (!template) return;
+
+        document.querySelectorAll('#grid-body [data-record-id]').forEach(cell => {
+            const existing = cell.querySelector('.comment-indicator');
+            if (existing) existing.remove();
+
+            const recordId = cell.dataset.recordId;
+            const field = cell.dataset.field;
+            const open = tableData.openThreads[`${recordId}:${field}`];
+            if (!open) return;
+
+            const indicator = template.content.firstElementChild.cloneNode(true);
+            indicator.title = `열린 댓글 ${open}개`;
+            indicator.addEventListener('click', e => {
+                e.stopPropagation();
+                openCommentsModal(Number(recordId), field);
+            });
+            cell.appendChild(indicator);
+        });
+    }
+
+    function openCommentsModal(recordId, field) {
+        tableData.commentAnchor = { recordId: recordId || 0, field: field || '' };
+
+        const title = document.getElementById('comments-modal-title');
+        if (title) {
+            const { recordId: id, field: f } = tableData.commentAnchor;
+            title.textContent = !id ? '테이블 댓글' : f ? `레코드 ${id} · ${f} 댓글` : `레코드 ${id} 댓글`;
+        }
+
+        const base = `/api/v1/tables/${tableData.tableId}/comments/export`;
+        document.getElementById('comments-export-md').href = `${base}?format=markdown`;
+        document.getElementById('comments-export-csv').href = `${base}?format=csv`;
+
+        const author = document.getElementById('comment-author');
+        if (author && !author.value) author.value = localStorage.getItem('progressive.author') || '';
+
+        document.getElementById('comments-modal').classList.remove('hidden');
+        loadCommentThreads();
+    }
+
+    function openCommentsForEditingCell() {
+        if (!tableData.editingCell) return;
+        const { recordId, field } = tableData.editingCell;
+        closeEditCellModal();
+        openCommentsModal(Number(recordId), field);
+    }
+
+    function closeCommentsModal() {
+        closeModal('comments-modal');
+        tableData.commentAnchor = null;
+    }
+
+    async function loadCommentThreads() {
+        const anchor = tableData.commentAnchor;
+        if (!anchor) return;
+
+        const params = new URLSearchParams();
+        params.set('status', document.getElementById('comments-show-resolved').checked ? 'all' : 'open');
+        if (anchor.recordId) params.set('record_id', anchor.recordId);
+        if (anchor.field) params.set('field', anchor.field);
+
+        const container = document.getElementById('comment-threads');
+        try {
+            const response = await fetch(`/api/v1/tables/${tableData.tableId}/comments?${params}`);
+            if (!response.ok) throw new Error('Failed to fetch threads');
+
+            let threads = await response.json();
+            // A record view shows record-level threads only; cell threads open from the cell
+            if (anchor.recordId && !anchor.field) {
+                threads = threads.filter(t => !t.anchor.field);
+            }
+            container.innerHTML = threads.length
+                ? threads.map(renderThread).join('')
+                : '<p class="text-sm text-gray-500">댓글이 없습니다.</p>';
+        } catch (error) {
+            console.error('Error loading comments:', error);
+            container.innerHTML = '<p class="text-sm text-red-600">댓글을 불러오지 못했습니다.</p>';
+        }
+    }
+
+    function renderThread(thread) {
+        const location = !thread.anchor.record_id ? '테이블'
+            : thread.anchor.field ? `레코드 ${thread.anchor.record_id} · ${escapeHtml(thread.anchor.field)}`
+            : `레코드 ${thread.anchor.record_id}`;
+        const comments = thread.comments.map(c => `
+            <div class="text-sm">
+                <span class="font-medium text-gray-900">${escapeHtml(c.author)}</span>
+                <span class="text-xs text-gray-400 ml-1">${new Date(c.created_at).toLocaleString('ko-KR')}${c.edited ? ' (수정됨)' : ''}</span>
+                <p class="text-gray-700 whitespace-pre-line">${highlightMentions(c.body)}</p>
+            </div>
+        `).join('');
+
+        return `
+            <div class="border rounded-lg p-4 ${thread.resolved ? 'border-gray-200 opacity-60' : 'border-amber-300'}">
+                <div class="flex items-center justify-between mb-2 text-xs text-gray-500">
+                    <span>#${thread.id} · ${location}</span>
+                    <button onclick="setThreadResolved(${thread.id}, ${!thread.resolved})" class="text-blue-600 hover:text-blue-800">
+                        ${thread.resolved ? '다시 열기' : '해결'}
+                    </button>
+                </div>
+                <div class="space-y-2">${comments}</div>
+                <div class="mt-3 flex space-x-2">
+                    <input type="text" id="reply-${thread.id}" placeholder="답글" class="flex-1 border border-gray-300 rounded-md px-2 py-1 text-sm"/>
+                    <button onclick="replyToThread(${thread.id})" class="px-3 py-1 text-sm rounded-md border border-gray-300 hover:bg-gray-50">답글</button>
+                </div>
+            </div>
+        `;
+    }
+
+    function highlightMentions(body) {
+        return escapeHtml(body).replace(/(^|[^\w@])@([\w][\w.-]*)/g, '$1<span class="text-blue-600">@$2</span>');
+    }
+
+    function commentAuthor() {
+        const author = document.getElementById('comment-author').value.trim();
+        if (author) localStorage.setItem('progressive.author', author);
+        return author;
+    }
+
+    async function postComment(url, payload) {
+        const response = await fetch(url, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(payload)
+        });
+        if (!response.ok) {
+            await throwProblem(response);
+        }
+        await loadOpenThreads();
+        applyCommentIndicators();
+        await loadCommentThreads();
+    }
+
+    async function submitCommentThread() {
+        const anchor = tableData.commentAnchor;
+        const body = document.getElementById('comment-body');
+        if (!anchor || !body.value.trim()) return;
+
+        try {
+            await postComment(`/api/v1/tables/${tableData.tableId}/comments`, {
+                record_id: anchor.recordId,
+                field: anchor.field,
+                author: commentAuthor(),
+                body: body.value
+            });
+            body.value = '';
+        } catch (error) {
+            console.error('Error creating comment:', error);
+            showError('댓글 작성에 실패했습니다');
+        }
+    }
+
+    async function replyToThread(threadId) {
+        const input = document.getElementById(`reply-${threadId}`);
+        if (!input || !input.value.trim()) return;
+
+        try {
+            await postComment(`/api/v1/tables/${tableData.tableId}/comments/${threadId}/replies`, {
+                author: commentAuthor(),
+                body: input.value
+            });
+        } catch (error) {
+            console.error('Error replying to thread:', error);
+            showError('답글 작성에 실패했습니다');
+        }
+    }
+
+    async function setThreadResolved(threadId, resolved) {
+        try {
+            await postComment(`/api/v1/tables/${tableData.tableId}/comments/${threadId}/${resolved ? 'resolve' : 'unresolve'}`, {
+                by: commentAuthor()
+            });
+        } catch (error) {
+            console.error('Error updating thread:', error);
+            showError('스레드 상태 변경에 실패했습니다');
+        }
+    }
+
     // Bulk edit functions
     function toggleBulkEditMode() {
         // TODO: Implement bulk edit mode